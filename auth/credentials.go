@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Credentials is the set of values used to sign requests.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// If the credentials can expire, and the time they expire at.
+	CanExpire bool
+	Expires   time.Time
+}
+
+// Expired returns if the credentials have expired at the time.
+func (c Credentials) Expired(now time.Time) bool {
+	return c.CanExpire && !now.Before(c.Expires)
+}
+
+// HasKeys returns if the credentials have both an access key ID and secret
+// access key.
+func (c Credentials) HasKeys() bool {
+	return len(c.AccessKeyID) != 0 && len(c.SecretAccessKey) != 0
+}
+
+// CredentialsProvider provides the interface for retrieving the credentials
+// requests are signed with.
+type CredentialsProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc wraps a function with the CredentialsProvider
+// interface.
+type CredentialsProviderFunc func(context.Context) (Credentials, error)
+
+// Retrieve returns the credentials returned by the wrapped function.
+func (fn CredentialsProviderFunc) Retrieve(ctx context.Context) (Credentials, error) {
+	return fn(ctx)
+}
+
+// StaticCredentialsProvider is a CredentialsProvider that always returns the
+// same credentials.
+type StaticCredentialsProvider struct {
+	Value Credentials
+}
+
+// Retrieve returns the static credentials.
+func (p StaticCredentialsProvider) Retrieve(context.Context) (Credentials, error) {
+	return p.Value, nil
+}
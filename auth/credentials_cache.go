@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// CachingProviderOptions provides the options for configuring the
+// CachingProvider.
+type CachingProviderOptions struct {
+	// Duration before the cached credentials expire that they are refreshed,
+	// so requests are not signed with credentials about to expire. If zero,
+	// the credentials are refreshed once they have expired.
+	ExpiryWindow time.Duration
+}
+
+// CachingProvider is a CredentialsProvider that caches the credentials
+// retrieved from the wrapped provider until they expire, or are within the
+// ExpiryWindow of expiring. Credentials that cannot expire are cached until
+// invalidated.
+//
+// Concurrent calls to Retrieve while the credentials are being refreshed
+// wait for the single refresh in progress, instead of each retrieving
+// credentials from the wrapped provider. The expiry of the cached
+// credentials is determined with the context's clock, see
+// middleware.GetClock.
+type CachingProvider struct {
+	provider CredentialsProvider
+	options  CachingProviderOptions
+
+	mu       sync.Mutex
+	creds    *Credentials
+	inflight *credentialsRefresh
+}
+
+type credentialsRefresh struct {
+	done  chan struct{}
+	creds Credentials
+	err   error
+}
+
+// NewCachingProvider returns a CachingProvider caching the credentials of the
+// provider, modified by the functional options provided.
+func NewCachingProvider(provider CredentialsProvider, optFns ...func(*CachingProviderOptions)) *CachingProvider {
+	var o CachingProviderOptions
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return &CachingProvider{
+		provider: provider,
+		options:  o,
+	}
+}
+
+// Retrieve returns the cached credentials, refreshing them from the wrapped
+// provider if they are not cached, or are expiring. Returns an error if the
+// refresh failed, or the context is done before the refresh completes.
+func (p *CachingProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	now := middleware.GetClock(ctx).Now()
+
+	p.mu.Lock()
+	if p.creds != nil && !p.creds.Expired(now.Add(p.options.ExpiryWindow)) {
+		creds := *p.creds
+		p.mu.Unlock()
+		return creds, nil
+	}
+
+	refresh := p.inflight
+	if refresh == nil {
+		refresh = &credentialsRefresh{done: make(chan struct{})}
+		p.inflight = refresh
+		p.mu.Unlock()
+		p.refresh(ctx, refresh)
+	} else {
+		p.mu.Unlock()
+	}
+
+	select {
+	case <-refresh.done:
+		return refresh.creds, refresh.err
+	case <-ctx.Done():
+		return Credentials{}, ctx.Err()
+	}
+}
+
+func (p *CachingProvider) refresh(ctx context.Context, refresh *credentialsRefresh) {
+	creds, err := p.provider.Retrieve(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to refresh cached credentials, %w", err)
+	}
+
+	p.mu.Lock()
+	if err == nil {
+		p.creds = &creds
+	}
+	p.inflight = nil
+	p.mu.Unlock()
+
+	refresh.creds, refresh.err = creds, err
+	close(refresh.done)
+}
+
+// Invalidate clears the cached credentials, so the next call to Retrieve
+// refreshes them, (e.g. after the service rejected the credentials).
+func (p *CachingProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.creds = nil
+}
+
+var _ CredentialsProvider = (*CachingProvider)(nil)
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+func TestCachingProvider_ConcurrentRefresh(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	provider := NewCachingProvider(CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+	}))
+
+	const callers = 100
+	var started, wg sync.WaitGroup
+	started.Add(callers)
+	wg.Add(callers)
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			started.Done()
+			creds, err := provider.Retrieve(context.Background())
+			if err == nil && creds.AccessKeyID != "AKID" {
+				err = fmt.Errorf("expect AKID access key, got %v", creds.AccessKeyID)
+			}
+			errs <- err
+		}()
+	}
+	started.Wait()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expect no error, got %v", err)
+		}
+	}
+	if e, a := int32(1), atomic.LoadInt32(&calls); e != a {
+		t.Errorf("expect %v refresh, got %v", e, a)
+	}
+}
+
+func TestCachingProvider_Expiry(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+		return now
+	}))
+
+	var calls int
+	provider := NewCachingProvider(CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		calls++
+		return Credentials{
+			AccessKeyID:     fmt.Sprintf("AKID%d", calls),
+			SecretAccessKey: "SECRET",
+			CanExpire:       true,
+			Expires:         now.Add(10 * time.Minute),
+		}, nil
+	}), func(o *CachingProviderOptions) {
+		o.ExpiryWindow = time.Minute
+	})
+
+	cases := []struct {
+		Advance   time.Duration
+		ExpectKey string
+	}{
+		{ExpectKey: "AKID1"},
+		{Advance: 8 * time.Minute, ExpectKey: "AKID1"},
+		// Within the expiry window of the first credentials.
+		{Advance: time.Minute + time.Second, ExpectKey: "AKID2"},
+		{Advance: time.Minute, ExpectKey: "AKID2"},
+	}
+
+	for i, c := range cases {
+		now = now.Add(c.Advance)
+		creds, err := provider.Retrieve(ctx)
+		if err != nil {
+			t.Fatalf("%d, expect no error, got %v", i, err)
+		}
+		if e, a := c.ExpectKey, creds.AccessKeyID; e != a {
+			t.Errorf("%d, expect %v access key, got %v", i, e, a)
+		}
+	}
+
+	provider.Invalidate()
+	creds, err := provider.Retrieve(ctx)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "AKID3", creds.AccessKeyID; e != a {
+		t.Errorf("expect %v access key after invalidate, got %v", e, a)
+	}
+}
+
+func TestCachingProvider_RefreshError(t *testing.T) {
+	refreshErr := fmt.Errorf("service unavailable")
+	var fail bool
+	provider := NewCachingProvider(CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		if fail {
+			return Credentials{}, refreshErr
+		}
+		return Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+	}))
+
+	fail = true
+	if _, err := provider.Retrieve(context.Background()); err == nil {
+		t.Fatalf("expect refresh error, got none")
+	}
+
+	fail = false
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "AKID", creds.AccessKeyID; e != a {
+		t.Errorf("expect %v access key, got %v", e, a)
+	}
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCredentialsExpired(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		Credentials Credentials
+		Expect      bool
+	}{
+		"cannot expire": {
+			Credentials: Credentials{Expires: now.Add(-time.Hour)},
+		},
+		"not expired": {
+			Credentials: Credentials{CanExpire: true, Expires: now.Add(time.Hour)},
+		},
+		"at expiry": {
+			Credentials: Credentials{CanExpire: true, Expires: now},
+			Expect:      true,
+		},
+		"expired": {
+			Credentials: Credentials{CanExpire: true, Expires: now.Add(-time.Hour)},
+			Expect:      true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Expect, c.Credentials.Expired(now); e != a {
+				t.Errorf("expect expired %v, got %v", e, a)
+			}
+		})
+	}
+}
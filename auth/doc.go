@@ -0,0 +1,20 @@
+/*
+Package auth provides the interfaces for the credentials and request signers
+used to authenticate operation requests.
+
+Request signers, (e.g. SigV4 and the asymmetric SigV4a), are provided by
+implementations of the Signer interface. The transport/http package's
+SignRequest middleware invokes the Signer for each request attempt, and the
+PresignRequest middleware invokes a PresignSigner to presign requests instead
+of sending them.
+
+Signers building a canonical request select the headers to sign with the
+SignedHeaderOptions from GetSignedHeaderOptions. The SignedHeaders and
+UnsignableHeaders options set the headers allowed and denied, and are set on
+the context by the SignRequest middleware's SignedHeaderOptions.
+
+CachingProvider wraps a CredentialsProvider, caching its credentials until
+they are about to expire, and sharing a single refresh between concurrent
+callers.
+*/
+package auth
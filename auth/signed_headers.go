@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// DefaultUnsignableHeaders are the headers excluded from the signature if no
+// deny list is set. These headers are commonly modified by proxies and
+// transports after the request is signed.
+var DefaultUnsignableHeaders = []string{
+	"Authorization",
+	"User-Agent",
+	"X-Amzn-Trace-Id",
+	"Expect",
+}
+
+// SignedHeaderOptions selects the request headers a signer includes in the
+// canonical request.
+//
+// If Allow is set only the headers in the allow list are signed, otherwise all
+// headers are signed. Headers in the deny list are never signed, even if they
+// are allowed. The host header is always signed. Header names are matched case
+// insensitively.
+type SignedHeaderOptions struct {
+	// The headers to sign. If empty all headers not denied are signed.
+	Allow []string
+
+	// The headers not to sign. Defaults to DefaultUnsignableHeaders.
+	Deny []string
+}
+
+// SignedHeaders returns a signer option that sets headers allowed to be
+// signed.
+func SignedHeaders(allow []string) func(*SignedHeaderOptions) {
+	return func(o *SignedHeaderOptions) {
+		o.Allow = allow
+	}
+}
+
+// UnsignableHeaders returns a signer option that sets the headers that will
+// not be signed.
+func UnsignableHeaders(deny []string) func(*SignedHeaderOptions) {
+	return func(o *SignedHeaderOptions) {
+		o.Deny = deny
+	}
+}
+
+// NewSignedHeaderOptions returns the SignedHeaderOptions with the options
+// applied, and defaults set.
+func NewSignedHeaderOptions(optFns ...func(*SignedHeaderOptions)) SignedHeaderOptions {
+	var o SignedHeaderOptions
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if o.Deny == nil {
+		o.Deny = DefaultUnsignableHeaders
+	}
+	return o
+}
+
+// IsSigned returns if the header name should be included in the signature.
+func (o SignedHeaderOptions) IsSigned(name string) bool {
+	if strings.EqualFold(name, "Host") {
+		return true
+	}
+
+	deny := o.Deny
+	if deny == nil {
+		deny = DefaultUnsignableHeaders
+	}
+	if containsHeader(deny, name) {
+		return false
+	}
+	if len(o.Allow) != 0 {
+		return containsHeader(o.Allow, name)
+	}
+	return true
+}
+
+// CanonicalHeaders returns the canonical headers block, and the semicolon
+// separated list of signed header names, of the request headers selected by
+// the options. Header names are lowercased and sorted, and values are trimmed
+// and joined by commas. The host header is taken from the request's Host, or
+// URL host if not set.
+func (o SignedHeaderOptions) CanonicalHeaders(r *http.Request) (canonical, signedHeaders string) {
+	values := map[string][]string{}
+	for name, vs := range r.Header {
+		if !o.IsSigned(name) {
+			continue
+		}
+		lower := strings.ToLower(name)
+		values[lower] = append(values[lower], vs...)
+	}
+
+	host := r.Host
+	if len(host) == 0 && r.URL != nil {
+		host = r.URL.Host
+	}
+	values["host"] = []string{host}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		vs := values[name]
+		trimmed := make([]string, len(vs))
+		for i, v := range vs {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		b.WriteString(name)
+		b.WriteRune(':')
+		b.WriteString(strings.Join(trimmed, ","))
+		b.WriteRune('\n')
+	}
+
+	return b.String(), strings.Join(names, ";")
+}
+
+func containsHeader(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+type signedHeaderOptionsKey struct{}
+
+// WithSignedHeaderOptions returns a context with the options selecting the
+// headers to sign set. Signers building a canonical request should consult
+// the options with GetSignedHeaderOptions.
+func WithSignedHeaderOptions(ctx context.Context, o SignedHeaderOptions) context.Context {
+	return context.WithValue(ctx, signedHeaderOptionsKey{}, o)
+}
+
+// GetSignedHeaderOptions returns the signed header options set on the
+// context, or the default options if none were set.
+func GetSignedHeaderOptions(ctx context.Context) SignedHeaderOptions {
+	v, ok := ctx.Value(signedHeaderOptionsKey{}).(SignedHeaderOptions)
+	if !ok {
+		return NewSignedHeaderOptions()
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestSignedHeaderOptionsCanonicalHeaders(t *testing.T) {
+	cases := map[string]struct {
+		Options         []func(*SignedHeaderOptions)
+		ExpectSigned    string
+		ExpectCanonical string
+	}{
+		"default": {
+			ExpectSigned: "content-type;host;x-amz-date;x-amz-meta-list",
+			ExpectCanonical: "content-type:application/json\n" +
+				"host:example.com\n" +
+				"x-amz-date:20200601T120000Z\n" +
+				"x-amz-meta-list:a b,c\n",
+		},
+		"allow list": {
+			Options:      []func(*SignedHeaderOptions){SignedHeaders([]string{"X-Amz-Date", "user-agent"})},
+			ExpectSigned: "host;x-amz-date",
+			ExpectCanonical: "host:example.com\n" +
+				"x-amz-date:20200601T120000Z\n",
+		},
+		"deny list": {
+			Options:      []func(*SignedHeaderOptions){UnsignableHeaders([]string{"content-type", "X-Amz-Meta-List"})},
+			ExpectSigned: "authorization;expect;host;user-agent;x-amz-date",
+			ExpectCanonical: "authorization:secret\n" +
+				"expect:100-continue\n" +
+				"host:example.com\n" +
+				"user-agent:agent\n" +
+				"x-amz-date:20200601T120000Z\n",
+		},
+		"deny overrides allow": {
+			Options: []func(*SignedHeaderOptions){
+				SignedHeaders([]string{"Content-Type", "X-Amz-Date"}),
+				UnsignableHeaders([]string{"x-amz-date"}),
+			},
+			ExpectSigned: "content-type;host",
+			ExpectCanonical: "content-type:application/json\n" +
+				"host:example.com\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "https://example.com/path", nil)
+			r.Header.Set("Authorization", "secret")
+			r.Header.Set("User-Agent", "agent")
+			r.Header.Set("Expect", "100-continue")
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("X-Amz-Date", "20200601T120000Z")
+			r.Header.Add("X-Amz-Meta-List", "  a   b ")
+			r.Header.Add("X-Amz-Meta-List", "c")
+
+			canonical, signed := NewSignedHeaderOptions(c.Options...).CanonicalHeaders(r)
+			if e, a := c.ExpectSigned, signed; e != a {
+				t.Errorf("expect %q signed headers, got %q", e, a)
+			}
+			if e, a := c.ExpectCanonical, canonical; e != a {
+				t.Errorf("expect %q canonical headers, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestGetSignedHeaderOptions(t *testing.T) {
+	if o := GetSignedHeaderOptions(context.Background()); o.IsSigned("Authorization") || !o.IsSigned("X-Amz-Date") {
+		t.Errorf("expect default options, got %v", o)
+	}
+
+	ctx := WithSignedHeaderOptions(context.Background(), NewSignedHeaderOptions(SignedHeaders([]string{"Content-Type"})))
+	o := GetSignedHeaderOptions(ctx)
+	if !o.IsSigned("content-type") {
+		t.Errorf("expect content-type signed")
+	}
+	if o.IsSigned("X-Amz-Date") {
+		t.Errorf("expect x-amz-date not signed")
+	}
+	if !o.IsSigned("Host") {
+		t.Errorf("expect host always signed")
+	}
+}
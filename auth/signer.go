@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Signer provides the interface for signing HTTP requests. Implementations
+// add the signature, and any values it covers, to the request's headers or
+// query string.
+//
+// The region set is the set of regions the signature is valid for. SigV4
+// signers use a single region, where asymmetric signers such as SigV4a sign
+// with an ECDSA key valid for all regions in the set, (e.g. ["*"]).
+type Signer interface {
+	SignHTTP(
+		ctx context.Context, credentials Credentials, r *http.Request,
+		payloadHash string, service string, regionSet []string, signingTime time.Time,
+	) error
+}
+
+// SignerFunc wraps a function with the Signer interface.
+type SignerFunc func(
+	ctx context.Context, credentials Credentials, r *http.Request,
+	payloadHash string, service string, regionSet []string, signingTime time.Time,
+) error
+
+// SignHTTP signs the request with the wrapped function.
+func (fn SignerFunc) SignHTTP(
+	ctx context.Context, credentials Credentials, r *http.Request,
+	payloadHash string, service string, regionSet []string, signingTime time.Time,
+) error {
+	return fn(ctx, credentials, r, payloadHash, service, regionSet, signingTime)
+}
+
+// NopSigner is a Signer that does not sign the request, for operations that
+// do not require authentication.
+type NopSigner struct{}
+
+// SignHTTP returns without modifying the request.
+func (NopSigner) SignHTTP(
+	context.Context, Credentials, *http.Request, string, string, []string, time.Time,
+) error {
+	return nil
+}
+
+var _ Signer = NopSigner{}
+var _ Signer = SignerFunc(nil)
+
+// PresignSigner provides the interface for presigning HTTP requests. Unlike
+// Signer, the signature is added to the request's query string along with
+// the duration the signature is valid for, so the request's URL can be used
+// without the Authorization header.
+type PresignSigner interface {
+	PresignHTTP(
+		ctx context.Context, credentials Credentials, r *http.Request,
+		payloadHash string, service string, regionSet []string, signingTime time.Time,
+		expires time.Duration,
+	) error
+}
+
+// PresignSignerFunc wraps a function with the PresignSigner interface.
+type PresignSignerFunc func(
+	ctx context.Context, credentials Credentials, r *http.Request,
+	payloadHash string, service string, regionSet []string, signingTime time.Time,
+	expires time.Duration,
+) error
+
+// PresignHTTP presigns the request with the wrapped function.
+func (fn PresignSignerFunc) PresignHTTP(
+	ctx context.Context, credentials Credentials, r *http.Request,
+	payloadHash string, service string, regionSet []string, signingTime time.Time,
+	expires time.Duration,
+) error {
+	return fn(ctx, credentials, r, payloadHash, service, regionSet, signingTime, expires)
+}
+
+var _ PresignSigner = PresignSignerFunc(nil)
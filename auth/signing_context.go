@@ -0,0 +1,35 @@
+package auth
+
+import "context"
+
+type (
+	signingNameKey   struct{}
+	signingRegionKey struct{}
+)
+
+// WithSigningName returns a context with the name of the service the
+// request is signed for set, (e.g. by endpoint resolution). The signing name
+// is available to the signing and logging middleware.
+func WithSigningName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, signingNameKey{}, name)
+}
+
+// GetSigningName returns the signing name set on the context, or empty
+// string if no signing name was set.
+func GetSigningName(ctx context.Context) string {
+	v, _ := ctx.Value(signingNameKey{}).(string)
+	return v
+}
+
+// WithSigningRegion returns a context with the region the request is signed
+// for set.
+func WithSigningRegion(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, signingRegionKey{}, region)
+}
+
+// GetSigningRegion returns the signing region set on the context, or empty
+// string if no signing region was set.
+func GetSigningRegion(ctx context.Context) string {
+	v, _ := ctx.Value(signingRegionKey{}).(string)
+	return v
+}
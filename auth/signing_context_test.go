@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSigningContext(t *testing.T) {
+	ctx := context.Background()
+	if e, a := "", GetSigningName(ctx); e != a {
+		t.Errorf("expect no signing name, got %v", a)
+	}
+	if e, a := "", GetSigningRegion(ctx); e != a {
+		t.Errorf("expect no signing region, got %v", a)
+	}
+
+	ctx = WithSigningRegion(WithSigningName(ctx, "svc"), "us-west-2")
+	if e, a := "svc", GetSigningName(ctx); e != a {
+		t.Errorf("expect %v signing name, got %v", e, a)
+	}
+	if e, a := "us-west-2", GetSigningRegion(ctx); e != a {
+		t.Errorf("expect %v signing region, got %v", e, a)
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Package document provides the protocol agnostic representation of Smithy
+document shapes, which hold untyped JSON-like data.
+
+A document's value is stored as an untyped Go value tree, made up of the
+following types:
+
+	map[string]interface{}     object
+	[]interface{}              array
+	string                     string
+	bool                       true, false
+	nil                        null
+	int64, uint64              integer number
+	float64                    floating point number
+	Number                     number literal, as serialized
+
+Values provided to New are normalized to these types, (e.g. int32 is stored
+as int64, and []string as []interface{}), so protocol encoders only need to
+handle the normalized types. Integer and floating point numbers remain
+distinguishable, including number literals decoded from a serialized
+document, whose kind is determined by the literal.
+*/
+package document
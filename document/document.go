@@ -0,0 +1,391 @@
+package document
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// Interface provides the interface of a Smithy document. Protocol encoders
+// marshal the document's untyped value, and generated code unmarshals the
+// document's value into Go values.
+type Interface interface {
+	// MarshalSmithyDocument returns the document's normalized untyped
+	// value. Number literals are returned unchanged so no precision is lost.
+	MarshalSmithyDocument() (interface{}, error)
+
+	// UnmarshalSmithyDocument decodes the document's value into the value
+	// pointed to by v.
+	UnmarshalSmithyDocument(v interface{}) error
+}
+
+// Document is an untyped document value.
+type Document struct {
+	value interface{}
+}
+
+// New returns a document for the untyped value. Returns an error if the
+// value, or a value nested within it, cannot be represented by a document.
+func New(v interface{}) (*Document, error) {
+	nv, err := normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{value: nv}, nil
+}
+
+// IsNull returns if the document's value is null.
+func (d *Document) IsNull() bool {
+	return d.value == nil
+}
+
+// MarshalSmithyDocument returns the document's normalized untyped value.
+func (d *Document) MarshalSmithyDocument() (interface{}, error) {
+	return copyValue(d.value), nil
+}
+
+// UnmarshalSmithyDocument decodes the document's value into the value v
+// points to. v may be a pointer to interface{}, map[string]interface{},
+// []interface{}, string, bool, Number, *big.Int, or any integer or floating
+// point type.
+//
+// Number literals unmarshaled into an interface{} are converted to int64,
+// or uint64 if they overflow int64, for integers, and float64 otherwise.
+// Integers that overflow uint64 remain a Number.
+func (d *Document) UnmarshalSmithyDocument(v interface{}) error {
+	switch tv := v.(type) {
+	case *interface{}:
+		*tv = unmarshalValue(d.value)
+	case *map[string]interface{}:
+		if d.value == nil {
+			*tv = nil
+			return nil
+		}
+		m, ok := d.value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cannot unmarshal document %T into %T", d.value, v)
+		}
+		*tv = unmarshalValue(m).(map[string]interface{})
+	case *[]interface{}:
+		if d.value == nil {
+			*tv = nil
+			return nil
+		}
+		a, ok := d.value.([]interface{})
+		if !ok {
+			return fmt.Errorf("cannot unmarshal document %T into %T", d.value, v)
+		}
+		*tv = unmarshalValue(a).([]interface{})
+	case *string:
+		s, ok := d.value.(string)
+		if !ok && d.value != nil {
+			return fmt.Errorf("cannot unmarshal document %T into %T", d.value, v)
+		}
+		*tv = s
+	case *bool:
+		b, ok := d.value.(bool)
+		if !ok && d.value != nil {
+			return fmt.Errorf("cannot unmarshal document %T into %T", d.value, v)
+		}
+		*tv = b
+	case *Number:
+		n, err := asNumber(d.value)
+		if err != nil {
+			return err
+		}
+		*tv = n
+	case *big.Int:
+		n, err := asNumber(d.value)
+		if err != nil {
+			return err
+		}
+		i, err := n.BigInt()
+		if err != nil {
+			return err
+		}
+		tv.Set(i)
+	default:
+		return d.unmarshalNumber(v)
+	}
+	return nil
+}
+
+// unmarshalNumber decodes the document's number value into the integer or
+// floating point value v points to.
+func (d *Document) unmarshalNumber(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal document into non-pointer %T", v)
+	}
+	ev := rv.Elem()
+
+	if d.value == nil {
+		ev.Set(reflect.Zero(ev.Type()))
+		return nil
+	}
+
+	n, err := asNumber(d.value)
+	if err != nil {
+		return err
+	}
+
+	switch ev.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(string(n), 10, ev.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal document number %v into %T, %w", n, v, err)
+		}
+		ev.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(string(n), 10, ev.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal document number %v into %T, %w", n, v, err)
+		}
+		ev.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := parseFloat(n, ev.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal document number %v into %T, %w", n, v, err)
+		}
+		ev.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported document unmarshal type %T", v)
+	}
+	return nil
+}
+
+// asNumber returns the number value as a Number literal.
+func asNumber(v interface{}) (Number, error) {
+	switch tv := v.(type) {
+	case Number:
+		return tv, nil
+	case int64:
+		return Number(strconv.FormatInt(tv, 10)), nil
+	case uint64:
+		return Number(strconv.FormatUint(tv, 10)), nil
+	case float64:
+		s := strconv.FormatFloat(tv, 'g', -1, 64)
+		if Number(s).IsInteger() && !math.IsInf(tv, 0) && !math.IsNaN(tv) {
+			s += ".0"
+		}
+		return Number(s), nil
+	default:
+		return "", fmt.Errorf("document value %T is not a number", v)
+	}
+}
+
+// parseFloat parses the number literal, allowing the "NaN", "Infinity", and
+// "-Infinity" values of float64 numbers formatted by asNumber.
+func parseFloat(n Number, bitSize int) (float64, error) {
+	switch n {
+	case "NaN":
+		return math.NaN(), nil
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	}
+	return strconv.ParseFloat(string(n), bitSize)
+}
+
+// normalize returns a copy of the value converted to the types documents are
+// made up of.
+func normalize(v interface{}) (interface{}, error) {
+	switch tv := v.(type) {
+	case nil, bool, string, int64, uint64, float64:
+		return tv, nil
+	case Number:
+		if _, err := strconv.ParseFloat(string(tv), 64); err != nil && !isRangeErr(err) {
+			return nil, fmt.Errorf("invalid document number %q", string(tv))
+		}
+		return tv, nil
+	case int:
+		return int64(tv), nil
+	case int8:
+		return int64(tv), nil
+	case int16:
+		return int64(tv), nil
+	case int32:
+		return int64(tv), nil
+	case uint:
+		return normalizeUint(uint64(tv)), nil
+	case uint8:
+		return int64(tv), nil
+	case uint16:
+		return int64(tv), nil
+	case uint32:
+		return int64(tv), nil
+	case float32:
+		return float64(tv), nil
+	case *big.Int:
+		if tv == nil {
+			return nil, nil
+		}
+		return Number(tv.String()), nil
+	case *big.Float:
+		if tv == nil {
+			return nil, nil
+		}
+		s := tv.Text('g', -1)
+		if Number(s).IsInteger() {
+			s += ".0"
+		}
+		return Number(s), nil
+	case Interface:
+		dv, err := tv.MarshalSmithyDocument()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal nested document, %w", err)
+		}
+		return normalize(dv)
+	case []interface{}:
+		a := make([]interface{}, len(tv))
+		for i, av := range tv {
+			nv, err := normalize(av)
+			if err != nil {
+				return nil, fmt.Errorf("index %d, %w", i, err)
+			}
+			a[i] = nv
+		}
+		return a, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(tv))
+		for k, mv := range tv {
+			nv, err := normalize(mv)
+			if err != nil {
+				return nil, fmt.Errorf("key %q, %w", k, err)
+			}
+			m[k] = nv
+		}
+		return m, nil
+	}
+
+	return normalizeReflect(reflect.ValueOf(v))
+}
+
+// normalizeReflect normalizes typed slices, and maps with string keys.
+func normalizeReflect(rv reflect.Value) (interface{}, error) {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return normalize(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		a := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			nv, err := normalize(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("index %d, %w", i, err)
+			}
+			a[i] = nv
+		}
+		return a, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported document map key type %v", rv.Type().Key())
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			nv, err := normalize(iter.Value().Interface())
+			if err != nil {
+				return nil, fmt.Errorf("key %q, %w", k, err)
+			}
+			m[k] = nv
+		}
+		return m, nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return normalizeUint(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	default:
+		return nil, fmt.Errorf("unsupported document value type %v", rv.Type())
+	}
+}
+
+// normalizeUint returns the value as an int64 if it does not overflow int64.
+func normalizeUint(v uint64) interface{} {
+	if v <= math.MaxInt64 {
+		return int64(v)
+	}
+	return v
+}
+
+func isRangeErr(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
+
+// copyValue returns a deep copy of the normalized value's objects and
+// arrays.
+func copyValue(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case []interface{}:
+		a := make([]interface{}, len(tv))
+		for i, av := range tv {
+			a[i] = copyValue(av)
+		}
+		return a
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(tv))
+		for k, mv := range tv {
+			m[k] = copyValue(mv)
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+// unmarshalValue returns a deep copy of the normalized value, with Number
+// literals converted to Go numbers.
+func unmarshalValue(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case Number:
+		if tv.IsInteger() {
+			if i, err := tv.Int64(); err == nil {
+				return i
+			}
+			if u, err := tv.Uint64(); err == nil {
+				return u
+			}
+			return tv
+		}
+		f, err := tv.Float64()
+		if err != nil && !isRangeErr(err) {
+			return tv
+		}
+		return f
+	case []interface{}:
+		a := make([]interface{}, len(tv))
+		for i, av := range tv {
+			a[i] = unmarshalValue(av)
+		}
+		return a
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(tv))
+		for k, mv := range tv {
+			m[k] = unmarshalValue(mv)
+		}
+		return m
+	default:
+		return v
+	}
+}
+
+var _ Interface = (*Document)(nil)
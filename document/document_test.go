@@ -0,0 +1,272 @@
+package document
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type nestedDocument struct {
+	value interface{}
+}
+
+func (d nestedDocument) MarshalSmithyDocument() (interface{}, error) { return d.value, nil }
+
+func (d nestedDocument) UnmarshalSmithyDocument(interface{}) error { return nil }
+
+func TestNew(t *testing.T) {
+	cases := map[string]struct {
+		Value     interface{}
+		Expect    interface{}
+		ExpectErr string
+	}{
+		"null": {},
+		"scalars": {
+			Value: map[string]interface{}{
+				"string": "abc",
+				"bool":   true,
+				"int32":  int32(-7),
+				"uint8":  uint8(7),
+				"max":    uint64(math.MaxUint64),
+				"float":  float32(1.5),
+			},
+			Expect: map[string]interface{}{
+				"string": "abc",
+				"bool":   true,
+				"int32":  int64(-7),
+				"uint8":  int64(7),
+				"max":    uint64(math.MaxUint64),
+				"float":  float64(1.5),
+			},
+		},
+		"typed collections": {
+			Value: map[string][]int{"a": {1, 2}},
+			Expect: map[string]interface{}{
+				"a": []interface{}{int64(1), int64(2)},
+			},
+		},
+		"nested document": {
+			Value: []interface{}{
+				nestedDocument{value: map[string]interface{}{"b": []string{"c"}}},
+			},
+			Expect: []interface{}{
+				map[string]interface{}{"b": []interface{}{"c"}},
+			},
+		},
+		"big numbers": {
+			Value: []interface{}{
+				new(big.Int).Lsh(big.NewInt(1), 70),
+				big.NewFloat(2),
+			},
+			Expect: []interface{}{
+				Number("1180591620717411303424"),
+				Number("2.0"),
+			},
+		},
+		"invalid number": {
+			Value:     Number("abc"),
+			ExpectErr: `invalid document number "abc"`,
+		},
+		"unsupported type": {
+			Value:     map[string]interface{}{"a": struct{}{}},
+			ExpectErr: `key "a", unsupported document value type struct {}`,
+		},
+		"unsupported map key": {
+			Value:     map[int]string{1: "a"},
+			ExpectErr: "unsupported document map key type int",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := New(c.Value)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect %q error, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			v, err := d.MarshalSmithyDocument()
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %#v, got %#v", e, a)
+			}
+			if e, a := c.Expect == nil, d.IsNull(); e != a {
+				t.Errorf("expect %v null, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	value := map[string]interface{}{
+		"int":      int64(1),
+		"float":    float64(1),
+		"maxInt":   Number("9223372036854775807"),
+		"maxUint":  Number("18446744073709551615"),
+		"overflow": Number("18446744073709551616"),
+		"decimal":  Number("1.5"),
+		"list": []interface{}{
+			map[string]interface{}{"nested": []interface{}{nil, false, "x"}},
+		},
+	}
+
+	d, err := New(value)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var actual interface{}
+	if err := d.UnmarshalSmithyDocument(&actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]interface{}{
+		"int":      int64(1),
+		"float":    float64(1),
+		"maxInt":   int64(math.MaxInt64),
+		"maxUint":  uint64(math.MaxUint64),
+		"overflow": Number("18446744073709551616"),
+		"decimal":  float64(1.5),
+		"list": []interface{}{
+			map[string]interface{}{"nested": []interface{}{nil, false, "x"}},
+		},
+	}
+	if e, a := expect, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %#v, got %#v", e, a)
+	}
+
+	// Modifying the unmarshaled value must not modify the document.
+	actual.(map[string]interface{})["int"] = "changed"
+	var again map[string]interface{}
+	if err := d.UnmarshalSmithyDocument(&again); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(1), again["int"]; e != a {
+		t.Errorf("expect document unmodified %v, got %v", e, a)
+	}
+}
+
+func TestDocumentUnmarshalNumber(t *testing.T) {
+	cases := map[string]struct {
+		Value     interface{}
+		Target    interface{}
+		Expect    interface{}
+		ExpectErr string
+	}{
+		"int64 literal": {
+			Value:  Number("9007199254740993"),
+			Target: new(int64),
+			Expect: int64(9007199254740993),
+		},
+		"int8 overflow": {
+			Value:     int64(300),
+			Target:    new(int8),
+			ExpectErr: "cannot unmarshal document number 300 into *int8",
+		},
+		"float into int": {
+			Value:     float64(1),
+			Target:    new(int),
+			ExpectErr: "cannot unmarshal document number 1.0 into *int",
+		},
+		"int into float": {
+			Value:  int64(2),
+			Target: new(float64),
+			Expect: float64(2),
+		},
+		"float keeps kind": {
+			Value:  float64(3),
+			Target: new(Number),
+			Expect: Number("3.0"),
+		},
+		"NaN": {
+			Value:  math.NaN(),
+			Target: new(float32),
+		},
+		"uint64": {
+			Value:  uint64(math.MaxUint64),
+			Target: new(uint64),
+			Expect: uint64(math.MaxUint64),
+		},
+		"big int": {
+			Value:  Number("123456789012345678901234567890"),
+			Target: new(big.Int),
+			Expect: "123456789012345678901234567890",
+		},
+		"string into number": {
+			Value:     "abc",
+			Target:    new(int),
+			ExpectErr: "document value string is not a number",
+		},
+		"number into string": {
+			Value:     int64(1),
+			Target:    new(string),
+			ExpectErr: "cannot unmarshal document int64 into *string",
+		},
+		"null into int": {
+			Target: new(int),
+			Expect: 0,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := New(c.Value)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			err = d.UnmarshalSmithyDocument(c.Target)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect %q error, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			actual := reflect.ValueOf(c.Target).Elem().Interface()
+			switch tv := actual.(type) {
+			case float32:
+				if !math.IsNaN(float64(tv)) {
+					t.Errorf("expect NaN, got %v", tv)
+				}
+			case big.Int:
+				if e, a := c.Expect, tv.String(); e != a {
+					t.Errorf("expect %v, got %v", e, a)
+				}
+			default:
+				if e, a := c.Expect, actual; !reflect.DeepEqual(e, a) {
+					t.Errorf("expect %#v, got %#v", e, a)
+				}
+			}
+		})
+	}
+}
+
+func TestNumberIsInteger(t *testing.T) {
+	cases := map[Number]bool{
+		"1":     true,
+		"-12":   true,
+		"1.0":   false,
+		"1e3":   false,
+		"-2E-2": false,
+		"":      false,
+	}
+
+	for n, expect := range cases {
+		if e, a := expect, n.IsInteger(); e != a {
+			t.Errorf("expect %q integer %v, got %v", n, e, a)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package document
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Number is a document number literal, preserving the exact value of the
+// number as it was written in the serialized document. Whether the number is
+// an integer or a floating point value is determined by the literal, (e.g. 1
+// and 1.0).
+type Number string
+
+// String returns the number literal.
+func (n Number) String() string {
+	return string(n)
+}
+
+// IsInteger returns if the number literal is an integer, with no fractional
+// or exponent part.
+func (n Number) IsInteger() bool {
+	return len(n) != 0 && !strings.ContainsAny(string(n), ".eE")
+}
+
+// Int64 returns the number as an int64. Returns an error if the number is
+// not an integer, or overflows int64.
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Uint64 returns the number as a uint64. Returns an error if the number is
+// not a non-negative integer, or overflows uint64.
+func (n Number) Uint64() (uint64, error) {
+	return strconv.ParseUint(string(n), 10, 64)
+}
+
+// Float64 returns the number as a float64, which may lose precision.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// BigInt returns the number as an arbitrary precision integer. Returns an
+// error if the number is not an integer.
+func (n Number) BigInt() (*big.Int, error) {
+	v, ok := new(big.Int).SetString(string(n), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer number %q", string(n))
+	}
+	return v, nil
+}
+
+// BigFloat returns the number as an arbitrary precision decimal, with
+// enough precision to represent every digit of the number.
+func (n Number) BigFloat() (*big.Float, error) {
+	prec := uint(len(n)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+	v, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid decimal number %q, %w", string(n), err)
+	}
+	return v, nil
+}
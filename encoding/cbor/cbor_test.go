@@ -0,0 +1,366 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Test vectors from RFC 8949 Appendix A.
+var canonicalVectors = []struct {
+	Hex        string
+	Value      interface{}
+	DecodeOnly bool
+}{
+	{Hex: "00", Value: uint64(0)},
+	{Hex: "01", Value: uint64(1)},
+	{Hex: "0a", Value: uint64(10)},
+	{Hex: "17", Value: uint64(23)},
+	{Hex: "1818", Value: uint64(24)},
+	{Hex: "1819", Value: uint64(25)},
+	{Hex: "1864", Value: uint64(100)},
+	{Hex: "1903e8", Value: uint64(1000)},
+	{Hex: "1a000f4240", Value: uint64(1000000)},
+	{Hex: "1b000000e8d4a51000", Value: uint64(1000000000000)},
+	{Hex: "1bffffffffffffffff", Value: uint64(18446744073709551615)},
+	{Hex: "3bffffffffffffffff", Value: bigInt("-18446744073709551616"), DecodeOnly: true},
+	{Hex: "20", Value: int64(-1)},
+	{Hex: "29", Value: int64(-10)},
+	{Hex: "3863", Value: int64(-100)},
+	{Hex: "3903e7", Value: int64(-1000)},
+	{Hex: "f90000", Value: 0.0},
+	{Hex: "f98000", Value: math.Copysign(0, -1)},
+	{Hex: "f93c00", Value: 1.0},
+	{Hex: "fb3ff199999999999a", Value: 1.1},
+	{Hex: "f93e00", Value: 1.5},
+	{Hex: "f97bff", Value: 65504.0},
+	{Hex: "fa47c35000", Value: 100000.0},
+	{Hex: "fa7f7fffff", Value: 3.4028234663852886e+38},
+	{Hex: "fb7e37e43c8800759c", Value: 1.0e+300},
+	{Hex: "f90001", Value: 5.960464477539063e-8},
+	{Hex: "f90400", Value: 0.00006103515625},
+	{Hex: "f9c400", Value: -4.0},
+	{Hex: "fbc010666666666666", Value: -4.1},
+	{Hex: "f97c00", Value: math.Inf(1)},
+	{Hex: "f97e00", Value: math.NaN()},
+	{Hex: "f9fc00", Value: math.Inf(-1)},
+	{Hex: "fa7f800000", Value: math.Inf(1), DecodeOnly: true},
+	{Hex: "fb7ff0000000000000", Value: math.Inf(1), DecodeOnly: true},
+	{Hex: "f4", Value: false},
+	{Hex: "f5", Value: true},
+	{Hex: "f6", Value: nil},
+	{Hex: "f7", Value: Undefined{}},
+	{Hex: "f0", Value: Simple(16), DecodeOnly: true},
+	{Hex: "f8ff", Value: Simple(255), DecodeOnly: true},
+	{Hex: "c074323031332d30332d32315432303a30343a30305a", Value: Tag{Number: 0, Content: "2013-03-21T20:04:00Z"}},
+	{Hex: "c11a514b67b0", Value: Tag{Number: 1, Content: uint64(1363896240)}},
+	{Hex: "c1fb41d452d9ec200000", Value: Tag{Number: 1, Content: 1363896240.5}},
+	{Hex: "d74401020304", Value: Tag{Number: 23, Content: []byte{1, 2, 3, 4}}},
+	{Hex: "d82076687474703a2f2f7777772e6578616d706c652e636f6d", Value: Tag{Number: 32, Content: "http://www.example.com"}},
+	{Hex: "40", Value: []byte{}},
+	{Hex: "4401020304", Value: []byte{1, 2, 3, 4}},
+	{Hex: "60", Value: ""},
+	{Hex: "6161", Value: "a"},
+	{Hex: "6449455446", Value: "IETF"},
+	{Hex: "62225c", Value: "\"\\"},
+	{Hex: "62c3bc", Value: "ü"},
+	{Hex: "63e6b0b4", Value: "水"},
+	{Hex: "64f0908591", Value: "\U00010151"},
+	{Hex: "80", Value: []interface{}{}},
+	{Hex: "83010203", Value: []interface{}{uint64(1), uint64(2), uint64(3)}},
+	{Hex: "8301820203820405", Value: []interface{}{
+		uint64(1),
+		[]interface{}{uint64(2), uint64(3)},
+		[]interface{}{uint64(4), uint64(5)},
+	}},
+	{Hex: "98190102030405060708090a0b0c0d0e0f101112131415161718181819", Value: oneTo25()},
+	{Hex: "a0", Value: map[interface{}]interface{}{}},
+	{Hex: "a201020304", Value: map[interface{}]interface{}{uint64(1): uint64(2), uint64(3): uint64(4)}},
+	{Hex: "a26161016162820203", Value: map[interface{}]interface{}{
+		"a": uint64(1),
+		"b": []interface{}{uint64(2), uint64(3)},
+	}},
+	{Hex: "826161a161626163", Value: []interface{}{
+		"a",
+		map[interface{}]interface{}{"b": "c"},
+	}},
+	{Hex: "a56161614161626142616361436164614461656145", Value: map[interface{}]interface{}{
+		"a": "A", "b": "B", "c": "C", "d": "D", "e": "E",
+	}},
+	{Hex: "5f42010243030405ff", Value: []byte{1, 2, 3, 4, 5}, DecodeOnly: true},
+	{Hex: "7f657374726561646d696e67ff", Value: "streaming", DecodeOnly: true},
+	{Hex: "9fff", Value: []interface{}{}, DecodeOnly: true},
+	{Hex: "9f018202039f0405ffff", Value: []interface{}{
+		uint64(1),
+		[]interface{}{uint64(2), uint64(3)},
+		[]interface{}{uint64(4), uint64(5)},
+	}, DecodeOnly: true},
+	{Hex: "9f0102030405060708090a0b0c0d0e0f101112131415161718181819ff", Value: oneTo25(), DecodeOnly: true},
+	{Hex: "bf61610161629f0203ffff", Value: map[interface{}]interface{}{
+		"a": uint64(1),
+		"b": []interface{}{uint64(2), uint64(3)},
+	}, DecodeOnly: true},
+	{Hex: "826161bf61626163ff", Value: []interface{}{
+		"a",
+		map[interface{}]interface{}{"b": "c"},
+	}, DecodeOnly: true},
+	{Hex: "bf6346756ef563416d7421ff", Value: map[interface{}]interface{}{
+		"Fun": true,
+		"Amt": int64(-2),
+	}, DecodeOnly: true},
+}
+
+func TestDecodeCanonicalVectors(t *testing.T) {
+	for _, c := range canonicalVectors {
+		t.Run(c.Hex, func(t *testing.T) {
+			d := NewDecoder(bytes.NewReader(mustDecodeHex(t, c.Hex)))
+			v, err := d.Decode()
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if !valuesEqual(c.Value, v) {
+				t.Errorf("expect %#v, got %#v", c.Value, v)
+			}
+
+			if _, err := d.Decode(); err != io.EOF {
+				t.Errorf("expect end of stream, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodeCanonicalVectors(t *testing.T) {
+	for _, c := range canonicalVectors {
+		if c.DecodeOnly {
+			continue
+		}
+		t.Run(c.Hex, func(t *testing.T) {
+			e := NewEncoder()
+			encodeValue(e, c.Value)
+
+			if ex, a := c.Hex, hex.EncodeToString(e.Bytes()); ex != a {
+				t.Errorf("expect %v, got %v", ex, a)
+			}
+		})
+	}
+}
+
+func TestEncodeIndefinite(t *testing.T) {
+	e := NewEncoder()
+	e.WriteIndefiniteMapHeader()
+	e.WriteString("a")
+	e.WriteUint(1)
+	e.WriteString("b")
+	e.WriteIndefiniteArrayHeader()
+	e.WriteUint(2)
+	e.WriteUint(3)
+	e.WriteBreak()
+	e.WriteBreak()
+
+	if ex, a := "bf61610161629f0203ffff", hex.EncodeToString(e.Bytes()); ex != a {
+		t.Errorf("expect %v, got %v", ex, a)
+	}
+
+	v, err := NewDecoder(bytes.NewReader(e.Bytes())).Decode()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[interface{}]interface{}{
+		"a": uint64(1),
+		"b": []interface{}{uint64(2), uint64(3)},
+	}
+	if !reflect.DeepEqual(expect, v) {
+		t.Errorf("expect %v, got %v", expect, v)
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	e := NewEncoder()
+	e.WriteString("first")
+	e.WriteInt(-2)
+	e.WriteBool(true)
+
+	d := NewDecoder(bytes.NewReader(e.Bytes()))
+	var vs []interface{}
+	for {
+		v, err := d.Decode()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		vs = append(vs, v)
+	}
+
+	if e, a := []interface{}{"first", int64(-2), true}, vs; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	cases := map[string]string{
+		"truncated argument":     "19",
+		"truncated string":       "6449",
+		"truncated array":        "8301",
+		"unterminated array":     "9f01",
+		"unexpected break":       "ff",
+		"break in definite":      "82ff",
+		"missing map value":      "bf6161ff",
+		"invalid string chunk":   "7f4161ff",
+		"invalid additional":     "1c",
+		"unhashable map key":     "a18001",
+		"large declared length":  "5bffffffffffffffff",
+		"truncated indefinite":   "5f42",
+		"truncated tag contents": "c1",
+	}
+
+	for name, h := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewDecoder(bytes.NewReader(mustDecodeHex(t, h))).Decode()
+			if err == nil || err == io.EOF {
+				t.Errorf("expect error, got %v", err)
+			}
+		})
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	b.Run("cbor", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			e := NewEncoder()
+			e.WriteMapHeader(5)
+			e.WriteString("Name")
+			e.WriteString("benchmark")
+			e.WriteString("Count")
+			e.WriteUint(1234567)
+			e.WriteString("Offset")
+			e.WriteInt(-4321)
+			e.WriteString("Ratio")
+			e.WriteFloat(1.1)
+			e.WriteString("Tags")
+			e.WriteIndefiniteArrayHeader()
+			for _, tag := range []string{"a", "b", "c", "d"} {
+				e.WriteString(tag)
+			}
+			e.WriteBreak()
+		}
+	})
+
+	b.Run("json", func(b *testing.B) {
+		v := map[string]interface{}{
+			"Name":   "benchmark",
+			"Count":  1234567,
+			"Offset": -4321,
+			"Ratio":  1.1,
+			"Tags":   []string{"a", "b", "c", "d"},
+		}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// encodeValue encodes the Go value using the canonical encoding, with map
+// keys sorted by their encoded bytes.
+func encodeValue(e *Encoder, v interface{}) {
+	switch tv := v.(type) {
+	case nil:
+		e.WriteNull()
+	case Undefined:
+		e.WriteUndefined()
+	case bool:
+		e.WriteBool(tv)
+	case uint64:
+		e.WriteUint(tv)
+	case int64:
+		e.WriteInt(tv)
+	case float64:
+		e.WriteFloat(tv)
+	case []byte:
+		e.WriteBytes(tv)
+	case string:
+		e.WriteString(tv)
+	case Tag:
+		e.WriteTag(tv.Number)
+		encodeValue(e, tv.Content)
+	case []interface{}:
+		e.WriteArrayHeader(len(tv))
+		for _, ev := range tv {
+			encodeValue(e, ev)
+		}
+	case map[interface{}]interface{}:
+		type entry struct{ k, v []byte }
+		entries := make([]entry, 0, len(tv))
+		for k, mv := range tv {
+			ke, ve := NewEncoder(), NewEncoder()
+			encodeValue(ke, k)
+			encodeValue(ve, mv)
+			entries = append(entries, entry{k: ke.Bytes(), v: ve.Bytes()})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].k, entries[j].k) < 0
+		})
+
+		e.WriteMapHeader(len(entries))
+		for _, entry := range entries {
+			e.buf.Write(entry.k)
+			e.buf.Write(entry.v)
+		}
+	default:
+		panic("unsupported test value type")
+	}
+}
+
+func valuesEqual(expect, actual interface{}) bool {
+	switch ev := expect.(type) {
+	case float64:
+		av, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		if math.IsNaN(ev) {
+			return math.IsNaN(av)
+		}
+		return ev == av && math.Signbit(ev) == math.Signbit(av)
+	case *big.Int:
+		av, ok := actual.(*big.Int)
+		return ok && ev.Cmp(av) == 0
+	}
+	return reflect.DeepEqual(expect, actual)
+}
+
+func bigInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big int " + s)
+	}
+	return v
+}
+
+func oneTo25() []interface{} {
+	vs := make([]interface{}, 25)
+	for i := range vs {
+		vs[i] = uint64(i + 1)
+	}
+	return vs
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %v, %v", s, err)
+	}
+	return b
+}
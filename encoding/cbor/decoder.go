@@ -0,0 +1,373 @@
+package cbor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// Tag is a decoded tagged data item.
+type Tag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// Undefined is the decoded undefined simple value.
+type Undefined struct{}
+
+// Simple is a decoded simple value which has no Go equivalent.
+type Simple uint8
+
+// Decoder provides decoding of CBOR data items from a stream.
+//
+// Data items are decoded into the following Go types:
+//
+//	unsigned integer         uint64
+//	negative integer         int64, or *big.Int if it overflows the int64 range
+//	byte string              []byte
+//	text string              string
+//	array                    []interface{}
+//	map                      map[interface{}]interface{}
+//	tagged data item         Tag
+//	false, true              bool
+//	null                     nil
+//	undefined                Undefined
+//	half, single, double     float64
+//	other simple values      Simple
+type Decoder struct {
+	r byteReader
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// NewDecoder returns a decoder that reads data items from the reader. The
+// reader will be buffered if it does not implement io.ByteReader.
+func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Decoder{r: br}
+}
+
+// Decode reads the next data item from the stream. Returns io.EOF if there
+// are no more data items in the stream, and io.ErrUnexpectedEOF if the
+// stream ends within a data item.
+func (d *Decoder) Decode() (interface{}, error) {
+	v, err := d.decode()
+	return v, definiteErr(err)
+}
+
+var errBreak = fmt.Errorf("cbor: break")
+
+// definiteErr converts a break read where an indefinite-length item was not
+// being decoded into an error.
+func definiteErr(err error) error {
+	if err == errBreak {
+		return fmt.Errorf("cbor: unexpected break outside indefinite-length item")
+	}
+	return err
+}
+
+func (d *Decoder) decode() (interface{}, error) {
+	initial, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	major := initial & 0xe0
+	info := initial & 0x1f
+
+	if major == majorTypeSimple {
+		return d.decodeSimple(info)
+	}
+
+	if info == addInfoIndefinite {
+		return d.decodeIndefinite(major)
+	}
+
+	arg, err := d.readArgument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorTypeUint:
+		return arg, nil
+
+	case majorTypeNegInt:
+		if arg <= math.MaxInt64 {
+			return -1 - int64(arg), nil
+		}
+		v := new(big.Int).SetUint64(arg)
+		return v.Neg(v).Sub(v, big.NewInt(1)), nil
+
+	case majorTypeBytes:
+		return d.readBytes(arg)
+
+	case majorTypeString:
+		b, err := d.readBytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+
+	case majorTypeArray:
+		vs := make([]interface{}, 0, capHint(arg))
+		for i := uint64(0); i < arg; i++ {
+			v, err := d.decodeElement()
+			if err != nil {
+				return nil, definiteErr(err)
+			}
+			vs = append(vs, v)
+		}
+		return vs, nil
+
+	case majorTypeMap:
+		m := make(map[interface{}]interface{}, capHint(arg))
+		for i := uint64(0); i < arg; i++ {
+			if err := d.decodeMapEntry(m); err != nil {
+				return nil, definiteErr(err)
+			}
+		}
+		return m, nil
+
+	case majorTypeTag:
+		v, err := d.decodeElement()
+		if err != nil {
+			return nil, definiteErr(err)
+		}
+		return Tag{Number: arg, Content: v}, nil
+	}
+
+	return nil, fmt.Errorf("cbor: unknown major type %d", major>>5)
+}
+
+// decodeElement decodes a data item nested within another data item, where
+// the end of the stream is unexpected.
+func (d *Decoder) decodeElement() (interface{}, error) {
+	v, err := d.decode()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return v, err
+}
+
+func (d *Decoder) decodeMapEntry(m map[interface{}]interface{}) error {
+	k, err := d.decodeElement()
+	if err != nil {
+		return err
+	}
+	if k != nil && !reflect.TypeOf(k).Comparable() {
+		return fmt.Errorf("cbor: unsupported map key type %T", k)
+	}
+
+	v, err := d.decodeElement()
+	if err == errBreak {
+		return fmt.Errorf("cbor: missing value for map key %v", k)
+	} else if err != nil {
+		return err
+	}
+
+	m[k] = v
+	return nil
+}
+
+func (d *Decoder) decodeIndefinite(major byte) (interface{}, error) {
+	switch major {
+	case majorTypeBytes, majorTypeString:
+		var buf bytes.Buffer
+		for {
+			v, err := d.decodeElement()
+			if err == errBreak {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+
+			switch tv := v.(type) {
+			case []byte:
+				if major != majorTypeBytes {
+					return nil, fmt.Errorf("cbor: invalid indefinite-length string chunk %T", v)
+				}
+				buf.Write(tv)
+			case string:
+				if major != majorTypeString {
+					return nil, fmt.Errorf("cbor: invalid indefinite-length byte string chunk %T", v)
+				}
+				buf.WriteString(tv)
+			default:
+				return nil, fmt.Errorf("cbor: invalid indefinite-length chunk %T", v)
+			}
+		}
+		if major == majorTypeString {
+			return buf.String(), nil
+		}
+		return buf.Bytes(), nil
+
+	case majorTypeArray:
+		vs := []interface{}{}
+		for {
+			v, err := d.decodeElement()
+			if err == errBreak {
+				return vs, nil
+			} else if err != nil {
+				return nil, err
+			}
+			vs = append(vs, v)
+		}
+
+	case majorTypeMap:
+		m := map[interface{}]interface{}{}
+		for {
+			err := d.decodeMapEntry(m)
+			if err == errBreak {
+				return m, nil
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("cbor: invalid indefinite-length major type %d", major>>5)
+}
+
+func (d *Decoder) decodeSimple(info byte) (interface{}, error) {
+	switch initial := majorTypeSimple | info; initial {
+	case simpleFalse:
+		return false, nil
+	case simpleTrue:
+		return true, nil
+	case simpleNull:
+		return nil, nil
+	case simpleUndefined:
+		return Undefined{}, nil
+	case simpleBreak:
+		return nil, errBreak
+
+	case simpleFloat16:
+		v, err := d.readArgument(addInfoUint16)
+		if err != nil {
+			return nil, err
+		}
+		return float16ToFloat64(uint16(v)), nil
+
+	case simpleFloat32:
+		v, err := d.readArgument(addInfoUint32)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(uint32(v))), nil
+
+	case simpleFloat64:
+		v, err := d.readArgument(addInfoUint64)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(v), nil
+	}
+
+	if info < addInfoUint8 {
+		return Simple(info), nil
+	}
+	if info == addInfoUint8 {
+		v, err := d.readArgument(addInfoUint8)
+		if err != nil {
+			return nil, err
+		}
+		return Simple(v), nil
+	}
+
+	return nil, fmt.Errorf("cbor: invalid simple value additional information %d", info)
+}
+
+// readArgument reads the argument of the data item following its initial
+// byte.
+func (d *Decoder) readArgument(info byte) (uint64, error) {
+	var n int
+	switch info {
+	case addInfoUint8:
+		n = 1
+	case addInfoUint16:
+		n = 2
+	case addInfoUint32:
+		n = 4
+	case addInfoUint64:
+		n = 8
+	default:
+		if info < addInfoUint8 {
+			return uint64(info), nil
+		}
+		return 0, fmt.Errorf("cbor: invalid additional information %d", info)
+	}
+
+	var b [8]byte
+	for i := 0; i < n; i++ {
+		c, err := d.r.ReadByte()
+		if err == io.EOF {
+			return 0, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return 0, err
+		}
+		b[8-n+i] = c
+	}
+
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// readBytes reads n bytes from the stream. The bytes are read incrementally
+// so a malformed length does not cause a large allocation up front.
+func (d *Decoder) readBytes(n uint64) ([]byte, error) {
+	if n > math.MaxInt32 {
+		return nil, fmt.Errorf("cbor: data item length %d too large", n)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(capHint(n))
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// capHint bounds the capacity preallocated for data items based on their
+// declared length.
+func capHint(n uint64) int {
+	const maxHint = 1024
+	if n > maxHint {
+		return maxHint
+	}
+	return int(n)
+}
+
+func float16ToFloat64(v uint16) float64 {
+	sign := 1.0
+	if v&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(v>>10) & 0x1f
+	mant := float64(v & 0x3ff)
+
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+
+	return sign * math.Ldexp(mant+1024, exp-25)
+}
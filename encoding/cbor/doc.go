@@ -0,0 +1,12 @@
+/*
+Package cbor provides the encoder and decoder for the Concise Binary Object
+Representation (CBOR) data format defined by RFC 8949.
+
+The Encoder writes CBOR data items, including indefinite-length arrays and
+maps, to a buffer. The Decoder reads data items from a stream mapping them
+onto Go values.
+
+Smithy documents are written with WriteDocument, and read with
+DecodeDocument.
+*/
+package cbor
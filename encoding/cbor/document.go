@@ -0,0 +1,169 @@
+package cbor
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/awslabs/smithy-go/document"
+)
+
+// Tag numbers of the bignum tagged data items, RFC 8949 section 3.4.3.
+const (
+	tagPositiveBignum uint64 = 2
+	tagNegativeBignum uint64 = 3
+)
+
+// WriteDocument writes the document's value as a CBOR data item. Document
+// objects are written as maps with text string keys, in sorted key order.
+// Integer number literals which overflow the CBOR integer range are written
+// as bignums.
+func (e *Encoder) WriteDocument(d document.Interface) error {
+	v, err := d.MarshalSmithyDocument()
+	if err != nil {
+		return fmt.Errorf("failed to marshal document, %w", err)
+	}
+	return e.writeDocumentValue(v)
+}
+
+func (e *Encoder) writeDocumentValue(v interface{}) error {
+	switch tv := v.(type) {
+	case nil:
+		e.WriteNull()
+	case bool:
+		e.WriteBool(tv)
+	case string:
+		e.WriteString(tv)
+	case int64:
+		e.WriteInt(tv)
+	case uint64:
+		e.WriteUint(tv)
+	case float64:
+		e.WriteFloat(tv)
+	case document.Number:
+		return e.writeDocumentNumber(tv)
+	case []interface{}:
+		e.WriteArrayHeader(len(tv))
+		for i, av := range tv {
+			if err := e.writeDocumentValue(av); err != nil {
+				return fmt.Errorf("index %d, %w", i, err)
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(tv))
+		for k := range tv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		e.WriteMapHeader(len(tv))
+		for _, k := range keys {
+			e.WriteString(k)
+			if err := e.writeDocumentValue(tv[k]); err != nil {
+				return fmt.Errorf("key %q, %w", k, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported document value type %T", v)
+	}
+	return nil
+}
+
+func (e *Encoder) writeDocumentNumber(n document.Number) error {
+	if !n.IsInteger() {
+		f, err := n.Float64()
+		if err != nil {
+			return fmt.Errorf("invalid document number %q, %w", string(n), err)
+		}
+		e.WriteFloat(f)
+		return nil
+	}
+
+	if v, err := n.Int64(); err == nil {
+		e.WriteInt(v)
+		return nil
+	}
+	if v, err := n.Uint64(); err == nil {
+		e.WriteUint(v)
+		return nil
+	}
+
+	v, err := n.BigInt()
+	if err != nil {
+		return err
+	}
+	if v.Sign() < 0 {
+		// Negative bignums encode the value -1-n.
+		e.WriteTag(tagNegativeBignum)
+		v = new(big.Int).Sub(new(big.Int).Neg(v), big.NewInt(1))
+	} else {
+		e.WriteTag(tagPositiveBignum)
+	}
+	e.WriteBytes(v.Bytes())
+	return nil
+}
+
+// DecodeDocument reads the next data item from the stream as a Smithy
+// document. Returns an error if the data item, or a data item nested within
+// it, cannot be represented by a document, (e.g. byte strings, or maps with
+// non text string keys). Returns io.EOF if there are no more data items in
+// the stream.
+func (d *Decoder) DecodeDocument() (*document.Document, error) {
+	v, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	dv, err := documentValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode CBOR document, %w", err)
+	}
+	return document.New(dv)
+}
+
+// documentValue converts the decoded data item to the types documents are
+// made up of.
+func documentValue(v interface{}) (interface{}, error) {
+	switch tv := v.(type) {
+	case nil, bool, string, int64, uint64, float64:
+		return tv, nil
+	case *big.Int:
+		return document.Number(tv.String()), nil
+	case Tag:
+		b, ok := tv.Content.([]byte)
+		if !ok || (tv.Number != tagPositiveBignum && tv.Number != tagNegativeBignum) {
+			return nil, fmt.Errorf("unsupported document tag %d", tv.Number)
+		}
+		n := new(big.Int).SetBytes(b)
+		if tv.Number == tagNegativeBignum {
+			n.Sub(new(big.Int).Neg(n), big.NewInt(1))
+		}
+		return document.Number(n.String()), nil
+	case []interface{}:
+		a := make([]interface{}, len(tv))
+		for i, av := range tv {
+			dv, err := documentValue(av)
+			if err != nil {
+				return nil, fmt.Errorf("index %d, %w", i, err)
+			}
+			a[i] = dv
+		}
+		return a, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(tv))
+		for k, mv := range tv {
+			ks, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported document map key type %T", k)
+			}
+			dv, err := documentValue(mv)
+			if err != nil {
+				return nil, fmt.Errorf("key %q, %w", ks, err)
+			}
+			m[ks] = dv
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unsupported document value type %T", v)
+	}
+}
@@ -0,0 +1,83 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/document"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	doc, err := document.New(map[string]interface{}{
+		"int":      int64(-2),
+		"uint":     uint64(18446744073709551615),
+		"float":    float64(1),
+		"literal":  document.Number("1.5"),
+		"bignum":   document.Number("18446744073709551616"),
+		"negative": document.Number("-18446744073709551617"),
+		"list":     []interface{}{nil, true, map[string]interface{}{"s": "x"}},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	e := NewEncoder()
+	if err := e.WriteDocument(doc); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	actual, err := NewDecoder(bytes.NewReader(e.Bytes())).DecodeDocument()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	v, err := actual.MarshalSmithyDocument()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]interface{}{
+		"int":      int64(-2),
+		"uint":     uint64(18446744073709551615),
+		"float":    float64(1),
+		"literal":  float64(1.5),
+		"bignum":   document.Number("18446744073709551616"),
+		"negative": document.Number("-18446744073709551617"),
+		"list":     []interface{}{nil, true, map[string]interface{}{"s": "x"}},
+	}
+	if e, a := expect, v; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %#v, got %#v", e, a)
+	}
+}
+
+func TestDecodeDocumentUnsupported(t *testing.T) {
+	cases := map[string]struct {
+		Hex       string
+		ExpectErr string
+	}{
+		"byte string": {
+			Hex:       "4101",
+			ExpectErr: "unsupported document value type []uint8",
+		},
+		"integer map key": {
+			Hex:       "a10102",
+			ExpectErr: "unsupported document map key type uint64",
+		},
+		"unknown tag": {
+			Hex:       "c11a514b67b0",
+			ExpectErr: "unsupported document tag 1",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, _ := hex.DecodeString(c.Hex)
+			_, err := NewDecoder(bytes.NewReader(b)).DecodeDocument()
+			if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+				t.Errorf("expect %q error, got %v", c.ExpectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// Major types of the CBOR data item's initial byte.
+const (
+	majorTypeUint byte = iota << 5
+	majorTypeNegInt
+	majorTypeBytes
+	majorTypeString
+	majorTypeArray
+	majorTypeMap
+	majorTypeTag
+	majorTypeSimple
+)
+
+// Additional information of the CBOR data item's initial byte.
+const (
+	addInfoUint8      byte = 24
+	addInfoUint16     byte = 25
+	addInfoUint32     byte = 26
+	addInfoUint64     byte = 27
+	addInfoIndefinite byte = 31
+)
+
+// Simple values and float initial bytes of major type 7.
+const (
+	simpleFalse     byte = majorTypeSimple | 20
+	simpleTrue      byte = majorTypeSimple | 21
+	simpleNull      byte = majorTypeSimple | 22
+	simpleUndefined byte = majorTypeSimple | 23
+	simpleFloat16   byte = majorTypeSimple | addInfoUint16
+	simpleFloat32   byte = majorTypeSimple | addInfoUint32
+	simpleFloat64   byte = majorTypeSimple | addInfoUint64
+	simpleBreak     byte = majorTypeSimple | addInfoIndefinite
+)
+
+// Encoder provides encoding of CBOR data items to an underlying buffer.
+//
+// Arrays and maps are started with a header declaring their length, followed
+// by the encoded elements. Indefinite-length arrays and maps must be
+// terminated with WriteBreak once all elements have been written.
+type Encoder struct {
+	buf     *bytes.Buffer
+	scratch [9]byte
+}
+
+// NewEncoder returns an initialized CBOR encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		buf: bytes.NewBuffer(nil),
+	}
+}
+
+// Bytes returns the encoded CBOR data items.
+func (e *Encoder) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// Len returns the number of encoded bytes.
+func (e *Encoder) Len() int {
+	return e.buf.Len()
+}
+
+// WriteUint writes an unsigned integer data item.
+func (e *Encoder) WriteUint(v uint64) {
+	e.writeHead(majorTypeUint, v)
+}
+
+// WriteInt writes a signed integer data item. Negative values are encoded as
+// the negative integer major type.
+func (e *Encoder) WriteInt(v int64) {
+	if v < 0 {
+		e.writeHead(majorTypeNegInt, uint64(^v))
+		return
+	}
+	e.writeHead(majorTypeUint, uint64(v))
+}
+
+// WriteFloat writes a floating point data item using the shortest of the
+// half, single, or double precision encodings that represents the value
+// without loss of precision.
+func (e *Encoder) WriteFloat(v float64) {
+	if f16, ok := float16Bits(v); ok {
+		e.scratch[0] = simpleFloat16
+		binary.BigEndian.PutUint16(e.scratch[1:], f16)
+		e.buf.Write(e.scratch[:3])
+		return
+	}
+
+	if f32 := float32(v); float64(f32) == v {
+		e.WriteFloat32(f32)
+		return
+	}
+
+	e.WriteFloat64(v)
+}
+
+// WriteFloat32 writes a single precision floating point data item.
+func (e *Encoder) WriteFloat32(v float32) {
+	e.scratch[0] = simpleFloat32
+	binary.BigEndian.PutUint32(e.scratch[1:], math.Float32bits(v))
+	e.buf.Write(e.scratch[:5])
+}
+
+// WriteFloat64 writes a double precision floating point data item.
+func (e *Encoder) WriteFloat64(v float64) {
+	e.scratch[0] = simpleFloat64
+	binary.BigEndian.PutUint64(e.scratch[1:], math.Float64bits(v))
+	e.buf.Write(e.scratch[:9])
+}
+
+// WriteBool writes a boolean simple value data item.
+func (e *Encoder) WriteBool(v bool) {
+	if v {
+		e.buf.WriteByte(simpleTrue)
+	} else {
+		e.buf.WriteByte(simpleFalse)
+	}
+}
+
+// WriteNull writes the null simple value data item.
+func (e *Encoder) WriteNull() {
+	e.buf.WriteByte(simpleNull)
+}
+
+// WriteUndefined writes the undefined simple value data item.
+func (e *Encoder) WriteUndefined() {
+	e.buf.WriteByte(simpleUndefined)
+}
+
+// WriteBytes writes a byte string data item.
+func (e *Encoder) WriteBytes(v []byte) {
+	e.writeHead(majorTypeBytes, uint64(len(v)))
+	e.buf.Write(v)
+}
+
+// WriteString writes a UTF-8 text string data item.
+func (e *Encoder) WriteString(v string) {
+	e.writeHead(majorTypeString, uint64(len(v)))
+	e.buf.WriteString(v)
+}
+
+// WriteArrayHeader starts an array data item of n elements. The n elements
+// must be written following the header.
+func (e *Encoder) WriteArrayHeader(n int) {
+	e.writeHead(majorTypeArray, uint64(n))
+}
+
+// WriteIndefiniteArrayHeader starts an indefinite-length array data item.
+// The array must be terminated with WriteBreak.
+func (e *Encoder) WriteIndefiniteArrayHeader() {
+	e.buf.WriteByte(majorTypeArray | addInfoIndefinite)
+}
+
+// WriteMapHeader starts a map data item of n key value pairs. The n pairs
+// must be written following the header, with each key followed by its value.
+func (e *Encoder) WriteMapHeader(n int) {
+	e.writeHead(majorTypeMap, uint64(n))
+}
+
+// WriteIndefiniteMapHeader starts an indefinite-length map data item. The
+// map must be terminated with WriteBreak.
+func (e *Encoder) WriteIndefiniteMapHeader() {
+	e.buf.WriteByte(majorTypeMap | addInfoIndefinite)
+}
+
+// WriteBreak terminates the current indefinite-length array or map.
+func (e *Encoder) WriteBreak() {
+	e.buf.WriteByte(simpleBreak)
+}
+
+// WriteTag writes the tag number of a tagged data item. The tagged data item
+// must be written following the tag.
+func (e *Encoder) WriteTag(tag uint64) {
+	e.writeHead(majorTypeTag, tag)
+}
+
+// writeHead writes the initial byte of a data item for the major type, and
+// the argument value using the shortest encoding.
+func (e *Encoder) writeHead(major byte, v uint64) {
+	switch {
+	case v < uint64(addInfoUint8):
+		e.buf.WriteByte(major | byte(v))
+	case v <= math.MaxUint8:
+		e.scratch[0] = major | addInfoUint8
+		e.scratch[1] = byte(v)
+		e.buf.Write(e.scratch[:2])
+	case v <= math.MaxUint16:
+		e.scratch[0] = major | addInfoUint16
+		binary.BigEndian.PutUint16(e.scratch[1:], uint16(v))
+		e.buf.Write(e.scratch[:3])
+	case v <= math.MaxUint32:
+		e.scratch[0] = major | addInfoUint32
+		binary.BigEndian.PutUint32(e.scratch[1:], uint32(v))
+		e.buf.Write(e.scratch[:5])
+	default:
+		e.scratch[0] = major | addInfoUint64
+		binary.BigEndian.PutUint64(e.scratch[1:], v)
+		e.buf.Write(e.scratch[:9])
+	}
+}
+
+// float16Bits returns the half precision encoding of the value, and if the
+// value can be represented as a half precision float without loss.
+func float16Bits(v float64) (uint16, bool) {
+	if math.IsNaN(v) {
+		return 0x7e00, true
+	}
+
+	f32 := float32(v)
+	if float64(f32) != v {
+		return 0, false
+	}
+
+	bits := math.Float32bits(f32)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int((bits >> 23) & 0xff)
+	mant := bits & 0x7fffff
+
+	switch {
+	case exp == 0xff:
+		// Infinity, NaN was handled above.
+		return sign | 0x7c00, true
+	case exp == 0 && mant == 0:
+		// Signed zero.
+		return sign, true
+	}
+
+	exp -= 127
+	switch {
+	case exp >= -14 && exp <= 15:
+		// Normal half precision value, mantissa must fit in 10 bits.
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(exp+15)<<10 | uint16(mant>>13), true
+
+	case exp >= -24 && exp < -14:
+		// Subnormal half precision value.
+		full := mant | 0x800000
+		shift := uint(-exp - 14 + 13)
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	}
+
+	return 0, false
+}
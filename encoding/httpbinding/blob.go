@@ -0,0 +1,25 @@
+package httpbinding
+
+import (
+	"io"
+	"io/ioutil"
+
+	smithyio "github.com/awslabs/smithy-go/io"
+)
+
+// BlobStream encodes the bytes read from r as a base64 header string value.
+// The blob is encoded as it is read, only the encoded value is buffered.
+func (h HeaderValue) BlobStream(r io.Reader) error {
+	v, err := ioutil.ReadAll(smithyio.Base64EncodeReader(r))
+	if err != nil {
+		return err
+	}
+	h.modifyHeader(string(v))
+	return nil
+}
+
+// Base64BlobBody returns a reader for a blob request body that is base64
+// encoded as it is read, so that a large blob is not buffered in full.
+func Base64BlobBody(r io.Reader) io.Reader {
+	return smithyio.Base64EncodeReader(r)
+}
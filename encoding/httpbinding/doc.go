@@ -0,0 +1,29 @@
+/*
+Package httpbinding provides the encoder for binding operation input members
+to the HTTP request's URI path, query string, and headers, as described by
+the Smithy HTTP binding traits. Timestamps bound to headers are encoded as
+http-date, unless the member's timestampFormat trait selects another format.
+AddRawQuery appends pre-encoded query segments verbatim, (e.g. S3
+subresources such as "?versioning").
+
+HeaderDecoder populates outputs from the response headers bound to their
+members, and SplitHeaderListValues splits the comma separated values of list
+headers. JoinHeaderListValues joins list members into a header value,
+quoting members containing commas, quotes, or whitespace.
+
+AddQueryParams and AddQueryParamsList add the entries of maps bound to the
+query string with the httpQueryParams trait, in sorted key order.
+
+QueryDecoder populates shapes from the query string values bound to their
+members, (e.g. parsed from a presigned or callback URL).
+
+ParseLinkHeader and NextLink read the RFC 5988 Link headers of services
+paginating with links instead of tokens, and FormatLinkHeader writes them.
+
+FormatRange builds the Range header of partial downloads, and
+ParseContentRange parses the Content-Range header of partial responses.
+
+MultipartForm builds multipart/form-data request bodies from text field and
+streaming file parts.
+*/
+package httpbinding
@@ -0,0 +1,218 @@
+package httpbinding
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Encoder provides encoding of REST URI path, query, and header components
+// of an HTTP request.
+type Encoder struct {
+	segments []pathSegment
+	labels   map[string]string
+
+	query    url.Values
+	rawQuery []string
+	header   http.Header
+}
+
+// pathSegment is a literal, or label component of the URI path template.
+type pathSegment struct {
+	literal string
+	label   string
+	greedy  bool
+}
+
+// NewEncoder returns an encoder for the operation's URI path template, static
+// query string, and initial headers. Query and header values encoded will be
+// added on top of these initial values.
+//
+// The path is the operation's URI path template. Labels within the template
+// are delimited by braces, (e.g. "/{Bucket}/{Key+}"). Labels ending with "+"
+// are greedy, and may contain unescaped path separators.
+func NewEncoder(path, query string, headers http.Header) (*Encoder, error) {
+	segments, err := parsePathTemplate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	parseQuery, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query string: %w", err)
+	}
+
+	if headers == nil {
+		headers = http.Header{}
+	}
+
+	return &Encoder{
+		segments: segments,
+		labels:   map[string]string{},
+		query:    parseQuery,
+		header:   headers.Clone(),
+	}, nil
+}
+
+// Encode sets the encoded URI path, query string, and headers on the request.
+//
+// The net/http package ignores the Content-Length header, and requires the
+// length to be set on the request directly. If the header was encoded, it
+// will be removed and its value set as the request's content length.
+//
+// Returns an error if one occurred during encoding, including if a label of
+// the URI path template was not set.
+func (e *Encoder) Encode(req *http.Request) (*http.Request, error) {
+	var path, rawPath strings.Builder
+	for _, segment := range e.segments {
+		if len(segment.label) == 0 {
+			path.WriteString(segment.literal)
+			rawPath.WriteString(segment.literal)
+			continue
+		}
+
+		v, ok := e.labels[segment.label]
+		if !ok {
+			return nil, fmt.Errorf("httpbinding: missing value for URI label %s", segment.label)
+		}
+		path.WriteString(v)
+		rawPath.WriteString(EscapePath(v, !segment.greedy))
+	}
+
+	req.URL.Path, req.URL.RawPath = path.String(), rawPath.String()
+	req.URL.RawQuery = e.encodeQuery()
+
+	// net/http ignores Content-Length header and requires it to be set on http.Request
+	if v := e.header.Get("Content-Length"); len(v) > 0 {
+		iv, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = iv
+		e.header.Del("Content-Length")
+	}
+
+	req.Header = e.header
+
+	return req, nil
+}
+
+// AddHeader returns a HeaderValue for appending to the given header name
+func (e *Encoder) AddHeader(key string) HeaderValue {
+	return newHeaderValue(e.header, key, true)
+}
+
+// SetHeader returns a HeaderValue for setting the given header name
+func (e *Encoder) SetHeader(key string) HeaderValue {
+	return newHeaderValue(e.header, key, false)
+}
+
+// SetURI returns a URIValue used for setting the given path key
+func (e *Encoder) SetURI(key string) URIValue {
+	return newURIValue(e, key)
+}
+
+// SetQuery returns a QueryValue used for setting the given query key
+func (e *Encoder) SetQuery(key string) QueryValue {
+	return newQueryValue(e.query, key, false)
+}
+
+// AddQuery returns a QueryValue used for appending the given query key
+func (e *Encoder) AddQuery(key string) QueryValue {
+	return newQueryValue(e.query, key, true)
+}
+
+// AddRawQuery appends the pre-encoded segment to the query string verbatim,
+// without the segment being re-encoded, (e.g. the "versioning" of an S3
+// subresource request, which must be sent as "?versioning" not
+// "?versioning="). Raw segments are written before the encoded query values,
+// in the order added, and are part of the URL the request is signed with.
+func (e *Encoder) AddRawQuery(segment string) {
+	if len(segment) == 0 {
+		return
+	}
+	e.rawQuery = append(e.rawQuery, segment)
+}
+
+// encodeQuery returns the raw query segments, and the encoded query values,
+// joined as the request's query string.
+func (e *Encoder) encodeQuery() string {
+	encoded := e.query.Encode()
+	if len(e.rawQuery) == 0 {
+		return encoded
+	}
+
+	raw := strings.Join(e.rawQuery, "&")
+	if len(encoded) == 0 {
+		return raw
+	}
+	return raw + "&" + encoded
+}
+
+// setLabel sets the value of the URI path label.
+func (e *Encoder) setLabel(key, value string) error {
+	if len(key) == 0 {
+		return fmt.Errorf("httpbinding: URI label key must not be empty")
+	}
+	if len(value) == 0 {
+		return fmt.Errorf("httpbinding: value for URI label %s must not be empty", key)
+	}
+
+	for _, segment := range e.segments {
+		if segment.label == key {
+			e.labels[key] = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("httpbinding: URI label %s not found in path", key)
+}
+
+// parsePathTemplate splits the URI path template into its literal and label
+// segments.
+func parsePathTemplate(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	for len(path) != 0 {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			segments = append(segments, pathSegment{literal: path})
+			break
+		}
+		if start > 0 {
+			segments = append(segments, pathSegment{literal: path[:start]})
+		}
+
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("httpbinding: unterminated URI label in path %q", path)
+		}
+		end += start
+
+		label := path[start+1 : end]
+		greedy := strings.HasSuffix(label, "+")
+		label = strings.TrimSuffix(label, "+")
+		if len(label) == 0 {
+			return nil, fmt.Errorf("httpbinding: empty URI label in path %q", path)
+		}
+
+		segments = append(segments, pathSegment{label: label, greedy: greedy})
+		path = path[end+1:]
+	}
+
+	return segments, nil
+}
+
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(v, 'f', -1, bitSize)
+}
@@ -0,0 +1,356 @@
+package httpbinding
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func TestEncoderURI(t *testing.T) {
+	cases := map[string]struct {
+		Path          string
+		Labels        map[string]string
+		ExpectPath    string
+		ExpectRawPath string
+		ExpectURL     string
+		ExpectErr     string
+	}{
+		"label": {
+			Path:          "/{Bucket}",
+			Labels:        map[string]string{"Bucket": "my-bucket"},
+			ExpectPath:    "/my-bucket",
+			ExpectRawPath: "/my-bucket",
+			ExpectURL:     "https://example.com/my-bucket",
+		},
+		"label with space and unicode": {
+			Path:          "/{Bucket}/{Key}",
+			Labels:        map[string]string{"Bucket": "b", "Key": "my key ü"},
+			ExpectPath:    "/b/my key ü",
+			ExpectRawPath: "/b/my%20key%20%C3%BC",
+			ExpectURL:     "https://example.com/b/my%20key%20%C3%BC",
+		},
+		"label with slash": {
+			Path:          "/{Bucket}/{Key}",
+			Labels:        map[string]string{"Bucket": "b", "Key": "path/to/my key"},
+			ExpectPath:    "/b/path/to/my key",
+			ExpectRawPath: "/b/path%2Fto%2Fmy%20key",
+			ExpectURL:     "https://example.com/b/path%2Fto%2Fmy%20key",
+		},
+		"greedy label with slash": {
+			Path:          "/{Bucket}/{Key+}",
+			Labels:        map[string]string{"Bucket": "b", "Key": "path/to/my key"},
+			ExpectPath:    "/b/path/to/my key",
+			ExpectRawPath: "/b/path/to/my%20key",
+			ExpectURL:     "https://example.com/b/path/to/my%20key",
+		},
+		"greedy label with unicode": {
+			Path:          "/{Bucket}/{Key+}",
+			Labels:        map[string]string{"Bucket": "b", "Key": "水/ü/a+b"},
+			ExpectPath:    "/b/水/ü/a+b",
+			ExpectRawPath: "/b/%E6%B0%B4/%C3%BC/a%2Bb",
+		},
+		"label value containing braces": {
+			Path:          "/{A}/{B}",
+			Labels:        map[string]string{"A": "{B}", "B": "b"},
+			ExpectPath:    "/{B}/b",
+			ExpectRawPath: "/%7BB%7D/b",
+		},
+		"missing label": {
+			Path:      "/{Bucket}/{Key+}",
+			Labels:    map[string]string{"Bucket": "b"},
+			ExpectErr: "missing value for URI label Key",
+		},
+		"empty label": {
+			Path:      "/{Bucket}",
+			Labels:    map[string]string{"Bucket": ""},
+			ExpectErr: "must not be empty",
+		},
+		"unknown label": {
+			Path:      "/{Bucket}",
+			Labels:    map[string]string{"Key": "k"},
+			ExpectErr: "URI label Key not found",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewEncoder(c.Path, "", nil)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			for k, v := range c.Labels {
+				if err = e.SetURI(k).String(v); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				req := &http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}}
+				if req, err = e.Encode(req); err == nil {
+					if e, a := c.ExpectPath, req.URL.Path; e != a {
+						t.Errorf("expect %v path, got %v", e, a)
+					}
+					if e, a := c.ExpectRawPath, req.URL.RawPath; e != a {
+						t.Errorf("expect %v raw path, got %v", e, a)
+					}
+					if len(c.ExpectURL) != 0 {
+						if e, a := c.ExpectURL, req.URL.String(); e != a {
+							t.Errorf("expect %v URL, got %v", e, a)
+						}
+					}
+				}
+			}
+
+			if len(c.ExpectErr) != 0 {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				if e, a := c.ExpectErr, err.Error(); !strings.Contains(a, e) {
+					t.Errorf("expect %q error, got %q", e, a)
+				}
+			} else if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEncoderHeaderQuery(t *testing.T) {
+	e, err := NewEncoder("/", "x-id=Foo", http.Header{"X-Static": []string{"static"}})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	e.SetHeader("x-amz-count").Integer(3)
+	e.AddHeader("x-amz-list").String("a")
+	e.AddHeader("X-Amz-List").String("b")
+	e.SetHeader("Content-Length").Long(10)
+	if err := e.SetHeader("x-amz-blob").BlobStream(strings.NewReader("abcd")); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	e.SetQuery("flag").Boolean(true)
+	e.AddQuery("item").Double(1.5)
+	e.AddQuery("item").String("two")
+
+	req, err := e.Encode(&http.Request{URL: &url.URL{}})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expectHeader := http.Header{
+		"X-Static":    []string{"static"},
+		"X-Amz-Count": []string{"3"},
+		"X-Amz-List":  []string{"a", "b"},
+		"X-Amz-Blob":  []string{"YWJjZA=="},
+	}
+	if e, a := expectHeader, req.Header; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v headers, got %v", e, a)
+	}
+	if e, a := int64(10), req.ContentLength; e != a {
+		t.Errorf("expect %v content length, got %v", e, a)
+	}
+	if e, a := "flag=true&item=1.5&item=two&x-id=Foo", req.URL.RawQuery; e != a {
+		t.Errorf("expect %v query, got %v", e, a)
+	}
+}
+
+func TestEncoderRawQuery(t *testing.T) {
+	cases := map[string]struct {
+		Query       string
+		Raw         []string
+		Values      map[string]string
+		ExpectQuery string
+	}{
+		"subresource": {
+			Raw:         []string{"versioning"},
+			ExpectQuery: "versioning",
+		},
+		"subresource with values": {
+			Query:       "x-id=GetObject",
+			Raw:         []string{"acl"},
+			Values:      map[string]string{"versionId": "a b+c"},
+			ExpectQuery: "acl&versionId=a+b%2Bc&x-id=GetObject",
+		},
+		"multiple segments": {
+			Raw:         []string{"uploads", "prefix=a%2Fb"},
+			ExpectQuery: "uploads&prefix=a%2Fb",
+		},
+		"empty segment": {
+			Raw:         []string{""},
+			Values:      map[string]string{"key": "value"},
+			ExpectQuery: "key=value",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewEncoder("/{Bucket}", c.Query, nil)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if err := e.SetURI("Bucket").String("bucket"); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			for _, v := range c.Raw {
+				e.AddRawQuery(v)
+			}
+			for k, v := range c.Values {
+				e.SetQuery(k).String(v)
+			}
+
+			req := smithyhttp.NewStackRequest().(*smithyhttp.Request)
+			req.URL, _ = url.Parse("https://example.com")
+			if req.Request, err = e.Encode(req.Request); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectQuery, req.URL.RawQuery; e != a {
+				t.Errorf("expect %v query, got %v", e, a)
+			}
+
+			var signedQuery string
+			stack := middleware.NewStack("raw query", func() interface{} { return req })
+			stack.Finalize.Add(&smithyhttp.SignRequest{
+				Signer: auth.SignerFunc(func(
+					ctx context.Context, credentials auth.Credentials, r *http.Request,
+					payloadHash string, service string, regionSet []string, signingTime time.Time,
+				) error {
+					signedQuery = r.URL.RawQuery
+					return nil
+				}),
+			}, middleware.After)
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					return nil, middleware.Metadata{}, nil
+				}), stack)
+			if _, _, err := handler.Handle(context.Background(), struct{}{}); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectQuery, signedQuery; e != a {
+				t.Errorf("expect %v signed query, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestHeaderValueTimestamp(t *testing.T) {
+	v := time.Date(2014, 4, 29, 18, 30, 38, 0, time.UTC)
+
+	cases := map[string]struct {
+		Format    smithytime.Format
+		Expect    string
+		ExpectErr bool
+	}{
+		"default http-date": {
+			Expect: "Tue, 29 Apr 2014 18:30:38 GMT",
+		},
+		"http-date": {
+			Format: smithytime.HTTPDate,
+			Expect: "Tue, 29 Apr 2014 18:30:38 GMT",
+		},
+		"epoch-seconds": {
+			Format: smithytime.EpochSeconds,
+			Expect: "1398796238",
+		},
+		"date-time": {
+			Format: smithytime.DateTime,
+			Expect: "2014-04-29T18:30:38Z",
+		},
+		"unknown format": {
+			Format:    "rfc-822",
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewEncoder("/", "", nil)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			err = e.SetHeader("x-amz-timestamp").Timestamp(v, c.Format)
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			req, err := e.Encode(&http.Request{URL: &url.URL{}})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, req.Header.Get("X-Amz-Timestamp"); e != a {
+				t.Errorf("expect %q header, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestJoinHeaderListValues(t *testing.T) {
+	cases := map[string]struct {
+		Members []string
+		Expect  string
+	}{
+		"plain":          {Members: []string{"a", "b", "c"}, Expect: "a,b,c"},
+		"comma":          {Members: []string{"a", "b,c", "d"}, Expect: `a,"b,c",d`},
+		"quote":          {Members: []string{`a"b`}, Expect: `"a\"b"`},
+		"backslash":      {Members: []string{`a\b`, `"c\"`}, Expect: `a\b,"\"c\\\""`},
+		"whitespace":     {Members: []string{" a", "b c", "d\t"}, Expect: `" a","b c","d` + "\t" + `"`},
+		"empty member":   {Members: []string{"a", "", "b"}, Expect: "a,,b"},
+		"single member":  {Members: []string{"a"}, Expect: "a"},
+		"no members":     {Members: []string{}, Expect: ""},
+		"quoted comma":   {Members: []string{"a,b,c"}, Expect: `"a,b,c"`},
+		"leading quote":  {Members: []string{`"a`}, Expect: `"\"a"`},
+		"trailing comma": {Members: []string{"a,"}, Expect: `"a,"`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			newHeaderValue(header, "X-Amz-List", false).StringList(c.Members)
+
+			v := header.Get("X-Amz-List")
+			if e, a := c.Expect, v; e != a {
+				t.Errorf("expect %q header, got %q", e, a)
+			}
+			if len(c.Members) == 0 {
+				return
+			}
+
+			members, err := SplitHeaderListValues(header.Values("X-Amz-List"))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Members, members; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %q members, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestHeaderListRoundTrip(t *testing.T) {
+	members, err := SplitHeaderListValues([]string{`a,"b,c",d`})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"a", "b,c", "d"}, members; !reflect.DeepEqual(e, a) {
+		t.Fatalf("expect %q members, got %q", e, a)
+	}
+
+	if e, a := `a,"b,c",d`, JoinHeaderListValues(members); e != a {
+		t.Errorf("expect %q header, got %q", e, a)
+	}
+}
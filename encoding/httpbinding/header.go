@@ -0,0 +1,118 @@
+package httpbinding
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+// HeaderValue is used to encode values to an HTTP header
+type HeaderValue struct {
+	header http.Header
+	key    string
+	append bool
+}
+
+func newHeaderValue(header http.Header, key string, append bool) HeaderValue {
+	return HeaderValue{header: header, key: http.CanonicalHeaderKey(key), append: append}
+}
+
+func (h HeaderValue) modifyHeader(value string) {
+	if h.append {
+		h.header[h.key] = append(h.header[h.key], value)
+	} else {
+		h.header[h.key] = append(h.header[h.key][:0], value)
+	}
+}
+
+// String encodes the value v as the header string value
+func (h HeaderValue) String(v string) {
+	h.modifyHeader(v)
+}
+
+// Boolean encodes the value v as a header bool value
+func (h HeaderValue) Boolean(v bool) {
+	h.modifyHeader(strconv.FormatBool(v))
+}
+
+// Integer encodes the value v as the header int32 value
+func (h HeaderValue) Integer(v int32) {
+	h.modifyHeader(strconv.FormatInt(int64(v), 10))
+}
+
+// Long encodes the value v as the header int64 value
+func (h HeaderValue) Long(v int64) {
+	h.modifyHeader(strconv.FormatInt(v, 10))
+}
+
+// Float encodes the value v as the header float32 value
+func (h HeaderValue) Float(v float32) {
+	h.modifyHeader(formatFloat(float64(v), 32))
+}
+
+// Double encodes the value v as the header float64 value
+func (h HeaderValue) Double(v float64) {
+	h.modifyHeader(formatFloat(v, 64))
+}
+
+// Blob encodes the value v as a base64 header string value
+func (h HeaderValue) Blob(v []byte) {
+	h.modifyHeader(base64.StdEncoding.EncodeToString(v))
+}
+
+// Timestamp encodes the value v as a header timestamp value in the format of
+// the member's timestampFormat trait. If the format is empty, the header
+// default of http-date is used, (e.g. "Tue, 29 Apr 2014 18:30:38 GMT").
+// Returns an error if the format is unknown.
+func (h HeaderValue) Timestamp(v time.Time, format smithytime.Format) error {
+	switch format {
+	case "", smithytime.HTTPDate:
+		h.modifyHeader(smithytime.FormatHTTPDate(v))
+	case smithytime.DateTime:
+		h.modifyHeader(smithytime.FormatDateTime(v))
+	case smithytime.EpochSeconds:
+		h.modifyHeader(formatFloat(smithytime.FormatEpochSeconds(v), 64))
+	default:
+		return fmt.Errorf("unknown header timestamp format %q", format)
+	}
+	return nil
+}
+
+// StringList encodes the list member's values as a single comma separated
+// header value, see JoinHeaderListValues.
+func (h HeaderValue) StringList(vs []string) {
+	h.modifyHeader(JoinHeaderListValues(vs))
+}
+
+// JoinHeaderListValues joins the list's members into a comma separated
+// header value. Members containing commas, quotes, or whitespace are double
+// quoted, with quotes and backslashes within them escaped by a backslash, so
+// the members are split back unchanged by SplitHeaderListValues.
+func JoinHeaderListValues(vs []string) string {
+	var sb strings.Builder
+	for i, v := range vs {
+		if i != 0 {
+			sb.WriteByte(',')
+		}
+		if !strings.ContainsAny(v, ",\" \t") {
+			sb.WriteString(v)
+			continue
+		}
+
+		sb.WriteByte('"')
+		for j := 0; j < len(v); j++ {
+			c := v[j]
+			if c == '"' || c == '\\' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(c)
+		}
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
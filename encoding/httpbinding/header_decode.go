@@ -0,0 +1,136 @@
+package httpbinding
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HeaderDecoder populates an operation's output from the response headers
+// bound to its members with the httpHeader and httpPrefixHeaders traits,
+// without reading the response body, (e.g. the output of HEAD operations).
+type HeaderDecoder struct {
+	// Setters of members bound to a header, keyed by header name. Setters are
+	// only called for headers present in the response, with all of the
+	// header's values.
+	Headers map[string]func(values []string) error
+
+	// Setters of map members bound to all headers with a prefix, keyed by
+	// the prefix. The map passed to the setter is keyed by the lower cased
+	// header name with the prefix removed, with the header's first value.
+	// Setters are only called if a header with the prefix is present.
+	PrefixHeaders map[string]func(map[string]string) error
+}
+
+// Decode calls the setters for the headers present in the response headers.
+// Returns an error if a setter fails.
+func (d HeaderDecoder) Decode(header http.Header) error {
+	for name, set := range d.Headers {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if err := set(values); err != nil {
+			return fmt.Errorf("failed to decode %s header, %w", name, err)
+		}
+	}
+
+	for prefix, set := range d.PrefixHeaders {
+		m := prefixHeaders(header, prefix)
+		if m == nil {
+			continue
+		}
+		if err := set(m); err != nil {
+			return fmt.Errorf("failed to decode %s prefix headers, %w", prefix, err)
+		}
+	}
+
+	return nil
+}
+
+func prefixHeaders(header http.Header, prefix string) map[string]string {
+	prefix = strings.ToLower(prefix)
+
+	var m map[string]string
+	for key, values := range header {
+		lower := strings.ToLower(key)
+		if !strings.HasPrefix(lower, prefix) || len(values) == 0 {
+			continue
+		}
+		if m == nil {
+			m = map[string]string{}
+		}
+		m[lower[len(prefix):]] = values[0]
+	}
+	return m
+}
+
+// SplitHeaderListValues splits the values of a list member's header into
+// the list's members. Each header value may contain multiple comma separated
+// members, and members containing commas or quotes are double quoted, with
+// quotes and backslashes within them escaped by a backslash. Whitespace
+// around unquoted members is trimmed.
+//
+// Returns an error if a quoted member is not terminated, or is followed by
+// characters other than a comma.
+func SplitHeaderListValues(values []string) ([]string, error) {
+	var members []string
+	for _, v := range values {
+		split, err := splitHeaderListValue(v)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, split...)
+	}
+	return members, nil
+}
+
+func splitHeaderListValue(v string) ([]string, error) {
+	var members []string
+	for i := 0; i <= len(v); {
+		// Skip leading whitespace of the member.
+		for i < len(v) && (v[i] == ' ' || v[i] == '\t') {
+			i++
+		}
+
+		if i < len(v) && v[i] == '"' {
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(v) {
+				c := v[i]
+				if c == '\\' && i+1 < len(v) {
+					sb.WriteByte(v[i+1])
+					i += 2
+					continue
+				}
+				i++
+				if c == '"' {
+					closed = true
+					break
+				}
+				sb.WriteByte(c)
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted header list member, %q", v)
+			}
+			for i < len(v) && (v[i] == ' ' || v[i] == '\t') {
+				i++
+			}
+			if i < len(v) && v[i] != ',' {
+				return nil, fmt.Errorf("invalid header list member after quoted member, %q", v)
+			}
+			members = append(members, sb.String())
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(v[i:], ',')
+		if end == -1 {
+			end = len(v) - i
+		}
+		members = append(members, strings.TrimSpace(v[i:i+end]))
+		i += end + 1
+	}
+	return members, nil
+}
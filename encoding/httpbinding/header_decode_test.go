@@ -0,0 +1,153 @@
+package httpbinding
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type headerOutput struct {
+	ContentLength int64
+	ETag          string
+	Tags          []string
+	Metadata      map[string]string
+}
+
+func newHeaderOutputDecoder(out *headerOutput) HeaderDecoder {
+	return HeaderDecoder{
+		Headers: map[string]func([]string) error{
+			"Content-Length": func(values []string) error {
+				v, err := strconv.ParseInt(values[0], 10, 64)
+				if err != nil {
+					return err
+				}
+				out.ContentLength = v
+				return nil
+			},
+			"ETag": func(values []string) error {
+				out.ETag = values[0]
+				return nil
+			},
+			"X-Amz-Tags": func(values []string) error {
+				v, err := SplitHeaderListValues(values)
+				if err != nil {
+					return err
+				}
+				out.Tags = v
+				return nil
+			},
+		},
+		PrefixHeaders: map[string]func(map[string]string) error{
+			"X-Amz-Meta-": func(m map[string]string) error {
+				out.Metadata = m
+				return nil
+			},
+		},
+	}
+}
+
+func TestHeaderDecoder(t *testing.T) {
+	cases := map[string]struct {
+		Header    http.Header
+		Expect    headerOutput
+		ExpectErr string
+	}{
+		"scalar headers": {
+			Header: http.Header{
+				"Content-Length": []string{"123"},
+				"Etag":           []string{`"abc"`},
+			},
+			Expect: headerOutput{ContentLength: 123, ETag: `"abc"`},
+		},
+		"prefix header group": {
+			Header: http.Header{
+				"X-Amz-Meta-Foo":  []string{"bar"},
+				"X-Amz-Meta-Baz":  []string{"qux", "ignored"},
+				"X-Amz-Metadata":  []string{"not prefixed"},
+				"X-Amz-Other-Foo": []string{"other"},
+			},
+			Expect: headerOutput{Metadata: map[string]string{"foo": "bar", "baz": "qux"}},
+		},
+		"comma split list header": {
+			Header: http.Header{
+				"X-Amz-Tags": []string{`a, b ,"c,d"`, `"e \"quoted\""`},
+			},
+			Expect: headerOutput{Tags: []string{"a", "b", "c,d", `e "quoted"`}},
+		},
+		"no headers": {
+			Header: http.Header{},
+		},
+		"setter error": {
+			Header:    http.Header{"Content-Length": []string{"abc"}},
+			ExpectErr: "failed to decode Content-Length header",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var out headerOutput
+			err := newHeaderOutputDecoder(&out).Decode(c.Header)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect error containing %q, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, out; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %+v, got %+v", e, a)
+			}
+		})
+	}
+}
+
+func TestSplitHeaderListValues(t *testing.T) {
+	cases := map[string]struct {
+		Values    []string
+		Expect    []string
+		ExpectErr bool
+	}{
+		"single":           {Values: []string{"a"}, Expect: []string{"a"}},
+		"comma separated":  {Values: []string{"a,b, c"}, Expect: []string{"a", "b", "c"}},
+		"multiple values":  {Values: []string{"a", "b,c"}, Expect: []string{"a", "b", "c"}},
+		"quoted comma":     {Values: []string{`"a,b", c`}, Expect: []string{"a,b", "c"}},
+		"escaped quote":    {Values: []string{`"a\"b"`}, Expect: []string{`a"b`}},
+		"empty member":     {Values: []string{"a,,b"}, Expect: []string{"a", "", "b"}},
+		"unterminated":     {Values: []string{`"a,b`}, ExpectErr: true},
+		"trailing garbage": {Values: []string{`"a"b,c`}, ExpectErr: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := SplitHeaderListValues(c.Values)
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestHeaderDecoder_PrefixSetterError(t *testing.T) {
+	d := HeaderDecoder{
+		PrefixHeaders: map[string]func(map[string]string) error{
+			"X-Meta-": func(map[string]string) error { return fmt.Errorf("invalid") },
+		},
+	}
+	if err := d.Decode(http.Header{"X-Meta-A": []string{"b"}}); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
@@ -0,0 +1,154 @@
+package httpbinding
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ParseLinkHeader parses the values of RFC 5988 Link headers, (e.g. a REST
+// service paginating with Link headers), returning the map of link relation
+// types to the link's URL. Each value may contain multiple comma separated
+// links, and a link with several space separated relation types is mapped to
+// each of them. Parameter values may be quoted, with quotes and backslashes
+// escaped by a backslash. Links without a rel parameter are ignored. If a
+// relation type is repeated, the first link is used.
+//
+// Returns an error if a link is malformed.
+func ParseLinkHeader(values []string) (map[string]string, error) {
+	rels := map[string]string{}
+	for _, v := range values {
+		if err := parseLinkValue(v, rels); err != nil {
+			return nil, err
+		}
+	}
+	return rels, nil
+}
+
+// NextLink returns the URL of the link with the next relation type from the
+// header's Link headers, and if the link is present. Returns an error if the
+// Link headers are malformed.
+func NextLink(header http.Header) (string, bool, error) {
+	rels, err := ParseLinkHeader(header.Values("Link"))
+	if err != nil {
+		return "", false, err
+	}
+	next, ok := rels["next"]
+	return next, ok, nil
+}
+
+// FormatLinkHeader returns the Link header value for the map of link relation
+// types to link URLs, with links sorted by relation type, (e.g.
+// `<https://example.com/?page=2>; rel="next"`).
+func FormatLinkHeader(rels map[string]string) string {
+	names := make([]string, 0, len(rels))
+	for rel := range rels {
+		names = append(names, rel)
+	}
+	sort.Strings(names)
+
+	links := make([]string, 0, len(names))
+	for _, rel := range names {
+		links = append(links, "<"+rels[rel]+">; rel="+quoteLinkParam(rel))
+	}
+	return strings.Join(links, ", ")
+}
+
+func quoteLinkParam(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
+
+func parseLinkValue(v string, rels map[string]string) error {
+	for i := 0; i < len(v); {
+		i = skipLinkSpace(v, i)
+		if i == len(v) {
+			break
+		}
+		if v[i] == ',' {
+			i++
+			continue
+		}
+		if v[i] != '<' {
+			return fmt.Errorf("expect link URL to start with <, %q", v)
+		}
+		end := strings.IndexByte(v[i:], '>')
+		if end == -1 {
+			return fmt.Errorf("unterminated link URL, %q", v)
+		}
+		target := v[i+1 : i+end]
+		i += end + 1
+
+		var rel string
+		for {
+			i = skipLinkSpace(v, i)
+			if i == len(v) || v[i] == ',' {
+				break
+			}
+			if v[i] != ';' {
+				return fmt.Errorf("expect ; before link parameter, %q", v)
+			}
+			i = skipLinkSpace(v, i+1)
+
+			start := i
+			for i < len(v) && v[i] != '=' && v[i] != ';' && v[i] != ',' && v[i] != ' ' && v[i] != '\t' {
+				i++
+			}
+			name := strings.ToLower(v[start:i])
+			i = skipLinkSpace(v, i)
+
+			var value string
+			if i < len(v) && v[i] == '=' {
+				var err error
+				if value, i, err = parseLinkParamValue(v, skipLinkSpace(v, i+1)); err != nil {
+					return err
+				}
+			}
+			if name == "rel" && len(rel) == 0 {
+				rel = value
+			}
+		}
+
+		for _, r := range strings.Fields(rel) {
+			r = strings.ToLower(r)
+			if _, ok := rels[r]; !ok {
+				rels[r] = target
+			}
+		}
+	}
+	return nil
+}
+
+// parseLinkParamValue returns the quoted or token parameter value starting at
+// i, and the index after it.
+func parseLinkParamValue(v string, i int) (string, int, error) {
+	if i < len(v) && v[i] == '"' {
+		var sb strings.Builder
+		for i++; i < len(v); i++ {
+			c := v[i]
+			if c == '\\' && i+1 < len(v) {
+				i++
+				sb.WriteByte(v[i])
+				continue
+			}
+			if c == '"' {
+				return sb.String(), i + 1, nil
+			}
+			sb.WriteByte(c)
+		}
+		return "", i, fmt.Errorf("unterminated quoted link parameter, %q", v)
+	}
+
+	start := i
+	for i < len(v) && v[i] != ';' && v[i] != ',' {
+		i++
+	}
+	return strings.TrimSpace(v[start:i]), i, nil
+}
+
+func skipLinkSpace(v string, i int) int {
+	for i < len(v) && (v[i] == ' ' || v[i] == '\t') {
+		i++
+	}
+	return i
+}
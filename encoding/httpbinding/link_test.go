@@ -0,0 +1,121 @@
+package httpbinding
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	cases := map[string]struct {
+		Values    []string
+		Expect    map[string]string
+		ExpectErr bool
+	}{
+		"multiple rels": {
+			Values: []string{
+				`<https://example.com/items?page=3>; rel="next", <https://example.com/items?page=1>; rel="prev"`,
+			},
+			Expect: map[string]string{
+				"next": "https://example.com/items?page=3",
+				"prev": "https://example.com/items?page=1",
+			},
+		},
+		"multiple headers": {
+			Values: []string{
+				`<https://example.com/items?page=3>; rel=next`,
+				`<https://example.com/items?page=9>; rel="last"`,
+			},
+			Expect: map[string]string{
+				"next": "https://example.com/items?page=3",
+				"last": "https://example.com/items?page=9",
+			},
+		},
+		"quoted params": {
+			Values: []string{
+				`<https://example.com/a,b>; title="page; two, \"next\""; rel="next first"`,
+			},
+			Expect: map[string]string{
+				"next":  "https://example.com/a,b",
+				"first": "https://example.com/a,b",
+			},
+		},
+		"no rel ignored": {
+			Values: []string{`<https://example.com/>; title="home"`},
+			Expect: map[string]string{},
+		},
+		"unterminated url": {
+			Values:    []string{`<https://example.com/; rel="next"`},
+			ExpectErr: true,
+		},
+		"unterminated quote": {
+			Values:    []string{`<https://example.com/>; rel="next`},
+			ExpectErr: true,
+		},
+		"missing url": {
+			Values:    []string{`rel="next"`},
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			rels, err := ParseLinkHeader(c.Values)
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, rels; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `<https://example.com/items?page=1>; rel="prev"`)
+	header.Add("Link", `<https://example.com/items?page=3>; rel="next"`)
+
+	next, ok, err := NextLink(header)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !ok {
+		t.Fatalf("expect next link")
+	}
+	if e, a := "https://example.com/items?page=3", next; e != a {
+		t.Errorf("expect %v next link, got %v", e, a)
+	}
+
+	header.Del("Link")
+	header.Set("Link", `<https://example.com/items?page=1>; rel="prev"`)
+	if next, ok, err = NextLink(header); err != nil || ok || len(next) != 0 {
+		t.Errorf("expect no next link, got %q, %v, %v", next, ok, err)
+	}
+}
+
+func TestFormatLinkHeader(t *testing.T) {
+	rels := map[string]string{
+		"prev": "https://example.com/items?page=1",
+		"next": "https://example.com/items?page=3",
+	}
+
+	v := FormatLinkHeader(rels)
+	if e, a := `<https://example.com/items?page=3>; rel="next", <https://example.com/items?page=1>; rel="prev"`, v; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	parsed, err := ParseLinkHeader([]string{v})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := rels, parsed; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v round trip, got %v", e, a)
+	}
+}
@@ -0,0 +1,125 @@
+package httpbinding
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// MultipartForm provides building a multipart/form-data request body from
+// named text field and file parts.
+//
+// The body is not buffered in memory. Only the part boundaries and headers
+// are buffered, the reader of each file part is streamed as the body is
+// read.
+type MultipartForm struct {
+	buf      *bytes.Buffer
+	w        *multipart.Writer
+	segments []io.Reader
+	built    bool
+}
+
+// NewMultipartForm returns an initialized MultipartForm with a random
+// boundary.
+func NewMultipartForm() *MultipartForm {
+	buf := bytes.NewBuffer(nil)
+	return &MultipartForm{
+		buf: buf,
+		w:   multipart.NewWriter(buf),
+	}
+}
+
+// SetBoundary overrides the form's random boundary. Must be called before
+// any parts are added.
+func (f *MultipartForm) SetBoundary(boundary string) error {
+	return f.w.SetBoundary(boundary)
+}
+
+// Boundary returns the form's boundary.
+func (f *MultipartForm) Boundary() string {
+	return f.w.Boundary()
+}
+
+// ContentType returns the Content-Type header value of the form, including
+// the boundary.
+func (f *MultipartForm) ContentType() string {
+	return f.w.FormDataContentType()
+}
+
+// AddField adds a text field part with the name and value.
+func (f *MultipartForm) AddField(name, value string) error {
+	if f.built {
+		return fmt.Errorf("multipart form already built")
+	}
+
+	pw, err := f.w.CreateFormField(name)
+	if err != nil {
+		return fmt.Errorf("failed to create form field %v, %w", name, err)
+	}
+	if _, err = io.WriteString(pw, value); err != nil {
+		return fmt.Errorf("failed to write form field %v, %w", name, err)
+	}
+	return nil
+}
+
+// AddFile adds a file part with the name and file name, whose content is
+// read from the reader when the form's body is read. If contentType is
+// empty, application/octet-stream is used.
+func (f *MultipartForm) AddFile(name, filename, contentType string, r io.Reader) error {
+	if f.built {
+		return fmt.Errorf("multipart form already built")
+	}
+	if len(contentType) == 0 {
+		contentType = "application/octet-stream"
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(name), escapeQuotes(filename)))
+	h.Set("Content-Type", contentType)
+
+	if _, err := f.w.CreatePart(h); err != nil {
+		return fmt.Errorf("failed to create form file %v, %w", name, err)
+	}
+
+	f.flush()
+	f.segments = append(f.segments, r)
+	return nil
+}
+
+// Build completes the form, returning the reader of the form's body. The
+// form's parts cannot be modified after the form is built.
+func (f *MultipartForm) Build() (io.Reader, error) {
+	if f.built {
+		return nil, fmt.Errorf("multipart form already built")
+	}
+	f.built = true
+
+	if err := f.w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close multipart form, %w", err)
+	}
+	f.flush()
+
+	return io.MultiReader(f.segments...), nil
+}
+
+// flush moves the buffered boundaries, headers, and text fields into a body
+// segment.
+func (f *MultipartForm) flush() {
+	if f.buf.Len() == 0 {
+		return
+	}
+	b := make([]byte, f.buf.Len())
+	copy(b, f.buf.Bytes())
+	f.buf.Reset()
+	f.segments = append(f.segments, bytes.NewReader(b))
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
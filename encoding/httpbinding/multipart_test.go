@@ -0,0 +1,140 @@
+package httpbinding
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"testing"
+)
+
+type trackingReader struct {
+	r     io.Reader
+	reads int
+}
+
+func (r *trackingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.r.Read(p)
+}
+
+type repeatReader byte
+
+func (r repeatReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+func TestMultipartForm(t *testing.T) {
+	const fileSize = 4 << 20
+
+	file := &trackingReader{r: io.LimitReader(repeatReader('a'), fileSize)}
+
+	f := NewMultipartForm()
+	if err := f.AddField("title", "my \"upload\""); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := f.AddFile("file", "data.bin", "", file); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := f.AddFile("note", `a"b.txt`, "text/plain", bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := f.AddField("empty", ""); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	body, err := f.Build()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 0, file.reads; e != a {
+		t.Errorf("expect file not read before body is read, got %v reads", a)
+	}
+	if err := f.AddField("late", "v"); err == nil {
+		t.Errorf("expect error adding part after build")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(f.ContentType())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "multipart/form-data", mediaType; e != a {
+		t.Errorf("expect %v media type, got %v", e, a)
+	}
+	if e, a := f.Boundary(), params["boundary"]; e != a {
+		t.Errorf("expect %v boundary, got %v", e, a)
+	}
+
+	type part struct {
+		Name, FileName, ContentType string
+		Size                        int
+		Content                     string
+	}
+	expect := []part{
+		{Name: "title", Content: `my "upload"`},
+		{Name: "file", FileName: "data.bin", ContentType: "application/octet-stream", Size: fileSize},
+		{Name: "note", FileName: `a"b.txt`, ContentType: "text/plain", Size: 5, Content: "hello"},
+		{Name: "empty"},
+	}
+
+	r := multipart.NewReader(body, params["boundary"])
+	for i, e := range expect {
+		p, err := r.NextPart()
+		if err != nil {
+			t.Fatalf("part %d, expect no error, got %v", i, err)
+		}
+		b, err := ioutil.ReadAll(p)
+		if err != nil {
+			t.Fatalf("part %d, expect no error, got %v", i, err)
+		}
+
+		a := part{
+			Name:        p.FormName(),
+			FileName:    p.FileName(),
+			ContentType: p.Header.Get("Content-Type"),
+			Size:        len(b),
+		}
+		if e.Size == 0 {
+			e.Size = len(e.Content)
+		}
+		if len(e.Content) != 0 || e.Size == 0 {
+			a.Content = string(b)
+		}
+		if e != a {
+			t.Errorf("part %d, expect %+v, got %+v", i, e, a)
+		}
+	}
+	if _, err := r.NextPart(); err != io.EOF {
+		t.Errorf("expect no more parts, got %v", err)
+	}
+}
+
+func TestMultipartFormBoundary(t *testing.T) {
+	f := NewMultipartForm()
+	if err := f.SetBoundary("fooBoundary"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := f.AddField("a", "b"); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	body, err := f.Build()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	b, _ := ioutil.ReadAll(body)
+	expect := "--fooBoundary\r\n" +
+		"Content-Disposition: form-data; name=\"a\"\r\n\r\n" +
+		"b\r\n" +
+		"--fooBoundary--\r\n"
+	if e, a := expect, string(b); e != a {
+		t.Errorf("expect %q body, got %q", e, a)
+	}
+	if e, a := "multipart/form-data; boundary=fooBoundary", f.ContentType(); e != a {
+		t.Errorf("expect %v content type, got %v", e, a)
+	}
+}
@@ -0,0 +1,83 @@
+package httpbinding
+
+import (
+	"strconv"
+	"strings"
+)
+
+const hexUpper = "0123456789ABCDEF"
+
+// noEscape reports if the byte does not need to be percent-encoded within a
+// URI path segment. These are the unreserved characters of RFC 3986.
+var noEscape [256]bool
+
+func init() {
+	for i := 0; i < len(noEscape); i++ {
+		noEscape[i] = (i >= 'A' && i <= 'Z') ||
+			(i >= 'a' && i <= 'z') ||
+			(i >= '0' && i <= '9') ||
+			i == '-' ||
+			i == '.' ||
+			i == '_' ||
+			i == '~'
+	}
+}
+
+// EscapePath percent-encodes all characters of the URI path, except the
+// unreserved characters of RFC 3986. Path separators are only escaped if
+// encodeSep is set.
+func EscapePath(path string, encodeSep bool) string {
+	var buf strings.Builder
+	buf.Grow(len(path))
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if noEscape[c] || (c == '/' && !encodeSep) {
+			buf.WriteByte(c)
+		} else {
+			buf.WriteByte('%')
+			buf.WriteByte(hexUpper[c>>4])
+			buf.WriteByte(hexUpper[c&0xf])
+		}
+	}
+	return buf.String()
+}
+
+// URIValue is used to encode named URI parameters
+type URIValue struct {
+	encoder *Encoder
+	key     string
+}
+
+func newURIValue(encoder *Encoder, key string) URIValue {
+	return URIValue{encoder: encoder, key: key}
+}
+
+// String encodes the value v as a URI string value
+func (u URIValue) String(v string) error {
+	return u.encoder.setLabel(u.key, v)
+}
+
+// Boolean encodes v as a URI bool value
+func (u URIValue) Boolean(v bool) error {
+	return u.encoder.setLabel(u.key, strconv.FormatBool(v))
+}
+
+// Integer encodes v as a URI int32 value
+func (u URIValue) Integer(v int32) error {
+	return u.encoder.setLabel(u.key, strconv.FormatInt(int64(v), 10))
+}
+
+// Long encodes v as a URI int64 value
+func (u URIValue) Long(v int64) error {
+	return u.encoder.setLabel(u.key, strconv.FormatInt(v, 10))
+}
+
+// Float encodes v as a URI float32 value
+func (u URIValue) Float(v float32) error {
+	return u.encoder.setLabel(u.key, formatFloat(float64(v), 32))
+}
+
+// Double encodes v as a URI float64 value
+func (u URIValue) Double(v float64) error {
+	return u.encoder.setLabel(u.key, formatFloat(v, 64))
+}
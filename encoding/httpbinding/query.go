@@ -0,0 +1,101 @@
+package httpbinding
+
+import (
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// QueryValue is used to encode query key values
+type QueryValue struct {
+	query  url.Values
+	key    string
+	append bool
+}
+
+func newQueryValue(query url.Values, key string, append bool) QueryValue {
+	return QueryValue{query: query, key: key, append: append}
+}
+
+func (qv QueryValue) updateKey(value string) {
+	if qv.append {
+		qv.query.Add(qv.key, value)
+	} else {
+		qv.query.Set(qv.key, value)
+	}
+}
+
+// String encodes the value v as a query string value
+func (qv QueryValue) String(v string) {
+	qv.updateKey(v)
+}
+
+// Boolean encodes the value v as a query string bool value
+func (qv QueryValue) Boolean(v bool) {
+	qv.updateKey(strconv.FormatBool(v))
+}
+
+// Integer encodes the value v as a query string int32 value
+func (qv QueryValue) Integer(v int32) {
+	qv.updateKey(strconv.FormatInt(int64(v), 10))
+}
+
+// Long encodes the value v as a query string int64 value
+func (qv QueryValue) Long(v int64) {
+	qv.updateKey(strconv.FormatInt(v, 10))
+}
+
+// Float encodes the value v as a query string float32 value
+func (qv QueryValue) Float(v float32) {
+	qv.updateKey(formatFloat(float64(v), 32))
+}
+
+// Double encodes the value v as a query string float64 value
+func (qv QueryValue) Double(v float64) {
+	qv.updateKey(formatFloat(v, 64))
+}
+
+// Blob encodes the value v as a base64 query string value
+func (qv QueryValue) Blob(v []byte) {
+	qv.updateKey(base64.StdEncoding.EncodeToString(v))
+}
+
+// AddQueryParams adds the entries of the map to the query, with each key
+// prefixed by prefix, for maps bound to the query string with the
+// httpQueryParams trait. Entries are added in sorted key order. Keys already
+// in the query, (e.g. bound by members with the httpQuery trait), are not
+// added, as those members take precedence.
+func AddQueryParams(query url.Values, prefix string, params map[string]string) {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := query[prefix+k]; ok {
+			continue
+		}
+		query.Add(prefix+k, params[k])
+	}
+}
+
+// AddQueryParamsList adds the entries of the multi-valued map to the query,
+// the same as AddQueryParams. Each of an entry's values is added in order.
+func AddQueryParamsList(query url.Values, prefix string, params map[string][]string) {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := query[prefix+k]; ok {
+			continue
+		}
+		for _, v := range params[k] {
+			query.Add(prefix+k, v)
+		}
+	}
+}
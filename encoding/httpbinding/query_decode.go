@@ -0,0 +1,60 @@
+package httpbinding
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// QueryDecoder populates a shape from the query string values bound to its
+// members with the httpQuery and httpQueryParams traits, (e.g. the query of a
+// presigned or callback URL). It is the inverse of the Encoder's SetQuery and
+// AddQuery.
+type QueryDecoder struct {
+	// Setters of members bound to a query key, keyed by the query key.
+	// Setters are only called for keys present in the query, with all of the
+	// key's values, so list members receive every repeated value in order.
+	Params map[string]func(values []string) error
+
+	// Setter of the map member bound to the query params not bound to a
+	// member by Params, with the httpQueryParams trait. The map passed to the
+	// setter is keyed by query key, with all of the key's values. The setter
+	// is only called if at least one unbound key is present.
+	ParamsMap func(map[string][]string) error
+}
+
+// Decode calls the setters for the keys present in the query values.
+// Returns an error if a setter fails.
+func (d QueryDecoder) Decode(query url.Values) error {
+	for key, set := range d.Params {
+		values, ok := query[key]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if err := set(values); err != nil {
+			return fmt.Errorf("failed to decode %s query param, %w", key, err)
+		}
+	}
+
+	if d.ParamsMap == nil {
+		return nil
+	}
+
+	var m map[string][]string
+	for key, values := range query {
+		if _, ok := d.Params[key]; ok {
+			continue
+		}
+		if m == nil {
+			m = map[string][]string{}
+		}
+		m[key] = append([]string(nil), values...)
+	}
+	if m == nil {
+		return nil
+	}
+	if err := d.ParamsMap(m); err != nil {
+		return fmt.Errorf("failed to decode query params, %w", err)
+	}
+
+	return nil
+}
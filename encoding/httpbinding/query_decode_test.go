@@ -0,0 +1,101 @@
+package httpbinding
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type queryOutput struct {
+	State   string
+	MaxKeys int32
+	Items   []string
+	Params  map[string][]string
+}
+
+func newQueryOutputDecoder(out *queryOutput) QueryDecoder {
+	return QueryDecoder{
+		Params: map[string]func([]string) error{
+			"state": func(values []string) error {
+				out.State = values[0]
+				return nil
+			},
+			"max-keys": func(values []string) error {
+				v, err := strconv.ParseInt(values[0], 10, 32)
+				if err != nil {
+					return err
+				}
+				out.MaxKeys = int32(v)
+				return nil
+			},
+			"item": func(values []string) error {
+				out.Items = values
+				return nil
+			},
+		},
+		ParamsMap: func(m map[string][]string) error {
+			out.Params = m
+			return nil
+		},
+	}
+}
+
+func TestQueryDecoder(t *testing.T) {
+	cases := map[string]struct {
+		Query     string
+		Expect    queryOutput
+		ExpectErr string
+	}{
+		"scalar params": {
+			Query:  "state=abc&max-keys=10",
+			Expect: queryOutput{State: "abc", MaxKeys: 10},
+		},
+		"repeated keys": {
+			Query:  "item=a&item=b%20c&item=a",
+			Expect: queryOutput{Items: []string{"a", "b c", "a"}},
+		},
+		"catch all map": {
+			Query: "state=abc&code=123&scope=x&scope=y",
+			Expect: queryOutput{
+				State: "abc",
+				Params: map[string][]string{
+					"code":  {"123"},
+					"scope": {"x", "y"},
+				},
+			},
+		},
+		"no params": {
+			Query: "",
+		},
+		"setter error": {
+			Query:     "max-keys=abc",
+			ExpectErr: "failed to decode max-keys query param",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			query, err := url.ParseQuery(c.Query)
+			if err != nil {
+				t.Fatalf("expect no error parsing query, got %v", err)
+			}
+
+			var out queryOutput
+			err = newQueryOutputDecoder(&out).Decode(query)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect error containing %q, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, out; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %+v, got %+v", e, a)
+			}
+		})
+	}
+}
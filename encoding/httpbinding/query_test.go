@@ -0,0 +1,64 @@
+package httpbinding
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestAddQueryParams(t *testing.T) {
+	cases := map[string]struct {
+		Query  url.Values
+		Prefix string
+		Params map[string]string
+		Expect string
+	}{
+		"sorted": {
+			Params: map[string]string{"b": "2", "a": "1", "c": "3"},
+			Expect: "a=1&b=2&c=3",
+		},
+		"prefix": {
+			Prefix: "tag.",
+			Params: map[string]string{"Key": "x", "Value": "y"},
+			Expect: "tag.Key=x&tag.Value=y",
+		},
+		"special characters": {
+			Params: map[string]string{"a b&c=d": "e/f?g", "ü": "%"},
+			Expect: "a+b%26c%3Dd=e%2Ff%3Fg&%C3%BC=%25",
+		},
+		"bound query member precedence": {
+			Query:  url.Values{"a": []string{"bound"}},
+			Params: map[string]string{"a": "1", "b": "2"},
+			Expect: "a=bound&b=2",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			query := c.Query
+			if query == nil {
+				query = url.Values{}
+			}
+			AddQueryParams(query, c.Prefix, c.Params)
+			if e, a := c.Expect, query.Encode(); e != a {
+				t.Errorf("expect %q query, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestAddQueryParamsList(t *testing.T) {
+	query := url.Values{"x-id": []string{"ListItems"}}
+	AddQueryParamsList(query, "filter.", map[string][]string{
+		"status": {"open", "closed"},
+		"a&b":    {"1 2"},
+		"empty":  {""},
+	})
+	AddQueryParamsList(query, "", map[string][]string{
+		"x-id": {"ignored"},
+	})
+
+	expect := "filter.a%26b=1+2&filter.empty=&filter.status=open&filter.status=closed&x-id=ListItems"
+	if e, a := expect, query.Encode(); e != a {
+		t.Errorf("expect %q query, got %q", e, a)
+	}
+}
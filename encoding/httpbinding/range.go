@@ -0,0 +1,80 @@
+package httpbinding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatRange returns the Range header value requesting the bytes from start
+// to end, inclusive, (e.g. "bytes=0-499"). If end is negative the range is
+// open-ended, requesting the bytes from start to the end of the
+// representation, (e.g. "bytes=500-").
+func FormatRange(start, end int64) string {
+	if end < 0 {
+		return "bytes=" + strconv.FormatInt(start, 10) + "-"
+	}
+	return "bytes=" + strconv.FormatInt(start, 10) + "-" + strconv.FormatInt(end, 10)
+}
+
+// ParseContentRange parses the Content-Range header value of a partial
+// response, returning the first and last byte positions of the range,
+// inclusive, and the total length of the representation, (e.g. "bytes
+// 0-499/1234"). If the total length is unknown, (e.g. "bytes 0-499/*"), total
+// is -1.
+//
+// The unsatisfied range form, (e.g. "bytes */1234"), returned with 416 Range
+// Not Satisfiable responses, returns -1 for start and end, with the total
+// length.
+//
+// Returns an error if the value is malformed, or not a byte range.
+func ParseContentRange(s string) (start, end, total int64, err error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "bytes ") {
+		return 0, 0, 0, fmt.Errorf("expect bytes content range, got %q", s)
+	}
+	s = strings.TrimSpace(s[len("bytes "):])
+
+	i := strings.IndexByte(s, '/')
+	if i == -1 {
+		return 0, 0, 0, fmt.Errorf("expect content range total length, got %q", s)
+	}
+	rng, length := s[:i], s[i+1:]
+
+	total = -1
+	if length != "*" {
+		if total, err = parseRangePosition(length); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid content range total length, %w", err)
+		}
+	}
+
+	if rng == "*" {
+		if total < 0 {
+			return 0, 0, 0, fmt.Errorf("expect unsatisfied content range total length, got %q", s)
+		}
+		return -1, -1, total, nil
+	}
+
+	j := strings.IndexByte(rng, '-')
+	if j == -1 {
+		return 0, 0, 0, fmt.Errorf("expect content range first and last positions, got %q", rng)
+	}
+	if start, err = parseRangePosition(rng[:j]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid content range first position, %w", err)
+	}
+	if end, err = parseRangePosition(rng[j+1:]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid content range last position, %w", err)
+	}
+	if end < start || (total >= 0 && end >= total) {
+		return 0, 0, 0, fmt.Errorf("invalid content range %q", s)
+	}
+
+	return start, end, total, nil
+}
+
+func parseRangePosition(v string) (int64, error) {
+	if len(v) == 0 || v[0] < '0' || v[0] > '9' {
+		return 0, fmt.Errorf("expect non-negative integer, got %q", v)
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
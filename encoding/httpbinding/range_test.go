@@ -0,0 +1,66 @@
+package httpbinding
+
+import "testing"
+
+func TestFormatRange(t *testing.T) {
+	cases := map[string]struct {
+		Start, End int64
+		Expect     string
+	}{
+		"closed":     {Start: 0, End: 499, Expect: "bytes=0-499"},
+		"single":     {Start: 10, End: 10, Expect: "bytes=10-10"},
+		"open-ended": {Start: 500, End: -1, Expect: "bytes=500-"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Expect, FormatRange(c.Start, c.End); e != a {
+				t.Errorf("expect %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	cases := map[string]struct {
+		Value             string
+		Start, End, Total int64
+		ExpectErr         bool
+	}{
+		"range":                 {Value: "bytes 0-499/1234", Start: 0, End: 499, Total: 1234},
+		"last byte":             {Value: "bytes 1233-1233/1234", Start: 1233, End: 1233, Total: 1234},
+		"unknown total":         {Value: "bytes 500-999/*", Start: 500, End: 999, Total: -1},
+		"unsatisfiable":         {Value: "bytes */1234", Start: -1, End: -1, Total: 1234},
+		"unsatisfiable unknown": {Value: "bytes */*", ExpectErr: true},
+		"other unit":            {Value: "items 0-1/2", ExpectErr: true},
+		"missing total":         {Value: "bytes 0-499", ExpectErr: true},
+		"missing last":          {Value: "bytes 0/1234", ExpectErr: true},
+		"negative":              {Value: "bytes -1-499/1234", ExpectErr: true},
+		"reversed":              {Value: "bytes 499-0/1234", ExpectErr: true},
+		"beyond total":          {Value: "bytes 0-1234/1234", ExpectErr: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			start, end, total, err := ParseContentRange(c.Value)
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Start, start; e != a {
+				t.Errorf("expect %v start, got %v", e, a)
+			}
+			if e, a := c.End, end; e != a {
+				t.Errorf("expect %v end, got %v", e, a)
+			}
+			if e, a := c.Total, total; e != a {
+				t.Errorf("expect %v total, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/awslabs/smithy-go/document"
+)
+
+// Decoder provides decoding of JSON documents from a stream.
+//
+// JSON values are decoded into the following Go types:
+//
+//	object      map[string]interface{}
+//	array       []interface{}
+//	string      string
+//	number      float64, or Number if UseNumber is enabled
+//	true, false bool
+//	null        nil
+type Decoder struct {
+	decoder   *json.Decoder
+	useNumber bool
+
+	disallowUnknownFields bool
+}
+
+// NewDecoder returns a decoder that reads JSON documents from the reader. A
+// UTF-8 byte order mark at the start of the reader, (e.g. prepended by a
+// gateway), is skipped, as is whitespace before the first document.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		decoder: json.NewDecoder(&bomSkipReader{r: bufio.NewReader(r)}),
+	}
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// bomSkipReader skips the UTF-8 byte order mark at the start of the reader.
+type bomSkipReader struct {
+	r       *bufio.Reader
+	checked bool
+}
+
+func (r *bomSkipReader) Read(p []byte) (int, error) {
+	if !r.checked {
+		r.checked = true
+		if b, _ := r.r.Peek(len(utf8BOM)); bytes.Equal(b, utf8BOM) {
+			r.r.Discard(len(utf8BOM))
+		}
+	}
+	return r.r.Read(p)
+}
+
+// UseNumber causes the decoder to decode JSON numbers as Number values
+// instead of float64, so no precision is lost.
+func (d *Decoder) UseNumber() {
+	d.useNumber = true
+	d.decoder.UseNumber()
+}
+
+// Decode reads the next JSON document from the stream. Returns io.EOF if
+// there are no more documents in the stream.
+func (d *Decoder) Decode() (interface{}, error) {
+	var v interface{}
+	if err := d.decoder.Decode(&v); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to decode JSON document, %w", err)
+	}
+
+	if d.useNumber {
+		v = convertNumbers(v)
+	}
+	return v, nil
+}
+
+// DecodeBody reads the JSON document of a response body. An empty body, (e.g.
+// of a 204 No Content response), or one with only whitespace, is decoded as
+// an empty object, so deserializers produce an empty output instead of
+// failing.
+func (d *Decoder) DecodeBody() (interface{}, error) {
+	v, err := d.Decode()
+	if err == io.EOF {
+		return map[string]interface{}{}, nil
+	}
+	return v, err
+}
+
+// ObjectFieldFunc is called by WalkObject for each member of the object. The
+// value is the member's decoded value, and null reports if the member was
+// explicitly set to null. Members omitted from the object are not reported.
+type ObjectFieldFunc func(key string, value interface{}, null bool) error
+
+// WalkObject reads the next JSON document from the stream, which must be an
+// object, calling fn for each of the object's members in the order they
+// appear. Allows deserializers to distinguish members explicitly set to null
+// from members omitted from the object, (e.g. for PATCH-style operations).
+//
+// Returns io.EOF if there are no more documents in the stream. Returns the
+// error returned by fn, stopping the walk.
+func (d *Decoder) WalkObject(fn ObjectFieldFunc) error {
+	tok, err := d.decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return fmt.Errorf("failed to decode JSON object, %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expect JSON object, got %v", tok)
+	}
+
+	for d.decoder.More() {
+		tok, err := d.decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode JSON object key, %w", err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expect JSON object key, got %v", tok)
+		}
+
+		var v interface{}
+		if err := d.decoder.Decode(&v); err != nil {
+			return fmt.Errorf("failed to decode JSON object member %q, %w", key, err)
+		}
+		if d.useNumber {
+			v = convertNumbers(v)
+		}
+
+		if err := fn(key, v, v == nil); err != nil {
+			return err
+		}
+	}
+
+	if _, err := d.decoder.Token(); err != nil {
+		return fmt.Errorf("failed to decode JSON object end, %w", err)
+	}
+	return nil
+}
+
+// DecodeScalar reads the next JSON document from the stream, which must be a
+// scalar value, (e.g. the bare string, number, or boolean body of an
+// operation whose output is a single value), instead of an object or array.
+// The value is decoded into the same Go types as Decode, and null is decoded
+// as nil.
+//
+// Returns io.EOF if there are no more documents in the stream. Returns an
+// error if the document is an object or array.
+func (d *Decoder) DecodeScalar() (interface{}, error) {
+	tok, err := d.decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to decode JSON scalar, %w", err)
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		return nil, fmt.Errorf("expect JSON scalar, got %v", v)
+	case json.Number:
+		return Number(v), nil
+	default:
+		return v, nil
+	}
+}
+
+// DecodeDocument reads the next JSON document from the stream as a Smithy
+// document. Numbers are always decoded as Number literals, regardless of the
+// UseNumber option, so integers and floating point numbers remain
+// distinguishable. Returns io.EOF if there are no more documents in the
+// stream.
+func (d *Decoder) DecodeDocument() (*document.Document, error) {
+	var raw json.RawMessage
+	if err := d.decoder.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to decode JSON document, %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON document, %w", err)
+	}
+
+	return document.New(convertNumbers(v))
+}
+
+// convertNumbers replaces the encoding/json numbers within the decoded value
+// with Number values.
+func convertNumbers(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case json.Number:
+		return Number(tv)
+	case map[string]interface{}:
+		for k, mv := range tv {
+			tv[k] = convertNumbers(mv)
+		}
+	case []interface{}:
+		for i, av := range tv {
+			tv[i] = convertNumbers(av)
+		}
+	}
+	return v
+}
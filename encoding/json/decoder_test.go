@@ -0,0 +1,350 @@
+package json
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/ptr"
+)
+
+func TestDecoder(t *testing.T) {
+	cases := map[string]struct {
+		Input     string
+		UseNumber bool
+		Expect    interface{}
+	}{
+		"float numbers": {
+			Input: `{"a":1,"b":[2.5,"c",true,null]}`,
+			Expect: map[string]interface{}{
+				"a": float64(1),
+				"b": []interface{}{2.5, "c", true, nil},
+			},
+		},
+		"use number": {
+			Input:     `{"a":1,"b":[2.5,{"c":-3e10}]}`,
+			UseNumber: true,
+			Expect: map[string]interface{}{
+				"a": Number("1"),
+				"b": []interface{}{Number("2.5"), map[string]interface{}{"c": Number("-3e10")}},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(c.Input))
+			if c.UseNumber {
+				d.UseNumber()
+			}
+
+			v, err := d.Decode()
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %#v, got %#v", e, a)
+			}
+
+			if _, err := d.Decode(); err != io.EOF {
+				t.Errorf("expect EOF, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecoderLeadingBOM(t *testing.T) {
+	cases := map[string]struct {
+		Input  string
+		Expect interface{}
+	}{
+		"BOM": {
+			Input:  "\xEF\xBB\xBF{\"a\":1}",
+			Expect: map[string]interface{}{"a": float64(1)},
+		},
+		"BOM and whitespace": {
+			Input:  "\xEF\xBB\xBF \r\n\t{\"a\":1}",
+			Expect: map[string]interface{}{"a": float64(1)},
+		},
+		"whitespace": {
+			Input:  "\n\n  [1]",
+			Expect: []interface{}{float64(1)},
+		},
+		"BOM scalar": {
+			Input:  "\xEF\xBB\xBF\"abc\"",
+			Expect: "abc",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewDecoder(strings.NewReader(c.Input)).Decode()
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %#v, got %#v", e, a)
+			}
+		})
+	}
+}
+
+func TestDecoderDecodeBody(t *testing.T) {
+	cases := map[string]struct {
+		Input  string
+		Expect interface{}
+	}{
+		"empty body": {
+			Expect: map[string]interface{}{},
+		},
+		"whitespace body": {
+			Input:  " \n",
+			Expect: map[string]interface{}{},
+		},
+		"BOM only body": {
+			Input:  "\xEF\xBB\xBF",
+			Expect: map[string]interface{}{},
+		},
+		"object body": {
+			Input:  "\xEF\xBB\xBF{\"a\":\"b\"}",
+			Expect: map[string]interface{}{"a": "b"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewDecoder(strings.NewReader(c.Input)).DecodeBody()
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %#v, got %#v", e, a)
+			}
+		})
+	}
+
+	if _, err := NewDecoder(strings.NewReader(`{"a":`)).DecodeBody(); err == nil {
+		t.Errorf("expect error for invalid body, got none")
+	}
+}
+
+func TestDecoderInvalid(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a":`))
+	if _, err := d.Decode(); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
+
+func TestDecoderDecodeScalar(t *testing.T) {
+	cases := map[string]struct {
+		Input     string
+		UseNumber bool
+		Expect    interface{}
+		ExpectErr bool
+	}{
+		"bare string": {
+			Input:  `"hello world"`,
+			Expect: "hello world",
+		},
+		"bare number": {
+			Input:  ` 123.5 `,
+			Expect: 123.5,
+		},
+		"bare number use number": {
+			Input:     `9007199254740993`,
+			UseNumber: true,
+			Expect:    Number("9007199254740993"),
+		},
+		"bare boolean": {
+			Input:  `true`,
+			Expect: true,
+		},
+		"null": {
+			Input: `null`,
+		},
+		"object": {
+			Input:     `{"a":1}`,
+			ExpectErr: true,
+		},
+		"array": {
+			Input:     `["a"]`,
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(c.Input))
+			if c.UseNumber {
+				d.UseNumber()
+			}
+
+			v, err := d.DecodeScalar()
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %#v, got %#v", e, a)
+			}
+
+			if _, err := d.DecodeScalar(); err != io.EOF {
+				t.Errorf("expect EOF, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecoderNumberPrecision(t *testing.T) {
+	const (
+		bigInteger = "9223372036854775807"
+		bigDecimal = "3.14159265358979323846264338327950288"
+	)
+
+	d := NewDecoder(strings.NewReader(`{"id":` + bigInteger + `,"pi":` + bigDecimal + `}`))
+	d.UseNumber()
+
+	v, err := d.Decode()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	m := v.(map[string]interface{})
+
+	id := m["id"].(Number)
+	if e, a := bigInteger, id.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	i64, err := id.Int64()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := int64(9223372036854775807), i64; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	bi, err := id.BigInt()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := bigInteger, bi.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	pi := m["pi"].(Number)
+	if e, a := bigDecimal, pi.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	bf, err := pi.BigFloat()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := bigDecimal, bf.Text('f', len(bigDecimal)-2); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if _, err := pi.BigInt(); err == nil {
+		t.Errorf("expect error for decimal as integer, got none")
+	}
+}
+
+func TestDecoderWalkObject(t *testing.T) {
+	type patchInput struct {
+		Name      *string
+		NameNull  bool
+		Count     *float64
+		CountNull bool
+	}
+
+	cases := map[string]struct {
+		Input     string
+		Expect    patchInput
+		ExpectErr string
+	}{
+		"explicit null": {
+			Input:  `{"name":null}`,
+			Expect: patchInput{NameNull: true},
+		},
+		"absent": {
+			Input:  `{}`,
+			Expect: patchInput{},
+		},
+		"values": {
+			Input:  `{"name":"abc","count":2,"ignored":[1,{"a":null}]}`,
+			Expect: patchInput{Name: ptr.String("abc"), Count: ptr.Float64(2)},
+		},
+		"null and value": {
+			Input:  `{"count":null,"name":"abc"}`,
+			Expect: patchInput{Name: ptr.String("abc"), CountNull: true},
+		},
+		"not object": {
+			Input:     `[1]`,
+			ExpectErr: "expect JSON object",
+		},
+		"truncated": {
+			Input:     `{"name":`,
+			ExpectErr: `failed to decode JSON object member "name"`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var actual patchInput
+			err := NewDecoder(strings.NewReader(c.Input)).WalkObject(
+				func(key string, value interface{}, null bool) error {
+					switch key {
+					case "name":
+						if null {
+							actual.NameNull = true
+						} else {
+							v := value.(string)
+							actual.Name = &v
+						}
+					case "count":
+						if null {
+							actual.CountNull = true
+						} else {
+							v := value.(float64)
+							actual.Count = &v
+						}
+					}
+					return nil
+				})
+
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect %q error, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, actual; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %+v, got %+v", e, a)
+			}
+		})
+	}
+}
+
+func TestDecoderWalkObjectStop(t *testing.T) {
+	var keys []string
+	stopErr := io.ErrUnexpectedEOF
+	err := NewDecoder(strings.NewReader(`{"a":1,"b":2,"c":3}`)).WalkObject(
+		func(key string, value interface{}, null bool) error {
+			keys = append(keys, key)
+			if key == "b" {
+				return stopErr
+			}
+			return nil
+		})
+	if err != stopErr {
+		t.Errorf("expect callback error, got %v", err)
+	}
+	if e, a := []string{"a", "b"}, keys; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v keys, got %v", e, a)
+	}
+}
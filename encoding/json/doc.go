@@ -0,0 +1,55 @@
+/*
+Package json provides the decoder for deserializing JSON documents into
+untyped Go values that generated deserializers can walk to populate shapes,
+and the encoder for serializing those untyped values back to JSON.
+
+By default JSON numbers are decoded as float64. The decoder's UseNumber
+option decodes numbers as the string backed Number type instead, preserving
+the exact value of integers beyond 2^53 and high precision decimals for
+shapes modeled as BigInteger or BigDecimal.
+
+A UTF-8 byte order mark and whitespace before the first document are
+skipped. DecodeBody decodes an empty response body, (e.g. of a 204
+response), as an empty object instead of returning io.EOF.
+
+WalkObject decodes an object member by member, reporting members explicitly
+set to null separately from those omitted, so deserializers can distinguish
+the two for nullable members. DecodeScalar decodes a document that is a bare
+string, number, boolean, or null instead of an object, for operations whose
+output is a single value.
+
+DecodeShape decodes a document described by a Shape. With the decoder's
+DisallowUnknownFields option, members not in the shape are rejected, so
+strict clients can detect drift from the service's model. Unknown members are
+ignored by default.
+
+ErrorDeserializer reads an error response's code and message, from either a
+__type member or an object nested under a wrapper key, returning the modeled
+error for the code or a smithy.GenericAPIError. With a FallbackMessage, such
+as RawErrorMessage, responses whose content type is not JSON are returned as
+a GenericAPIError with a bounded snippet of the body as the message.
+DeserializeResponse marks the GenericAPIError of a response body truncated by
+the ErrorResponseBodyLimit middleware with BodyTruncated.
+
+Smithy document shapes are decoded with DecodeDocument, and values
+implementing document.Interface are encoded as the document's value.
+
+Object keys are encoded in map iteration order by default. The encoder's
+SortMapKeys option writes keys in sorted order for stable output. NaN and
+infinite floating point values are rejected by default, and with the
+NonFiniteFloatString mode are encoded as the strings "NaN", "Infinity", and
+"-Infinity".
+
+Object members that are empty lists or maps are omitted, unless the
+encoder's EmitEmptyCollections option is enabled, or the member is wrapped
+with EmitEmpty.
+
+SetTarget sets the X-Amz-Target and Content-Type headers of awsJson1_0 and
+awsJson1_1 protocol requests, and GetTarget reads the target's service and
+operation names.
+
+StreamEncoder encodes values directly to an io.Writer instead of a buffer,
+and NewEncodeReader returns a reader encoding the value as it is read, for
+streaming large request payloads as the request's body.
+*/
+package json
@@ -0,0 +1,94 @@
+package json
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/document"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	input := `{"a":[1,1.0,-2.5e-3,{"b":null}],"big":12345678901234567890123,"precise":0.10000000000000000555,"s":"x","t":true}`
+
+	d := NewDecoder(strings.NewReader(input))
+	doc, err := d.DecodeDocument()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	v, err := doc.MarshalSmithyDocument()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := map[string]interface{}{
+		"a": []interface{}{
+			Number("1"), Number("1.0"), Number("-2.5e-3"),
+			map[string]interface{}{"b": nil},
+		},
+		"big":     Number("12345678901234567890123"),
+		"precise": Number("0.10000000000000000555"),
+		"s":       "x",
+		"t":       true,
+	}
+	if e, a := expect, v; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %#v, got %#v", e, a)
+	}
+
+	e := NewEncoder()
+	e.SortMapKeys(true)
+	if err := e.Encode(doc); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := input, e.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if _, err := d.DecodeDocument(); err != io.EOF {
+		t.Errorf("expect EOF, got %v", err)
+	}
+}
+
+func TestEncodeDocumentFloats(t *testing.T) {
+	doc, err := document.New(map[string]interface{}{
+		"float": float64(2),
+		"int":   int64(2),
+		"exp":   float64(1e21),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	e := NewEncoder()
+	e.SortMapKeys(true)
+	if err := e.Encode([]interface{}{float64(2), doc}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := `[2,{"exp":1e+21,"float":2.0,"int":2}]`
+	if e, a := expect, e.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestEncodeDocumentEmptyCollections(t *testing.T) {
+	doc, err := document.New(map[string]interface{}{
+		"list": []interface{}{},
+		"map":  map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	e := NewEncoder()
+	e.SortMapKeys(true)
+	if err := e.Encode(map[string]interface{}{"doc": doc, "empty": []interface{}{}}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := `{"doc":{"list":[],"map":{}}}`
+	if e, a := expect, e.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,380 @@
+package json
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/awslabs/smithy-go/document"
+)
+
+// Encoder provides encoding of untyped Go values into JSON documents.
+//
+// Values of the following Go types are encoded:
+//
+//	map[string]interface{}     object
+//	[]interface{}              array
+//	string                     string
+//	[]byte                     base64 encoded string
+//	bool                       true, false
+//	nil                        null
+//	Number                     number, as written
+//	int, int8-64, uint, uint8-64 number
+//	float32, float64           number
+//	document.Interface         the document's value
+//
+// Floating point numbers within documents are always written with a
+// fractional part or exponent, (e.g. 1.0), so they remain distinguishable
+// from integers when the document is decoded.
+//
+// Floating point values not representable as a JSON number, NaN and the
+// infinities, are rejected, unless the NonFiniteFloatMode allows them.
+//
+// Object members whose values are empty lists or maps are omitted, following
+// Smithy's serialization of empty collections, unless EmitEmptyCollections is
+// enabled, or the member's value is wrapped with EmitEmpty. Empty collections
+// within documents are always written.
+//
+// Object keys are written in the map's iteration order, which is random,
+// unless SortMapKeys is enabled.
+type Encoder struct {
+	buf            *bytes.Buffer
+	w              encoderWriter
+	sortMapKeys    bool
+	emitEmpty      bool
+	nonFiniteFloat NonFiniteFloatMode
+	inDocument     bool
+	scratch        [64]byte
+}
+
+// NonFiniteFloatMode is the mode the encoder encodes floating point values
+// not representable as a JSON number, NaN and the infinities, with.
+type NonFiniteFloatMode int
+
+// The modes for encoding NaN and infinite floating point values.
+const (
+	// NonFiniteFloatReject rejects the values, returning an error from
+	// Encode. The default mode.
+	NonFiniteFloatReject NonFiniteFloatMode = iota
+
+	// NonFiniteFloatString encodes the values as the strings "NaN",
+	// "Infinity", and "-Infinity", as Smithy's JSON protocols do.
+	NonFiniteFloatString
+)
+
+// NewEncoder returns an initialized JSON encoder.
+func NewEncoder() *Encoder {
+	buf := bytes.NewBuffer(nil)
+	return &Encoder{
+		buf: buf,
+		w:   buf,
+	}
+}
+
+// encoderWriter is the writer values are encoded to, either the Encoder's
+// buffer, or the buffered writer of a StreamEncoder.
+type encoderWriter interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+}
+
+// SortMapKeys sets if object keys are written in sorted order, instead of the
+// map's iteration order. Sorting produces stable output, (e.g. for comparing
+// serialized documents in tests), at the cost of additional allocation.
+func (e *Encoder) SortMapKeys(v bool) {
+	e.sortMapKeys = v
+}
+
+// EmitEmptyCollections sets if object members whose values are empty lists
+// or maps are written, (e.g. "a":[]), instead of omitted. Members wrapped
+// with EmitEmpty or OmitEmpty are written according to the wrapper instead.
+func (e *Encoder) EmitEmptyCollections(v bool) {
+	e.emitEmpty = v
+}
+
+// emptyCollectionValue is an object member value overriding the encoder's
+// EmitEmptyCollections option for the member.
+type emptyCollectionValue struct {
+	value interface{}
+	emit  bool
+}
+
+// EmitEmpty wraps the object member's value so it is written even if it is
+// an empty list or map, for members whose empty value must be distinguished
+// from an absent one.
+func EmitEmpty(v interface{}) interface{} {
+	return emptyCollectionValue{value: v, emit: true}
+}
+
+// OmitEmpty wraps the object member's value so it is omitted if it is an
+// empty list or map, even if EmitEmptyCollections is enabled.
+func OmitEmpty(v interface{}) interface{} {
+	return emptyCollectionValue{value: v}
+}
+
+// SetNonFiniteFloatMode sets the mode NaN and infinite floating point values
+// are encoded with. Defaults to NonFiniteFloatReject.
+func (e *Encoder) SetNonFiniteFloatMode(mode NonFiniteFloatMode) {
+	e.nonFiniteFloat = mode
+}
+
+// Bytes returns the encoded JSON documents.
+func (e *Encoder) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// String returns the encoded JSON documents as a string.
+func (e *Encoder) String() string {
+	return e.buf.String()
+}
+
+// Encode writes the value to the encoder as a JSON document. Returns an error
+// if the value, or a value nested within it, is of an unsupported type.
+func (e *Encoder) Encode(v interface{}) error {
+	return e.encode(v)
+}
+
+func (e *Encoder) encode(v interface{}) error {
+	switch tv := v.(type) {
+	case nil:
+		e.w.WriteString("null")
+	case bool:
+		e.w.Write(strconv.AppendBool(e.scratch[:0], tv))
+	case string:
+		writeString(e.w, tv)
+	case []byte:
+		writeString(e.w, base64.StdEncoding.EncodeToString(tv))
+	case Number:
+		if len(tv) == 0 {
+			return fmt.Errorf("invalid empty number")
+		}
+		e.w.WriteString(string(tv))
+	case int:
+		e.writeInt(int64(tv))
+	case int8:
+		e.writeInt(int64(tv))
+	case int16:
+		e.writeInt(int64(tv))
+	case int32:
+		e.writeInt(int64(tv))
+	case int64:
+		e.writeInt(tv)
+	case uint:
+		e.writeUint(uint64(tv))
+	case uint8:
+		e.writeUint(uint64(tv))
+	case uint16:
+		e.writeUint(uint64(tv))
+	case uint32:
+		e.writeUint(uint64(tv))
+	case uint64:
+		e.writeUint(tv)
+	case float32:
+		return e.writeFloat(float64(tv), 32)
+	case float64:
+		return e.writeFloat(tv, 64)
+	case []interface{}:
+		return e.encodeArray(tv)
+	case map[string]interface{}:
+		return e.encodeObject(tv)
+	case document.Interface:
+		return e.encodeDocument(tv)
+	case emptyCollectionValue:
+		return e.encode(tv.value)
+	default:
+		return fmt.Errorf("unsupported JSON value type %T", v)
+	}
+	return nil
+}
+
+func (e *Encoder) encodeArray(vs []interface{}) error {
+	e.w.WriteByte('[')
+	for i, v := range vs {
+		if i != 0 {
+			e.w.WriteByte(',')
+		}
+		if err := e.encode(v); err != nil {
+			return err
+		}
+	}
+	e.w.WriteByte(']')
+	return nil
+}
+
+func (e *Encoder) encodeObject(m map[string]interface{}) error {
+	e.w.WriteByte('{')
+
+	var n int
+	writeEntry := func(k string, v interface{}) error {
+		if e.omitMember(v) {
+			return nil
+		}
+		if n != 0 {
+			e.w.WriteByte(',')
+		}
+		n++
+		writeString(e.w, k)
+		e.w.WriteByte(':')
+		if err := e.encode(v); err != nil {
+			return fmt.Errorf("object key %q, %w", k, err)
+		}
+		return nil
+	}
+
+	if e.sortMapKeys {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := writeEntry(k, m[k]); err != nil {
+				return err
+			}
+		}
+	} else {
+		for k, v := range m {
+			if err := writeEntry(k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	e.w.WriteByte('}')
+	return nil
+}
+
+// omitMember returns if the object member's value is an empty list or map
+// that is not written.
+func (e *Encoder) omitMember(v interface{}) bool {
+	emit := e.emitEmpty || e.inDocument
+	if tv, ok := v.(emptyCollectionValue); ok {
+		v, emit = tv.value, tv.emit
+	}
+	if emit {
+		return false
+	}
+
+	switch tv := v.(type) {
+	case []interface{}:
+		return len(tv) == 0
+	case map[string]interface{}:
+		return len(tv) == 0
+	}
+	return false
+}
+
+func (e *Encoder) encodeDocument(d document.Interface) error {
+	v, err := d.MarshalSmithyDocument()
+	if err != nil {
+		return fmt.Errorf("failed to marshal document, %w", err)
+	}
+
+	inDocument := e.inDocument
+	e.inDocument = true
+	err = e.encode(v)
+	e.inDocument = inDocument
+	return err
+}
+
+func (e *Encoder) writeInt(v int64) {
+	e.w.Write(strconv.AppendInt(e.scratch[:0], v, 10))
+}
+
+func (e *Encoder) writeUint(v uint64) {
+	e.w.Write(strconv.AppendUint(e.scratch[:0], v, 10))
+}
+
+// writeFloat writes the float as a JSON number. The values not representable
+// as a JSON number are written as the string "NaN", "Infinity", or
+// "-Infinity", or rejected, depending on the encoder's NonFiniteFloatMode.
+func (e *Encoder) writeFloat(v float64, bitSize int) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return e.writeNonFiniteFloat(v)
+	}
+
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b := strconv.AppendFloat(e.scratch[:0], v, format, -1, bitSize)
+	if e.inDocument && format == 'f' && bytes.IndexByte(b, '.') == -1 {
+		b = append(b, ".0"...)
+	}
+	e.w.Write(b)
+	return nil
+}
+
+func (e *Encoder) writeNonFiniteFloat(v float64) error {
+	if e.nonFiniteFloat != NonFiniteFloatString {
+		return fmt.Errorf("unsupported JSON float value %v", v)
+	}
+
+	switch {
+	case math.IsNaN(v):
+		e.w.WriteString(`"NaN"`)
+	case math.IsInf(v, 1):
+		e.w.WriteString(`"Infinity"`)
+	default:
+		e.w.WriteString(`"-Infinity"`)
+	}
+	return nil
+}
+
+const hexChars = "0123456789abcdef"
+
+// writeString writes the string as a quoted JSON string, escaping control
+// characters, quotes, and backslashes. Invalid UTF-8 is replaced with the
+// Unicode replacement character.
+func writeString(w encoderWriter, s string) {
+	w.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			w.WriteString(s[start:i])
+			switch b {
+			case '"', '\\':
+				w.WriteByte('\\')
+				w.WriteByte(b)
+			case '\n':
+				w.WriteString(`\n`)
+			case '\r':
+				w.WriteString(`\r`)
+			case '\t':
+				w.WriteString(`\t`)
+			default:
+				w.WriteString(`\u00`)
+				w.WriteByte(hexChars[b>>4])
+				w.WriteByte(hexChars[b&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			w.WriteString(s[start:i])
+			w.WriteString("\ufffd")
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	w.WriteString(s[start:])
+	w.WriteByte('"')
+}
@@ -0,0 +1,220 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	cases := map[string]struct {
+		Value  interface{}
+		Expect string
+	}{
+		"null":    {Value: nil, Expect: `null`},
+		"bool":    {Value: true, Expect: `true`},
+		"string":  {Value: "a\"b\\c\n\x01日本", Expect: `"a\"b\\c\n\u0001日本"`},
+		"invalid": {Value: "a\xffb", Expect: "\"a\ufffdb\""},
+		"bytes":   {Value: []byte("hello"), Expect: `"aGVsbG8="`},
+		"number":  {Value: Number("12345678901234567890"), Expect: `12345678901234567890`},
+		"int":     {Value: int64(-42), Expect: `-42`},
+		"uint":    {Value: uint64(math.MaxUint64), Expect: `18446744073709551615`},
+		"float":   {Value: 1.5, Expect: `1.5`},
+		"float32": {Value: float32(0.1), Expect: `0.1`},
+		"large":   {Value: 1e21, Expect: `1e+21`},
+		"small":   {Value: 1e-7, Expect: `1e-07`},
+		"array":   {Value: []interface{}{1, "a", nil}, Expect: `[1,"a",null]`},
+		"object":  {Value: map[string]interface{}{"a": []interface{}{true}}, Expect: `{"a":[true]}`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			if err := e.Encode(c.Value); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, e.String(); e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestEncoderNonFiniteFloat(t *testing.T) {
+	cases := map[string]struct {
+		Value     interface{}
+		Mode      NonFiniteFloatMode
+		Expect    string
+		ExpectErr bool
+	}{
+		"+inf reject":      {Value: math.Inf(1), ExpectErr: true},
+		"-inf reject":      {Value: math.Inf(-1), ExpectErr: true},
+		"nan reject":       {Value: math.NaN(), ExpectErr: true},
+		"float32 reject":   {Value: float32(math.Inf(1)), ExpectErr: true},
+		"nested reject":    {Value: []interface{}{1.5, math.NaN()}, ExpectErr: true},
+		"+inf string":      {Value: math.Inf(1), Mode: NonFiniteFloatString, Expect: `"Infinity"`},
+		"-inf string":      {Value: math.Inf(-1), Mode: NonFiniteFloatString, Expect: `"-Infinity"`},
+		"nan string":       {Value: math.NaN(), Mode: NonFiniteFloatString, Expect: `"NaN"`},
+		"float32 string":   {Value: float32(math.Inf(-1)), Mode: NonFiniteFloatString, Expect: `"-Infinity"`},
+		"finite unchanged": {Value: 1.5, Mode: NonFiniteFloatString, Expect: `1.5`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			e.SetNonFiniteFloatMode(c.Mode)
+			err := e.Encode(c.Value)
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, e.String(); e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestEncoderEmptyCollections(t *testing.T) {
+	cases := map[string]struct {
+		Value     interface{}
+		EmitEmpty bool
+		Expect    string
+	}{
+		"empty list omitted": {
+			Value:  map[string]interface{}{"list": []interface{}{}, "a": 1},
+			Expect: `{"a":1}`,
+		},
+		"empty map omitted": {
+			Value:  map[string]interface{}{"map": map[string]interface{}{}, "a": 1},
+			Expect: `{"a":1}`,
+		},
+		"nested empty omitted": {
+			Value: map[string]interface{}{
+				"a": map[string]interface{}{"list": []interface{}{}},
+			},
+			Expect: `{"a":{}}`,
+		},
+		"empty list emitted": {
+			Value:     map[string]interface{}{"list": []interface{}{}, "a": 1},
+			EmitEmpty: true,
+			Expect:    `{"a":1,"list":[]}`,
+		},
+		"empty map emitted": {
+			Value:     map[string]interface{}{"map": map[string]interface{}{}, "a": 1},
+			EmitEmpty: true,
+			Expect:    `{"a":1,"map":{}}`,
+		},
+		"member emit empty list": {
+			Value: map[string]interface{}{
+				"list":  EmitEmpty([]interface{}{}),
+				"other": []interface{}{},
+			},
+			Expect: `{"list":[]}`,
+		},
+		"member emit empty map": {
+			Value: map[string]interface{}{
+				"map":   EmitEmpty(map[string]interface{}{}),
+				"other": map[string]interface{}{},
+			},
+			Expect: `{"map":{}}`,
+		},
+		"member omit empty list": {
+			Value: map[string]interface{}{
+				"list":  OmitEmpty([]interface{}{}),
+				"other": []interface{}{},
+			},
+			EmitEmpty: true,
+			Expect:    `{"other":[]}`,
+		},
+		"member omit empty map": {
+			Value: map[string]interface{}{
+				"map":   OmitEmpty(map[string]interface{}{}),
+				"other": map[string]interface{}{},
+			},
+			EmitEmpty: true,
+			Expect:    `{"other":{}}`,
+		},
+		"member wrapper not empty": {
+			Value:  map[string]interface{}{"list": OmitEmpty([]interface{}{1})},
+			Expect: `{"list":[1]}`,
+		},
+		"list elements kept": {
+			Value:  []interface{}{[]interface{}{}, map[string]interface{}{}},
+			Expect: `[[],{}]`,
+		},
+		"top level kept": {
+			Value:  map[string]interface{}{},
+			Expect: `{}`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewEncoder()
+			e.SortMapKeys(true)
+			e.EmitEmptyCollections(c.EmitEmpty)
+			if err := e.Encode(c.Value); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, e.String(); e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestEncoderUnsupportedType(t *testing.T) {
+	err := NewEncoder().Encode(map[string]interface{}{"a": struct{}{}})
+	if err == nil || !strings.Contains(err.Error(), `"a"`) {
+		t.Errorf("expect unsupported type error for key, got %v", err)
+	}
+}
+
+func TestEncoderSortMapKeys(t *testing.T) {
+	value := map[string]interface{}{
+		"zeta":  1,
+		"alpha": map[string]interface{}{"c": 3, "b": 2, "a": 1},
+		"mid":   []interface{}{map[string]interface{}{"y": true, "x": false}},
+	}
+	const expect = `{"alpha":{"a":1,"b":2,"c":3},"mid":[{"x":false,"y":true}],"zeta":1}`
+
+	for i := 0; i < 50; i++ {
+		e := NewEncoder()
+		e.SortMapKeys(true)
+		if err := e.Encode(value); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if a := e.String(); expect != a {
+			t.Fatalf("expect %v, got %v", expect, a)
+		}
+	}
+}
+
+func TestEncoderUnsortedRoundTrip(t *testing.T) {
+	value := map[string]interface{}{
+		"a": "b",
+		"c": []interface{}{float64(1), true, nil},
+		"d": map[string]interface{}{"e": "f"},
+	}
+
+	e := NewEncoder()
+	if err := e.Encode(value); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var actual interface{}
+	if err := stdjson.Unmarshal(e.Bytes(), &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(value, actual) {
+		t.Errorf("expect %v, got %v", value, actual)
+	}
+}
@@ -0,0 +1,232 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"strings"
+
+	"github.com/awslabs/smithy-go"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// DefaultErrorWrapperKeys is the default set of members an error response's
+// error may be nested under, (e.g. {"Error":{"Code":"...","Message":"..."}}).
+var DefaultErrorWrapperKeys = []string{"Error"}
+
+// ErrorInfo is the code and message of an error response, and the members of
+// the object the error was decoded from.
+type ErrorInfo struct {
+	Code    string
+	Message string
+
+	// Members of the error object, either the response's top level object, or
+	// the object nested under a wrapper key.
+	Fields map[string]interface{}
+}
+
+// ErrorDeserializer deserializes JSON error responses into the error shape
+// modeled for the error's code, or a smithy.GenericAPIError if the code is
+// not modeled.
+//
+// The error's code is read from the __type member of the response, or the
+// Code or code member of the response, or of an object nested under one of
+// the WrapperKeys. Shape namespaces and URI suffixes are stripped from the
+// code, (e.g. "aws.example#FooError:http://internal/" becomes "FooError").
+// The message is read from the message or Message member of the same object.
+type ErrorDeserializer struct {
+	// Members the error may be nested under. If nil, DefaultErrorWrapperKeys
+	// is used.
+	WrapperKeys []string
+
+	// Functions returning the modeled error shape, keyed by error code.
+	Modeled map[string]func(ErrorInfo) error
+
+	// Fault of the GenericAPIError returned for unmodeled errors, (e.g.
+	// smithy.FaultFromHTTPStatusCode of the response's status code).
+	Fault smithy.ErrorFault
+
+	// Extracts the message of error responses whose content type is not
+	// JSON, (e.g. HTML or plain text bodies returned by gateways and
+	// proxies), see RawErrorMessage. If nil, the body is decoded as JSON
+	// regardless of its content type.
+	FallbackMessage func(contentType string, r io.Reader) (string, error)
+}
+
+// DefaultRawErrorMessageLength is the maximum number of bytes of the body
+// RawErrorMessage includes in the message.
+const DefaultRawErrorMessageLength = 256
+
+// RawErrorMessage returns an ErrorDeserializer FallbackMessage that uses at
+// most maxLength bytes of the raw body, with whitespace collapsed, as the
+// error's message. Truncated messages are suffixed with "...". If maxLength
+// is not positive, DefaultRawErrorMessageLength is used.
+func RawErrorMessage(maxLength int) func(contentType string, r io.Reader) (string, error) {
+	if maxLength <= 0 {
+		maxLength = DefaultRawErrorMessageLength
+	}
+	return func(contentType string, r io.Reader) (string, error) {
+		b, err := ioutil.ReadAll(io.LimitReader(r, int64(maxLength)+1))
+		if err != nil {
+			return "", fmt.Errorf("failed to read error response, %w", err)
+		}
+
+		truncated := len(b) > maxLength
+		if truncated {
+			b = b[:maxLength]
+		}
+		msg := strings.Join(strings.Fields(string(b)), " ")
+		if truncated {
+			msg += "..."
+		}
+		return msg, nil
+	}
+}
+
+// DeserializeContentType deserializes the error response like Deserialize
+// if its content type is JSON, or no content type is set. Otherwise, if
+// FallbackMessage is set, a *smithy.GenericAPIError is returned with the
+// message extracted from the body.
+func (d ErrorDeserializer) DeserializeContentType(contentType string, r io.Reader) error {
+	if d.FallbackMessage == nil || isJSONContentType(contentType) {
+		return d.Deserialize(r)
+	}
+
+	msg, err := d.FallbackMessage(contentType, r)
+	if err != nil {
+		return err
+	}
+	return &smithy.GenericAPIError{
+		Message: msg,
+		Fault:   d.Fault,
+	}
+}
+
+// DeserializeResponse deserializes the error response's body like
+// DeserializeContentType, with the response's Content-Type. If the body was
+// truncated by the smithyhttp.ErrorResponseBodyLimit middleware, the
+// returned GenericAPIError's BodyTruncated is set. A truncated body that
+// fails to decode returns a GenericAPIError instead of the decode error.
+func (d ErrorDeserializer) DeserializeResponse(resp *smithyhttp.Response) error {
+	err := d.DeserializeContentType(resp.Header.Get("Content-Type"), resp.Body)
+
+	// The body must be read to its end to determine if it was truncated.
+	io.Copy(ioutil.Discard, resp.Body)
+	if !smithyhttp.IsResponseBodyTruncated(resp) {
+		return err
+	}
+
+	var genericErr *smithy.GenericAPIError
+	if errors.As(err, &genericErr) {
+		genericErr.BodyTruncated = true
+		return err
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	return &smithy.GenericAPIError{
+		Message:       fmt.Sprintf("truncated error response, %v", err),
+		Fault:         d.Fault,
+		BodyTruncated: true,
+	}
+}
+
+// isJSONContentType returns if the media type is unset, application/json, an
+// application/x-amz-json version, or has the +json structured suffix.
+func isJSONContentType(contentType string) bool {
+	if len(contentType) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		strings.HasPrefix(mediaType, "application/x-amz-json") ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+// Deserialize reads the error response body, returning the modeled error for
+// its code, or a *smithy.GenericAPIError. Returns an error wrapping the
+// decode failure if the body could not be decoded.
+func (d ErrorDeserializer) Deserialize(r io.Reader) error {
+	info, err := d.GetErrorInfo(r)
+	if err != nil {
+		return err
+	}
+
+	if fn, ok := d.Modeled[info.Code]; ok {
+		return fn(info)
+	}
+	return &smithy.GenericAPIError{
+		Code:    info.Code,
+		Message: info.Message,
+		Fault:   d.Fault,
+	}
+}
+
+// GetErrorInfo reads the error response body, returning the error's code and
+// message. An empty body returns an empty ErrorInfo.
+func (d ErrorDeserializer) GetErrorInfo(r io.Reader) (ErrorInfo, error) {
+	v, err := NewDecoder(r).Decode()
+	if err == io.EOF {
+		return ErrorInfo{}, nil
+	} else if err != nil {
+		return ErrorInfo{}, fmt.Errorf("failed to decode error response, %w", err)
+	}
+
+	body, ok := v.(map[string]interface{})
+	if !ok {
+		return ErrorInfo{}, fmt.Errorf("expect error response object, got %T", v)
+	}
+
+	wrapperKeys := d.WrapperKeys
+	if wrapperKeys == nil {
+		wrapperKeys = DefaultErrorWrapperKeys
+	}
+
+	info := ErrorInfo{Fields: body}
+	info.Code = stringMember(body, "__type", "Code", "code")
+	if len(info.Code) == 0 {
+		for _, key := range wrapperKeys {
+			wrapped, ok := body[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if code := stringMember(wrapped, "__type", "Code", "code"); len(code) != 0 {
+				info.Code = code
+				info.Fields = wrapped
+				break
+			}
+		}
+	}
+	info.Code = SanitizeErrorCode(info.Code)
+	info.Message = stringMember(info.Fields, "message", "Message")
+
+	return info, nil
+}
+
+// SanitizeErrorCode returns the error code with the shape namespace prefix
+// and URI suffix removed, (e.g. "aws.example#FooError:http://internal/"
+// becomes "FooError").
+func SanitizeErrorCode(code string) string {
+	if i := strings.IndexByte(code, ':'); i != -1 {
+		code = code[:i]
+	}
+	if i := strings.IndexByte(code, '#'); i != -1 {
+		code = code[i+1:]
+	}
+	return code
+}
+
+func stringMember(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := m[key].(string); ok && len(v) != 0 {
+			return v
+		}
+	}
+	return ""
+}
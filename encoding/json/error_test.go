@@ -0,0 +1,274 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+type modeledError struct {
+	Message string
+	Reason  string
+}
+
+func (e *modeledError) Error() string { return e.Message }
+
+func TestErrorDeserializer(t *testing.T) {
+	d := ErrorDeserializer{
+		Modeled: map[string]func(ErrorInfo) error{
+			"ModeledError": func(info ErrorInfo) error {
+				reason, _ := info.Fields["Reason"].(string)
+				return &modeledError{Message: info.Message, Reason: reason}
+			},
+		},
+		Fault: smithy.FaultClient,
+	}
+
+	cases := map[string]struct {
+		Body          string
+		ExpectCode    string
+		ExpectMessage string
+		ExpectModeled *modeledError
+	}{
+		"type member": {
+			Body:          `{"__type":"FooError","message":"foo failed"}`,
+			ExpectCode:    "FooError",
+			ExpectMessage: "foo failed",
+		},
+		"namespaced type member": {
+			Body:          `{"__type":"aws.example#FooError:http://internal.example.com/","Message":"foo failed"}`,
+			ExpectCode:    "FooError",
+			ExpectMessage: "foo failed",
+		},
+		"wrapped": {
+			Body:          `{"Error":{"Code":"BarError","Message":"bar failed"},"RequestId":"abc"}`,
+			ExpectCode:    "BarError",
+			ExpectMessage: "bar failed",
+		},
+		"wrapped namespaced": {
+			Body:          `{"Error":{"__type":"aws.example#BarError","message":"bar failed"}}`,
+			ExpectCode:    "BarError",
+			ExpectMessage: "bar failed",
+		},
+		"modeled type member": {
+			Body:          `{"__type":"aws.example#ModeledError","message":"modeled","Reason":"because"}`,
+			ExpectModeled: &modeledError{Message: "modeled", Reason: "because"},
+		},
+		"modeled wrapped": {
+			Body:          `{"Error":{"Code":"ModeledError","Message":"modeled","Reason":"because"}}`,
+			ExpectModeled: &modeledError{Message: "modeled", Reason: "because"},
+		},
+		"empty body": {},
+		"no code": {
+			Body:          `{"message":"failed"}`,
+			ExpectMessage: "failed",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := d.Deserialize(strings.NewReader(c.Body))
+
+			if c.ExpectModeled != nil {
+				var modeled *modeledError
+				if !errors.As(err, &modeled) {
+					t.Fatalf("expect modeled error, got %T, %v", err, err)
+				}
+				if e, a := *c.ExpectModeled, *modeled; e != a {
+					t.Errorf("expect %+v modeled error, got %+v", e, a)
+				}
+				return
+			}
+
+			var apiErr *smithy.GenericAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expect generic API error, got %T, %v", err, err)
+			}
+			if e, a := c.ExpectCode, apiErr.ErrorCode(); e != a {
+				t.Errorf("expect %q code, got %q", e, a)
+			}
+			if e, a := c.ExpectMessage, apiErr.ErrorMessage(); e != a {
+				t.Errorf("expect %q message, got %q", e, a)
+			}
+			if e, a := smithy.FaultClient, apiErr.ErrorFault(); e != a {
+				t.Errorf("expect %v fault, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestErrorDeserializer_CustomWrapperKeys(t *testing.T) {
+	d := ErrorDeserializer{WrapperKeys: []string{"error"}}
+
+	info, err := d.GetErrorInfo(strings.NewReader(`{"error":{"code":"FooError","message":"foo failed"}}`))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "FooError", info.Code; e != a {
+		t.Errorf("expect %q code, got %q", e, a)
+	}
+	if e, a := "foo failed", info.Message; e != a {
+		t.Errorf("expect %q message, got %q", e, a)
+	}
+}
+
+func TestErrorDeserializer_InvalidBody(t *testing.T) {
+	d := ErrorDeserializer{}
+	for _, body := range []string{`{"__type":`, `["FooError"]`} {
+		var apiErr smithy.APIError
+		err := d.Deserialize(strings.NewReader(body))
+		if err == nil || errors.As(err, &apiErr) {
+			t.Errorf("expect decode error for %q, got %v", body, err)
+		}
+	}
+}
+
+func TestErrorDeserializer_FallbackMessage(t *testing.T) {
+	const gatewayBody = "<html>\n<head><title>502 Bad Gateway</title></head>\n" +
+		"<body>\n<center><h1>502 Bad Gateway</h1></center>\n</body>\n</html>\n"
+
+	cases := map[string]struct {
+		Deserializer  ErrorDeserializer
+		ContentType   string
+		Body          string
+		ExpectCode    string
+		ExpectMessage string
+	}{
+		"html gateway error": {
+			Deserializer: ErrorDeserializer{
+				FallbackMessage: RawErrorMessage(0),
+				Fault:           smithy.FaultServer,
+			},
+			ContentType: "text/html; charset=utf-8",
+			Body:        gatewayBody,
+			ExpectMessage: "<html> <head><title>502 Bad Gateway</title></head> " +
+				"<body> <center><h1>502 Bad Gateway</h1></center> </body> </html>",
+		},
+		"bounded snippet": {
+			Deserializer:  ErrorDeserializer{FallbackMessage: RawErrorMessage(22)},
+			ContentType:   "text/html",
+			Body:          gatewayBody,
+			ExpectMessage: "<html> <head><title>50...",
+		},
+		"plain text": {
+			Deserializer:  ErrorDeserializer{FallbackMessage: RawErrorMessage(0)},
+			ContentType:   "text/plain",
+			Body:          "  upstream connect error  ",
+			ExpectMessage: "upstream connect error",
+		},
+		"json content type": {
+			Deserializer:  ErrorDeserializer{FallbackMessage: RawErrorMessage(0)},
+			ContentType:   "application/x-amz-json-1.1",
+			Body:          `{"__type":"FooError","message":"foo failed"}`,
+			ExpectCode:    "FooError",
+			ExpectMessage: "foo failed",
+		},
+		"no content type": {
+			Deserializer:  ErrorDeserializer{FallbackMessage: RawErrorMessage(0)},
+			Body:          `{"code":"FooError","message":"foo failed"}`,
+			ExpectCode:    "FooError",
+			ExpectMessage: "foo failed",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := c.Deserializer.DeserializeContentType(c.ContentType, strings.NewReader(c.Body))
+
+			var apiErr *smithy.GenericAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expect generic API error, got %v", err)
+			}
+			if e, a := c.ExpectCode, apiErr.Code; e != a {
+				t.Errorf("expect %q code, got %q", e, a)
+			}
+			if e, a := c.ExpectMessage, apiErr.Message; e != a {
+				t.Errorf("expect %q message, got %q", e, a)
+			}
+			if e, a := c.Deserializer.Fault, apiErr.Fault; e != a {
+				t.Errorf("expect %v fault, got %v", e, a)
+			}
+		})
+	}
+
+	// Without a fallback, non-JSON bodies fail to decode as before.
+	var d ErrorDeserializer
+	if err := d.DeserializeContentType("text/html", strings.NewReader(gatewayBody)); err == nil {
+		t.Errorf("expect decode error, got none")
+	}
+}
+
+func TestErrorDeserializer_DeserializeResponse_BodyTruncated(t *testing.T) {
+	cases := map[string]struct {
+		Deserializer    ErrorDeserializer
+		ContentType     string
+		Body            string
+		ExpectCode      string
+		ExpectTruncated bool
+	}{
+		"not truncated": {
+			Body:       `{"__type":"FooError","message":"foo failed"}`,
+			ExpectCode: "FooError",
+		},
+		"truncated json": {
+			Body:            `{"__type":"FooError","message":"` + strings.Repeat("x", 100) + `"}`,
+			ExpectTruncated: true,
+		},
+		"truncated fallback message": {
+			Deserializer:    ErrorDeserializer{FallbackMessage: RawErrorMessage(0)},
+			ContentType:     "text/html",
+			Body:            "<html>" + strings.Repeat("x", 100) + "</html>",
+			ExpectTruncated: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stack := middleware.NewStack("error", smithyhttp.NewStackRequest)
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+				func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out, metadata, err = next.HandleDeserialize(ctx, in)
+					if err != nil {
+						return out, metadata, err
+					}
+					return out, metadata, c.Deserializer.DeserializeResponse(out.RawResponse.(*smithyhttp.Response))
+				}), middleware.After)
+			stack.Deserialize.Add(&smithyhttp.ErrorResponseBodyLimit{MaxErrorBodyBytes: 64}, middleware.After)
+
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					resp := &http.Response{
+						StatusCode: 400,
+						Header:     http.Header{},
+						Body:       ioutil.NopCloser(strings.NewReader(c.Body)),
+					}
+					if len(c.ContentType) != 0 {
+						resp.Header.Set("Content-Type", c.ContentType)
+					}
+					return &smithyhttp.Response{Response: resp}, middleware.Metadata{}, nil
+				}), stack)
+
+			_, _, err := handler.Handle(context.Background(), struct{}{})
+
+			var apiErr *smithy.GenericAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expect generic API error, got %v", err)
+			}
+			if e, a := c.ExpectCode, apiErr.Code; e != a {
+				t.Errorf("expect %q code, got %q", e, a)
+			}
+			if e, a := c.ExpectTruncated, apiErr.BodyTruncated; e != a {
+				t.Errorf("expect body truncated %v, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package json
+
+import "github.com/awslabs/smithy-go/document"
+
+// Number is a JSON number literal, preserving the exact value of the number
+// as it was written in the document. Number is the document package's
+// Number, so decoded JSON numbers can be used directly in documents.
+type Number = document.Number
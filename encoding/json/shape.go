@@ -0,0 +1,135 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Shape describes the members of a JSON value, used by DecodeShape to reject
+// object members not in the shape when DisallowUnknownFields is enabled.
+//
+// A structure's Members map each member's name to its shape. A list's, or
+// map's, Member is the shape of its elements, or values. A nil Shape, (e.g.
+// for scalar or document members), accepts any value.
+type Shape struct {
+	Members map[string]*Shape
+	Member  *Shape
+}
+
+// UnknownFieldError is returned by DecodeShape when DisallowUnknownFields is
+// enabled and an object has a member not in its structure shape.
+type UnknownFieldError struct {
+	// Name of the unknown member.
+	Field string
+
+	// Path of the unknown member within the document, (e.g.
+	// Foo.Bar[2].Baz).
+	Path string
+}
+
+// Error returns the error message.
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown JSON field %q at %s", e.Field, e.Path)
+}
+
+// DisallowUnknownFields causes DecodeShape to return an UnknownFieldError
+// if a decoded object has a member not in its structure shape. By default
+// unknown members are ignored for forward compatibility with new members
+// added to the service's shapes.
+func (d *Decoder) DisallowUnknownFields() {
+	d.disallowUnknownFields = true
+}
+
+// DecodeShape reads the next JSON document from the stream as with Decode.
+// If DisallowUnknownFields is enabled, the document is validated against the
+// shape, and the first member not in the shape, in document order, is
+// returned as an UnknownFieldError. Returns io.EOF if there are no more
+// documents in the stream.
+func (d *Decoder) DecodeShape(shape *Shape) (interface{}, error) {
+	if !d.disallowUnknownFields {
+		return d.Decode()
+	}
+
+	var raw json.RawMessage
+	if err := d.decoder.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to decode JSON document, %w", err)
+	}
+
+	if err := checkShape(json.NewDecoder(bytes.NewReader(raw)), shape, ""); err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if d.useNumber {
+		decoder.UseNumber()
+	}
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON document, %w", err)
+	}
+	if d.useNumber {
+		v = convertNumbers(v)
+	}
+	return v, nil
+}
+
+// checkShape walks the tokens of the next value, returning an
+// UnknownFieldError for the first object member not in the shape.
+func checkShape(decoder *json.Decoder, shape *Shape, path string) error {
+	tok, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode JSON document, %w", err)
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	var member *Shape
+	if shape != nil {
+		member = shape.Member
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			tok, err := decoder.Token()
+			if err != nil {
+				return fmt.Errorf("failed to decode JSON object key, %w", err)
+			}
+			key := tok.(string)
+
+			memberShape, memberPath := member, path+"["+strconv.Quote(key)+"]"
+			if shape != nil && shape.Members != nil {
+				memberPath = key
+				if len(path) != 0 {
+					memberPath = path + "." + key
+				}
+				if memberShape, ok = shape.Members[key]; !ok {
+					return &UnknownFieldError{Field: key, Path: memberPath}
+				}
+			}
+			if err := checkShape(decoder, memberShape, memberPath); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; decoder.More(); i++ {
+			if err := checkShape(decoder, member, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Consume the object or array's closing delimiter.
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to decode JSON document, %w", err)
+	}
+	return nil
+}
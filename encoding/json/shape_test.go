@@ -0,0 +1,93 @@
+package json
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoderDecodeShape(t *testing.T) {
+	item := &Shape{Members: map[string]*Shape{
+		"Id":   nil,
+		"Tags": {Member: &Shape{Members: map[string]*Shape{"Value": nil}}},
+	}}
+	shape := &Shape{Members: map[string]*Shape{
+		"Name":  nil,
+		"Items": {Member: item},
+		"Doc":   nil,
+	}}
+
+	cases := map[string]struct {
+		Document    string
+		Disallow    bool
+		Expect      interface{}
+		ExpectField string
+		ExpectPath  string
+	}{
+		"known fields": {
+			Document: `{"Name":"a","Items":[{"Id":"1","Tags":{"k":{"Value":"v"}}}],"Doc":{"any":1}}`,
+			Disallow: true,
+			Expect: map[string]interface{}{
+				"Name": "a",
+				"Items": []interface{}{map[string]interface{}{
+					"Id":   "1",
+					"Tags": map[string]interface{}{"k": map[string]interface{}{"Value": "v"}},
+				}},
+				"Doc": map[string]interface{}{"any": float64(1)},
+			},
+		},
+		"extra top-level field lenient": {
+			Document: `{"Name":"a","Extra":true}`,
+			Expect:   map[string]interface{}{"Name": "a", "Extra": true},
+		},
+		"extra top-level field": {
+			Document:    `{"Name":"a","Extra":true,"Other":1}`,
+			Disallow:    true,
+			ExpectField: "Extra",
+			ExpectPath:  "Extra",
+		},
+		"extra nested field": {
+			Document:    `{"Items":[{"Id":"1"},{"Id":"2","Extra":{}}]}`,
+			Disallow:    true,
+			ExpectField: "Extra",
+			ExpectPath:  "Items[1].Extra",
+		},
+		"extra field in map value": {
+			Document:    `{"Items":[{"Tags":{"k":{"Value":"v","Extra":1}}}]}`,
+			Disallow:    true,
+			ExpectField: "Extra",
+			ExpectPath:  `Items[0].Tags["k"].Extra`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(c.Document))
+			if c.Disallow {
+				d.DisallowUnknownFields()
+			}
+
+			v, err := d.DecodeShape(shape)
+			if len(c.ExpectField) != 0 {
+				var unknownErr *UnknownFieldError
+				if !errors.As(err, &unknownErr) {
+					t.Fatalf("expect unknown field error, got %v", err)
+				}
+				if e, a := c.ExpectField, unknownErr.Field; e != a {
+					t.Errorf("expect %v field, got %v", e, a)
+				}
+				if e, a := c.ExpectPath, unknownErr.Path; e != a {
+					t.Errorf("expect %v path, got %v", e, a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
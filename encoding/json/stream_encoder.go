@@ -0,0 +1,78 @@
+package json
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// StreamEncoder encodes untyped Go values as JSON documents written directly
+// to an io.Writer, (e.g. a request body), instead of buffering the full
+// document in memory. Values are encoded the same as the Encoder, see
+// Encoder for the supported types.
+type StreamEncoder struct {
+	bw  *bufio.Writer
+	enc *Encoder
+}
+
+// NewStreamEncoder returns an initialized JSON encoder writing to the writer.
+func NewStreamEncoder(w io.Writer) *StreamEncoder {
+	bw := bufio.NewWriter(w)
+	return &StreamEncoder{
+		bw:  bw,
+		enc: &Encoder{w: bw},
+	}
+}
+
+// SortMapKeys sets if object keys are written in sorted order, instead of the
+// map's iteration order.
+func (e *StreamEncoder) SortMapKeys(v bool) {
+	e.enc.sortMapKeys = v
+}
+
+// EmitEmptyCollections sets if object members whose values are empty lists
+// or maps are written instead of omitted.
+func (e *StreamEncoder) EmitEmptyCollections(v bool) {
+	e.enc.emitEmpty = v
+}
+
+// SetNonFiniteFloatMode sets the mode NaN and infinite floating point values
+// are encoded with. Defaults to NonFiniteFloatReject.
+func (e *StreamEncoder) SetNonFiniteFloatMode(mode NonFiniteFloatMode) {
+	e.enc.nonFiniteFloat = mode
+}
+
+// Encode writes the value to the writer as a JSON document, flushing the
+// document once it has been written. Returns an error if the value, or a
+// value nested within it, is of an unsupported type, or writing to the
+// writer failed. Part of the document may have been written when an error
+// is returned.
+func (e *StreamEncoder) Encode(v interface{}) error {
+	if err := e.enc.Encode(v); err != nil {
+		return err
+	}
+	if err := e.bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write JSON document, %w", err)
+	}
+	return nil
+}
+
+// NewEncodeReader returns a reader of the value encoded as a JSON document,
+// which is encoded as the reader is read, (e.g. to stream a large request
+// payload as the request's body). An error encoding the value is returned by
+// the reader's Read. The reader must be read to EOF, or closed, to release
+// the encoding goroutine.
+func NewEncodeReader(v interface{}, optFns ...func(*StreamEncoder)) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	encoder := NewStreamEncoder(pw)
+	for _, fn := range optFns {
+		fn(encoder)
+	}
+
+	go func() {
+		pw.CloseWithError(encoder.Encode(v))
+	}()
+
+	return pr
+}
@@ -0,0 +1,83 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+)
+
+func newStreamEncoderTestValue() interface{} {
+	items := make([]interface{}, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		items = append(items, map[string]interface{}{
+			"id":    i,
+			"name":  fmt.Sprintf("item \"%d\"\n", i),
+			"score": float64(i) / 3,
+			"blob":  []byte{byte(i)},
+			"tags":  []interface{}{"a", nil, true},
+		})
+	}
+	return map[string]interface{}{
+		"items":  items,
+		"number": Number("12345678901234567890"),
+		"inf":    math.Inf(1),
+		"small":  float32(1e-7),
+	}
+}
+
+func TestStreamEncoder(t *testing.T) {
+	v := newStreamEncoderTestValue()
+
+	buffered := NewEncoder()
+	buffered.SortMapKeys(true)
+	buffered.SetNonFiniteFloatMode(NonFiniteFloatString)
+	if err := buffered.Encode(v); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var streamed bytes.Buffer
+	encoder := NewStreamEncoder(&streamed)
+	encoder.SortMapKeys(true)
+	encoder.SetNonFiniteFloatMode(NonFiniteFloatString)
+	if err := encoder.Encode(v); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if !bytes.Equal(buffered.Bytes(), streamed.Bytes()) {
+		t.Errorf("expect streamed output to match buffered output, %d and %d bytes",
+			buffered.buf.Len(), streamed.Len())
+	}
+
+	r := NewEncodeReader(v, func(e *StreamEncoder) {
+		e.SortMapKeys(true)
+		e.SetNonFiniteFloatMode(NonFiniteFloatString)
+	})
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !bytes.Equal(buffered.Bytes(), b) {
+		t.Errorf("expect encode reader output to match buffered output")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, fmt.Errorf("write failed") }
+
+func TestStreamEncoder_Errors(t *testing.T) {
+	if err := NewStreamEncoder(failingWriter{}).Encode("abc"); err == nil ||
+		!strings.Contains(err.Error(), "write failed") {
+		t.Errorf("expect write error, got %v", err)
+	}
+
+	r := NewEncodeReader(map[string]interface{}{"a": struct{}{}})
+	defer r.Close()
+	if _, err := ioutil.ReadAll(r); err == nil || !strings.Contains(err.Error(), "unsupported JSON value type") {
+		t.Errorf("expect unsupported type error, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package json
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// TargetHeader is the header the operation of an awsJson1_0 or awsJson1_1
+// protocol request is selected by, as ServiceName.OperationName.
+const TargetHeader = "X-Amz-Target"
+
+// The media types of the awsJson1_0 and awsJson1_1 protocol request bodies.
+const (
+	ContentType10 = "application/x-amz-json-1.0"
+	ContentType11 = "application/x-amz-json-1.1"
+)
+
+// SetTarget is a build middleware that sets the X-Amz-Target header, and the
+// Content-Type header of the protocol's JSON version, on the requests of the
+// awsJson1_0 and awsJson1_1 protocols.
+type SetTarget struct {
+	// Name of the service shape, (e.g. "DynamoDB_20120810").
+	ServiceName string
+
+	// Name of the operation shape, (e.g. "GetItem").
+	OperationName string
+
+	// Version of the protocol, "1.0" or "1.1". Defaults to "1.0".
+	JSONVersion string
+}
+
+// ID returns the middleware identifier.
+func (m *SetTarget) ID() string {
+	return "SetTarget"
+}
+
+// HandleBuild sets the request's X-Amz-Target and Content-Type headers.
+func (m *SetTarget) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*smithyhttp.Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+	if len(m.ServiceName) == 0 || len(m.OperationName) == 0 {
+		return out, metadata, fmt.Errorf("target service and operation name must be set")
+	}
+
+	contentType, err := targetContentType(m.JSONVersion)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	req.Header.Set(TargetHeader, m.ServiceName+"."+m.OperationName)
+	req.Header.Set("Content-Type", contentType)
+
+	return next.HandleBuild(ctx, in)
+}
+
+// targetContentType returns the media type of the JSON protocol version.
+func targetContentType(version string) (string, error) {
+	switch version {
+	case "", "1.0":
+		return ContentType10, nil
+	case "1.1":
+		return ContentType11, nil
+	default:
+		return "", fmt.Errorf("unsupported JSON protocol version %q", version)
+	}
+}
+
+// GetTarget returns the service and operation names of the header's
+// X-Amz-Target value, and if the header was set to a valid target, (e.g. of
+// the request a response was received for).
+func GetTarget(header http.Header) (service, operation string, ok bool) {
+	v := header.Get(TargetHeader)
+	i := strings.LastIndexByte(v, '.')
+	if i <= 0 || i == len(v)-1 {
+		return "", "", false
+	}
+	return v[:i], v[i+1:], true
+}
@@ -0,0 +1,160 @@
+package json
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+type buildHandlerFunc func(context.Context, middleware.BuildInput) (
+	middleware.BuildOutput, middleware.Metadata, error,
+)
+
+func (fn buildHandlerFunc) HandleBuild(ctx context.Context, in middleware.BuildInput) (
+	middleware.BuildOutput, middleware.Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+func TestSetTarget(t *testing.T) {
+	cases := map[string]struct {
+		Middleware        SetTarget
+		ExpectTarget      string
+		ExpectContentType string
+		ExpectErr         bool
+	}{
+		"default version": {
+			Middleware: SetTarget{
+				ServiceName:   "DynamoDB_20120810",
+				OperationName: "GetItem",
+			},
+			ExpectTarget:      "DynamoDB_20120810.GetItem",
+			ExpectContentType: "application/x-amz-json-1.0",
+		},
+		"version 1.0": {
+			Middleware: SetTarget{
+				ServiceName:   "DynamoDB_20120810",
+				OperationName: "GetItem",
+				JSONVersion:   "1.0",
+			},
+			ExpectTarget:      "DynamoDB_20120810.GetItem",
+			ExpectContentType: "application/x-amz-json-1.0",
+		},
+		"version 1.1": {
+			Middleware: SetTarget{
+				ServiceName:   "Kinesis_20131202",
+				OperationName: "PutRecord",
+				JSONVersion:   "1.1",
+			},
+			ExpectTarget:      "Kinesis_20131202.PutRecord",
+			ExpectContentType: "application/x-amz-json-1.1",
+		},
+		"unsupported version": {
+			Middleware: SetTarget{
+				ServiceName:   "Kinesis_20131202",
+				OperationName: "PutRecord",
+				JSONVersion:   "2.0",
+			},
+			ExpectErr: true,
+		},
+		"no operation": {
+			Middleware: SetTarget{ServiceName: "Kinesis_20131202"},
+			ExpectErr:  true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := smithyhttp.NewStackRequest().(*smithyhttp.Request)
+			req.Header.Set("Content-Type", "application/json")
+
+			var sent bool
+			_, _, err := c.Middleware.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					sent = true
+					return out, metadata, nil
+				}))
+
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				if sent {
+					t.Errorf("expect request not sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectTarget, req.Header.Get(TargetHeader); e != a {
+				t.Errorf("expect %v target, got %v", e, a)
+			}
+			if e, a := c.ExpectContentType, req.Header.Get("Content-Type"); e != a {
+				t.Errorf("expect %v content type, got %v", e, a)
+			}
+
+			service, operation, ok := GetTarget(req.Header)
+			if !ok {
+				t.Fatalf("expect target")
+			}
+			if e, a := c.Middleware.ServiceName, service; e != a {
+				t.Errorf("expect %v service, got %v", e, a)
+			}
+			if e, a := c.Middleware.OperationName, operation; e != a {
+				t.Errorf("expect %v operation, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestGetTarget(t *testing.T) {
+	cases := map[string]struct {
+		Target          string
+		ExpectService   string
+		ExpectOperation string
+		ExpectOK        bool
+	}{
+		"target": {
+			Target:          "DynamoDB_20120810.GetItem",
+			ExpectService:   "DynamoDB_20120810",
+			ExpectOperation: "GetItem",
+			ExpectOK:        true,
+		},
+		"dotted service": {
+			Target:          "com.example.Service.GetItem",
+			ExpectService:   "com.example.Service",
+			ExpectOperation: "GetItem",
+			ExpectOK:        true,
+		},
+		"not set":      {},
+		"no operation": {Target: "DynamoDB_20120810."},
+		"no service":   {Target: ".GetItem"},
+		"no separator": {Target: "GetItem"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			if len(c.Target) != 0 {
+				header.Set(TargetHeader, c.Target)
+			}
+
+			service, operation, ok := GetTarget(header)
+			if e, a := c.ExpectOK, ok; e != a {
+				t.Fatalf("expect %v ok, got %v", e, a)
+			}
+			if e, a := c.ExpectService, service; e != a {
+				t.Errorf("expect %v service, got %v", e, a)
+			}
+			if e, a := c.ExpectOperation, operation; e != a {
+				t.Errorf("expect %v operation, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Package query provides the value builder encoder for serializing shapes to
+application/x-www-form-urlencoded request bodies, as used by the AWS query
+protocol.
+
+The Encoder is started with the top level object, and values are written by
+selecting the member key to write using the Value type. Nested structures,
+lists, and maps are flattened into dot separated keys.
+
+	encoder := query.NewEncoder()
+	body := encoder.Object()
+	body.Key("Action").String("PutItems")
+	list := body.Key("Items").Array("member")
+	list.Value().String("foo")
+	list.Value().String("bar")
+
+	// Action=PutItems&Items.member.1=foo&Items.member.2=bar
+
+List members are keyed by their one based index, and map entries by their
+one based index followed by the entry's key and value names. Lists and maps
+annotated with the xmlFlattened trait omit the member and entry names. Empty
+lists and maps are written as their key with an empty value.
+
+The encoded body is written with the keys in sorted order and the values
+percent-encoded, so the output is stable for the same input.
+*/
+package query
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// ContentType is the media type of the encoded request body.
+const ContentType = "application/x-www-form-urlencoded; charset=utf-8"
+
+// Encoder is a query encoder that collects the form values of a request
+// body.
+type Encoder struct {
+	values url.Values
+}
+
+// NewEncoder returns an initialized query encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		values: url.Values{},
+	}
+}
+
+// Object returns the builder for the top level members of the request body.
+func (e *Encoder) Object() *Object {
+	return newObject(e.values, "")
+}
+
+// Bytes returns the encoded request body, with the keys sorted and the
+// values percent-encoded.
+func (e *Encoder) Bytes() []byte {
+	return []byte(e.values.Encode())
+}
+
+// String returns the encoded request body as a string.
+func (e *Encoder) String() string {
+	return e.values.Encode()
+}
+
+// SetRequestBody sets the encoded body as the request's stream, and the
+// request's Content-Type header to ContentType. Returns the updated request.
+func (e *Encoder) SetRequestBody(req *smithyhttp.Request) (*smithyhttp.Request, error) {
+	req, err := req.SetStream(bytes.NewReader(e.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set query request body, %w", err)
+	}
+	req.Header.Set("Content-Type", ContentType)
+	return req, nil
+}
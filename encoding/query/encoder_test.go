@@ -0,0 +1,155 @@
+package query
+
+import (
+	"io/ioutil"
+	"math"
+	"net/url"
+	"reflect"
+	"testing"
+
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func TestEncoder(t *testing.T) {
+	cases := map[string]struct {
+		Encode func(*Object)
+		Expect string
+	}{
+		"scalars": {
+			Encode: func(o *Object) {
+				o.Key("String").String("foo")
+				o.Key("Boolean").Boolean(true)
+				o.Key("Integer").Integer(-123)
+				o.Key("Long").Long(math.MaxInt64)
+				o.Key("Float").Float(1.5)
+				o.Key("Double").Double(math.Inf(1))
+				o.Key("Blob").Base64EncodeBytes([]byte("hello"))
+			},
+			Expect: "Blob=aGVsbG8%3D&Boolean=true&Double=Infinity&Float=1.5&Integer=-123" +
+				"&Long=9223372036854775807&String=foo",
+		},
+		"reserved characters": {
+			Encode: func(o *Object) {
+				o.Key("Value").String("a b&c=d+e/f?g%h")
+			},
+			Expect: "Value=a+b%26c%3Dd%2Be%2Ff%3Fg%25h",
+		},
+		"nested structure": {
+			Encode: func(o *Object) {
+				nested := o.Key("Outer").Object()
+				nested.Key("Name").String("foo")
+				nested.Key("Inner").Object().Key("Value").Integer(1)
+			},
+			Expect: "Outer.Inner.Value=1&Outer.Name=foo",
+		},
+		"list": {
+			Encode: func(o *Object) {
+				list := o.Key("Items").Array("member")
+				list.Value().String("a")
+				list.Value().String("b")
+			},
+			Expect: "Items.member.1=a&Items.member.2=b",
+		},
+		"flattened list": {
+			Encode: func(o *Object) {
+				list := o.Key("Items").Array("")
+				list.Value().String("a")
+				list.Value().String("b")
+			},
+			Expect: "Items.1=a&Items.2=b",
+		},
+		"empty list": {
+			Encode: func(o *Object) {
+				o.Key("Items").Array("member")
+			},
+			Expect: "Items=",
+		},
+		"list of structures": {
+			Encode: func(o *Object) {
+				list := o.Key("Items").Array("member")
+				list.Value().Object().Key("Name").String("a")
+				list.Value().Object().Key("Name").String("b")
+			},
+			Expect: "Items.member.1.Name=a&Items.member.2.Name=b",
+		},
+		"map": {
+			Encode: func(o *Object) {
+				m := o.Key("Tags").Map("entry", "key", "value")
+				m.Key("k1").String("v1")
+				m.Key("k2").String("v2")
+			},
+			Expect: "Tags.entry.1.key=k1&Tags.entry.1.value=v1&Tags.entry.2.key=k2&Tags.entry.2.value=v2",
+		},
+		"flattened map": {
+			Encode: func(o *Object) {
+				m := o.Key("Tags").Map("", "Name", "Value")
+				m.Key("k1").String("v1")
+			},
+			Expect: "Tags.1.Name=k1&Tags.1.Value=v1",
+		},
+		"empty map": {
+			Encode: func(o *Object) {
+				o.Key("Tags").Map("entry", "key", "value")
+			},
+			Expect: "Tags=",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			encoder := NewEncoder()
+			c.Encode(encoder.Object())
+
+			if e, a := c.Expect, encoder.String(); e != a {
+				t.Errorf("expect\n%v\ngot\n%v", e, a)
+			}
+
+			expectValues, err := url.ParseQuery(c.Expect)
+			if err != nil {
+				t.Fatalf("expect no error parsing expected query, got %v", err)
+			}
+			actualValues, err := url.ParseQuery(string(encoder.Bytes()))
+			if err != nil {
+				t.Fatalf("expect no error parsing encoded query, got %v", err)
+			}
+			if e, a := expectValues, actualValues; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v values, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestEncoderRoundTripReservedCharacters(t *testing.T) {
+	const value = "a b&c=d+e/f?g%h#i;jé"
+
+	encoder := NewEncoder()
+	encoder.Object().Key("Nested").Object().Key("Value").String(value)
+
+	values, err := url.ParseQuery(encoder.String())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := value, values.Get("Nested.Value"); e != a {
+		t.Errorf("expect %q value, got %q", e, a)
+	}
+}
+
+func TestEncoderSetRequestBody(t *testing.T) {
+	encoder := NewEncoder()
+	encoder.Object().Key("Action").String("Foo")
+
+	req, err := encoder.SetRequestBody(smithyhttp.NewStackRequest().(*smithyhttp.Request))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := ContentType, req.Header.Get("Content-Type"); e != a {
+		t.Errorf("expect %v content type, got %v", e, a)
+	}
+	body, err := ioutil.ReadAll(req.GetStream())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "Action=Foo", string(body); e != a {
+		t.Errorf("expect %v body, got %v", e, a)
+	}
+}
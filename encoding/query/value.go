@@ -0,0 +1,194 @@
+package query
+
+import (
+	"encoding/base64"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// Value is a form value which has not yet been written. The value is written
+// by calling one of the Value's methods.
+type Value struct {
+	values url.Values
+	key    string
+}
+
+func newValue(values url.Values, key string) Value {
+	return Value{
+		values: values,
+		key:    key,
+	}
+}
+
+// String writes the string as the value.
+func (v Value) String(s string) {
+	v.values.Set(v.key, s)
+}
+
+// Boolean writes the boolean as the value.
+func (v Value) Boolean(b bool) {
+	v.String(strconv.FormatBool(b))
+}
+
+// Byte writes the integer as the value.
+func (v Value) Byte(i int8) {
+	v.Long(int64(i))
+}
+
+// Short writes the integer as the value.
+func (v Value) Short(i int16) {
+	v.Long(int64(i))
+}
+
+// Integer writes the integer as the value.
+func (v Value) Integer(i int32) {
+	v.Long(int64(i))
+}
+
+// Long writes the integer as the value.
+func (v Value) Long(i int64) {
+	v.String(strconv.FormatInt(i, 10))
+}
+
+// Float writes the float as the value.
+func (v Value) Float(f float32) {
+	v.String(formatFloat(float64(f), 32))
+}
+
+// Double writes the float as the value.
+func (v Value) Double(f float64) {
+	v.String(formatFloat(f, 64))
+}
+
+// Base64EncodeBytes writes the base64 encoded bytes as the value.
+func (v Value) Base64EncodeBytes(b []byte) {
+	v.String(base64.StdEncoding.EncodeToString(b))
+}
+
+// Object starts the value as a structure, returning the builder for its
+// members. Members are keyed by the value's key followed by the member's
+// name, (e.g. Parent.Member).
+func (v Value) Object() *Object {
+	return newObject(v.values, v.key)
+}
+
+// Array starts the value as a list whose members are keyed by the value's
+// key, the member name, and the one based index of the member, (e.g.
+// Items.member.1). An empty member name writes the list flattened, without
+// the member name, (e.g. Items.1).
+func (v Value) Array(memberName string) *Array {
+	return newArray(v.values, v.key, memberName)
+}
+
+// Map starts the value as a map whose entries are keyed by the value's key,
+// the entry name, and the one based index of the entry, with the entry's key
+// and value named by keyName and valueName, (e.g. Tags.entry.1.key). An empty
+// entry name writes the map flattened, without the entry name, (e.g.
+// Tags.1.key).
+func (v Value) Map(entryName, keyName, valueName string) *Map {
+	return newMap(v.values, v.key, entryName, keyName, valueName)
+}
+
+// Object is the builder for the members of a structure.
+type Object struct {
+	values url.Values
+	prefix string
+}
+
+func newObject(values url.Values, prefix string) *Object {
+	return &Object{
+		values: values,
+		prefix: prefix,
+	}
+}
+
+// Key returns the Value for a member of the structure.
+func (o *Object) Key(name string) Value {
+	return newValue(o.values, joinKey(o.prefix, name))
+}
+
+// Array is the builder for the members of a list.
+type Array struct {
+	values     url.Values
+	key        string
+	memberName string
+	size       int
+}
+
+func newArray(values url.Values, key, memberName string) *Array {
+	// An empty list is written as the list's key with an empty value, which
+	// is replaced once the first member is written.
+	values.Set(key, "")
+	return &Array{
+		values:     values,
+		key:        key,
+		memberName: memberName,
+	}
+}
+
+// Value returns the Value for the next member of the list.
+func (a *Array) Value() Value {
+	if a.size == 0 {
+		delete(a.values, a.key)
+	}
+	a.size++
+	return newValue(a.values, joinKey(joinKey(a.key, a.memberName), strconv.Itoa(a.size)))
+}
+
+// Map is the builder for the entries of a map.
+type Map struct {
+	values    url.Values
+	key       string
+	entryName string
+	keyName   string
+	valueName string
+	size      int
+}
+
+func newMap(values url.Values, key, entryName, keyName, valueName string) *Map {
+	// An empty map is written as the map's key with an empty value, which is
+	// replaced once the first entry is written.
+	values.Set(key, "")
+	return &Map{
+		values:    values,
+		key:       key,
+		entryName: entryName,
+		keyName:   keyName,
+		valueName: valueName,
+	}
+}
+
+// Key writes the next entry of the map with the entry's key, returning the
+// Value for the entry's value.
+func (m *Map) Key(name string) Value {
+	if m.size == 0 {
+		delete(m.values, m.key)
+	}
+	m.size++
+	entry := joinKey(joinKey(m.key, m.entryName), strconv.Itoa(m.size))
+	newValue(m.values, joinKey(entry, m.keyName)).String(name)
+	return newValue(m.values, joinKey(entry, m.valueName))
+}
+
+func joinKey(prefix, name string) string {
+	if len(prefix) == 0 {
+		return name
+	}
+	if len(name) == 0 {
+		return prefix
+	}
+	return prefix + "." + name
+}
+
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(v, 'f', -1, bitSize)
+}
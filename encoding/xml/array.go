@@ -0,0 +1,39 @@
+package xml
+
+import "bytes"
+
+const defaultArrayMemberName = "member"
+
+// Array is the builder for the member elements of a list.
+type Array struct {
+	w            *bytes.Buffer
+	startElement StartElement
+	member       StartElement
+	flattened    bool
+}
+
+func newArray(v Value, member StartElement, flattened bool) *Array {
+	if !flattened {
+		v.writeStart()
+	}
+	return &Array{
+		w:            v.w,
+		startElement: v.startElement,
+		member:       member,
+		flattened:    flattened,
+	}
+}
+
+// Member returns the Value for the next member element of the list.
+func (a *Array) Member() Value {
+	return newValue(a.w, a.member)
+}
+
+// Close writes the end tag of the list's wrapping element. Closing a
+// flattened list writes nothing.
+func (a *Array) Close() {
+	if a.flattened {
+		return
+	}
+	writeEndElement(a.w, a.startElement.End())
+}
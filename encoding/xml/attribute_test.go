@@ -0,0 +1,93 @@
+package xml
+
+import (
+	stdxml "encoding/xml"
+	"reflect"
+	"testing"
+)
+
+type attributeChild struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+type attributeShape struct {
+	XMLName  stdxml.Name      `xml:"https://example.com/ns Shape"`
+	Name     string           `xml:"name,attr"`
+	Quoted   string           `xml:"quoted,attr"`
+	Prefixed string           `xml:"https://example.com/custom prefixed,attr"`
+	Children []attributeChild `xml:"Child"`
+	Nested   struct {
+		Kind  string `xml:"kind,attr"`
+		Value string `xml:"Value"`
+	} `xml:"Nested"`
+}
+
+func TestEncoderAttributes(t *testing.T) {
+	encoder := NewEncoder()
+	root := encoder.RootElement(newElement("Shape")).WithAttributes(
+		NewNamespaceAttribute("", "https://example.com/ns"),
+		NewNamespaceAttribute("custom", "https://example.com/custom"),
+		NewAttribute("name", "foo"),
+		NewAttribute("quoted", `a "b" & 'c' <d>`),
+		Attr{Name: Name{Space: "custom", Local: "prefixed"}, Value: "bar"},
+	).Struct()
+
+	children := root.MemberElement(newElement("Child")).Array(true)
+	children.Member().WithAttributes(NewAttribute("id", "1")).String("first")
+	children.Member().WithAttributes(NewAttribute("id", "2")).String("second")
+	children.Close()
+
+	nested := root.MemberElement(newElement("Nested")).WithAttributes(NewAttribute("kind", "x&y")).Struct()
+	nested.MemberElement(newElement("Value")).String("v")
+	nested.Close()
+
+	root.Close()
+
+	expectXML := `<Shape xmlns="https://example.com/ns" xmlns:custom="https://example.com/custom"` +
+		` name="foo" quoted="a &quot;b&quot; &amp; &apos;c&apos; &lt;d&gt;" custom:prefixed="bar">` +
+		`<Child id="1">first</Child><Child id="2">second</Child>` +
+		`<Nested kind="x&amp;y"><Value>v</Value></Nested>` +
+		`</Shape>`
+	if e, a := expectXML, encoder.String(); e != a {
+		t.Errorf("expect\n%v\ngot\n%v", e, a)
+	}
+
+	var actual attributeShape
+	if err := stdxml.Unmarshal(encoder.Bytes(), &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := attributeShape{
+		XMLName:  stdxml.Name{Space: "https://example.com/ns", Local: "Shape"},
+		Name:     "foo",
+		Quoted:   `a "b" & 'c' <d>`,
+		Prefixed: "bar",
+		Children: []attributeChild{
+			{ID: "1", Value: "first"},
+			{ID: "2", Value: "second"},
+		},
+	}
+	expect.Nested.Kind = "x&y"
+	expect.Nested.Value = "v"
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestValueWithAttributesCopies(t *testing.T) {
+	encoder := NewEncoder()
+	base := encoder.RootElement(StartElement{
+		Name: Name{Local: "Root"},
+		Attr: make([]Attr, 0, 4),
+	})
+
+	a := base.WithAttributes(NewAttribute("a", "1"))
+	b := base.WithAttributes(NewAttribute("b", "2"))
+	a.String("")
+	b.String("")
+
+	if e, a := `<Root a="1"></Root><Root b="2"></Root>`, encoder.String(); e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
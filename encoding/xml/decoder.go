@@ -0,0 +1,169 @@
+package xml
+
+import (
+	"bytes"
+	stdxml "encoding/xml"
+	"fmt"
+	"io"
+)
+
+// NamespaceMismatchError is returned by the Decoder's Match when strict
+// namespaces are enabled, and an element matching the member's local name is
+// not in the member's declared namespace.
+type NamespaceMismatchError struct {
+	Element   Name
+	Namespace string
+}
+
+// Error returns the error message.
+func (e *NamespaceMismatchError) Error() string {
+	return fmt.Sprintf("xml element %s namespace %q does not match expected namespace %q",
+		e.Element.Local, e.Element.Space, e.Namespace)
+}
+
+// Decoder decodes the elements of an XML document from a stream. Elements
+// are read one level at a time with Token, and their content read with
+// Value, or skipped with Skip.
+//
+// Unlike the names of elements written by the Encoder, the Space of decoded
+// element and attribute names is the namespace URI the name's prefix, or the
+// default namespace, resolves to. Elements are matched against member names
+// by their local name with Match, regardless of their prefix.
+type Decoder struct {
+	d      *stdxml.Decoder
+	strict bool
+}
+
+// NewDecoder returns a decoder that reads the XML document from the reader.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		d: stdxml.NewDecoder(r),
+	}
+}
+
+// StrictNamespaces causes Match to require elements matching a member with a
+// declared namespace to be in that namespace.
+func (d *Decoder) StrictNamespaces() {
+	d.strict = true
+}
+
+// Token returns the next start element within the current element, skipping
+// character data, comments, and processing instructions between elements.
+// Returns done once the current element's end tag has been read. The first
+// call returns the document's root element.
+//
+// Returns io.EOF if the end of the document has been reached.
+func (d *Decoder) Token() (el StartElement, done bool, err error) {
+	for {
+		t, err := d.d.Token()
+		if err != nil {
+			return el, false, err
+		}
+
+		switch t := t.(type) {
+		case stdxml.StartElement:
+			return convertStartElement(t), false, nil
+		case stdxml.EndElement:
+			return el, true, nil
+		}
+	}
+}
+
+// Value reads the character data of the element last returned by Token,
+// up to and including the element's end tag. Returns an error if the element
+// has child elements.
+func (d *Decoder) Value() (string, error) {
+	var v bytes.Buffer
+	for {
+		t, err := d.d.Token()
+		if err != nil {
+			return "", err
+		}
+
+		switch t := t.(type) {
+		case stdxml.CharData:
+			v.Write(t)
+		case stdxml.StartElement:
+			return "", fmt.Errorf("xml value has unexpected child element %s", t.Name.Local)
+		case stdxml.EndElement:
+			return v.String(), nil
+		}
+	}
+}
+
+// Skip skips the element last returned by Token, including its children.
+func (d *Decoder) Skip() error {
+	return d.d.Skip()
+}
+
+// Match returns if the element matches the member's local name. The
+// element's namespace prefix is ignored. If strict namespaces are enabled and
+// the member declares a namespace, a NamespaceMismatchError is returned for
+// elements matching the local name in a different namespace.
+func (d *Decoder) Match(el StartElement, local, namespace string) (bool, error) {
+	if el.Name.Local != local {
+		return false, nil
+	}
+	if !d.strict || len(namespace) == 0 || el.Name.Space == namespace {
+		return true, nil
+	}
+	return false, &NamespaceMismatchError{Element: el.Name, Namespace: namespace}
+}
+
+// StreamList invokes fn with each member element of the list element el, as
+// the members are read, so large lists can be processed incrementally
+// instead of decoded into memory first. fn must consume the member element,
+// (e.g. with Value, Skip, or by reading its children with Token until done).
+//
+// If the list is flattened, el is itself a member of the list, and fn is
+// invoked once with el. The list's remaining members are the element's
+// siblings, and are each streamed as they are matched by the caller.
+// Otherwise el is the list's wrapping element, and fn is invoked with each of
+// its child elements matching the member local name, skipping other
+// elements, until the wrapping element's end tag is read.
+func (d *Decoder) StreamList(el StartElement, member string, flattened bool, fn func(*Decoder, StartElement) error) error {
+	if flattened {
+		return fn(d, el)
+	}
+
+	for {
+		child, done, err := d.Token()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		ok, err := d.Match(child, member, "")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := d.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := fn(d, child); err != nil {
+			return err
+		}
+	}
+}
+
+func convertStartElement(t stdxml.StartElement) StartElement {
+	el := StartElement{
+		Name: Name{Space: t.Name.Space, Local: t.Name.Local},
+	}
+	if len(t.Attr) != 0 {
+		el.Attr = make([]Attr, 0, len(t.Attr))
+		for _, a := range t.Attr {
+			el.Attr = append(el.Attr, Attr{
+				Name:  Name{Space: a.Name.Space, Local: a.Name.Local},
+				Value: a.Value,
+			})
+		}
+	}
+	return el
+}
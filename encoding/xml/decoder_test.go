@@ -0,0 +1,260 @@
+package xml
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// decodeItems decodes the values of the root's Item members, skipping other
+// members.
+func decodeItems(d *Decoder, namespace string) ([]string, error) {
+	if _, _, err := d.Token(); err != nil {
+		return nil, err
+	}
+
+	var items []string
+	for {
+		el, done, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return items, nil
+		}
+
+		ok, err := d.Match(el, "Item", namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		v, err := d.Value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+}
+
+func TestDecoderMatch(t *testing.T) {
+	const mixedPrefixes = `<Root xmlns="urn:a" xmlns:ns2="urn:a" xmlns:ns3="urn:b">` +
+		`<ns2:Item>1</ns2:Item>` +
+		`<Item>2</Item>` +
+		`<Other><Item>skipped</Item></Other>` +
+		`<ns3:Item>3</ns3:Item>` +
+		`</Root>`
+
+	cases := map[string]struct {
+		Document  string
+		Namespace string
+		Strict    bool
+		Expect    []string
+		ExpectErr bool
+	}{
+		"prefix ignored": {
+			Document: mixedPrefixes,
+			Expect:   []string{"1", "2", "3"},
+		},
+		"namespace ignored when not strict": {
+			Document:  mixedPrefixes,
+			Namespace: "urn:a",
+			Expect:    []string{"1", "2", "3"},
+		},
+		"strict without declared namespace": {
+			Document: mixedPrefixes,
+			Strict:   true,
+			Expect:   []string{"1", "2", "3"},
+		},
+		"strict namespace mismatch": {
+			Document:  mixedPrefixes,
+			Namespace: "urn:a",
+			Strict:    true,
+			ExpectErr: true,
+		},
+		"strict default and prefixed namespace": {
+			Document: `<Root xmlns="urn:a"><Item>1</Item>` +
+				`<ns2:Item xmlns:ns2="urn:a">2</ns2:Item></Root>`,
+			Namespace: "urn:a",
+			Strict:    true,
+			Expect:    []string{"1", "2"},
+		},
+		"strict default namespace overridden": {
+			Document:  `<Root xmlns="urn:a"><Item xmlns="urn:b">1</Item></Root>`,
+			Namespace: "urn:a",
+			Strict:    true,
+			ExpectErr: true,
+		},
+		"strict no namespace": {
+			Document:  `<Root><Item>1</Item></Root>`,
+			Namespace: "urn:a",
+			Strict:    true,
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(c.Document))
+			if c.Strict {
+				d.StrictNamespaces()
+			}
+
+			items, err := decodeItems(d, c.Namespace)
+			if c.ExpectErr {
+				var mismatchErr *NamespaceMismatchError
+				if !errors.As(err, &mismatchErr) {
+					t.Fatalf("expect namespace mismatch error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, items; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v items, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestDecoderValue_ChildElement(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`<Root><Item><Nested/></Item></Root>`))
+	if _, _, err := d.Token(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, _, err := d.Token(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, err := d.Value(); err == nil {
+		t.Errorf("expect error for child element, got none")
+	}
+}
+
+// streamListResult streams the values of the root's Items list, wrapped or
+// flattened, to fn, and returns the root's NextToken.
+func streamListResult(d *Decoder, flattened bool, fn func(string)) (string, error) {
+	if _, _, err := d.Token(); err != nil {
+		return "", err
+	}
+
+	member := func(d *Decoder, el StartElement) error {
+		v, err := d.Value()
+		if err != nil {
+			return err
+		}
+		fn(v)
+		return nil
+	}
+
+	var token string
+	for {
+		el, done, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		if done {
+			return token, nil
+		}
+
+		switch el.Name.Local {
+		case "Items", "Item":
+			err = d.StreamList(el, "member", flattened, member)
+		case "NextToken":
+			token, err = d.Value()
+		default:
+			err = d.Skip()
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
+
+func listDocument(n int, flattened bool) string {
+	var sb strings.Builder
+	sb.WriteString("<Root>")
+	if !flattened {
+		sb.WriteString("<Items>")
+	}
+	for i := 0; i < n; i++ {
+		if flattened {
+			fmt.Fprintf(&sb, "<Item>%d</Item>", i)
+		} else {
+			fmt.Fprintf(&sb, "<member>%d</member><Other>skipped</Other>", i)
+		}
+	}
+	if !flattened {
+		sb.WriteString("</Items>")
+	}
+	sb.WriteString("<NextToken>next</NextToken></Root>")
+	return sb.String()
+}
+
+func TestDecoderStreamList(t *testing.T) {
+	const count = 1000
+
+	for _, flattened := range []bool{false, true} {
+		t.Run(fmt.Sprintf("flattened %t", flattened), func(t *testing.T) {
+			doc := listDocument(count, flattened)
+			r := &countingReader{r: strings.NewReader(doc)}
+
+			var n, readAtFirst int
+			token, err := streamListResult(NewDecoder(r), flattened, func(v string) {
+				if e, a := strconv.Itoa(n), v; e != a {
+					t.Errorf("expect member %v, got %v", e, a)
+				}
+				if n == 0 {
+					readAtFirst = r.n
+				}
+				n++
+			})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := count, n; e != a {
+				t.Errorf("expect %v members, got %v", e, a)
+			}
+			if e, a := "next", token; e != a {
+				t.Errorf("expect %v next token, got %v", e, a)
+			}
+			if readAtFirst >= len(doc) {
+				t.Errorf("expect first member streamed before document read, read %v of %v bytes",
+					readAtFirst, len(doc))
+			}
+
+			// Allocations per member must not grow with the size of the list,
+			// as they would if the members were buffered.
+			allocs := func(n int) float64 {
+				doc := listDocument(n, flattened)
+				return testing.AllocsPerRun(5, func() {
+					streamListResult(NewDecoder(strings.NewReader(doc)), flattened, func(string) {})
+				}) / float64(n)
+			}
+			if small, large := allocs(count/10), allocs(count); large > small*1.1 {
+				t.Errorf("expect constant allocations per member, got %.2f for %d, %.2f for %d",
+					small, count/10, large, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Package xml provides the value builder encoder for serializing shapes to XML
+documents, and the decoder for deserializing them, as described by the Smithy
+XML binding traits.
+
+The Encoder is started with a root element, and values are written by
+selecting the element to write using the Value type. Structures, lists, and
+maps are started with their respective builders, and must be closed once all
+members have been written.
+
+	encoder := xml.NewEncoder()
+	root := encoder.RootElement(xml.StartElement{Name: xml.Name{Local: "Root"}}).Struct()
+	root.MemberElement(xml.StartElement{Name: xml.Name{Local: "Name"}}).String("foo")
+	list := root.MemberElement(xml.StartElement{Name: xml.Name{Local: "Items"}}).Array(true)
+	list.Member().String("bar")
+	list.Close()
+	root.Close()
+
+Lists and maps annotated with the xmlFlattened trait are written as repeated
+sibling elements without a wrapping element. Map entries are written with
+elements named entry, key, and value, unless the map is started with
+MapWithCustomNames for members with the xmlName trait. Members with the
+xmlAttribute trait, and namespace declarations, are added to an element's
+start tag with Value's WithAttributes.
+
+The Decoder reads a document's elements one level at a time. Elements are
+matched to members by local name regardless of their namespace prefix, and
+StrictNamespaces requires members declaring a namespace to be in it.
+StreamList invokes a callback with each member of a wrapped or flattened list
+as it is read, so large list responses are not buffered before they are
+decoded.
+*/
+package xml
@@ -0,0 +1,57 @@
+package xml
+
+// Name is an XML element or attribute name, with an optional namespace
+// prefix.
+type Name struct {
+	Space, Local string
+}
+
+func (n Name) String() string {
+	if len(n.Space) == 0 {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// Attr is an attribute of an XML element.
+type Attr struct {
+	Name  Name
+	Value string
+}
+
+// NewAttribute returns an attribute for the name and value.
+func NewAttribute(local, value string) Attr {
+	return Attr{
+		Name:  Name{Local: local},
+		Value: value,
+	}
+}
+
+// NewNamespaceAttribute returns an xmlns attribute declaring the namespace
+// URI. If prefix is empty the attribute declares the default namespace.
+func NewNamespaceAttribute(prefix, uri string) Attr {
+	attr := Attr{
+		Name:  Name{Local: "xmlns"},
+		Value: uri,
+	}
+	if len(prefix) != 0 {
+		attr.Name = Name{Space: "xmlns", Local: prefix}
+	}
+	return attr
+}
+
+// StartElement is the start tag of an XML element.
+type StartElement struct {
+	Name Name
+	Attr []Attr
+}
+
+// End returns the end tag of the element.
+func (e StartElement) End() EndElement {
+	return EndElement{Name: e.Name}
+}
+
+// EndElement is the end tag of an XML element.
+type EndElement struct {
+	Name Name
+}
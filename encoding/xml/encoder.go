@@ -0,0 +1,226 @@
+package xml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math"
+	"strconv"
+)
+
+// Encoder is an XML encoder that writes elements to an underlying buffer.
+type Encoder struct {
+	w *bytes.Buffer
+}
+
+// NewEncoder returns an initialized XML encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		w: bytes.NewBuffer(nil),
+	}
+}
+
+// RootElement returns the Value for the root element of the XML document.
+func (e *Encoder) RootElement(element StartElement) Value {
+	return newValue(e.w, element)
+}
+
+// Bytes returns the encoded XML document.
+func (e *Encoder) Bytes() []byte {
+	return e.w.Bytes()
+}
+
+// String returns the encoded XML document as a string.
+func (e *Encoder) String() string {
+	return e.w.String()
+}
+
+// Value is an XML element which has not yet been written. The element is
+// written by calling one of the Value's methods to write its content.
+type Value struct {
+	w            *bytes.Buffer
+	startElement StartElement
+}
+
+func newValue(w *bytes.Buffer, element StartElement) Value {
+	return Value{
+		w:            w,
+		startElement: element,
+	}
+}
+
+// WithAttributes returns a copy of the Value with the attributes added to
+// the element's start tag, (e.g. members with the xmlAttribute trait, or
+// xmlns namespace declarations). Attribute values are escaped when written.
+func (v Value) WithAttributes(attrs ...Attr) Value {
+	element := v.startElement
+	element.Attr = make([]Attr, 0, len(v.startElement.Attr)+len(attrs))
+	element.Attr = append(element.Attr, v.startElement.Attr...)
+	element.Attr = append(element.Attr, attrs...)
+	v.startElement = element
+	return v
+}
+
+// String writes the element with the string as its text content.
+func (v Value) String(s string) {
+	v.writeStart()
+	escapeString(v.w, s)
+	v.writeEnd()
+}
+
+// Boolean writes the element with the boolean as its text content.
+func (v Value) Boolean(b bool) {
+	v.writeText(strconv.FormatBool(b))
+}
+
+// Byte writes the element with the integer as its text content.
+func (v Value) Byte(i int8) {
+	v.Long(int64(i))
+}
+
+// Short writes the element with the integer as its text content.
+func (v Value) Short(i int16) {
+	v.Long(int64(i))
+}
+
+// Integer writes the element with the integer as its text content.
+func (v Value) Integer(i int32) {
+	v.Long(int64(i))
+}
+
+// Long writes the element with the integer as its text content.
+func (v Value) Long(i int64) {
+	v.writeText(strconv.FormatInt(i, 10))
+}
+
+// Float writes the element with the float as its text content.
+func (v Value) Float(f float32) {
+	v.writeText(formatFloat(float64(f), 32))
+}
+
+// Double writes the element with the float as its text content.
+func (v Value) Double(f float64) {
+	v.writeText(formatFloat(f, 64))
+}
+
+// Base64EncodeBytes writes the element with the base64 encoded bytes as its
+// text content.
+func (v Value) Base64EncodeBytes(b []byte) {
+	v.writeText(base64.StdEncoding.EncodeToString(b))
+}
+
+// Struct starts the element as a structure, returning the builder for its
+// member elements. The structure must be closed once all members have been
+// written.
+func (v Value) Struct() *Struct {
+	v.writeStart()
+	return &Struct{
+		w:            v.w,
+		startElement: v.startElement,
+	}
+}
+
+// Array starts the element as a list whose members are written as elements
+// named "member". If flattened, the member elements are written as repeated
+// siblings named by the element itself, without a wrapping element. The list
+// must be closed once all members have been written.
+func (v Value) Array(flattened bool) *Array {
+	member := StartElement{Name: Name{Local: defaultArrayMemberName}}
+	if flattened {
+		member = v.startElement
+	}
+	return newArray(v, member, flattened)
+}
+
+// ArrayWithCustomName starts the element as a list whose members are written
+// as elements named by member, as set by the xmlName trait of the list's
+// member. If flattened, the member elements are written as repeated siblings
+// without a wrapping element. The list must be closed once all members have
+// been written.
+func (v Value) ArrayWithCustomName(member StartElement, flattened bool) *Array {
+	return newArray(v, member, flattened)
+}
+
+// Map starts the element as a map whose entries are written as elements
+// named "entry". If flattened, the entries are written as repeated siblings
+// named by the element itself, without a wrapping element. The map must be
+// closed once all entries have been written.
+func (v Value) Map(flattened bool) *Map {
+	entry := StartElement{Name: Name{Local: defaultMapEntryName}}
+	if flattened {
+		entry = v.startElement
+	}
+	return newMap(v, entry,
+		StartElement{Name: Name{Local: defaultMapKeyName}},
+		StartElement{Name: Name{Local: defaultMapValueName}},
+		flattened)
+}
+
+// MapWithCustomNames starts the element as a map whose entries are written as
+// elements named by entry, containing key and value elements named by key and
+// value, as set by the xmlName traits of the map's key and value members. If
+// flattened, the entries are written as repeated siblings without a wrapping
+// element. The map must be closed once all entries have been written.
+func (v Value) MapWithCustomNames(entry, key, value StartElement, flattened bool) *Map {
+	return newMap(v, entry, key, value, flattened)
+}
+
+func (v Value) writeText(s string) {
+	v.writeStart()
+	v.w.WriteString(s)
+	v.writeEnd()
+}
+
+func (v Value) writeStart() {
+	writeStartElement(v.w, v.startElement)
+}
+
+func (v Value) writeEnd() {
+	writeEndElement(v.w, v.startElement.End())
+}
+
+// Struct is the builder for the member elements of a structure.
+type Struct struct {
+	w            *bytes.Buffer
+	startElement StartElement
+}
+
+// MemberElement returns the Value for a member element of the structure.
+func (s *Struct) MemberElement(element StartElement) Value {
+	return newValue(s.w, element)
+}
+
+// Close writes the end tag of the structure.
+func (s *Struct) Close() {
+	writeEndElement(s.w, s.startElement.End())
+}
+
+func writeStartElement(w *bytes.Buffer, element StartElement) {
+	w.WriteByte('<')
+	w.WriteString(element.Name.String())
+	for _, attr := range element.Attr {
+		w.WriteByte(' ')
+		w.WriteString(attr.Name.String())
+		w.WriteString(`="`)
+		escapeString(w, attr.Value)
+		w.WriteByte('"')
+	}
+	w.WriteByte('>')
+}
+
+func writeEndElement(w *bytes.Buffer, element EndElement) {
+	w.WriteString("</")
+	w.WriteString(element.Name.String())
+	w.WriteByte('>')
+}
+
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(v, 'f', -1, bitSize)
+}
@@ -0,0 +1,302 @@
+package xml
+
+import (
+	stdxml "encoding/xml"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func newElement(local string) StartElement {
+	return StartElement{Name: Name{Local: local}}
+}
+
+func TestEncoderScalars(t *testing.T) {
+	encoder := NewEncoder()
+	root := encoder.RootElement(StartElement{
+		Name: Name{Local: "Root"},
+		Attr: []Attr{NewNamespaceAttribute("", "https://example.com/")},
+	}).Struct()
+	root.MemberElement(newElement("String")).String(`<a & "b">`)
+	root.MemberElement(newElement("Boolean")).Boolean(true)
+	root.MemberElement(newElement("Integer")).Integer(-123)
+	root.MemberElement(newElement("Long")).Long(math.MaxInt64)
+	root.MemberElement(newElement("Float")).Float(1.5)
+	root.MemberElement(newElement("Double")).Double(math.Inf(-1))
+	root.MemberElement(newElement("Blob")).Base64EncodeBytes([]byte("hello"))
+	root.Close()
+
+	expect := `<Root xmlns="https://example.com/">` +
+		`<String>&lt;a &amp; &quot;b&quot;&gt;</String>` +
+		`<Boolean>true</Boolean>` +
+		`<Integer>-123</Integer>` +
+		`<Long>9223372036854775807</Long>` +
+		`<Float>1.5</Float>` +
+		`<Double>-Infinity</Double>` +
+		`<Blob>aGVsbG8=</Blob>` +
+		`</Root>`
+	if e, a := expect, encoder.String(); e != a {
+		t.Errorf("expect\n%v\ngot\n%v", e, a)
+	}
+}
+
+func TestEncoderArray(t *testing.T) {
+	cases := map[string]struct {
+		Encode func(Value)
+		Expect string
+	}{
+		"wrapped": {
+			Encode: func(v Value) {
+				a := v.Array(false)
+				a.Member().String("a")
+				a.Member().String("b")
+				a.Close()
+			},
+			Expect: `<Root><Items><member>a</member><member>b</member></Items><After>x</After></Root>`,
+		},
+		"wrapped custom name": {
+			Encode: func(v Value) {
+				a := v.ArrayWithCustomName(newElement("Item"), false)
+				a.Member().String("a")
+				a.Member().String("b")
+				a.Close()
+			},
+			Expect: `<Root><Items><Item>a</Item><Item>b</Item></Items><After>x</After></Root>`,
+		},
+		"flattened": {
+			Encode: func(v Value) {
+				a := v.Array(true)
+				a.Member().String("a")
+				a.Member().String("b")
+				a.Close()
+			},
+			Expect: `<Root><Items>a</Items><Items>b</Items><After>x</After></Root>`,
+		},
+		"flattened custom name": {
+			Encode: func(v Value) {
+				a := v.ArrayWithCustomName(newElement("Item"), true)
+				a.Member().String("a")
+				a.Member().String("b")
+				a.Close()
+			},
+			Expect: `<Root><Item>a</Item><Item>b</Item><After>x</After></Root>`,
+		},
+		"flattened empty": {
+			Encode: func(v Value) {
+				v.Array(true).Close()
+			},
+			Expect: `<Root><After>x</After></Root>`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			encoder := NewEncoder()
+			root := encoder.RootElement(newElement("Root")).Struct()
+			c.Encode(root.MemberElement(newElement("Items")))
+			root.MemberElement(newElement("After")).String("x")
+			root.Close()
+
+			if e, a := c.Expect, encoder.String(); e != a {
+				t.Errorf("expect\n%v\ngot\n%v", e, a)
+			}
+		})
+	}
+}
+
+type roundTripEntry struct {
+	Key   string   `xml:"key"`
+	Value []string `xml:"value"`
+}
+
+type roundTripShape struct {
+	XMLName   stdxml.Name      `xml:"Shape"`
+	Wrapped   []string         `xml:"Wrapped>member"`
+	Flattened []string         `xml:"Flattened"`
+	Renamed   []int64          `xml:"Renamed"`
+	Map       []roundTripEntry `xml:"Map>entry"`
+	FlatMap   []roundTripEntry `xml:"FlatMap"`
+}
+
+func encodeRoundTripShape(shape roundTripShape) []byte {
+	encoder := NewEncoder()
+	root := encoder.RootElement(newElement("Shape")).Struct()
+
+	wrapped := root.MemberElement(newElement("Wrapped")).Array(false)
+	for _, v := range shape.Wrapped {
+		wrapped.Member().String(v)
+	}
+	wrapped.Close()
+
+	flattened := root.MemberElement(newElement("Flattened")).Array(true)
+	for _, v := range shape.Flattened {
+		flattened.Member().String(v)
+	}
+	flattened.Close()
+
+	renamed := root.MemberElement(newElement("Numbers")).ArrayWithCustomName(newElement("Renamed"), true)
+	for _, v := range shape.Renamed {
+		renamed.Member().Long(v)
+	}
+	renamed.Close()
+
+	encodeEntries := func(m *Map, entries []roundTripEntry) {
+		for _, entry := range entries {
+			e := m.Entry()
+			e.MemberElement(newElement("key")).String(entry.Key)
+			values := e.MemberElement(newElement("value")).Array(true)
+			for _, v := range entry.Value {
+				values.Member().String(v)
+			}
+			values.Close()
+			e.Close()
+		}
+		m.Close()
+	}
+	encodeEntries(root.MemberElement(newElement("Map")).Map(false), shape.Map)
+	encodeEntries(root.MemberElement(newElement("FlatMap")).Map(true), shape.FlatMap)
+
+	root.Close()
+	return encoder.Bytes()
+}
+
+func TestEncoderRoundTrip(t *testing.T) {
+	expect := roundTripShape{
+		XMLName:   stdxml.Name{Local: "Shape"},
+		Wrapped:   []string{"a", "b"},
+		Flattened: []string{"c", "d", "e"},
+		Renamed:   []int64{1, 2},
+		Map: []roundTripEntry{
+			{Key: "k1", Value: []string{"v1", "v2"}},
+			{Key: "k2", Value: []string{"v3"}},
+		},
+		FlatMap: []roundTripEntry{
+			{Key: "k3", Value: []string{"v4", "v5"}},
+		},
+	}
+
+	b := encodeRoundTripShape(expect)
+
+	var actual roundTripShape
+	if err := stdxml.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("expect no error, got %v\n%s", err, b)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v\n%s", expect, actual, b)
+	}
+
+	expectXML := `<Shape>` +
+		`<Wrapped><member>a</member><member>b</member></Wrapped>` +
+		`<Flattened>c</Flattened><Flattened>d</Flattened><Flattened>e</Flattened>` +
+		`<Renamed>1</Renamed><Renamed>2</Renamed>` +
+		`<Map>` +
+		`<entry><key>k1</key><value>v1</value><value>v2</value></entry>` +
+		`<entry><key>k2</key><value>v3</value></entry>` +
+		`</Map>` +
+		`<FlatMap><key>k3</key><value>v4</value><value>v5</value></FlatMap>` +
+		`</Shape>`
+	if e, a := expectXML, string(b); e != a {
+		t.Errorf("expect\n%v\ngot\n%v", e, a)
+	}
+}
+
+type customNameEntry struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Setting"`
+}
+
+type customNameMapShape struct {
+	XMLName stdxml.Name       `xml:"Shape"`
+	Map     []customNameEntry `xml:"Settings>Pair"`
+	FlatMap []customNameEntry `xml:"Item"`
+}
+
+func TestEncoderMapCustomNames(t *testing.T) {
+	expect := customNameMapShape{
+		XMLName: stdxml.Name{Local: "Shape"},
+		Map: []customNameEntry{
+			{Name: "k1", Value: "v1"},
+			{Name: "k2", Value: "v2"},
+		},
+		FlatMap: []customNameEntry{
+			{Name: "k3", Value: "v3"},
+			{Name: "k4", Value: "v4"},
+		},
+	}
+
+	encodeEntries := func(m *Map, entries []customNameEntry) {
+		for _, entry := range entries {
+			e := m.Entry()
+			e.Key().String(entry.Name)
+			e.Value().String(entry.Value)
+			e.Close()
+		}
+		m.Close()
+	}
+
+	encoder := NewEncoder()
+	root := encoder.RootElement(newElement("Shape")).Struct()
+	encodeEntries(root.MemberElement(newElement("Settings")).MapWithCustomNames(
+		newElement("Pair"), newElement("Name"), newElement("Setting"), false), expect.Map)
+	encodeEntries(root.MemberElement(newElement("FlatMap")).MapWithCustomNames(
+		newElement("Item"), newElement("Name"), newElement("Setting"), true), expect.FlatMap)
+	root.Close()
+
+	expectXML := `<Shape>` +
+		`<Settings>` +
+		`<Pair><Name>k1</Name><Setting>v1</Setting></Pair>` +
+		`<Pair><Name>k2</Name><Setting>v2</Setting></Pair>` +
+		`</Settings>` +
+		`<Item><Name>k3</Name><Setting>v3</Setting></Item>` +
+		`<Item><Name>k4</Name><Setting>v4</Setting></Item>` +
+		`</Shape>`
+	if e, a := expectXML, encoder.String(); e != a {
+		t.Errorf("expect\n%v\ngot\n%v", e, a)
+	}
+
+	var actual customNameMapShape
+	if err := stdxml.Unmarshal(encoder.Bytes(), &actual); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !reflect.DeepEqual(expect, actual) {
+		t.Errorf("expect %v, got %v", expect, actual)
+	}
+}
+
+func TestEncoderMapDefaultNames(t *testing.T) {
+	encoder := NewEncoder()
+	m := encoder.RootElement(newElement("Map")).Map(false)
+	e := m.Entry()
+	e.Key().String("k")
+	e.Value().String("v")
+	e.Close()
+	m.Close()
+
+	if e, a := `<Map><entry><key>k</key><value>v</value></entry></Map>`, encoder.String(); e != a {
+		t.Errorf("expect\n%v\ngot\n%v", e, a)
+	}
+}
+
+func TestEscapeString(t *testing.T) {
+	cases := map[string]struct {
+		Input  string
+		Expect string
+	}{
+		"plain":          {Input: "abc", Expect: "abc"},
+		"special":        {Input: `<&>"'`, Expect: "&lt;&amp;&gt;&quot;&apos;"},
+		"whitespace":     {Input: "a\tb\nc\r", Expect: "a&#x9;b&#xA;c&#xD;"},
+		"invalid char":   {Input: "a\x00b", Expect: "a\uFFFDb"},
+		"invalid utf8":   {Input: "a\xffb", Expect: "a\uFFFDb"},
+		"multibyte utf8": {Input: "日本", Expect: "日本"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			encoder := NewEncoder()
+			escapeString(encoder.w, c.Input)
+			if e, a := c.Expect, encoder.String(); e != a {
+				t.Errorf("expect %q, got %q", e, a)
+			}
+		})
+	}
+}
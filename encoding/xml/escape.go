@@ -0,0 +1,58 @@
+package xml
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// escapeString writes the string to the buffer with the XML special
+// characters escaped. Invalid UTF-8 and characters not permitted by XML are
+// replaced with the Unicode replacement character.
+func escapeString(w *bytes.Buffer, s string) {
+	last := 0
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		i += width
+
+		var esc string
+		switch r {
+		case '"':
+			esc = "&quot;"
+		case '\'':
+			esc = "&apos;"
+		case '&':
+			esc = "&amp;"
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '\t':
+			esc = "&#x9;"
+		case '\n':
+			esc = "&#xA;"
+		case '\r':
+			esc = "&#xD;"
+		default:
+			if !isInCharacterRange(r) || (r == utf8.RuneError && width == 1) {
+				esc = "\uFFFD"
+				break
+			}
+			continue
+		}
+
+		w.WriteString(s[last : i-width])
+		w.WriteString(esc)
+		last = i
+	}
+	w.WriteString(s[last:])
+}
+
+// isInCharacterRange returns if the rune is permitted in an XML document.
+func isInCharacterRange(r rune) bool {
+	return r == 0x09 ||
+		r == 0x0A ||
+		r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
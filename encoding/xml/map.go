@@ -0,0 +1,73 @@
+package xml
+
+import "bytes"
+
+const (
+	defaultMapEntryName = "entry"
+	defaultMapKeyName   = "key"
+	defaultMapValueName = "value"
+)
+
+// Map is the builder for the entry elements of a map. Each entry is a
+// structure containing the key and value elements of the entry.
+type Map struct {
+	w            *bytes.Buffer
+	startElement StartElement
+	entry        StartElement
+	key          StartElement
+	value        StartElement
+	flattened    bool
+}
+
+func newMap(v Value, entry, key, value StartElement, flattened bool) *Map {
+	if !flattened {
+		v.writeStart()
+	}
+	return &Map{
+		w:            v.w,
+		startElement: v.startElement,
+		entry:        entry,
+		key:          key,
+		value:        value,
+		flattened:    flattened,
+	}
+}
+
+// Entry starts the next entry element of the map, returning the builder for
+// the entry's key and value elements. The entry must be closed once its key
+// and value have been written.
+func (m *Map) Entry() *MapEntry {
+	return &MapEntry{
+		Struct: newValue(m.w, m.entry).Struct(),
+		key:    m.key,
+		value:  m.value,
+	}
+}
+
+// Close writes the end tag of the map's wrapping element. Closing a
+// flattened map writes nothing.
+func (m *Map) Close() {
+	if m.flattened {
+		return
+	}
+	writeEndElement(m.w, m.startElement.End())
+}
+
+// MapEntry is the builder for the key and value elements of a map entry.
+type MapEntry struct {
+	*Struct
+	key   StartElement
+	value StartElement
+}
+
+// Key returns the Value for the entry's key element, named "key" unless the
+// map was started with a custom key name.
+func (e *MapEntry) Key() Value {
+	return e.MemberElement(e.key)
+}
+
+// Value returns the Value for the entry's value element, named "value"
+// unless the map was started with a custom value name.
+func (e *MapEntry) Value() Value {
+	return e.MemberElement(e.value)
+}
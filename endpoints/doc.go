@@ -0,0 +1,8 @@
+/*
+Package endpoints provides the types for resolving the endpoint an operation's
+request is sent to, from the operation's endpoint parameters.
+
+The EndpointResolverV2 resolves an Endpoint, which is applied to the request
+by the transport/http package's ResolveEndpoint middleware.
+*/
+package endpoints
@@ -0,0 +1,45 @@
+package endpoints
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Endpoint properties of the signing name and region the request must be
+// signed with, if they differ from the client's.
+const (
+	SigningNameProperty   = "signingName"
+	SigningRegionProperty = "signingRegion"
+)
+
+// Endpoint is the endpoint resolved for an operation's request.
+type Endpoint struct {
+	// URI of the endpoint. The URI's path is the base path the operation's
+	// path is joined to.
+	URI url.URL
+
+	// Headers to merge into the request's headers.
+	Headers http.Header
+
+	// Properties of the endpoint, (e.g. the auth schemes and signing
+	// properties the request must be signed with).
+	Properties map[string]interface{}
+}
+
+// EndpointResolverV2 provides the interface for resolving the endpoint of
+// an operation from the operation's endpoint parameters.
+type EndpointResolverV2 interface {
+	ResolveEndpoint(ctx context.Context, params interface{}) (Endpoint, error)
+}
+
+// EndpointResolverV2Func wraps a function with the EndpointResolverV2
+// interface.
+type EndpointResolverV2Func func(ctx context.Context, params interface{}) (Endpoint, error)
+
+// ResolveEndpoint resolves the endpoint with the wrapped function.
+func (fn EndpointResolverV2Func) ResolveEndpoint(ctx context.Context, params interface{}) (Endpoint, error) {
+	return fn(ctx, params)
+}
+
+var _ EndpointResolverV2 = EndpointResolverV2Func(nil)
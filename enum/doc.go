@@ -0,0 +1,18 @@
+/*
+Package enum provides the helpers generated clients use to decode enum values
+returned by services, without failing on values added to the service after
+the client was generated.
+
+Generated string enums are defined as a named string type, with a Values
+method returning the known values. Decoding an enum value with Decode
+returns the value unchanged whether or not it is known, so values unknown to
+the client are preserved and round-trip back to the service as they were
+received:
+
+	v, ok := enum.Decode(raw, FooEnum("").Values())
+	if !ok {
+		logger.Logf(logging.Debug, "unknown FooEnum value %v", enum.UnknownEnumValue(v))
+	}
+	output.Foo = FooEnum(v)
+*/
+package enum
@@ -0,0 +1,27 @@
+package enum
+
+// UnknownEnumValue is an enum value returned by a service that is not one of
+// the values known to the client. The raw value is preserved unchanged.
+type UnknownEnumValue string
+
+// String returns the raw enum value.
+func (v UnknownEnumValue) String() string {
+	return string(v)
+}
+
+// Decode returns the known value matching raw, and true. If raw does not
+// match any of the known values, raw is returned unchanged, and false.
+func Decode(raw string, known []string) (string, bool) {
+	for _, v := range known {
+		if v == raw {
+			return v, true
+		}
+	}
+	return raw, false
+}
+
+// IsUnknown returns if raw is not one of the known values.
+func IsUnknown(raw string, known []string) bool {
+	_, ok := Decode(raw, known)
+	return !ok
+}
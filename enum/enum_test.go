@@ -0,0 +1,64 @@
+package enum
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	known := []string{"RED", "GREEN", "BLUE"}
+
+	cases := map[string]struct {
+		Raw         string
+		Expect      string
+		ExpectKnown bool
+	}{
+		"known value": {
+			Raw:         "GREEN",
+			Expect:      "GREEN",
+			ExpectKnown: true,
+		},
+		"unknown value": {
+			Raw:    "PURPLE",
+			Expect: "PURPLE",
+		},
+		"case differs": {
+			Raw:    "green",
+			Expect: "green",
+		},
+		"empty value": {
+			Raw:    "",
+			Expect: "",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, ok := Decode(c.Raw, known)
+			if e, a := c.Expect, v; e != a {
+				t.Errorf("expect %q value, got %q", e, a)
+			}
+			if e, a := c.ExpectKnown, ok; e != a {
+				t.Errorf("expect %v known, got %v", e, a)
+			}
+			if e, a := !c.ExpectKnown, IsUnknown(c.Raw, known); e != a {
+				t.Errorf("expect %v unknown, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestUnknownEnumValueRoundTrip(t *testing.T) {
+	type color string
+
+	raw := "ULTRAVIOLET"
+	v, ok := Decode(raw, []string{"RED"})
+	if ok {
+		t.Fatalf("expect value to be unknown")
+	}
+
+	decoded := color(v)
+	if e, a := raw, string(decoded); e != a {
+		t.Errorf("expect %q to round-trip, got %q", e, a)
+	}
+	if e, a := raw, UnknownEnumValue(v).String(); e != a {
+		t.Errorf("expect %q unknown value, got %q", e, a)
+	}
+}
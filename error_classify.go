@@ -0,0 +1,51 @@
+package smithy
+
+import "errors"
+
+// NotFoundErrorCodes is the set of API error codes IsNotFound classifies as
+// not found errors. Codes can be added for services that model their own not
+// found errors.
+var NotFoundErrorCodes = map[string]bool{
+	"NotFound":                  true,
+	"NotFoundException":         true,
+	"ResourceNotFoundException": true,
+	"NoSuchKey":                 true,
+	"NoSuchBucket":              true,
+	"NoSuchEntity":              true,
+}
+
+// ConflictErrorCodes is the set of API error codes IsConflict classifies as
+// conflict errors. Codes can be added for services that model their own
+// conflict errors.
+var ConflictErrorCodes = map[string]bool{
+	"Conflict":                     true,
+	"ConflictException":            true,
+	"ResourceConflictException":    true,
+	"ResourceInUseException":       true,
+	"ConditionalCheckFailed":       true,
+	"TransactionConflictException": true,
+}
+
+// IsNotFound returns if the error, or an error it wraps, is an API error with
+// a code in NotFoundErrorCodes, or an error response with the HTTP 404
+// status code.
+func IsNotFound(err error) bool {
+	return isErrorClass(err, NotFoundErrorCodes, 404)
+}
+
+// IsConflict returns if the error, or an error it wraps, is an API error with
+// a code in ConflictErrorCodes, or an error response with the HTTP 409 status
+// code.
+func IsConflict(err error) bool {
+	return isErrorClass(err, ConflictErrorCodes, 409)
+}
+
+func isErrorClass(err error, codes map[string]bool, statusCode int) bool {
+	var apiErr APIError
+	if errors.As(err, &apiErr) && codes[apiErr.ErrorCode()] {
+		return true
+	}
+
+	var statusErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &statusErr) && statusErr.HTTPStatusCode() == statusCode
+}
@@ -0,0 +1,62 @@
+package smithy
+
+import (
+	"fmt"
+	"testing"
+)
+
+type statusCodeError struct {
+	status int
+	err    error
+}
+
+func (e *statusCodeError) HTTPStatusCode() int { return e.status }
+func (e *statusCodeError) Unwrap() error       { return e.err }
+func (e *statusCodeError) Error() string       { return fmt.Sprintf("status %d, %v", e.status, e.err) }
+
+func TestIsNotFoundIsConflict(t *testing.T) {
+	wrap := func(err error) error {
+		return &OperationError{ServiceName: "FooService", OperationName: "GetFoo", Err: err}
+	}
+
+	cases := map[string]struct {
+		Err            error
+		ExpectNotFound bool
+		ExpectConflict bool
+	}{
+		"modeled not found code": {
+			Err:            wrap(&GenericAPIError{Code: "ResourceNotFoundException"}),
+			ExpectNotFound: true,
+		},
+		"modeled conflict code": {
+			Err:            wrap(&GenericAPIError{Code: "ConflictException"}),
+			ExpectConflict: true,
+		},
+		"404 fallback": {
+			Err:            wrap(&statusCodeError{status: 404, err: &GenericAPIError{Code: "UnknownError"}}),
+			ExpectNotFound: true,
+		},
+		"409 fallback": {
+			Err:            wrap(&statusCodeError{status: 409, err: &GenericAPIError{Code: "UnknownError"}}),
+			ExpectConflict: true,
+		},
+		"unrelated code and status": {
+			Err: wrap(&statusCodeError{status: 400, err: &GenericAPIError{Code: "ValidationException"}}),
+		},
+		"not an API error": {
+			Err: fmt.Errorf("connection reset"),
+		},
+		"nil": {},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.ExpectNotFound, IsNotFound(c.Err); e != a {
+				t.Errorf("expect not found %v, got %v", e, a)
+			}
+			if e, a := c.ExpectConflict, IsConflict(c.Err); e != a {
+				t.Errorf("expect conflict %v, got %v", e, a)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package smithy
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
 
 // APIError provides the generic API and protocol agnostic error type all SDK
 // generated exception types will implement.
@@ -15,6 +19,20 @@ type APIError interface {
 	ErrorFault() ErrorFault
 }
 
+// RetryableError provides the interface for errors that carry a hint whether
+// the operation which failed can be retried.
+type RetryableError interface {
+	// RetryableError returns if the error is retryable.
+	RetryableError() bool
+}
+
+// ThrottlingError provides the interface for errors that carry a hint whether
+// the operation failure was the result of the caller being throttled.
+type ThrottlingError interface {
+	// ThrottlingError returns if the error is the result of throttling.
+	ThrottlingError() bool
+}
+
 // GenericAPIError provides a generic concrete API error type that SDKs can use
 // to deserialize error responses into. Should be used for unmodeled or untyped
 // errors.
@@ -22,6 +40,19 @@ type GenericAPIError struct {
 	Code    string
 	Message string
 	Fault   ErrorFault
+
+	// Retryable is set by deserializers when the error response indicates
+	// the operation may be retried.
+	Retryable bool
+
+	// Throttling is set by deserializers when the error response indicates
+	// the caller was throttled.
+	Throttling bool
+
+	// BodyTruncated is set by deserializers when the error response body was
+	// larger than the maximum size read, and so the error was deserialized
+	// from a truncated body.
+	BodyTruncated bool
 }
 
 // ErrorCode returns the error code for the API exception.
@@ -33,11 +64,19 @@ func (e *GenericAPIError) ErrorMessage() string { return e.Message }
 // ErrorFault returns the fault for the API exception.
 func (e *GenericAPIError) ErrorFault() ErrorFault { return e.Fault }
 
+// RetryableError returns if the API error was marked as retryable.
+func (e *GenericAPIError) RetryableError() bool { return e.Retryable }
+
+// ThrottlingError returns if the API error was marked as throttling.
+func (e *GenericAPIError) ThrottlingError() bool { return e.Throttling }
+
 func (e *GenericAPIError) Error() string {
 	return fmt.Sprintf("api error %s: %s", e.Code, e.Message)
 }
 
 var _ APIError = (*GenericAPIError)(nil)
+var _ RetryableError = (*GenericAPIError)(nil)
+var _ ThrottlingError = (*GenericAPIError)(nil)
 
 // OperationError decorates an underlying error which occurred while invoking
 // an operation with names of the operation and API.
@@ -45,6 +84,10 @@ type OperationError struct {
 	ServiceName   string
 	OperationName string
 	Err           error
+
+	// RequestID is the ID of the request returned by the service, if any.
+	// When set, the request ID is included in the error's message.
+	RequestID string
 }
 
 // Service returns the name of the API service the error occurred with.
@@ -56,10 +99,89 @@ func (e *OperationError) Operation() string { return e.OperationName }
 // Unwrap returns the nested error if any, or nil.
 func (e *OperationError) Unwrap() error { return e.Err }
 
+// Error returns the error message formatted by the formatter set with
+// SetOperationErrorFormatter, or DefaultOperationErrorFormatter.
 func (e *OperationError) Error() string {
+	if fn, ok := operationErrorFormatter.Load().(func(*OperationError) string); ok && fn != nil {
+		return fn(e)
+	}
+	return DefaultOperationErrorFormatter(e)
+}
+
+// DefaultOperationErrorFormatter returns the default message of the
+// OperationError, (e.g. "operation error FooService: FooOperation, some
+// error"). The request ID is included after the operation name, if set.
+func DefaultOperationErrorFormatter(e *OperationError) string {
+	if len(e.RequestID) != 0 {
+		return fmt.Sprintf("operation error %s: %s, RequestID: %s, %v",
+			e.ServiceName, e.OperationName, e.RequestID, e.Err)
+	}
 	return fmt.Sprintf("operation error %s: %s, %v", e.ServiceName, e.OperationName, e.Err)
 }
 
+var operationErrorFormatter atomic.Value
+
+// SetOperationErrorFormatter sets the function formatting the message of
+// every OperationError, (e.g. as JSON for structured logs). If fn is nil,
+// DefaultOperationErrorFormatter is used. Safe to call concurrently with
+// errors being formatted, but should be set once, (e.g. in an init
+// function), so messages are formatted consistently.
+func SetOperationErrorFormatter(fn func(*OperationError) string) {
+	operationErrorFormatter.Store(fn)
+}
+
+// As provides support for errors.As to retrieve the deepest APIError wrapped
+// by the OperationError. Without this, errors.As would return the first
+// APIError found in the chain, which for nested errors, (e.g. retry wrapping
+// retry) may not be the root cause of the failure.
+//
+// Returns false if the target is not an *APIError, or the OperationError does
+// not wrap an APIError.
+func (e *OperationError) As(target interface{}) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+
+	var found APIError
+	for err := e.Err; err != nil; err = errors.Unwrap(err) {
+		if v, ok := err.(APIError); ok {
+			found = v
+		}
+	}
+	if found == nil {
+		return false
+	}
+
+	*t = found
+	return true
+}
+
+// AsOperationError walks the error's chain returning the outermost
+// OperationError found. Returns false if the chain does not contain an
+// OperationError.
+func AsOperationError(err error) (*OperationError, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if v, ok := err.(*OperationError); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// AsInnermostOperationError walks the error's chain returning the innermost
+// OperationError found. Returns false if the chain does not contain an
+// OperationError.
+func AsInnermostOperationError(err error) (*OperationError, bool) {
+	var found *OperationError
+	for ; err != nil; err = errors.Unwrap(err) {
+		if v, ok := err.(*OperationError); ok {
+			found = v
+		}
+	}
+	return found, found != nil
+}
+
 // ErrorFault provides the type for a Smithy API error fault.
 type ErrorFault int
 
@@ -80,3 +202,82 @@ func (f ErrorFault) String() string {
 		return "unknown"
 	}
 }
+
+// FaultFromHTTPStatusCode returns the fault implied by the HTTP status code
+// of an error response. 4xx status codes are client faults, 5xx status codes
+// are server faults, and all other status codes are unknown.
+func FaultFromHTTPStatusCode(code int) ErrorFault {
+	switch {
+	case code >= 400 && code < 500:
+		return FaultClient
+	case code >= 500 && code < 600:
+		return FaultServer
+	default:
+		return FaultUnknown
+	}
+}
+
+// MultiAPIError provides an aggregate of multiple API errors, (e.g. the
+// per-entry failures of a batch operation). The first error contained is used
+// for the MultiAPIError's APIError code, message, and fault.
+type MultiAPIError struct {
+	APIErrors []APIError
+}
+
+// Errors returns the API errors contained in the aggregate.
+func (e *MultiAPIError) Errors() []APIError { return e.APIErrors }
+
+// ErrorCode returns the error code of the first API error, or empty string if
+// there are no errors.
+func (e *MultiAPIError) ErrorCode() string {
+	if len(e.APIErrors) == 0 {
+		return ""
+	}
+	return e.APIErrors[0].ErrorCode()
+}
+
+// ErrorMessage returns the error message of the first API error, or empty
+// string if there are no errors.
+func (e *MultiAPIError) ErrorMessage() string {
+	if len(e.APIErrors) == 0 {
+		return ""
+	}
+	return e.APIErrors[0].ErrorMessage()
+}
+
+// ErrorFault returns the fault of the first API error, or FaultUnknown if
+// there are no errors.
+func (e *MultiAPIError) ErrorFault() ErrorFault {
+	if len(e.APIErrors) == 0 {
+		return FaultUnknown
+	}
+	return e.APIErrors[0].ErrorFault()
+}
+
+// FilterByFault returns the API errors contained in the aggregate whose fault
+// matches the fault provided.
+func (e *MultiAPIError) FilterByFault(fault ErrorFault) []APIError {
+	var filtered []APIError
+	for _, err := range e.APIErrors {
+		if err.ErrorFault() == fault {
+			filtered = append(filtered, err)
+		}
+	}
+	return filtered
+}
+
+// Unwrap returns the API errors contained in the aggregate. Allows errors.Is
+// and errors.As to match any of the contained errors with Go 1.20 and later.
+func (e *MultiAPIError) Unwrap() []error {
+	errs := make([]error, len(e.APIErrors))
+	for i, err := range e.APIErrors {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e *MultiAPIError) Error() string {
+	return fmt.Sprintf("api errors %d, first %s", len(e.APIErrors), e.ErrorCode())
+}
+
+var _ APIError = (*MultiAPIError)(nil)
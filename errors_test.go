@@ -0,0 +1,213 @@
+package smithy
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type wrappedAPIError struct {
+	*GenericAPIError
+	Err error
+}
+
+func (e *wrappedAPIError) Unwrap() error { return e.Err }
+
+func TestOperationErrorChain(t *testing.T) {
+	rootErr := &GenericAPIError{Code: "RootCause", Message: "root message"}
+
+	inner := &OperationError{
+		ServiceName:   "FooService",
+		OperationName: "Inner",
+		Err:           rootErr,
+	}
+	middle := &OperationError{
+		ServiceName:   "FooService",
+		OperationName: "Middle",
+		Err: &wrappedAPIError{
+			GenericAPIError: &GenericAPIError{Code: "MiddleCode"},
+			Err:             inner,
+		},
+	}
+	outer := &OperationError{
+		ServiceName:   "FooService",
+		OperationName: "Outer",
+		Err:           fmt.Errorf("retry failed, %w", middle),
+	}
+
+	var err error = fmt.Errorf("wrapped, %w", outer)
+
+	opErr, ok := AsOperationError(err)
+	if !ok {
+		t.Fatalf("expect operation error found")
+	}
+	if e, a := "Outer", opErr.Operation(); e != a {
+		t.Errorf("expect %v operation, got %v", e, a)
+	}
+
+	opErr, ok = AsInnermostOperationError(err)
+	if !ok {
+		t.Fatalf("expect innermost operation error found")
+	}
+	if e, a := "Inner", opErr.Operation(); e != a {
+		t.Errorf("expect %v operation, got %v", e, a)
+	}
+
+	var stdOpErr *OperationError
+	if !errors.As(err, &stdOpErr) {
+		t.Fatalf("expect errors.As to find operation error")
+	}
+	if e, a := "Outer", stdOpErr.Operation(); e != a {
+		t.Errorf("expect %v operation, got %v", e, a)
+	}
+
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expect errors.As to find API error")
+	}
+	if e, a := "RootCause", apiErr.ErrorCode(); e != a {
+		t.Errorf("expect %v code, got %v", e, a)
+	}
+}
+
+func TestOperationErrorNilErr(t *testing.T) {
+	err := &OperationError{
+		ServiceName:   "FooService",
+		OperationName: "FooOperation",
+	}
+
+	if v := err.Unwrap(); v != nil {
+		t.Errorf("expect no nested error, got %v", v)
+	}
+
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("expect no API error found, got %v", apiErr)
+	}
+
+	opErr, ok := AsInnermostOperationError(err)
+	if !ok || opErr != err {
+		t.Errorf("expect operation error to be found, got %v, %v", opErr, ok)
+	}
+
+	if _, ok := AsOperationError(nil); ok {
+		t.Errorf("expect no operation error for nil error")
+	}
+
+	if e, a := "operation error FooService: FooOperation, <nil>", err.Error(); e != a {
+		t.Errorf("expect %q error, got %q", e, a)
+	}
+}
+
+func TestOperationErrorRequestID(t *testing.T) {
+	err := &OperationError{
+		ServiceName:   "FooService",
+		OperationName: "FooOperation",
+		RequestID:     "abc-123",
+		Err:           fmt.Errorf("some error"),
+	}
+
+	if e, a := "operation error FooService: FooOperation, RequestID: abc-123, some error", err.Error(); e != a {
+		t.Errorf("expect %q error, got %q", e, a)
+	}
+}
+
+func TestSetOperationErrorFormatter(t *testing.T) {
+	defer SetOperationErrorFormatter(nil)
+
+	err := &OperationError{
+		ServiceName:   "FooService",
+		OperationName: "FooOperation",
+		RequestID:     "abc-123",
+		Err:           fmt.Errorf("some error"),
+	}
+
+	SetOperationErrorFormatter(func(e *OperationError) string {
+		return fmt.Sprintf(`{"service":%q,"operation":%q,"requestId":%q,"error":%q}`,
+			e.ServiceName, e.OperationName, e.RequestID, e.Err.Error())
+	})
+	expect := `{"service":"FooService","operation":"FooOperation","requestId":"abc-123","error":"some error"}`
+	if e, a := expect, err.Error(); e != a {
+		t.Errorf("expect %q error, got %q", e, a)
+	}
+
+	SetOperationErrorFormatter(nil)
+	if e, a := "operation error FooService: FooOperation, RequestID: abc-123, some error", err.Error(); e != a {
+		t.Errorf("expect %q error, got %q", e, a)
+	}
+}
+
+func TestMultiAPIError(t *testing.T) {
+	errFoo := &GenericAPIError{Code: "FooException", Fault: FaultClient}
+	errBar := &GenericAPIError{Code: "BarException", Fault: FaultServer}
+	errBaz := &GenericAPIError{Code: "BazException", Fault: FaultClient}
+
+	var err error = &MultiAPIError{
+		APIErrors: []APIError{errFoo, errBar},
+	}
+
+	if e, a := "api errors 2, first FooException", err.Error(); e != a {
+		t.Errorf("expect %q error, got %q", e, a)
+	}
+
+	if !errors.Is(err, errFoo) {
+		t.Errorf("expect %v to match", errFoo)
+	}
+	if !errors.Is(err, errBar) {
+		t.Errorf("expect %v to match", errBar)
+	}
+	if errors.Is(err, errBaz) {
+		t.Errorf("expect %v to not match", errBaz)
+	}
+
+	var multiErr *MultiAPIError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("expect multi API error")
+	}
+	if e, a := 2, len(multiErr.Errors()); e != a {
+		t.Errorf("expect %v errors, got %v", e, a)
+	}
+
+	serverErrs := multiErr.FilterByFault(FaultServer)
+	if e, a := 1, len(serverErrs); e != a {
+		t.Fatalf("expect %v server errors, got %v", e, a)
+	}
+	if e, a := "BarException", serverErrs[0].ErrorCode(); e != a {
+		t.Errorf("expect %v code, got %v", e, a)
+	}
+
+	empty := &MultiAPIError{}
+	if e, a := FaultUnknown, empty.ErrorFault(); e != a {
+		t.Errorf("expect %v fault, got %v", e, a)
+	}
+	if v := empty.ErrorCode(); len(v) != 0 {
+		t.Errorf("expect no code, got %v", v)
+	}
+}
+
+func TestFaultFromHTTPStatusCode(t *testing.T) {
+	cases := map[string]struct {
+		StatusCode int
+		Expect     ErrorFault
+	}{
+		"ok":                    {StatusCode: 200, Expect: FaultUnknown},
+		"redirect":              {StatusCode: 301, Expect: FaultUnknown},
+		"bad request":           {StatusCode: 400, Expect: FaultClient},
+		"not found":             {StatusCode: 404, Expect: FaultClient},
+		"too many requests":     {StatusCode: 429, Expect: FaultClient},
+		"client upper bound":    {StatusCode: 499, Expect: FaultClient},
+		"internal server error": {StatusCode: 500, Expect: FaultServer},
+		"service unavailable":   {StatusCode: 503, Expect: FaultServer},
+		"server upper bound":    {StatusCode: 599, Expect: FaultServer},
+		"out of range":          {StatusCode: 600, Expect: FaultUnknown},
+		"zero":                  {StatusCode: 0, Expect: FaultUnknown},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Expect, FaultFromHTTPStatusCode(c.StatusCode); e != a {
+				t.Errorf("expect %v fault, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Decoder provides decoding of event stream messages from binary frames.
+type Decoder struct {
+	frame bytes.Buffer
+}
+
+// NewDecoder returns an initialized event stream decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Decode reads the next message frame from the reader. Returns io.EOF if the
+// reader has no more frames, and io.ErrUnexpectedEOF if the reader ends
+// within a frame. Returns a ChecksumError if the frame's prelude or message
+// checksum is invalid.
+func (d *Decoder) Decode(r io.Reader) (Message, error) {
+	d.frame.Reset()
+
+	var prelude [preludeLen + preludeCRCLen]byte
+	if _, err := io.ReadFull(r, prelude[:]); err != nil {
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			err = fmt.Errorf("failed to read message prelude, %w", err)
+		}
+		return Message{}, err
+	}
+
+	if expect, actual := binary.BigEndian.Uint32(prelude[8:]), crc32.ChecksumIEEE(prelude[:preludeLen]); expect != actual {
+		return Message{}, &ChecksumError{Part: "prelude", Expect: expect, Actual: actual}
+	}
+
+	totalLen := binary.BigEndian.Uint32(prelude[0:])
+	headersLen := binary.BigEndian.Uint32(prelude[4:])
+	if headersLen > maxHeadersLen {
+		return Message{}, fmt.Errorf("message headers length %d exceeds maximum %d",
+			headersLen, maxHeadersLen)
+	}
+	if totalLen < minMessageLen+headersLen {
+		return Message{}, fmt.Errorf("message length %d too small for headers length %d",
+			totalLen, headersLen)
+	}
+	payloadLen := totalLen - minMessageLen - headersLen
+	if payloadLen > maxPayloadLen {
+		return Message{}, fmt.Errorf("message payload length %d exceeds maximum %d",
+			payloadLen, maxPayloadLen)
+	}
+
+	d.frame.Write(prelude[:])
+	if _, err := io.CopyN(&d.frame, r, int64(totalLen)-int64(len(prelude))); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Message{}, fmt.Errorf("failed to read message, %w", err)
+	}
+
+	frame := d.frame.Bytes()
+	crcOffset := len(frame) - messageCRCLen
+	if expect, actual := binary.BigEndian.Uint32(frame[crcOffset:]), crc32.ChecksumIEEE(frame[:crcOffset]); expect != actual {
+		return Message{}, &ChecksumError{Part: "message", Expect: expect, Actual: actual}
+	}
+
+	headersEnd := len(prelude) + int(headersLen)
+	headers, err := decodeHeaders(bytes.NewReader(frame[len(prelude):headersEnd]))
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to decode message headers, %w", err)
+	}
+
+	var payload []byte
+	if payloadLen > 0 {
+		payload = make([]byte, payloadLen)
+		copy(payload, frame[headersEnd:crcOffset])
+	}
+
+	return Message{
+		Headers: headers,
+		Payload: payload,
+	}, nil
+}
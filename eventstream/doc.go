@@ -0,0 +1,10 @@
+/*
+Package eventstream provides the encoder and decoder for the
+application/vnd.amazon.eventstream binary framing of messages sent over
+bidirectional event streams.
+
+Each message frame is made up of a prelude containing the total and headers
+lengths, followed by the prelude's CRC32 checksum, the message's headers,
+payload, and the CRC32 checksum of the whole message.
+*/
+package eventstream
@@ -0,0 +1,59 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Encoder provides encoding of event stream messages into binary frames.
+type Encoder struct {
+	headers bytes.Buffer
+	frame   bytes.Buffer
+}
+
+// NewEncoder returns an initialized event stream encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Encode writes the message's frame to the writer.
+func (e *Encoder) Encode(w io.Writer, msg Message) error {
+	e.headers.Reset()
+	e.frame.Reset()
+
+	if err := msg.Headers.encode(&e.headers); err != nil {
+		return fmt.Errorf("failed to encode message headers, %w", err)
+	}
+	if e.headers.Len() > maxHeadersLen {
+		return fmt.Errorf("message headers length %d exceeds maximum %d",
+			e.headers.Len(), maxHeadersLen)
+	}
+	if len(msg.Payload) > maxPayloadLen {
+		return fmt.Errorf("message payload length %d exceeds maximum %d",
+			len(msg.Payload), maxPayloadLen)
+	}
+
+	totalLen := minMessageLen + e.headers.Len() + len(msg.Payload)
+
+	var prelude [preludeLen + preludeCRCLen]byte
+	binary.BigEndian.PutUint32(prelude[0:], uint32(totalLen))
+	binary.BigEndian.PutUint32(prelude[4:], uint32(e.headers.Len()))
+	binary.BigEndian.PutUint32(prelude[8:], crc32.ChecksumIEEE(prelude[:preludeLen]))
+
+	e.frame.Grow(totalLen)
+	e.frame.Write(prelude[:])
+	e.frame.Write(e.headers.Bytes())
+	e.frame.Write(msg.Payload)
+
+	var crc [messageCRCLen]byte
+	binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(e.frame.Bytes()))
+	e.frame.Write(crc[:])
+
+	if _, err := w.Write(e.frame.Bytes()); err != nil {
+		return fmt.Errorf("failed to write message frame, %w", err)
+	}
+	return nil
+}
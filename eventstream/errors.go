@@ -0,0 +1,19 @@
+package eventstream
+
+import "fmt"
+
+// ChecksumError provides the error type for when the CRC32 checksum of a
+// message's prelude, or of the whole message, does not match the checksum
+// computed from the message frame.
+type ChecksumError struct {
+	// Part of the message the checksum covers, "prelude" or "message".
+	Part string
+
+	Expect uint32
+	Actual uint32
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("event stream %s checksum mismatch, expect %08x, got %08x",
+		e.Part, e.Expect, e.Actual)
+}
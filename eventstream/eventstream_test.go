@@ -0,0 +1,187 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.Replace(s, " ", "", -1))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return b
+}
+
+func TestDecodeCapturedFrames(t *testing.T) {
+	cases := map[string]struct {
+		Frame  string
+		Expect Message
+	}{
+		"empty message": {
+			Frame:  "00000010 00000000 05c248eb 7d98c8ff",
+			Expect: Message{},
+		},
+		"payload no headers": {
+			Frame: "0000001d 00000000 fd528c5a 7b27666f6f273a27626172277d c3653936",
+			Expect: Message{
+				Payload: []byte("{'foo':'bar'}"),
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			frame := decodeHex(t, c.Frame)
+
+			msg, err := NewDecoder().Decode(bytes.NewReader(frame))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, msg; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v message, got %v", e, a)
+			}
+
+			var buf bytes.Buffer
+			if err := NewEncoder().Encode(&buf, msg); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := frame, buf.Bytes(); !bytes.Equal(e, a) {
+				t.Errorf("expect frame %x, got %x", e, a)
+			}
+		})
+	}
+}
+
+func TestEncodeDecodeHeaders(t *testing.T) {
+	var headers Headers
+	headers.Set(":message-type", StringValue("event"))
+	headers.Set("true", BoolValue(true))
+	headers.Set("false", BoolValue(false))
+	headers.Set("int8", Int8Value(-8))
+	headers.Set("int16", Int16Value(-1600))
+	headers.Set("int32", Int32Value(0x20a0b0c))
+	headers.Set("int64", Int64Value(-0x1020304050607))
+	headers.Set("bytes", BytesValue([]byte{0x00, 0x01, 0xff}))
+	headers.Set("timestamp", TimestampValue(time.Unix(1600000000, 123*int64(time.Millisecond))))
+	headers.Set("uuid", UUIDValue{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15})
+
+	expect := Message{
+		Headers: headers,
+		Payload: []byte(`{"hello":"world"}`),
+	}
+
+	var buf bytes.Buffer
+	encoder := NewEncoder()
+	if err := encoder.Encode(&buf, expect); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := encoder.Encode(&buf, Message{Payload: []byte("second")}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	decoder := NewDecoder()
+	actual, err := decoder.Decode(&buf)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := expect.Payload, actual.Payload; !bytes.Equal(e, a) {
+		t.Errorf("expect %s payload, got %s", e, a)
+	}
+	if e, a := len(expect.Headers), len(actual.Headers); e != a {
+		t.Fatalf("expect %v headers, got %v", e, a)
+	}
+	for i, h := range expect.Headers {
+		a := actual.Headers[i]
+		if e, a := h.Name, a.Name; e != a {
+			t.Errorf("expect %v header name, got %v", e, a)
+		}
+		if e, a := h.Value.Type(), a.Value.Type(); e != a {
+			t.Errorf("expect %v header %v type, got %v", h.Name, e, a)
+		}
+		if e, a := h.Value.String(), a.Value.String(); e != a {
+			t.Errorf("expect %v header %v value, got %v", h.Name, e, a)
+		}
+	}
+	if v := actual.Headers.Get("int32"); v == nil || v.Get() != int32(0x20a0b0c) {
+		t.Errorf("expect int32 header value, got %v", v)
+	}
+
+	second, err := decoder.Decode(&buf)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "second", string(second.Payload); e != a {
+		t.Errorf("expect %v payload, got %v", e, a)
+	}
+
+	if _, err := decoder.Decode(&buf); err != io.EOF {
+		t.Errorf("expect EOF, got %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	const frame = "0000001d 00000000 fd528c5a 7b27666f6f273a27626172277d c3653936"
+
+	cases := map[string]struct {
+		Modify         func([]byte) []byte
+		ExpectChecksum string
+		ExpectErr      error
+	}{
+		"prelude checksum": {
+			Modify:         func(b []byte) []byte { b[8] ^= 0xff; return b },
+			ExpectChecksum: "prelude",
+		},
+		"message checksum": {
+			Modify:         func(b []byte) []byte { b[14] ^= 0xff; return b },
+			ExpectChecksum: "message",
+		},
+		"truncated prelude": {
+			Modify:    func(b []byte) []byte { return b[:6] },
+			ExpectErr: io.ErrUnexpectedEOF,
+		},
+		"truncated message": {
+			Modify:    func(b []byte) []byte { return b[:20] },
+			ExpectErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := c.Modify(decodeHex(t, frame))
+
+			_, err := NewDecoder().Decode(bytes.NewReader(b))
+			if err == nil {
+				t.Fatalf("expect error, got none")
+			}
+
+			if len(c.ExpectChecksum) != 0 {
+				var checksumErr *ChecksumError
+				if !errors.As(err, &checksumErr) {
+					t.Fatalf("expect checksum error, got %v", err)
+				}
+				if e, a := c.ExpectChecksum, checksumErr.Part; e != a {
+					t.Errorf("expect %v checksum part, got %v", e, a)
+				}
+			}
+			if c.ExpectErr != nil && !errors.Is(err, c.ExpectErr) {
+				t.Errorf("expect %v error, got %v", c.ExpectErr, err)
+			}
+		})
+	}
+}
+
+func TestEncodeInvalidHeader(t *testing.T) {
+	msg := Message{Headers: Headers{{Name: "", Value: BoolValue(true)}}}
+	if err := NewEncoder().Encode(ioutil.Discard, msg); err == nil {
+		t.Errorf("expect error for empty header name, got none")
+	}
+}
@@ -0,0 +1,346 @@
+package eventstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ValueType is the wire type of a header value.
+type ValueType uint8
+
+// Enumeration values of the header value types.
+const (
+	TrueValueType ValueType = iota
+	FalseValueType
+	Int8ValueType
+	Int16ValueType
+	Int32ValueType
+	Int64ValueType
+	BytesValueType
+	StringValueType
+	TimestampValueType
+	UUIDValueType
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case TrueValueType, FalseValueType:
+		return "bool"
+	case Int8ValueType:
+		return "int8"
+	case Int16ValueType:
+		return "int16"
+	case Int32ValueType:
+		return "int32"
+	case Int64ValueType:
+		return "int64"
+	case BytesValueType:
+		return "byte_array"
+	case StringValueType:
+		return "string"
+	case TimestampValueType:
+		return "timestamp"
+	case UUIDValueType:
+		return "uuid"
+	default:
+		return fmt.Sprintf("unknown value type %d", uint8(t))
+	}
+}
+
+// Value is a header value of one of the supported header value types.
+type Value interface {
+	// Type returns the wire type of the value.
+	Type() ValueType
+
+	// Get returns the Go value of the header value.
+	Get() interface{}
+
+	String() string
+
+	encode(w *bytes.Buffer) error
+}
+
+// BoolValue is a boolean header value.
+type BoolValue bool
+
+// Type returns the wire type of the value.
+func (v BoolValue) Type() ValueType {
+	if v {
+		return TrueValueType
+	}
+	return FalseValueType
+}
+
+// Get returns the value as a bool.
+func (v BoolValue) Get() interface{} { return bool(v) }
+
+func (v BoolValue) String() string { return strconv.FormatBool(bool(v)) }
+
+func (v BoolValue) encode(w *bytes.Buffer) error {
+	return w.WriteByte(byte(v.Type()))
+}
+
+// Int8Value is a single byte header value.
+type Int8Value int8
+
+// Type returns the wire type of the value.
+func (v Int8Value) Type() ValueType { return Int8ValueType }
+
+// Get returns the value as an int8.
+func (v Int8Value) Get() interface{} { return int8(v) }
+
+func (v Int8Value) String() string { return strconv.FormatInt(int64(v), 10) }
+
+func (v Int8Value) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	return w.WriteByte(byte(v))
+}
+
+// Int16Value is a two byte integer header value.
+type Int16Value int16
+
+// Type returns the wire type of the value.
+func (v Int16Value) Type() ValueType { return Int16ValueType }
+
+// Get returns the value as an int16.
+func (v Int16Value) Get() interface{} { return int16(v) }
+
+func (v Int16Value) String() string { return strconv.FormatInt(int64(v), 10) }
+
+func (v Int16Value) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	return binary.Write(w, binary.BigEndian, int16(v))
+}
+
+// Int32Value is a four byte integer header value.
+type Int32Value int32
+
+// Type returns the wire type of the value.
+func (v Int32Value) Type() ValueType { return Int32ValueType }
+
+// Get returns the value as an int32.
+func (v Int32Value) Get() interface{} { return int32(v) }
+
+func (v Int32Value) String() string { return strconv.FormatInt(int64(v), 10) }
+
+func (v Int32Value) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	return binary.Write(w, binary.BigEndian, int32(v))
+}
+
+// Int64Value is an eight byte integer header value.
+type Int64Value int64
+
+// Type returns the wire type of the value.
+func (v Int64Value) Type() ValueType { return Int64ValueType }
+
+// Get returns the value as an int64.
+func (v Int64Value) Get() interface{} { return int64(v) }
+
+func (v Int64Value) String() string { return strconv.FormatInt(int64(v), 10) }
+
+func (v Int64Value) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	return binary.Write(w, binary.BigEndian, int64(v))
+}
+
+// BytesValue is a byte array header value.
+type BytesValue []byte
+
+// Type returns the wire type of the value.
+func (v BytesValue) Type() ValueType { return BytesValueType }
+
+// Get returns the value as a []byte.
+func (v BytesValue) Get() interface{} { return []byte(v) }
+
+func (v BytesValue) String() string { return hex.EncodeToString(v) }
+
+func (v BytesValue) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	return writeBytesWithLen(w, v)
+}
+
+// StringValue is a UTF-8 string header value.
+type StringValue string
+
+// Type returns the wire type of the value.
+func (v StringValue) Type() ValueType { return StringValueType }
+
+// Get returns the value as a string.
+func (v StringValue) Get() interface{} { return string(v) }
+
+func (v StringValue) String() string { return string(v) }
+
+func (v StringValue) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	return writeBytesWithLen(w, []byte(v))
+}
+
+// TimestampValue is a timestamp header value, encoded with millisecond
+// precision.
+type TimestampValue time.Time
+
+// Type returns the wire type of the value.
+func (v TimestampValue) Type() ValueType { return TimestampValueType }
+
+// Get returns the value as a time.Time.
+func (v TimestampValue) Get() interface{} { return time.Time(v) }
+
+func (v TimestampValue) String() string {
+	return time.Time(v).UTC().Format(time.RFC3339Nano)
+}
+
+func (v TimestampValue) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	ms := time.Time(v).UnixNano() / int64(time.Millisecond)
+	return binary.Write(w, binary.BigEndian, ms)
+}
+
+// UUIDValue is a 16 byte UUID header value.
+type UUIDValue [16]byte
+
+// Type returns the wire type of the value.
+func (v UUIDValue) Type() ValueType { return UUIDValueType }
+
+// Get returns the value as a [16]byte.
+func (v UUIDValue) Get() interface{} { return [16]byte(v) }
+
+func (v UUIDValue) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:])
+}
+
+func (v UUIDValue) encode(w *bytes.Buffer) error {
+	w.WriteByte(byte(v.Type()))
+	_, err := w.Write(v[:])
+	return err
+}
+
+func writeBytesWithLen(w *bytes.Buffer, b []byte) error {
+	if len(b) > 0xffff {
+		return fmt.Errorf("header value length %d exceeds maximum %d", len(b), 0xffff)
+	}
+	binary.Write(w, binary.BigEndian, uint16(len(b)))
+	_, err := w.Write(b)
+	return err
+}
+
+func encodeHeader(w *bytes.Buffer, h Header) error {
+	if len(h.Name) == 0 || len(h.Name) > maxHeaderNameLen {
+		return fmt.Errorf("header name length %d, must be between 1 and %d",
+			len(h.Name), maxHeaderNameLen)
+	}
+	if h.Value == nil {
+		return fmt.Errorf("header %s has no value", h.Name)
+	}
+
+	w.WriteByte(byte(len(h.Name)))
+	w.WriteString(h.Name)
+	return h.Value.encode(w)
+}
+
+func decodeHeaders(r *bytes.Reader) (Headers, error) {
+	var hs Headers
+	for r.Len() > 0 {
+		h, err := decodeHeader(r)
+		if err != nil {
+			return nil, err
+		}
+		hs = append(hs, h)
+	}
+	return hs, nil
+}
+
+func decodeHeader(r *bytes.Reader) (Header, error) {
+	nameLen, err := r.ReadByte()
+	if err != nil {
+		return Header{}, fmt.Errorf("failed to read header name length, %w", io.ErrUnexpectedEOF)
+	}
+	if nameLen == 0 {
+		return Header{}, fmt.Errorf("invalid empty header name")
+	}
+	name := make([]byte, nameLen)
+	if _, err := io.ReadFull(r, name); err != nil {
+		return Header{}, fmt.Errorf("failed to read header name, %w", io.ErrUnexpectedEOF)
+	}
+
+	value, err := decodeHeaderValue(r)
+	if err != nil {
+		return Header{}, fmt.Errorf("failed to decode header %s value, %w", name, err)
+	}
+
+	return Header{Name: string(name), Value: value}, nil
+}
+
+func decodeHeaderValue(r *bytes.Reader) (Value, error) {
+	t, err := r.ReadByte()
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	readInt := func(v interface{}) error {
+		if err := binary.Read(r, binary.BigEndian, v); err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	}
+
+	switch ValueType(t) {
+	case TrueValueType:
+		return BoolValue(true), nil
+	case FalseValueType:
+		return BoolValue(false), nil
+	case Int8ValueType:
+		var v int8
+		err := readInt(&v)
+		return Int8Value(v), err
+	case Int16ValueType:
+		var v int16
+		err := readInt(&v)
+		return Int16Value(v), err
+	case Int32ValueType:
+		var v int32
+		err := readInt(&v)
+		return Int32Value(v), err
+	case Int64ValueType:
+		var v int64
+		err := readInt(&v)
+		return Int64Value(v), err
+	case BytesValueType:
+		b, err := readBytesWithLen(r)
+		return BytesValue(b), err
+	case StringValueType:
+		b, err := readBytesWithLen(r)
+		return StringValue(b), err
+	case TimestampValueType:
+		var ms int64
+		if err := readInt(&ms); err != nil {
+			return nil, err
+		}
+		return TimestampValue(time.Unix(0, ms*int64(time.Millisecond))), nil
+	case UUIDValueType:
+		var v UUIDValue
+		if _, err := io.ReadFull(r, v[:]); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unknown header value type %d", t)
+	}
+}
+
+func readBytesWithLen(r *bytes.Reader) ([]byte, error) {
+	var n uint16
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return b, nil
+}
@@ -0,0 +1,70 @@
+package eventstream
+
+import "bytes"
+
+const (
+	preludeLen       = 8
+	preludeCRCLen    = 4
+	messageCRCLen    = 4
+	minMessageLen    = preludeLen + preludeCRCLen + messageCRCLen
+	maxPayloadLen    = 1024 * 1024 * 16
+	maxHeadersLen    = 1024 * 128
+	maxHeaderNameLen = 255
+)
+
+// Message is an event stream message, made up of headers and a payload.
+type Message struct {
+	Headers Headers
+	Payload []byte
+}
+
+// Header is a named header value of a message.
+type Header struct {
+	Name  string
+	Value Value
+}
+
+// Headers is the ordered list of headers of a message.
+type Headers []Header
+
+// Set sets the header's value, replacing the value of an existing header
+// with the same name.
+func (hs *Headers) Set(name string, value Value) {
+	for i := range *hs {
+		if (*hs)[i].Name == name {
+			(*hs)[i].Value = value
+			return
+		}
+	}
+	*hs = append(*hs, Header{Name: name, Value: value})
+}
+
+// Get returns the value of the header with the name, or nil if the header
+// is not present.
+func (hs Headers) Get(name string) Value {
+	for _, h := range hs {
+		if h.Name == name {
+			return h.Value
+		}
+	}
+	return nil
+}
+
+// Del removes the header with the name.
+func (hs *Headers) Del(name string) {
+	for i := 0; i < len(*hs); i++ {
+		if (*hs)[i].Name == name {
+			*hs = append((*hs)[:i], (*hs)[i+1:]...)
+			i--
+		}
+	}
+}
+
+func (hs Headers) encode(w *bytes.Buffer) error {
+	for _, h := range hs {
+		if err := encodeHeader(w, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
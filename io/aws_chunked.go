@@ -0,0 +1,162 @@
+package io
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+)
+
+// DefaultAWSChunkSize is the default size of the chunks an aws-chunked
+// payload is framed into.
+const DefaultAWSChunkSize = 64 * 1024
+
+// ChunkTrailer is a trailing header sent after the final chunk of an
+// aws-chunked payload, (e.g. the payload's checksum).
+type ChunkTrailer struct {
+	Name  string
+	Value string
+}
+
+// ChunkSigner provides the signatures of an aws-chunked payload's chunks and
+// trailing headers. Chunks are signed in order, and the final chunk is empty.
+// Signatures are typically chained, with each chunk's signature derived from
+// the previous, starting from the request's seed signature.
+type ChunkSigner interface {
+	SignChunk(chunk []byte) (string, error)
+	SignTrailer(trailers []ChunkTrailer) (string, error)
+}
+
+// AWSChunkedReaderOptions are the options of an AWSChunkedReader.
+type AWSChunkedReaderOptions struct {
+	// Size of the payload's chunks. Defaults to DefaultAWSChunkSize.
+	ChunkSize int
+
+	// Signer of the chunks and trailers. If nil, the payload is framed as
+	// unsigned chunks.
+	Signer ChunkSigner
+
+	// Returns the trailing headers to send after the final chunk. Called
+	// once the payload has been read to the end.
+	Trailers func() ([]ChunkTrailer, error)
+}
+
+// AWSChunkedReader frames the bytes read from the underlying payload with
+// the aws-chunked content encoding. Each chunk is prefixed with its size in
+// hex, and its signature if signed, followed by an empty final chunk, and the
+// trailing headers.
+//
+//	<hex-size>;chunk-signature=<signature>\r\n<data>\r\n
+//	0;chunk-signature=<signature>\r\n
+//	<trailer-name>:<trailer-value>\r\n
+//	x-amz-trailer-signature:<signature>\r\n
+//	\r\n
+type AWSChunkedReader struct {
+	r       io.Reader
+	options AWSChunkedReaderOptions
+
+	chunk []byte
+	buf   bytes.Buffer
+	done  bool
+	err   error
+}
+
+// NewAWSChunkedReader returns an AWSChunkedReader framing the payload r.
+func NewAWSChunkedReader(r io.Reader, optFns ...func(*AWSChunkedReaderOptions)) *AWSChunkedReader {
+	options := AWSChunkedReaderOptions{
+		ChunkSize: DefaultAWSChunkSize,
+	}
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = DefaultAWSChunkSize
+	}
+
+	return &AWSChunkedReader{
+		r:       r,
+		options: options,
+		chunk:   make([]byte, options.ChunkSize),
+	}
+}
+
+// Read reads the framed chunks of the payload.
+func (c *AWSChunkedReader) Read(p []byte) (int, error) {
+	for c.buf.Len() == 0 && !c.done && c.err == nil {
+		n, err := io.ReadFull(c.r, c.chunk)
+		if n > 0 {
+			if ferr := c.writeChunk(c.chunk[:n]); ferr != nil {
+				c.err = ferr
+				break
+			}
+		}
+
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			c.done = true
+			c.err = c.writeFinalChunk()
+		default:
+			c.err = err
+		}
+	}
+
+	if c.buf.Len() != 0 {
+		return c.buf.Read(p)
+	}
+	if c.err != nil {
+		return 0, c.err
+	}
+	return 0, io.EOF
+}
+
+// writeChunk writes the chunk's header, data, and terminating CRLF.
+func (c *AWSChunkedReader) writeChunk(chunk []byte) error {
+	c.buf.WriteString(strconv.FormatInt(int64(len(chunk)), 16))
+	if c.options.Signer != nil {
+		sig, err := c.options.Signer.SignChunk(chunk)
+		if err != nil {
+			return err
+		}
+		c.buf.WriteString(";chunk-signature=")
+		c.buf.WriteString(sig)
+	}
+	c.buf.WriteString("\r\n")
+	if len(chunk) != 0 {
+		c.buf.Write(chunk)
+		c.buf.WriteString("\r\n")
+	}
+	return nil
+}
+
+// writeFinalChunk writes the empty final chunk, followed by the trailing
+// headers.
+func (c *AWSChunkedReader) writeFinalChunk() error {
+	if err := c.writeChunk(nil); err != nil {
+		return err
+	}
+
+	var trailers []ChunkTrailer
+	if c.options.Trailers != nil {
+		var err error
+		if trailers, err = c.options.Trailers(); err != nil {
+			return err
+		}
+	}
+	for _, t := range trailers {
+		c.buf.WriteString(t.Name)
+		c.buf.WriteByte(':')
+		c.buf.WriteString(t.Value)
+		c.buf.WriteString("\r\n")
+	}
+	if len(trailers) != 0 && c.options.Signer != nil {
+		sig, err := c.options.Signer.SignTrailer(trailers)
+		if err != nil {
+			return err
+		}
+		c.buf.WriteString("x-amz-trailer-signature:")
+		c.buf.WriteString(sig)
+		c.buf.WriteString("\r\n")
+	}
+	c.buf.WriteString("\r\n")
+	return nil
+}
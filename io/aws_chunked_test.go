@@ -0,0 +1,107 @@
+package io
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type mockChunkSigner struct {
+	chunks   []string
+	trailers [][]ChunkTrailer
+}
+
+func (s *mockChunkSigner) SignChunk(chunk []byte) (string, error) {
+	s.chunks = append(s.chunks, string(chunk))
+	return fmt.Sprintf("sig%d", len(s.chunks)), nil
+}
+
+func (s *mockChunkSigner) SignTrailer(trailers []ChunkTrailer) (string, error) {
+	s.trailers = append(s.trailers, trailers)
+	return "trailersig", nil
+}
+
+func TestAWSChunkedReader(t *testing.T) {
+	trailers := func() ([]ChunkTrailer, error) {
+		return []ChunkTrailer{{Name: "x-amz-checksum-crc32", Value: "AAAAAA=="}}, nil
+	}
+
+	cases := map[string]struct {
+		Payload  string
+		Signed   bool
+		Trailers func() ([]ChunkTrailer, error)
+		Expect   string
+	}{
+		"unsigned multi-chunk": {
+			Payload: "abcdefghij",
+			Expect:  "4\r\nabcd\r\n4\r\nefgh\r\n2\r\nij\r\n0\r\n\r\n",
+		},
+		"unsigned aligned to chunk size": {
+			Payload: "abcdefgh",
+			Expect:  "4\r\nabcd\r\n4\r\nefgh\r\n0\r\n\r\n",
+		},
+		"unsigned with trailer": {
+			Payload:  "abcdef",
+			Trailers: trailers,
+			Expect:   "4\r\nabcd\r\n2\r\nef\r\n0\r\nx-amz-checksum-crc32:AAAAAA==\r\n\r\n",
+		},
+		"signed multi-chunk with trailer": {
+			Payload:  "abcdefghij",
+			Signed:   true,
+			Trailers: trailers,
+			Expect: "4;chunk-signature=sig1\r\nabcd\r\n" +
+				"4;chunk-signature=sig2\r\nefgh\r\n" +
+				"2;chunk-signature=sig3\r\nij\r\n" +
+				"0;chunk-signature=sig4\r\n" +
+				"x-amz-checksum-crc32:AAAAAA==\r\n" +
+				"x-amz-trailer-signature:trailersig\r\n" +
+				"\r\n",
+		},
+		"signed empty payload": {
+			Signed: true,
+			Expect: "0;chunk-signature=sig1\r\n\r\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			signer := &mockChunkSigner{}
+			r := NewAWSChunkedReader(strings.NewReader(c.Payload), func(o *AWSChunkedReaderOptions) {
+				o.ChunkSize = 4
+				o.Trailers = c.Trailers
+				if c.Signed {
+					o.Signer = signer
+				}
+			})
+
+			actual, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, string(actual); e != a {
+				t.Errorf("expect %q, got %q", e, a)
+			}
+
+			if c.Signed {
+				if e, a := "", signer.chunks[len(signer.chunks)-1]; e != a {
+					t.Errorf("expect final chunk signed empty, got %q", a)
+				}
+				if e, a := c.Trailers != nil, len(signer.trailers) == 1; e != a {
+					t.Errorf("expect trailer signed %v, got %v", e, a)
+				}
+			}
+		})
+	}
+}
+
+func TestAWSChunkedReader_TrailerError(t *testing.T) {
+	expectErr := fmt.Errorf("checksum failed")
+	r := NewAWSChunkedReader(strings.NewReader("abc"), func(o *AWSChunkedReaderOptions) {
+		o.Trailers = func() ([]ChunkTrailer, error) { return nil, expectErr }
+	})
+
+	if _, err := ioutil.ReadAll(r); err != expectErr {
+		t.Errorf("expect %v error, got %v", expectErr, err)
+	}
+}
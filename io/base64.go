@@ -0,0 +1,77 @@
+package io
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+)
+
+// base64EncodeChunkSize is the number of bytes read from the underlying
+// reader for each chunk encoded, a multiple of 3 so that chunks do not
+// require padding.
+const base64EncodeChunkSize = 3 * 1024
+
+// Base64EncodeReader returns a reader that base64 encodes the bytes read
+// from r with the standard padded encoding. The input is encoded as it is
+// read, and is not buffered in full.
+func Base64EncodeReader(r io.Reader) io.Reader {
+	return newBase64EncodeReader(base64.StdEncoding, r)
+}
+
+// Base64DecodeReader returns a reader that decodes the standard padded
+// base64 encoded bytes read from r.
+func Base64DecodeReader(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.StdEncoding, r)
+}
+
+// Base64URLEncodeReader returns a reader that base64url encodes the bytes
+// read from r with the padded URL and filename safe encoding.
+func Base64URLEncodeReader(r io.Reader) io.Reader {
+	return newBase64EncodeReader(base64.URLEncoding, r)
+}
+
+// Base64URLDecodeReader returns a reader that decodes the padded base64url
+// encoded bytes read from r.
+func Base64URLDecodeReader(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.URLEncoding, r)
+}
+
+// base64EncodeReader encodes the underlying reader a chunk at a time,
+// buffering only the encoded chunk not yet read.
+type base64EncodeReader struct {
+	r     io.Reader
+	enc   io.WriteCloser
+	buf   bytes.Buffer
+	chunk []byte
+	err   error
+}
+
+func newBase64EncodeReader(encoding *base64.Encoding, r io.Reader) *base64EncodeReader {
+	e := &base64EncodeReader{
+		r:     r,
+		chunk: make([]byte, base64EncodeChunkSize),
+	}
+	e.enc = base64.NewEncoder(encoding, &e.buf)
+	return e
+}
+
+// Read reads the base64 encoded bytes of the underlying reader.
+func (e *base64EncodeReader) Read(p []byte) (int, error) {
+	for e.buf.Len() == 0 && e.err == nil {
+		n, err := e.r.Read(e.chunk)
+		if n > 0 {
+			// Writes to the buffer do not fail.
+			e.enc.Write(e.chunk[:n])
+		}
+		if err == io.EOF {
+			// Flush the final partial block, with its padding.
+			e.enc.Close()
+		}
+		e.err = err
+	}
+
+	if e.buf.Len() != 0 {
+		return e.buf.Read(p)
+	}
+	return 0, e.err
+}
@@ -0,0 +1,78 @@
+package io
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBase64EncodeReader(t *testing.T) {
+	cases := map[string]struct {
+		Input string
+	}{
+		"empty":        {},
+		"one byte":     {Input: "a"},
+		"two bytes":    {Input: "ab"},
+		"aligned":      {Input: "abc"},
+		"unaligned":    {Input: "abcdefg"},
+		"over a chunk": {Input: strings.Repeat("abcdefgh", base64EncodeChunkSize/4)},
+		"binary":       {Input: "\xff\xfe\xfd\x00\x01"},
+	}
+
+	encodings := map[string]struct {
+		Encoding *base64.Encoding
+		Encode   func(io.Reader) io.Reader
+		Decode   func(io.Reader) io.Reader
+	}{
+		"base64": {
+			Encoding: base64.StdEncoding,
+			Encode:   Base64EncodeReader,
+			Decode:   Base64DecodeReader,
+		},
+		"base64url": {
+			Encoding: base64.URLEncoding,
+			Encode:   Base64URLEncodeReader,
+			Decode:   Base64URLDecodeReader,
+		},
+	}
+
+	for encName, enc := range encodings {
+		for name, c := range cases {
+			t.Run(encName+" "+name, func(t *testing.T) {
+				// Read the input a byte at a time, so reads do not align to
+				// 3-byte blocks.
+				actual, err := ioutil.ReadAll(enc.Encode(iotest.OneByteReader(strings.NewReader(c.Input))))
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				if e, a := enc.Encoding.EncodeToString([]byte(c.Input)), string(actual); e != a {
+					t.Errorf("expect %q encoded, got %q", e, a)
+				}
+
+				decoded, err := ioutil.ReadAll(enc.Decode(bytes.NewReader(actual)))
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				if e, a := c.Input, string(decoded); e != a {
+					t.Errorf("expect %q decoded, got %q", e, a)
+				}
+			})
+		}
+	}
+}
+
+func TestBase64EncodeReader_ReadError(t *testing.T) {
+	r := Base64EncodeReader(iotest.TimeoutReader(strings.NewReader("abcd")))
+
+	actual, err := ioutil.ReadAll(r)
+	if e, a := iotest.ErrTimeout, err; e != a {
+		t.Fatalf("expect %v error, got %v", e, a)
+	}
+	if e, a := "YWJj", string(actual); e != a {
+		t.Errorf("expect %q encoded before error, got %q", e, a)
+	}
+}
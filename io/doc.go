@@ -0,0 +1,8 @@
+// Package io provides utilities for reading and writing streams, extending
+// the standard library's io package.
+//
+// Base64EncodeReader and Base64DecodeReader, and their base64url variants,
+// encode and decode blob streams as they are read. AWSChunkedReader frames a
+// payload into the signed chunks, and trailing headers, of the aws-chunked
+// content encoding.
+package io
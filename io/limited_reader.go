@@ -0,0 +1,77 @@
+package io
+
+import (
+	"io"
+)
+
+// LimitedReadCloser wraps a ReadCloser, reading at most N bytes from it. Once
+// N bytes have been read, Read returns io.EOF. Truncated reports if the
+// underlying reader had more than N bytes, and so was not read completely.
+//
+// Unlike io.LimitedReader, LimitedReadCloser reads one byte past the limit
+// in order to determine if the underlying reader was truncated.
+type LimitedReadCloser struct {
+	rc        io.ReadCloser
+	remaining int64
+	truncated bool
+	done      bool
+}
+
+// NewLimitedReadCloser returns a LimitedReadCloser reading at most n bytes
+// from the ReadCloser.
+func NewLimitedReadCloser(rc io.ReadCloser, n int64) *LimitedReadCloser {
+	return &LimitedReadCloser{
+		rc:        rc,
+		remaining: n,
+	}
+}
+
+// Read reads up to len(p) bytes, without exceeding the limit.
+func (r *LimitedReadCloser) Read(p []byte) (n int, err error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	if r.remaining <= 0 {
+		r.checkTruncated()
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err = r.rc.Read(p)
+	r.remaining -= int64(n)
+	if err == io.EOF {
+		r.done = true
+	}
+	return n, err
+}
+
+// checkTruncated reads a single byte past the limit to determine if the
+// underlying reader had more bytes.
+func (r *LimitedReadCloser) checkTruncated() {
+	r.done = true
+
+	var b [1]byte
+	for {
+		n, err := r.rc.Read(b[:])
+		if n > 0 {
+			r.truncated = true
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Truncated returns if the underlying reader had more bytes than the limit.
+// Only valid once Read has returned io.EOF.
+func (r *LimitedReadCloser) Truncated() bool {
+	return r.truncated
+}
+
+// Close closes the underlying ReadCloser.
+func (r *LimitedReadCloser) Close() error {
+	return r.rc.Close()
+}
@@ -0,0 +1,62 @@
+package io
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestLimitedReadCloser(t *testing.T) {
+	cases := map[string]struct {
+		Body            string
+		Limit           int64
+		Expect          string
+		ExpectTruncated bool
+	}{
+		"under limit": {
+			Body:   "abc",
+			Limit:  10,
+			Expect: "abc",
+		},
+		"at limit": {
+			Body:   "abcdefghij",
+			Limit:  10,
+			Expect: "abcdefghij",
+		},
+		"over limit": {
+			Body:            "abcdefghijklmnop",
+			Limit:           10,
+			Expect:          "abcdefghij",
+			ExpectTruncated: true,
+		},
+		"zero limit": {
+			Body:            "abc",
+			Limit:           0,
+			Expect:          "",
+			ExpectTruncated: true,
+		},
+		"empty body": {
+			Limit: 10,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewLimitedReadCloser(ioutil.NopCloser(strings.NewReader(c.Body)), c.Limit)
+
+			b, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, string(b); e != a {
+				t.Errorf("expect %q, got %q", e, a)
+			}
+			if e, a := c.ExpectTruncated, r.Truncated(); e != a {
+				t.Errorf("expect truncated %v, got %v", e, a)
+			}
+			if err := r.Close(); err != nil {
+				t.Errorf("expect no close error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package logging provides the Logger interface used by clients and
+// middleware to emit log entries.
+package logging
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// Classification is the type of the log entry's classification name.
+type Classification string
+
+// Set of standard classifications that can be used by clients and middleware
+const (
+	Warn  Classification = "WARN"
+	Debug Classification = "DEBUG"
+)
+
+// Logger is an interface for logging entries at certain classifications.
+type Logger interface {
+	// Logf is expected to support the standard fmt package "verbs".
+	Logf(classification Classification, format string, v ...interface{})
+}
+
+// FieldLogger is a Logger that supports attaching structured key value
+// fields to the entries it logs, (e.g. operation name, attempt, and request
+// ID).
+type FieldLogger interface {
+	Logger
+
+	// With returns a Logger that includes the key value pairs with every
+	// entry logged. Keys are expected to be strings.
+	With(keyvals ...interface{}) Logger
+}
+
+// With returns a Logger that includes the key value pairs with every entry
+// logged. If the logger is a FieldLogger its With method is used, otherwise
+// the fields are appended to the formatted message of each entry as
+// key=value pairs.
+func With(logger Logger, keyvals ...interface{}) Logger {
+	if len(keyvals) == 0 {
+		return logger
+	}
+	if l, ok := logger.(FieldLogger); ok {
+		return l.With(keyvals...)
+	}
+	return fieldsLogger{logger: logger, fields: keyvals}
+}
+
+// fieldsLogger appends its fields to the message of each entry logged.
+type fieldsLogger struct {
+	logger Logger
+	fields []interface{}
+}
+
+func (l fieldsLogger) Logf(classification Classification, format string, v ...interface{}) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, format, v...)
+	for i := 0; i < len(l.fields); i += 2 {
+		sb.WriteByte(' ')
+		if i+1 < len(l.fields) {
+			fmt.Fprintf(&sb, "%v=%v", l.fields[i], l.fields[i+1])
+		} else {
+			fmt.Fprintf(&sb, "%v=(MISSING)", l.fields[i])
+		}
+	}
+	l.logger.Logf(classification, "%s", sb.String())
+}
+
+func (l fieldsLogger) With(keyvals ...interface{}) Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(keyvals))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keyvals...)
+	return fieldsLogger{logger: l.logger, fields: fields}
+}
+
+// LoggerFunc is a wrapper around a function to satisfy the Logger interface.
+type LoggerFunc func(classification Classification, format string, v ...interface{})
+
+// Logf delegates the logging request to the wrapped function.
+func (f LoggerFunc) Logf(classification Classification, format string, v ...interface{}) {
+	f(classification, format, v...)
+}
+
+// Nop is a Logger implementation that simply does not perform any logging.
+type Nop struct{}
+
+// Logf simply returns without performing any action.
+func (n Nop) Logf(Classification, string, ...interface{}) {
+	return
+}
+
+// With returns the Nop logger.
+func (n Nop) With(...interface{}) Logger {
+	return n
+}
+
+// StandardLogger is a Logger implementation that wraps the standard library
+// logger, and delegates logging to its Printf method.
+type StandardLogger struct {
+	Logger *log.Logger
+}
+
+// Logf logs the given classification and message to the underlying logger.
+func (s StandardLogger) Logf(classification Classification, format string, v ...interface{}) {
+	if len(classification) != 0 {
+		format = string(classification) + " " + format
+	}
+
+	s.Logger.Printf(format, v...)
+}
+
+// With returns a Logger that appends the key value pairs to each entry
+// logged as key=value pairs.
+func (s StandardLogger) With(keyvals ...interface{}) Logger {
+	return fieldsLogger{logger: s, fields: keyvals}
+}
+
+// NewStandardLogger returns a new StandardLogger writing to the provided
+// writer.
+func NewStandardLogger(writer io.Writer) *StandardLogger {
+	return &StandardLogger{
+		Logger: log.New(writer, "SDK ", log.LstdFlags),
+	}
+}
+
+var _ Logger = (*StandardLogger)(nil)
+var _ Logger = Nop{}
+var _ Logger = LoggerFunc(nil)
+var _ FieldLogger = (*StandardLogger)(nil)
+var _ FieldLogger = Nop{}
+var _ FieldLogger = fieldsLogger{}
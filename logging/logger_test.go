@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	var entries []string
+	var logger Logger = LoggerFunc(func(classification Classification, format string, v ...interface{}) {
+		entries = append(entries, string(classification)+" "+fmt.Sprintf(format, v...))
+	})
+
+	opLogger := With(logger, "operation", "GetItem")
+	attemptLogger := With(opLogger, "attempt", 2, "request_id", "abc-123")
+
+	opLogger.Logf(Debug, "starting %v%%", 100)
+	attemptLogger.Logf(Warn, "retrying")
+	With(logger, "dangling").Logf(Debug, "odd fields")
+	With(logger).Logf(Debug, "no fields")
+
+	expect := []string{
+		"DEBUG starting 100% operation=GetItem",
+		"WARN retrying operation=GetItem attempt=2 request_id=abc-123",
+		"DEBUG odd fields dangling=(MISSING)",
+		"DEBUG no fields",
+	}
+	if e, a := len(expect), len(entries); e != a {
+		t.Fatalf("expect %v entries, got %v", e, a)
+	}
+	for i := range expect {
+		if e, a := expect[i], entries[i]; e != a {
+			t.Errorf("expect %q entry, got %q", e, a)
+		}
+	}
+}
+
+func TestStandardLoggerWith(t *testing.T) {
+	var buf bytes.Buffer
+	logger := StandardLogger{Logger: log.New(&buf, "", 0)}
+
+	With(With(logger, "a", 1), "b", 2).Logf(Debug, "message")
+
+	if e, a := "DEBUG message a=1 b=2\n", buf.String(); e != a {
+		t.Errorf("expect %q, got %q", e, a)
+	}
+}
+
+func TestNopWith(t *testing.T) {
+	if _, ok := With(Nop{}, "a", 1).(Nop); !ok {
+		t.Errorf("expect Nop logger")
+	}
+}
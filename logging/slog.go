@@ -0,0 +1,52 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// SlogLogger is a FieldLogger that delegates logging to a log/slog Logger.
+// Fields attached with With are logged as slog attributes.
+//
+// The Warn classification is logged at slog's warn level, Debug at the debug
+// level, and all other classifications at the info level.
+type SlogLogger struct {
+	Logger *slog.Logger
+}
+
+// NewSlogLogger returns a SlogLogger delegating to the slog Logger.
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	return &SlogLogger{Logger: logger}
+}
+
+// Logf logs the formatted message at the level of the classification.
+func (s SlogLogger) Logf(classification Classification, format string, v ...interface{}) {
+	level := slogLevel(classification)
+	if !s.Logger.Enabled(context.Background(), level) {
+		return
+	}
+	s.Logger.Log(context.Background(), level, fmt.Sprintf(format, v...))
+}
+
+// With returns a SlogLogger which logs the key value pairs as attributes of
+// every entry.
+func (s SlogLogger) With(keyvals ...interface{}) Logger {
+	return SlogLogger{Logger: s.Logger.With(keyvals...)}
+}
+
+func slogLevel(classification Classification) slog.Level {
+	switch classification {
+	case Warn:
+		return slog.LevelWarn
+	case Debug:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+var _ FieldLogger = SlogLogger{}
@@ -0,0 +1,61 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLoggerWith(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	var logger Logger = NewSlogLogger(slog.New(handler))
+
+	opLogger := With(logger, "operation", "GetItem")
+	attemptLogger := With(opLogger, "attempt", 2)
+	With(attemptLogger, "request_id", "abc-123").Logf(Warn, "retrying %s", "now")
+	opLogger.Logf(Debug, "done")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expect := []string{
+		`level=WARN msg="retrying now" operation=GetItem attempt=2 request_id=abc-123`,
+		`level=DEBUG msg=done operation=GetItem`,
+	}
+	if e, a := len(expect), len(lines); e != a {
+		t.Fatalf("expect %v lines, got %v\n%v", e, a, buf.String())
+	}
+	for i := range expect {
+		if e, a := expect[i], lines[i]; e != a {
+			t.Errorf("expect %q, got %q", e, a)
+		}
+	}
+}
+
+func TestSlogLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+	})))
+
+	logger.Logf(Debug, "not logged")
+	if buf.Len() != 0 {
+		t.Errorf("expect debug entry not logged, got %v", buf.String())
+	}
+
+	logger.Logf(Warn, "logged")
+	if !strings.Contains(buf.String(), "msg=logged") {
+		t.Errorf("expect warn entry logged, got %v", buf.String())
+	}
+}
@@ -0,0 +1,13 @@
+/*
+Package metrics provides the interfaces for emitting client metrics to a
+metrics backend, and the middleware instrumenting operations with them.
+
+A MeterProvider returns the Meter for an instrumentation scope, from which
+counter and histogram instruments are created. Implementations adapt the
+interfaces to a metrics backend. NopMeterProvider discards all measurements.
+
+AddMiddleware adds the middleware recording the duration of each operation
+call and attempt, and the number of retries, throttled attempts, and errors,
+to an operation's stack.
+*/
+package metrics
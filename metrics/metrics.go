@@ -0,0 +1,65 @@
+package metrics
+
+import "context"
+
+// Attribute is a key value pair describing a measurement, (e.g. the name of
+// the operation measured).
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// InstrumentOptions are the options of an instrument created by a Meter.
+type InstrumentOptions struct {
+	// Unit of the instrument's measurements, (e.g. "s" or "{attempt}").
+	Unit string
+
+	// Description of the instrument.
+	Description string
+}
+
+// MeterProvider provides the Meters of instrumentation scopes.
+type MeterProvider interface {
+	Meter(scope string) Meter
+}
+
+// Meter creates the instruments measurements are recorded with.
+type Meter interface {
+	Int64Counter(name string, optFns ...func(*InstrumentOptions)) Int64Counter
+	Float64Histogram(name string, optFns ...func(*InstrumentOptions)) Float64Histogram
+}
+
+// Int64Counter is an instrument recording monotonically increasing counts.
+type Int64Counter interface {
+	Add(ctx context.Context, incr int64, attrs ...Attribute)
+}
+
+// Float64Histogram is an instrument recording the distribution of values,
+// (e.g. latencies).
+type Float64Histogram interface {
+	Record(ctx context.Context, v float64, attrs ...Attribute)
+}
+
+// NopMeterProvider is a MeterProvider whose instruments discard all
+// measurements.
+type NopMeterProvider struct{}
+
+// Meter returns a Meter whose instruments discard all measurements.
+func (NopMeterProvider) Meter(string) Meter { return nopMeter{} }
+
+type nopMeter struct{}
+
+func (nopMeter) Int64Counter(string, ...func(*InstrumentOptions)) Int64Counter {
+	return nopInstrument{}
+}
+
+func (nopMeter) Float64Histogram(string, ...func(*InstrumentOptions)) Float64Histogram {
+	return nopInstrument{}
+}
+
+type nopInstrument struct{}
+
+func (nopInstrument) Add(context.Context, int64, ...Attribute)      {}
+func (nopInstrument) Record(context.Context, float64, ...Attribute) {}
+
+var _ MeterProvider = NopMeterProvider{}
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// Scope is the instrumentation scope of the meter the middleware's
+// instruments are created with.
+const Scope = "github.com/awslabs/smithy-go"
+
+// Names of the instruments recorded by the middleware.
+const (
+	CallDuration        = "client.call.duration"
+	CallAttemptDuration = "client.call.attempt_duration"
+	CallRetries         = "client.call.retries"
+	CallThrottles       = "client.call.throttles"
+	CallErrors          = "client.call.errors"
+)
+
+// AddMiddleware adds the OperationMetrics middleware to the front of the
+// stack's finalize step, so it measures the operation's call including all
+// retries, and AttemptMetrics to the end, so it measures each attempt. If
+// the provider is nil, no middleware is added.
+func AddMiddleware(stack *middleware.Stack, provider MeterProvider) error {
+	if provider == nil {
+		return nil
+	}
+	meter := provider.Meter(Scope)
+
+	if err := stack.Finalize.Add(NewOperationMetrics(meter), middleware.Before); err != nil {
+		return err
+	}
+	return stack.Finalize.Add(NewAttemptMetrics(meter), middleware.After)
+}
+
+// OperationMetrics is a finalize middleware that records the duration of the
+// operation's call, the number of retries made, and if the call failed. The
+// retries are computed from the attempt count set in the metadata by the
+// Retry middleware, see middleware.GetAttemptCountMetadata.
+//
+// Measurements are recorded with the operation's name as the "operation"
+// attribute.
+type OperationMetrics struct {
+	duration Float64Histogram
+	retries  Int64Counter
+	errors   Int64Counter
+}
+
+// NewOperationMetrics returns an OperationMetrics recording with the meter's
+// instruments.
+func NewOperationMetrics(meter Meter) *OperationMetrics {
+	return &OperationMetrics{
+		duration: meter.Float64Histogram(CallDuration, withUnit("s")),
+		retries:  meter.Int64Counter(CallRetries, withUnit("{attempt}")),
+		errors:   meter.Int64Counter(CallErrors, withUnit("{error}")),
+	}
+}
+
+// ID returns the middleware identifier.
+func (m *OperationMetrics) ID() string {
+	return "OperationMetrics"
+}
+
+// HandleFinalize records the measurements of the operation's call.
+func (m *OperationMetrics) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	clock := middleware.GetClock(ctx)
+	start := clock.Now()
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+
+	attrs := operationAttributes(ctx)
+	m.duration.Record(ctx, clock.Now().Sub(start).Seconds(), attrs...)
+	if attempts, ok := middleware.GetAttemptCountMetadata(metadata); ok && attempts > 1 {
+		m.retries.Add(ctx, int64(attempts-1), attrs...)
+	}
+	if err != nil {
+		m.errors.Add(ctx, 1, append(attrs, errorAttribute(err))...)
+	}
+
+	return out, metadata, err
+}
+
+// AttemptMetrics is a finalize middleware that records the duration of each
+// attempt, and if the attempt was throttled. The middleware must be added
+// after the Retry middleware so each attempt is measured.
+//
+// Measurements are recorded with the operation's name as the "operation"
+// attribute.
+type AttemptMetrics struct {
+	duration  Float64Histogram
+	throttles Int64Counter
+}
+
+// NewAttemptMetrics returns an AttemptMetrics recording with the meter's
+// instruments.
+func NewAttemptMetrics(meter Meter) *AttemptMetrics {
+	return &AttemptMetrics{
+		duration:  meter.Float64Histogram(CallAttemptDuration, withUnit("s")),
+		throttles: meter.Int64Counter(CallThrottles, withUnit("{attempt}")),
+	}
+}
+
+// ID returns the middleware identifier.
+func (m *AttemptMetrics) ID() string {
+	return "AttemptMetrics"
+}
+
+// HandleFinalize records the measurements of the attempt.
+func (m *AttemptMetrics) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	clock := middleware.GetClock(ctx)
+	start := clock.Now()
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+
+	attrs := operationAttributes(ctx)
+	m.duration.Record(ctx, clock.Now().Sub(start).Seconds(), attrs...)
+
+	var throttleErr smithy.ThrottlingError
+	if errors.As(err, &throttleErr) && throttleErr.ThrottlingError() {
+		m.throttles.Add(ctx, 1, attrs...)
+	}
+
+	return out, metadata, err
+}
+
+func operationAttributes(ctx context.Context) []Attribute {
+	if name := middleware.GetOperationName(ctx); len(name) != 0 {
+		return []Attribute{{Key: "operation", Value: name}}
+	}
+	return nil
+}
+
+// errorAttribute returns the error's API error code as the "error.type"
+// attribute, or "unknown" if the error is not an API error.
+func errorAttribute(err error) Attribute {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return Attribute{Key: "error.type", Value: apiErr.ErrorCode()}
+	}
+	return Attribute{Key: "error.type", Value: "unknown"}
+}
+
+func withUnit(unit string) func(*InstrumentOptions) {
+	return func(o *InstrumentOptions) {
+		o.Unit = unit
+	}
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+	"github.com/awslabs/smithy-go/retry"
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+type measurement struct {
+	Name  string
+	Value float64
+	Attrs []Attribute
+}
+
+type recordingMeter struct {
+	mu           sync.Mutex
+	scope        string
+	measurements []measurement
+}
+
+func (m *recordingMeter) Meter(scope string) Meter {
+	m.scope = scope
+	return m
+}
+
+func (m *recordingMeter) Int64Counter(name string, optFns ...func(*InstrumentOptions)) Int64Counter {
+	return recordingInstrument{meter: m, name: name}
+}
+
+func (m *recordingMeter) Float64Histogram(name string, optFns ...func(*InstrumentOptions)) Float64Histogram {
+	return recordingInstrument{meter: m, name: name}
+}
+
+func (m *recordingMeter) record(name string, v float64, attrs []Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.measurements = append(m.measurements, measurement{Name: name, Value: v, Attrs: attrs})
+}
+
+type recordingInstrument struct {
+	meter *recordingMeter
+	name  string
+}
+
+func (i recordingInstrument) Add(ctx context.Context, incr int64, attrs ...Attribute) {
+	i.meter.record(i.name, float64(incr), attrs)
+}
+
+func (i recordingInstrument) Record(ctx context.Context, v float64, attrs ...Attribute) {
+	i.meter.record(i.name, v, attrs)
+}
+
+func TestAddMiddleware(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "Throttled", Retryable: true, Throttling: true}
+
+	cases := map[string]struct {
+		Errs   []error
+		Expect []measurement
+	}{
+		"success": {
+			Expect: []measurement{
+				{Name: CallAttemptDuration, Value: 1},
+				{Name: CallDuration, Value: 3},
+			},
+		},
+		"throttled retry": {
+			Errs: []error{throttled},
+			Expect: []measurement{
+				{Name: CallAttemptDuration, Value: 1},
+				{Name: CallThrottles, Value: 1},
+				{Name: CallAttemptDuration, Value: 1},
+				{Name: CallDuration, Value: 6},
+				{Name: CallRetries, Value: 1},
+			},
+		},
+		"failed": {
+			Errs: []error{&smithy.GenericAPIError{Code: "BadRequest"}},
+			Expect: []measurement{
+				{Name: CallAttemptDuration, Value: 1},
+				{Name: CallDuration, Value: 3},
+				{Name: CallErrors, Value: 1, Attrs: []Attribute{{Key: "error.type", Value: "BadRequest"}}},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Each call for the time advances the clock by one second.
+			var now time.Time
+			ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+				now = now.Add(time.Second)
+				return now
+			}))
+			ctx = middleware.WithOperationName(ctx, "GetItem")
+
+			stack := middleware.NewStack("metrics", func() interface{} { return struct{}{} })
+			stack.Finalize.Add(retry.NewAttemptMiddleware(retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = 3
+				o.MaxBackoff = time.Nanosecond
+			})), middleware.After)
+
+			meter := &recordingMeter{}
+			if err := AddMiddleware(stack, meter); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			errs := c.Errs
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					var err error
+					if len(errs) != 0 {
+						err, errs = errs[0], errs[1:]
+					}
+					return nil, middleware.Metadata{}, err
+				}), stack)
+			handler.Handle(ctx, struct{}{})
+
+			if e, a := Scope, meter.scope; e != a {
+				t.Errorf("expect %v scope, got %v", e, a)
+			}
+
+			operation := Attribute{Key: "operation", Value: "GetItem"}
+			for i := range c.Expect {
+				c.Expect[i].Attrs = append([]Attribute{operation}, c.Expect[i].Attrs...)
+			}
+			if e, a := c.Expect, meter.measurements; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect measurements\n%v\ngot\n%v", e, a)
+			}
+		})
+	}
+}
+
+func TestAddMiddleware_NilProvider(t *testing.T) {
+	stack := middleware.NewStack("metrics", func() interface{} { return struct{}{} })
+	if err := AddMiddleware(stack, nil); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 0, len(stack.Finalize.List()); e != a {
+		t.Errorf("expect %v finalize middleware, got %v", e, a)
+	}
+}
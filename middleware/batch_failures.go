@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchItemFailure is an entry of a batch operation that failed, while other
+// entries of the batch may have succeeded.
+type BatchItemFailure struct {
+	// Identifier of the entry in the batch request, (e.g. the entry's Id
+	// member).
+	ID string
+
+	// Error code and message the entry failed with.
+	Code    string
+	Message string
+}
+
+// BatchItemFailures is the list of entries of a batch operation that failed.
+type BatchItemFailures []BatchItemFailure
+
+type batchItemFailuresKey struct{}
+
+// SetBatchItemFailuresMetadata sets the entries of a batch operation that
+// failed in the metadata.
+func SetBatchItemFailuresMetadata(metadata *Metadata, failures BatchItemFailures) {
+	metadata.Set(batchItemFailuresKey{}, failures)
+}
+
+// GetBatchItemFailuresMetadata returns the entries of a batch operation that
+// failed from the metadata, and if the failures were set. Batch operations
+// with no failed entries do not set the failures.
+func GetBatchItemFailuresMetadata(metadata MetadataReader) (BatchItemFailures, bool) {
+	v, ok := metadata.Get(batchItemFailuresKey{}).(BatchItemFailures)
+	return v, ok
+}
+
+// RecordBatchItemFailures is a deserialize middleware that sets the failed
+// entries of a batch operation's deserialized output in the metadata, see
+// GetBatchItemFailuresMetadata, so middleware and loggers can surface partial
+// failures without the output being parsed again.
+//
+// The middleware should be added before the operation's deserializer, so the
+// output it deserializes is available once the remainder of the stack
+// returns.
+type RecordBatchItemFailures struct {
+	// Returns the failed entries of the operation's output. Required.
+	Failures func(output interface{}) BatchItemFailures
+}
+
+// ID returns the middleware identifier.
+func (m *RecordBatchItemFailures) ID() string {
+	return "RecordBatchItemFailures"
+}
+
+// HandleDeserialize invokes the remainder of the stack, and sets the failed
+// entries of the deserialized output in the metadata.
+func (m *RecordBatchItemFailures) HandleDeserialize(ctx context.Context, in DeserializeInput, next DeserializeHandler) (
+	out DeserializeOutput, metadata Metadata, err error,
+) {
+	if m.Failures == nil {
+		return out, metadata, fmt.Errorf("batch item failures function not set")
+	}
+
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil || out.Result == nil {
+		return out, metadata, err
+	}
+
+	if failures := m.Failures(out.Result); len(failures) != 0 {
+		SetBatchItemFailuresMetadata(&metadata, failures)
+	}
+	return out, metadata, nil
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type deserializeHandlerFunc func(context.Context, DeserializeInput) (DeserializeOutput, Metadata, error)
+
+func (fn deserializeHandlerFunc) HandleDeserialize(ctx context.Context, in DeserializeInput) (
+	DeserializeOutput, Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+type batchOutput struct {
+	Successful []struct {
+		Id string
+	}
+	Failed []struct {
+		Id      string
+		Code    string
+		Message string
+	}
+}
+
+func batchOutputFailures(output interface{}) BatchItemFailures {
+	out, ok := output.(*batchOutput)
+	if !ok {
+		return nil
+	}
+	var failures BatchItemFailures
+	for _, f := range out.Failed {
+		failures = append(failures, BatchItemFailure{ID: f.Id, Code: f.Code, Message: f.Message})
+	}
+	return failures
+}
+
+func TestRecordBatchItemFailures(t *testing.T) {
+	cases := map[string]struct {
+		Body           string
+		DeserializeErr error
+		Expect         BatchItemFailures
+		ExpectSet      bool
+		ExpectErr      bool
+	}{
+		"mixed success and failure": {
+			Body: `{
+				"Successful": [{"Id": "1"}, {"Id": "3"}],
+				"Failed": [
+					{"Id": "2", "Code": "InvalidParameterValue", "Message": "value too long"},
+					{"Id": "4", "Code": "InternalError", "Message": "retry later"}
+				]
+			}`,
+			Expect: BatchItemFailures{
+				{ID: "2", Code: "InvalidParameterValue", Message: "value too long"},
+				{ID: "4", Code: "InternalError", Message: "retry later"},
+			},
+			ExpectSet: true,
+		},
+		"all successful": {
+			Body: `{"Successful": [{"Id": "1"}], "Failed": []}`,
+		},
+		"deserialize error": {
+			DeserializeErr: fmt.Errorf("failed to deserialize"),
+			ExpectErr:      true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := RecordBatchItemFailures{Failures: batchOutputFailures}
+			_, metadata, err := m.HandleDeserialize(context.Background(), DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in DeserializeInput) (
+					out DeserializeOutput, metadata Metadata, err error,
+				) {
+					if c.DeserializeErr != nil {
+						return out, metadata, c.DeserializeErr
+					}
+					var output batchOutput
+					if err := json.Unmarshal([]byte(c.Body), &output); err != nil {
+						return out, metadata, err
+					}
+					out.Result = &output
+					return out, metadata, nil
+				}))
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			failures, ok := GetBatchItemFailuresMetadata(metadata)
+			if e, a := c.ExpectSet, ok; e != a {
+				t.Fatalf("expect failures set %v, got %v", e, a)
+			}
+			if e, a := c.Expect, failures; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v failures, got %v", e, a)
+			}
+		})
+	}
+}
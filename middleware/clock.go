@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+type clockKey struct{}
+
+// SetClock sets the clock the middleware will use to retrieve the current
+// time.
+func SetClock(ctx context.Context, clock smithytime.Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// GetClock returns the clock set on the context, or a smithytime.SystemClock
+// if no clock was set.
+func GetClock(ctx context.Context) smithytime.Clock {
+	v, ok := ctx.Value(clockKey{}).(smithytime.Clock)
+	if !ok || v == nil {
+		return smithytime.SystemClock{}
+	}
+	return v
+}
+
+// ClockMiddleware is an initialize middleware that sets the clock used by the
+// remainder of the stack. Allows a fixed clock to be injected into a stack
+// for deterministic testing of time based middleware.
+type ClockMiddleware struct {
+	Clock smithytime.Clock
+}
+
+// ID returns the middleware identifier.
+func (m *ClockMiddleware) ID() string {
+	return "Clock"
+}
+
+// HandleInitialize sets the clock on the context for the remainder of the
+// stack.
+func (m *ClockMiddleware) HandleInitialize(ctx context.Context, in InitializeInput, next InitializeHandler) (
+	out InitializeOutput, metadata Metadata, err error,
+) {
+	return next.HandleInitialize(SetClock(ctx, m.Clock), in)
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+// tokenExpiryMiddleware fails the operation if the token has expired
+// according to the stack's clock.
+func tokenExpiryMiddleware(expires time.Time) BuildMiddleware {
+	return BuildMiddlewareFunc("token expiry",
+		func(ctx context.Context, in BuildInput, next BuildHandler) (BuildOutput, Metadata, error) {
+			if now := GetClock(ctx).Now(); !now.Before(expires) {
+				return BuildOutput{}, Metadata{}, fmt.Errorf("token expired at %v", expires)
+			}
+			return next.HandleBuild(ctx, in)
+		})
+}
+
+func TestClockMiddleware(t *testing.T) {
+	expires := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		Now       time.Time
+		ExpectErr bool
+	}{
+		"before expiry": {
+			Now: expires.Add(-time.Second),
+		},
+		"at expiry": {
+			Now:       expires,
+			ExpectErr: true,
+		},
+		"after expiry": {
+			Now:       expires.Add(time.Hour),
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stack := NewStack("clock", func() interface{} { return struct{}{} })
+			stack.Initialize.Add(&ClockMiddleware{
+				Clock: smithytime.ClockFunc(func() time.Time { return c.Now }),
+			}, After)
+			stack.Build.Add(tokenExpiryMiddleware(expires), After)
+
+			h := DecorateHandler(&mockHandler{}, stack)
+			_, _, err := h.Handle(context.Background(), struct{}{})
+			if c.ExpectErr && err == nil {
+				t.Fatalf("expect error, got none")
+			} else if !c.ExpectErr && err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetClockDefault(t *testing.T) {
+	if _, ok := GetClock(context.Background()).(smithytime.SystemClock); !ok {
+		t.Errorf("expect system clock by default")
+	}
+}
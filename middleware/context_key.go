@@ -0,0 +1,52 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware
+
+import "context"
+
+// ContextKey provides a typed key for storing values in a context.Context.
+// Keys are compared by identity, so two keys created with the same name will
+// not collide. This allows packages to share access to context values
+// through an exported key, without exposing the key's underlying type.
+//
+// Keys must be created with NewContextKey. The zero value has no identity, so
+// every zero value key stores and retrieves the same context value.
+//
+// Requires Go 1.21 or later.
+type ContextKey[T any] struct {
+	key *contextKey
+}
+
+type contextKey struct {
+	name string
+}
+
+// NewContextKey returns a new ContextKey for values of type T. The name is
+// only used to describe the key, and does not need to be unique.
+func NewContextKey[T any](name string) ContextKey[T] {
+	return ContextKey[T]{
+		key: &contextKey{name: name},
+	}
+}
+
+// String returns the name of the key, or empty string if the key was not
+// created with NewContextKey.
+func (k ContextKey[T]) String() string {
+	if k.key == nil {
+		return ""
+	}
+	return k.key.name
+}
+
+// WithValue returns a copy of the context with the value stored at the key.
+func (k ContextKey[T]) WithValue(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k.key, v)
+}
+
+// Value returns the value stored in the context at the key, and if the value
+// was found.
+func (k ContextKey[T]) Value(ctx context.Context) (v T, ok bool) {
+	v, ok = ctx.Value(k.key).(T)
+	return v, ok
+}
@@ -0,0 +1,47 @@
+//go:build go1.21
+// +build go1.21
+
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestContextKey(t *testing.T) {
+	attemptKey := NewContextKey[int]("attempt")
+	otherAttemptKey := NewContextKey[int]("attempt")
+	signingTimeKey := NewContextKey[time.Time]("signing time")
+
+	if e, a := "attempt", attemptKey.String(); e != a {
+		t.Errorf("expect %v name, got %v", e, a)
+	}
+
+	ctx := context.Background()
+	if _, ok := attemptKey.Value(ctx); ok {
+		t.Errorf("expect no value before set")
+	}
+
+	signingTime := time.Unix(1600000000, 0)
+	ctx = attemptKey.WithValue(ctx, 2)
+	ctx = otherAttemptKey.WithValue(ctx, 5)
+	ctx = signingTimeKey.WithValue(ctx, signingTime)
+
+	if v, ok := attemptKey.Value(ctx); !ok || v != 2 {
+		t.Errorf("expect 2 attempt, got %v, %v", v, ok)
+	}
+	if v, ok := otherAttemptKey.Value(ctx); !ok || v != 5 {
+		t.Errorf("expect 5 attempt for key with same name, got %v, %v", v, ok)
+	}
+	if v, ok := signingTimeKey.Value(ctx); !ok || !v.Equal(signingTime) {
+		t.Errorf("expect %v signing time, got %v, %v", signingTime, v, ok)
+	}
+}
+
+func TestContextKeyZeroValue(t *testing.T) {
+	var key ContextKey[int]
+	if e, a := "", key.String(); e != a {
+		t.Errorf("expect %q name, got %q", e, a)
+	}
+}
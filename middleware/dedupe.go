@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/smithy-go/logging"
+)
+
+// DedupeStore provides the interface for the store the Dedupe middleware
+// records the results of operations in, keyed by their idempotency token.
+// Stores persisting results, (e.g. to disk), allow a client restarted after
+// a crash to retrieve the result of an operation it already sent, instead
+// of sending it again. Implementations must be safe for concurrent use.
+type DedupeStore interface {
+	// Get returns the result recorded for the key, and if a result was
+	// recorded.
+	Get(ctx context.Context, key string) (result interface{}, ok bool, err error)
+
+	// Put records the result for the key.
+	Put(ctx context.Context, key string, result interface{}) error
+}
+
+type dedupeHitKey struct{}
+
+// IsDedupeHitMetadata returns if the operation's output was the result
+// recorded for its idempotency token by the Dedupe middleware, instead of
+// being returned by the service.
+func IsDedupeHitMetadata(metadata MetadataReader) bool {
+	v, _ := metadata.Get(dedupeHitKey{}).(bool)
+	return v
+}
+
+// Dedupe is a finalize middleware providing exactly-once semantics for write
+// operations with an idempotency token. Before the operation's request is
+// sent, the Store is consulted for a result recorded for the operation's
+// token, and if present the result is returned without sending the request.
+// Otherwise the request is sent, and its output recorded in the Store if the
+// operation succeeded.
+//
+// Failures recording the result are logged, the operation's output is still
+// returned. Operations whose Key function does not return a key are not
+// deduplicated. Results returned from the store are marked in the metadata,
+// see IsDedupeHitMetadata.
+//
+// The middleware should be added first in the Finalize step, so attempts
+// retried by the retry middleware are not deduplicated.
+type Dedupe struct {
+	// Store results are recorded in.
+	Store DedupeStore
+
+	// Returns the idempotency token of the operation's request, (e.g. a
+	// client generated token, see IdempotencyTokenAutoFill), and if the
+	// request has one.
+	Key func(ctx context.Context, request interface{}) (string, bool)
+}
+
+// ID returns the middleware identifier.
+func (m *Dedupe) ID() string {
+	return "Dedupe"
+}
+
+// HandleFinalize returns the result recorded for the request's idempotency
+// token, or invokes the remainder of the stack, recording its output.
+func (m *Dedupe) HandleFinalize(ctx context.Context, in FinalizeInput, next FinalizeHandler) (
+	out FinalizeOutput, metadata Metadata, err error,
+) {
+	if m.Store == nil || m.Key == nil {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	key, ok := m.Key(ctx, in.Request)
+	if !ok || len(key) == 0 {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	result, ok, err := m.Store.Get(ctx, key)
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to get deduplicated result, %w", err)
+	}
+	if ok {
+		out.Result = result
+		metadata.Set(dedupeHitKey{}, true)
+		return out, metadata, nil
+	}
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	if putErr := m.Store.Put(ctx, key, out.Result); putErr != nil {
+		GetLogger(ctx).Logf(logging.Warn, "failed to record result for idempotency token %s, %v", key, putErr)
+	}
+	return out, metadata, nil
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type mapDedupeStore struct {
+	results map[string]interface{}
+	getErr  error
+	putErr  error
+}
+
+func (s *mapDedupeStore) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	if s.getErr != nil {
+		return nil, false, s.getErr
+	}
+	v, ok := s.results[key]
+	return v, ok, nil
+}
+
+func (s *mapDedupeStore) Put(ctx context.Context, key string, result interface{}) error {
+	if s.putErr != nil {
+		return s.putErr
+	}
+	s.results[key] = result
+	return nil
+}
+
+func TestDedupe(t *testing.T) {
+	cases := map[string]struct {
+		Recorded   map[string]interface{}
+		Request    interface{}
+		SendErr    error
+		GetErr     error
+		PutErr     error
+		Expect     interface{}
+		ExpectSent bool
+		ExpectHit  bool
+		ExpectPut  bool
+		ExpectErr  string
+	}{
+		"hit returns prior result": {
+			Recorded:  map[string]interface{}{"token-1": "prior result"},
+			Request:   "token-1",
+			Expect:    "prior result",
+			ExpectHit: true,
+		},
+		"miss sends and records": {
+			Recorded:   map[string]interface{}{},
+			Request:    "token-2",
+			Expect:     "sent token-2",
+			ExpectSent: true,
+			ExpectPut:  true,
+		},
+		"no token": {
+			Recorded:   map[string]interface{}{},
+			Request:    "",
+			Expect:     "sent ",
+			ExpectSent: true,
+		},
+		"send error not recorded": {
+			Recorded:   map[string]interface{}{},
+			Request:    "token-3",
+			SendErr:    fmt.Errorf("send failed"),
+			ExpectSent: true,
+			ExpectErr:  "send failed",
+		},
+		"store get error": {
+			Recorded:  map[string]interface{}{},
+			Request:   "token-4",
+			GetErr:    fmt.Errorf("store unavailable"),
+			ExpectErr: "failed to get deduplicated result, store unavailable",
+		},
+		"store put error": {
+			Recorded:   map[string]interface{}{},
+			Request:    "token-5",
+			PutErr:     fmt.Errorf("disk full"),
+			Expect:     "sent token-5",
+			ExpectSent: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			store := &mapDedupeStore{results: c.Recorded, getErr: c.GetErr, putErr: c.PutErr}
+			m := Dedupe{
+				Store: store,
+				Key: func(ctx context.Context, request interface{}) (string, bool) {
+					key, ok := request.(string)
+					return key, ok
+				},
+			}
+
+			var sent bool
+			out, metadata, err := m.HandleFinalize(context.Background(), FinalizeInput{Request: c.Request},
+				finalizeHandlerFunc(func(ctx context.Context, in FinalizeInput) (
+					out FinalizeOutput, metadata Metadata, err error,
+				) {
+					sent = true
+					if c.SendErr != nil {
+						return out, metadata, c.SendErr
+					}
+					out.Result = fmt.Sprintf("sent %v", in.Request)
+					return out, metadata, nil
+				}))
+			if e, a := c.ExpectSent, sent; e != a {
+				t.Errorf("expect sent %v, got %v", e, a)
+			}
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect error containing %q, got %v", c.ExpectErr, err)
+				}
+				if _, ok := store.results[c.Request.(string)]; ok {
+					t.Errorf("expect failed result not recorded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := c.Expect, out.Result; e != a {
+				t.Errorf("expect %v result, got %v", e, a)
+			}
+			if e, a := c.ExpectHit, IsDedupeHitMetadata(metadata); e != a {
+				t.Errorf("expect hit %v, got %v", e, a)
+			}
+			if c.ExpectPut {
+				if e, a := c.Expect, store.results[c.Request.(string)]; e != a {
+					t.Errorf("expect %v recorded, got %v", e, a)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/smithy-go/rand"
+)
+
+// IdempotencyTokenAutoFill is an initialize middleware that fills an
+// operation input's idempotency token member with a generated UUID if the
+// caller left the member empty. A token provided by the caller is never
+// replaced.
+type IdempotencyTokenAutoFill struct {
+	// Provider of the idempotency token. Defaults to a version 4 UUID
+	// generated from rand.Reader.
+	TokenProvider rand.IdempotencyTokenProvider
+
+	// IsEmpty returns if the input's idempotency token member is empty.
+	IsEmpty func(input interface{}) bool
+
+	// SetToken sets the input's idempotency token member.
+	SetToken func(input interface{}, token string)
+}
+
+// ID returns the middleware identifier.
+func (m *IdempotencyTokenAutoFill) ID() string {
+	return "IdempotencyTokenAutoFill"
+}
+
+// HandleInitialize fills the input's idempotency token if empty.
+func (m *IdempotencyTokenAutoFill) HandleInitialize(ctx context.Context, in InitializeInput, next InitializeHandler) (
+	out InitializeOutput, metadata Metadata, err error,
+) {
+	if !m.IsEmpty(in.Parameters) {
+		return next.HandleInitialize(ctx, in)
+	}
+
+	provider := m.TokenProvider
+	if provider == nil {
+		provider = rand.NewUUID(rand.Reader)
+	}
+
+	token, err := provider.GetIdempotencyToken()
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to generate idempotency token, %w", err)
+	}
+	m.SetToken(in.Parameters, token)
+
+	return next.HandleInitialize(ctx, in)
+}
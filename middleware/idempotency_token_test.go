@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/awslabs/smithy-go/rand"
+)
+
+type mockIdempotentInput struct {
+	ClientToken *string
+}
+
+func newMockIdempotencyTokenAutoFill(provider rand.IdempotencyTokenProvider) *IdempotencyTokenAutoFill {
+	return &IdempotencyTokenAutoFill{
+		TokenProvider: provider,
+		IsEmpty: func(input interface{}) bool {
+			v := input.(*mockIdempotentInput).ClientToken
+			return v == nil || len(*v) == 0
+		},
+		SetToken: func(input interface{}, token string) {
+			input.(*mockIdempotentInput).ClientToken = &token
+		},
+	}
+}
+
+func TestIdempotencyTokenAutoFill(t *testing.T) {
+	provided := "caller-token"
+
+	cases := map[string]struct {
+		Input  *mockIdempotentInput
+		Expect string
+	}{
+		"nil token": {
+			Input:  &mockIdempotentInput{},
+			Expect: "00010203-0405-4607-8809-0a0b0c0d0e0f",
+		},
+		"empty token": {
+			Input:  &mockIdempotentInput{ClientToken: new(string)},
+			Expect: "00010203-0405-4607-8809-0a0b0c0d0e0f",
+		},
+		"provided token": {
+			Input:  &mockIdempotentInput{ClientToken: &provided},
+			Expect: "caller-token",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			src := bytes.NewReader([]byte{
+				0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+				0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+			})
+			m := newMockIdempotencyTokenAutoFill(rand.NewUUID(src))
+
+			var token string
+			_, _, err := m.HandleInitialize(context.Background(), InitializeInput{Parameters: c.Input},
+				initializeHandlerFunc(func(ctx context.Context, in InitializeInput) (
+					out InitializeOutput, metadata Metadata, err error,
+				) {
+					token = *in.Parameters.(*mockIdempotentInput).ClientToken
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, token; e != a {
+				t.Errorf("expect %v token, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestIdempotencyTokenAutoFillDefaultProvider(t *testing.T) {
+	input := &mockIdempotentInput{}
+	m := newMockIdempotencyTokenAutoFill(nil)
+
+	_, _, err := m.HandleInitialize(context.Background(), InitializeInput{Parameters: input},
+		initializeHandlerFunc(func(ctx context.Context, in InitializeInput) (
+			out InitializeOutput, metadata Metadata, err error,
+		) {
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if input.ClientToken == nil || len(*input.ClientToken) != 36 {
+		t.Errorf("expect generated UUID token, got %v", input.ClientToken)
+	}
+}
+
+type initializeHandlerFunc func(context.Context, InitializeInput) (InitializeOutput, Metadata, error)
+
+func (fn initializeHandlerFunc) HandleInitialize(ctx context.Context, in InitializeInput) (
+	InitializeOutput, Metadata, error,
+) {
+	return fn(ctx, in)
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import "context"
+
+type idempotentOperationKey struct{}
+
+// WithIdempotentOperation returns a context marking the operation being
+// invoked as idempotent, so middleware can safely send the operation's
+// request more than once concurrently, (e.g. request hedging).
+func WithIdempotentOperation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentOperationKey{}, true)
+}
+
+// IsIdempotentOperation returns if the context marks the operation being
+// invoked as idempotent.
+func IsIdempotentOperation(ctx context.Context) bool {
+	v, _ := ctx.Value(idempotentOperationKey{}).(bool)
+	return v
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+)
+
+// InputValidation is a serialize middleware that validates the operation's
+// input before it is serialized, returning the validation error without
+// invoking the remainder of the stack. Validate should return a
+// smithy.InvalidParamsError listing each invalid member of the input.
+//
+// The middleware must be added before the middleware serializing the input.
+type InputValidation struct {
+	Validate func(input interface{}) error
+}
+
+// ID returns the middleware identifier.
+func (m *InputValidation) ID() string {
+	return "OperationInputValidation"
+}
+
+// HandleSerialize validates the input, and continues with serialization if
+// the input is valid.
+func (m *InputValidation) HandleSerialize(ctx context.Context, in SerializeInput, next SerializeHandler) (
+	out SerializeOutput, metadata Metadata, err error,
+) {
+	if m.Validate != nil {
+		if err := m.Validate(in.Parameters); err != nil {
+			return out, metadata, err
+		}
+	}
+	return next.HandleSerialize(ctx, in)
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+)
+
+type validationInput struct {
+	Name  *string
+	Items []validationItem
+}
+
+type validationItem struct {
+	ID *string
+}
+
+func validateInput(input interface{}) error {
+	v := input.(*validationInput)
+
+	invalid := &smithy.InvalidParamsError{Context: "validationInput"}
+	if v.Name == nil {
+		invalid.Add(smithy.NewParamRequiredError("Name"))
+	}
+	for i, item := range v.Items {
+		nested := &smithy.InvalidParamsError{Context: "validationItem"}
+		if item.ID == nil {
+			nested.Add(smithy.NewParamRequiredError("ID"))
+		}
+		invalid.AddNested(smithy.ListMemberPath("Items", i), nested)
+	}
+
+	if invalid.Len() != 0 {
+		return invalid
+	}
+	return nil
+}
+
+func TestInputValidation(t *testing.T) {
+	name, id := "name", "id"
+
+	cases := map[string]struct {
+		Input           *validationInput
+		ExpectPaths     []string
+		ExpectSerialize bool
+	}{
+		"valid": {
+			Input:           &validationInput{Name: &name, Items: []validationItem{{ID: &id}}},
+			ExpectSerialize: true,
+		},
+		"nested missing": {
+			Input:       &validationInput{Items: []validationItem{{ID: &id}, {}, {}}},
+			ExpectPaths: []string{"Name", "Items[1].ID", "Items[2].ID"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var serialized bool
+			m := InputValidation{Validate: validateInput}
+			_, _, err := m.HandleSerialize(context.Background(), SerializeInput{Parameters: c.Input},
+				serializeHandlerFunc(func(ctx context.Context, in SerializeInput) (
+					out SerializeOutput, metadata Metadata, err error,
+				) {
+					serialized = true
+					return out, metadata, nil
+				}))
+
+			if e, a := c.ExpectSerialize, serialized; e != a {
+				t.Errorf("expect serialized %v, got %v", e, a)
+			}
+			if len(c.ExpectPaths) == 0 {
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				return
+			}
+
+			var invalid *smithy.InvalidParamsError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expect invalid params error, got %v", err)
+			}
+			if e, a := len(c.ExpectPaths), invalid.Len(); e != a {
+				t.Fatalf("expect %v errors, got %v", e, a)
+			}
+			for i, e := range c.ExpectPaths {
+				if a := invalid.Errs[i].Path; e != a {
+					t.Errorf("expect %v path, got %v", e, a)
+				}
+			}
+		})
+	}
+}
+
+type serializeHandlerFunc func(context.Context, SerializeInput) (SerializeOutput, Metadata, error)
+
+func (fn serializeHandlerFunc) HandleSerialize(ctx context.Context, in SerializeInput) (
+	SerializeOutput, Metadata, error,
+) {
+	return fn(ctx, in)
+}
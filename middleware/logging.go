@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/awslabs/smithy-go/logging"
+)
+
+type loggerKey struct{}
+
+// SetLogger sets the logger the middleware will use to emit log entries.
+func SetLogger(ctx context.Context, logger logging.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// GetLogger returns the logger set on the context, or a logging.Nop logger
+// if no logger was set.
+func GetLogger(ctx context.Context) logging.Logger {
+	v, ok := ctx.Value(loggerKey{}).(logging.Logger)
+	if !ok || v == nil {
+		return logging.Nop{}
+	}
+	return v
+}
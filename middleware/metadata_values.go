@@ -0,0 +1,50 @@
+package middleware
+
+import "time"
+
+// Keys of the well-known metadata values. The request ID's key is shared
+// with its context value, see SetRequestIDMetadata.
+type (
+	attemptCountKey       struct{}
+	retryDelayKey         struct{}
+	responseStatusCodeKey struct{}
+)
+
+// SetAttemptCountMetadata sets the number of attempts made to send the
+// operation's request in the metadata.
+func SetAttemptCountMetadata(metadata *Metadata, count int) {
+	metadata.Set(attemptCountKey{}, count)
+}
+
+// GetAttemptCountMetadata returns the number of attempts made to send the
+// operation's request from the metadata, and if the count was set.
+func GetAttemptCountMetadata(metadata MetadataReader) (int, bool) {
+	v, ok := metadata.Get(attemptCountKey{}).(int)
+	return v, ok
+}
+
+// SetRetryDelayMetadata sets the total time delayed between the attempts of
+// the operation's request in the metadata.
+func SetRetryDelayMetadata(metadata *Metadata, delay time.Duration) {
+	metadata.Set(retryDelayKey{}, delay)
+}
+
+// GetRetryDelayMetadata returns the total time delayed between the attempts
+// of the operation's request from the metadata, and if the delay was set.
+func GetRetryDelayMetadata(metadata MetadataReader) (time.Duration, bool) {
+	v, ok := metadata.Get(retryDelayKey{}).(time.Duration)
+	return v, ok
+}
+
+// SetResponseStatusCodeMetadata sets the status code of the operation's
+// response in the metadata.
+func SetResponseStatusCodeMetadata(metadata *Metadata, code int) {
+	metadata.Set(responseStatusCodeKey{}, code)
+}
+
+// GetResponseStatusCodeMetadata returns the status code of the operation's
+// response from the metadata, and if the status code was set.
+func GetResponseStatusCodeMetadata(metadata MetadataReader) (int, bool) {
+	v, ok := metadata.Get(responseStatusCodeKey{}).(int)
+	return v, ok
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetadataValues(t *testing.T) {
+	var metadata Metadata
+
+	if _, ok := GetAttemptCountMetadata(metadata); ok {
+		t.Errorf("expect no attempt count metadata")
+	}
+	if _, ok := GetRetryDelayMetadata(metadata); ok {
+		t.Errorf("expect no retry delay metadata")
+	}
+	if _, ok := GetResponseStatusCodeMetadata(metadata); ok {
+		t.Errorf("expect no response status code metadata")
+	}
+
+	SetAttemptCountMetadata(&metadata, 3)
+	SetRetryDelayMetadata(&metadata, 250*time.Millisecond)
+	SetResponseStatusCodeMetadata(&metadata, 503)
+
+	if v, ok := GetAttemptCountMetadata(metadata); !ok || v != 3 {
+		t.Errorf("expect 3 attempt count metadata, got %v, %v", v, ok)
+	}
+	if v, ok := GetRetryDelayMetadata(metadata); !ok || v != 250*time.Millisecond {
+		t.Errorf("expect 250ms retry delay metadata, got %v, %v", v, ok)
+	}
+	if v, ok := GetResponseStatusCodeMetadata(metadata); !ok || v != 503 {
+		t.Errorf("expect 503 response status code metadata, got %v, %v", v, ok)
+	}
+}
@@ -61,9 +61,11 @@ func (m decoratedHandler) Handle(ctx context.Context, input interface{}) (
 type middlewareIDs struct{}
 
 // RecordMiddleware appends the middleware id to the list of middleware ids to
-// the context.
+// the context. The list is copied, so contexts derived from the same parent
+// can be used concurrently, (e.g. by hedged requests).
 func RecordMiddleware(ctx context.Context, id string) context.Context {
-	return context.WithValue(ctx, middlewareIDs{}, append(GetMiddlewareIDs(ctx), id))
+	ids := GetMiddlewareIDs(ctx)
+	return context.WithValue(ctx, middlewareIDs{}, append(ids[:len(ids):len(ids)], id))
 }
 
 // GetMiddlewareIDs returns a list of middleware IDs in the order they were
@@ -0,0 +1,19 @@
+package middleware
+
+import "context"
+
+type operationNameKey struct{}
+
+// WithOperationName returns a context with the name of the operation being
+// invoked set, so middleware can identify the operation, (e.g. to track
+// per-operation state).
+func WithOperationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// GetOperationName returns the name of the operation set on the context,
+// or empty string if no operation name was set.
+func GetOperationName(ctx context.Context) string {
+	v, _ := ctx.Value(operationNameKey{}).(string)
+	return v
+}
@@ -22,6 +22,11 @@ type orderedIDs struct {
 	order relativeOrder
 	items map[string]ider
 
+	// errors of the items that failed to be added, inserted, or swapped,
+	// reported by Stack.Validate. Building or invoking the stack does not
+	// report them.
+	errs []error
+
 	unamedCounter int
 }
 
@@ -39,7 +44,7 @@ func (g *orderedIDs) Add(m ider, pos RelativePosition) error {
 	}
 
 	if err := g.order.Add(m.ID(), pos); err != nil {
-		return err
+		return g.recordErr(fmt.Errorf("add %v, %w", m.ID(), err))
 	}
 
 	g.items[m.ID()] = m
@@ -57,7 +62,7 @@ func (g *orderedIDs) Insert(m ider, relativeTo string, pos RelativePosition) err
 	}
 
 	if err := g.order.Insert(m.ID(), relativeTo, pos); err != nil {
-		return err
+		return g.recordErr(fmt.Errorf("insert %v relative to %v, %w", m.ID(), relativeTo, err))
 	}
 
 	g.items[m.ID()] = m
@@ -75,7 +80,7 @@ func (g *orderedIDs) Swap(id string, m ider) (ider, error) {
 	}
 
 	if err := g.order.Swap(id, m.ID()); err != nil {
-		return nil, err
+		return nil, g.recordErr(fmt.Errorf("swap %v with %v, %w", id, m.ID(), err))
 	}
 
 	removed := g.items[id]
@@ -101,10 +106,29 @@ func (g *orderedIDs) Remove(id string) error {
 	return nil
 }
 
-// Clear removes all entries.
+// Clear removes all entries, and any recorded errors.
 func (g *orderedIDs) Clear() {
 	g.order.Clear()
 	g.items = map[string]ider{}
+	g.errs = nil
+}
+
+// recordErr records the error to be reported by Stack.Validate, and returns
+// it.
+func (g *orderedIDs) recordErr(err error) error {
+	g.errs = append(g.errs, err)
+	return err
+}
+
+// Errors returns the errors of the items which failed to be added,
+// inserted, or swapped.
+func (g *orderedIDs) Errors() []error {
+	return append([]error(nil), g.errs...)
+}
+
+// List returns the IDs of the items in the order they should be invoked in.
+func (g *orderedIDs) List() []string {
+	return g.order.GetOrder()
 }
 
 // GetOrder returns the item in the order it should be invoked in.
@@ -0,0 +1,26 @@
+package middleware
+
+import "context"
+
+type rawResponseHooksKey struct{}
+
+// WithRawResponseHook returns a context with the hook added to the hooks the
+// DeserializeStep calls with the raw response returned by the transport's
+// handler, before the response is deserialized. Finalize middleware use the
+// hook to access the transport's response, (e.g. to wrap its body), as the
+// result they are returned is the deserialized output. Hooks are called in
+// the order they were added, and must not retain the context.
+func WithRawResponseHook(ctx context.Context, fn func(rawResponse interface{})) context.Context {
+	hooks, _ := ctx.Value(rawResponseHooksKey{}).([]func(interface{}))
+	hooks = append(hooks[:len(hooks):len(hooks)], fn)
+	return context.WithValue(ctx, rawResponseHooksKey{}, hooks)
+}
+
+// callRawResponseHooks calls the hooks set on the context with the raw
+// response.
+func callRawResponseHooks(ctx context.Context, rawResponse interface{}) {
+	hooks, _ := ctx.Value(rawResponseHooksKey{}).([]func(interface{}))
+	for _, fn := range hooks {
+		fn(rawResponse)
+	}
+}
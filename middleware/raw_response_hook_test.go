@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestWithRawResponseHook(t *testing.T) {
+	stack := NewStack("hooks", func() interface{} { return struct{}{} })
+	stack.Deserialize.Add(DeserializeMiddlewareFunc("deserialize",
+		func(ctx context.Context, in DeserializeInput, next DeserializeHandler) (
+			out DeserializeOutput, metadata Metadata, err error,
+		) {
+			out, metadata, err = next.HandleDeserialize(ctx, in)
+			out.Result = "deserialized " + out.RawResponse.(string)
+			return out, metadata, err
+		}), After)
+
+	var calls []string
+	ctx := WithRawResponseHook(context.Background(), func(raw interface{}) {
+		calls = append(calls, "first "+raw.(string))
+	})
+	ctx = WithRawResponseHook(ctx, func(raw interface{}) {
+		calls = append(calls, "second "+raw.(string))
+	})
+
+	handler := DecorateHandler(HandlerFunc(func(ctx context.Context, in interface{}) (
+		interface{}, Metadata, error,
+	) {
+		return "response", Metadata{}, nil
+	}), stack)
+
+	out, _, err := handler.Handle(ctx, struct{}{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "deserialized response", out; e != a {
+		t.Errorf("expect %v output, got %v", e, a)
+	}
+	if e, a := []string{"first response", "second response"}, calls; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v hook calls, got %v", e, a)
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context with the request ID set, so the ID is
+// available to middleware and loggers handling the request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// GetRequestID returns the request ID set on the context, or empty string if
+// no request ID was set.
+func GetRequestID(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDKey{}).(string)
+	return v
+}
+
+// SetRequestIDMetadata sets the request ID returned by the service in the
+// metadata.
+func SetRequestIDMetadata(metadata *Metadata, id string) {
+	metadata.Set(requestIDKey{}, id)
+}
+
+// GetRequestIDMetadata returns the request ID returned by the service from
+// the metadata, and if the request ID was set.
+func GetRequestIDMetadata(metadata MetadataReader) (string, bool) {
+	v, ok := metadata.Get(requestIDKey{}).(string)
+	return v, ok
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	if e, a := "", GetRequestID(context.Background()); e != a {
+		t.Errorf("expect no request ID, got %v", a)
+	}
+
+	ctx := WithRequestID(context.Background(), "abc-123")
+	if e, a := "abc-123", GetRequestID(ctx); e != a {
+		t.Errorf("expect %v request ID, got %v", e, a)
+	}
+
+	var metadata Metadata
+	if _, ok := GetRequestIDMetadata(metadata); ok {
+		t.Errorf("expect no request ID metadata")
+	}
+	SetRequestIDMetadata(&metadata, "abc-123")
+	if v, ok := GetRequestIDMetadata(metadata); !ok || v != "abc-123" {
+		t.Errorf("expect abc-123 request ID metadata, got %v, %v", v, ok)
+	}
+}
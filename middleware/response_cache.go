@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ResponseCacheEntry is an operation's output stored in a ResponseCacheStore,
+// and the time it expires at.
+type ResponseCacheEntry struct {
+	Value   interface{}
+	Expires time.Time
+}
+
+// ResponseCacheStore provides the interface for the cache the ResponseCache
+// middleware stores outputs in. Implementations must be safe for concurrent
+// use.
+type ResponseCacheStore interface {
+	Get(key string) (ResponseCacheEntry, bool)
+	Set(key string, entry ResponseCacheEntry)
+	Delete(key string)
+}
+
+// MemoryResponseCache is a ResponseCacheStore keeping entries in memory.
+// Expired entries are deleted by the ResponseCache when they are next
+// retrieved.
+type MemoryResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]ResponseCacheEntry
+}
+
+// NewMemoryResponseCache returns an initialized, empty, MemoryResponseCache.
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{
+		entries: map[string]ResponseCacheEntry{},
+	}
+}
+
+// Get returns the entry for the key, if it is cached.
+func (c *MemoryResponseCache) Get(key string) (ResponseCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+// Set stores the entry for the key, replacing any existing entry.
+func (c *MemoryResponseCache) Set(key string, entry ResponseCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// Delete removes the entry for the key.
+func (c *MemoryResponseCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+type responseCacheHitKey struct{}
+
+// IsResponseCacheHitMetadata returns if the operation's output was returned
+// from the ResponseCache instead of sending the request.
+func IsResponseCacheHitMetadata(metadata MetadataReader) bool {
+	v, _ := metadata.Get(responseCacheHitKey{}).(bool)
+	return v
+}
+
+// ResponseCache is a finalize middleware that caches the outputs of
+// successful operations for the TTL, returning the cached output for
+// subsequent operations with the same cache key instead of sending their
+// request. The middleware should only be added to the stacks of idempotent
+// read operations, (e.g. describe operations).
+//
+// Outputs are stored, and returned, as copies made by Clone so that callers
+// modifying an output do not modify the cached value. Operations whose Key
+// function does not return a key are not cached. The expiry of entries is
+// determined with the context's clock, see GetClock, and outputs returned
+// from the cache are marked in the metadata, see IsResponseCacheHitMetadata.
+//
+// The middleware should be added first in the Finalize step, so cached
+// outputs are returned without the request being retried or signed.
+type ResponseCache struct {
+	// Cache outputs are stored in.
+	Cache ResponseCacheStore
+
+	// Returns the cache key for the operation's request, and if the request
+	// can be cached.
+	Key func(ctx context.Context, request interface{}) (string, bool)
+
+	// Duration outputs are cached for.
+	TTL time.Duration
+
+	// Returns a copy of the output. If nil, the output is cached as is.
+	Clone func(interface{}) interface{}
+}
+
+// ID returns the middleware identifier.
+func (m *ResponseCache) ID() string {
+	return "ResponseCache"
+}
+
+// HandleFinalize returns the cached output for the request's key, or invokes
+// the remainder of the stack, caching the output if it succeeded.
+func (m *ResponseCache) HandleFinalize(ctx context.Context, in FinalizeInput, next FinalizeHandler) (
+	out FinalizeOutput, metadata Metadata, err error,
+) {
+	if m.Cache == nil || m.Key == nil || m.TTL <= 0 {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	key, ok := m.Key(ctx, in.Request)
+	if !ok {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	now := GetClock(ctx).Now()
+	if entry, ok := m.Cache.Get(key); ok {
+		if now.Before(entry.Expires) {
+			out.Result = m.clone(entry.Value)
+			metadata.Set(responseCacheHitKey{}, true)
+			return out, metadata, nil
+		}
+		m.Cache.Delete(key)
+	}
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	m.Cache.Set(key, ResponseCacheEntry{
+		Value:   m.clone(out.Result),
+		Expires: now.Add(m.TTL),
+	})
+	return out, metadata, nil
+}
+
+func (m *ResponseCache) clone(v interface{}) interface{} {
+	if m.Clone == nil {
+		return v
+	}
+	return m.Clone(v)
+}
+
+var _ ResponseCacheStore = (*MemoryResponseCache)(nil)
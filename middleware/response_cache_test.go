@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+type cachedOutput struct {
+	Value string
+}
+
+type finalizeHandlerFunc func(context.Context, FinalizeInput) (FinalizeOutput, Metadata, error)
+
+func (fn finalizeHandlerFunc) HandleFinalize(ctx context.Context, in FinalizeInput) (
+	FinalizeOutput, Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+func TestResponseCache(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	ctx := SetClock(context.Background(), smithytime.ClockFunc(func() time.Time { return now }))
+
+	var calls int
+	var fail bool
+	next := finalizeHandlerFunc(func(ctx context.Context, in FinalizeInput) (
+		out FinalizeOutput, metadata Metadata, err error,
+	) {
+		calls++
+		if fail {
+			return out, metadata, fmt.Errorf("request failed")
+		}
+		out.Result = &cachedOutput{Value: fmt.Sprintf("%v-%d", in.Request, calls)}
+		return out, metadata, nil
+	})
+
+	m := &ResponseCache{
+		Cache: NewMemoryResponseCache(),
+		Key: func(ctx context.Context, request interface{}) (string, bool) {
+			key, ok := request.(string)
+			return key, ok && key != "uncached"
+		},
+		TTL: time.Minute,
+		Clone: func(v interface{}) interface{} {
+			cpy := *v.(*cachedOutput)
+			return &cpy
+		},
+	}
+
+	cases := []struct {
+		Name        string
+		Advance     time.Duration
+		Request     interface{}
+		Fail        bool
+		ExpectValue string
+		ExpectHit   bool
+		ExpectErr   bool
+	}{
+		{Name: "miss", Request: "a", ExpectValue: "a-1"},
+		{Name: "hit within TTL", Advance: 30 * time.Second, Request: "a", ExpectValue: "a-1", ExpectHit: true},
+		{Name: "different key", Request: "b", ExpectValue: "b-2"},
+		{Name: "miss after expiry", Advance: 31 * time.Second, Request: "a", ExpectValue: "a-3"},
+		{Name: "hit after refresh", Request: "a", ExpectValue: "a-3", ExpectHit: true},
+		{Name: "uncached key", Request: "uncached", ExpectValue: "uncached-4"},
+		{Name: "failure not cached", Request: "c", Fail: true, ExpectErr: true},
+		{Name: "miss after failure", Request: "c", ExpectValue: "c-6"},
+	}
+
+	for _, c := range cases {
+		now = now.Add(c.Advance)
+		fail = c.Fail
+
+		out, metadata, err := m.HandleFinalize(ctx, FinalizeInput{Request: c.Request}, next)
+		if c.ExpectErr {
+			if err == nil {
+				t.Fatalf("%s, expect error, got none", c.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s, expect no error, got %v", c.Name, err)
+		}
+
+		result := out.Result.(*cachedOutput)
+		if e, a := c.ExpectValue, result.Value; e != a {
+			t.Errorf("%s, expect %v value, got %v", c.Name, e, a)
+		}
+		if e, a := c.ExpectHit, IsResponseCacheHitMetadata(metadata); e != a {
+			t.Errorf("%s, expect cache hit %v, got %v", c.Name, e, a)
+		}
+
+		// Modifying the returned output must not modify the cached value.
+		result.Value = "modified"
+	}
+}
+
+func TestResponseCache_Concurrent(t *testing.T) {
+	m := &ResponseCache{
+		Cache: NewMemoryResponseCache(),
+		Key: func(ctx context.Context, request interface{}) (string, bool) {
+			return request.(string), true
+		},
+		TTL: time.Minute,
+	}
+	next := finalizeHandlerFunc(func(ctx context.Context, in FinalizeInput) (
+		out FinalizeOutput, metadata Metadata, err error,
+	) {
+		out.Result = in.Request
+		return out, metadata, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i%5)
+			out, _, err := m.HandleFinalize(context.Background(), FinalizeInput{Request: key}, next)
+			if err != nil {
+				t.Errorf("expect no error, got %v", err)
+			} else if e, a := key, out.Result; e != a {
+				t.Errorf("expect %v result, got %v", e, a)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
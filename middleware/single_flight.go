@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+)
+
+type singleFlightSharedKey struct{}
+
+// IsSingleFlightSharedMetadata returns if the operation's output was shared
+// from an identical operation in flight, by the SingleFlight middleware,
+// instead of sending the request.
+func IsSingleFlightSharedMetadata(metadata MetadataReader) bool {
+	v, _ := metadata.Get(singleFlightSharedKey{}).(bool)
+	return v
+}
+
+// SingleFlight is a finalize middleware that deduplicates identical
+// operations in flight concurrently. The first operation for a key sends its
+// request, and operations with the same key started before it completes wait
+// for, and share, its result instead of sending their own request. The
+// middleware should only be added to the stacks of idempotent read
+// operations, (e.g. GET requests).
+//
+// Each waiting operation receives a copy of the output made by Clone, and
+// the error of the shared operation, if any. Outputs shared are marked in the
+// metadata, see IsSingleFlightSharedMetadata. Operations whose Key function
+// does not return a key are not deduplicated. A waiting operation whose
+// context is canceled returns the context's error without waiting for the
+// shared operation to complete.
+//
+// The middleware should be added first in the Finalize step, so the shared
+// request is retried and signed once.
+type SingleFlight struct {
+	// Returns the key of the operation's request, and if the request can be
+	// deduplicated.
+	Key func(ctx context.Context, request interface{}) (string, bool)
+
+	// Returns a copy of the output. If nil, the output is shared as is.
+	Clone func(interface{}) interface{}
+
+	mu    sync.Mutex
+	calls map[string]*singleFlightCall
+}
+
+type singleFlightCall struct {
+	done     chan struct{}
+	out      FinalizeOutput
+	metadata Metadata
+	err      error
+}
+
+// ID returns the middleware identifier.
+func (m *SingleFlight) ID() string {
+	return "SingleFlight"
+}
+
+// HandleFinalize invokes the remainder of the stack for the request's key,
+// or waits for the result of the same key already in flight.
+func (m *SingleFlight) HandleFinalize(ctx context.Context, in FinalizeInput, next FinalizeHandler) (
+	out FinalizeOutput, metadata Metadata, err error,
+) {
+	if m.Key == nil {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	key, ok := m.Key(ctx, in.Request)
+	if !ok {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	m.mu.Lock()
+	if m.calls == nil {
+		m.calls = map[string]*singleFlightCall{}
+	}
+	if call, ok := m.calls[key]; ok {
+		m.mu.Unlock()
+		return m.wait(ctx, call)
+	}
+	call := &singleFlightCall{done: make(chan struct{})}
+	m.calls[key] = call
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.calls, key)
+		m.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.out, call.metadata, call.err = next.HandleFinalize(ctx, in)
+	out, metadata, err = call.out, copyMetadata(call.metadata), call.err
+	out.Result = m.clone(out.Result)
+	return out, metadata, err
+}
+
+func (m *SingleFlight) wait(ctx context.Context, call *singleFlightCall) (
+	out FinalizeOutput, metadata Metadata, err error,
+) {
+	select {
+	case <-ctx.Done():
+		return out, metadata, ctx.Err()
+	case <-call.done:
+	}
+
+	out = call.out
+	out.Result = m.clone(out.Result)
+	metadata = copyMetadata(call.metadata)
+	metadata.Set(singleFlightSharedKey{}, true)
+	return out, metadata, call.err
+}
+
+func (m *SingleFlight) clone(v interface{}) interface{} {
+	if m.Clone == nil || v == nil {
+		return v
+	}
+	return m.Clone(v)
+}
+
+// copyMetadata returns a shallow copy of the metadata, so that values set by
+// one caller are not visible to the others.
+func copyMetadata(m Metadata) Metadata {
+	var cpy Metadata
+	for k, v := range m.values {
+		cpy.Set(k, v)
+	}
+	return cpy
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSingleFlight(t *testing.T) {
+	const callers = 50
+
+	var sends int32
+	var keyed sync.WaitGroup
+	keyed.Add(callers)
+	release := make(chan struct{})
+
+	m := &SingleFlight{
+		Key: func(ctx context.Context, request interface{}) (string, bool) {
+			defer keyed.Done()
+			return request.(string), true
+		},
+		Clone: func(v interface{}) interface{} {
+			cpy := *v.(*cachedOutput)
+			return &cpy
+		},
+	}
+	next := finalizeHandlerFunc(func(ctx context.Context, in FinalizeInput) (
+		out FinalizeOutput, metadata Metadata, err error,
+	) {
+		atomic.AddInt32(&sends, 1)
+		<-release
+		out.Result = &cachedOutput{Value: "shared"}
+		metadata.Set("sent", true)
+		return out, metadata, nil
+	})
+
+	outputs := make([]*cachedOutput, callers)
+	shared := make([]bool, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			out, metadata, err := m.HandleFinalize(context.Background(), FinalizeInput{Request: "GET /thing"}, next)
+			errs[i] = err
+			shared[i] = IsSingleFlightSharedMetadata(metadata)
+			if v, _ := metadata.Get("sent").(bool); !v {
+				errs[i] = fmt.Errorf("expect metadata shared")
+			}
+			outputs[i], _ = out.Result.(*cachedOutput)
+		}(i)
+	}
+
+	// The first caller is blocked sending, so all callers keyed join it.
+	keyed.Wait()
+	close(release)
+	wg.Wait()
+
+	if e, a := int32(1), atomic.LoadInt32(&sends); e != a {
+		t.Errorf("expect %v send, got %v", e, a)
+	}
+
+	var sharedCount int
+	seen := map[*cachedOutput]bool{}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("expect no error, got %v", errs[i])
+		}
+		if outputs[i] == nil || outputs[i].Value != "shared" {
+			t.Fatalf("expect shared output, got %v", outputs[i])
+		}
+		if seen[outputs[i]] {
+			t.Errorf("expect output cloned per caller")
+		}
+		seen[outputs[i]] = true
+		if shared[i] {
+			sharedCount++
+		}
+	}
+	if e, a := callers-1, sharedCount; e != a {
+		t.Errorf("expect %v shared outputs, got %v", e, a)
+	}
+}
+
+func TestSingleFlightError(t *testing.T) {
+	const callers = 10
+
+	var keyed sync.WaitGroup
+	keyed.Add(callers)
+	release := make(chan struct{})
+
+	m := &SingleFlight{
+		Key: func(ctx context.Context, request interface{}) (string, bool) {
+			defer keyed.Done()
+			return "key", true
+		},
+	}
+	next := finalizeHandlerFunc(func(ctx context.Context, in FinalizeInput) (
+		out FinalizeOutput, metadata Metadata, err error,
+	) {
+		<-release
+		return out, metadata, fmt.Errorf("request failed")
+	})
+
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, _, err := m.HandleFinalize(context.Background(), FinalizeInput{}, next)
+			errs <- err
+		}()
+	}
+	keyed.Wait()
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		if err := <-errs; err == nil || err.Error() != "request failed" {
+			t.Errorf("expect request failed error, got %v", err)
+		}
+	}
+
+	// Completed keys are not shared with later operations.
+	var sends int
+	next = finalizeHandlerFunc(func(ctx context.Context, in FinalizeInput) (
+		out FinalizeOutput, metadata Metadata, err error,
+	) {
+		sends++
+		return out, metadata, nil
+	})
+	keyed.Add(2)
+	for i := 0; i < 2; i++ {
+		if _, _, err := m.HandleFinalize(context.Background(), FinalizeInput{}, next); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if e, a := 2, sends; e != a {
+		t.Errorf("expect %v sends, got %v", e, a)
+	}
+}
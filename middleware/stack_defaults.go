@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultMiddleware is a middleware registered with RegisterDefaultMiddleware.
+type defaultMiddleware struct {
+	step string
+	m    ider
+}
+
+var defaultRegistry = struct {
+	mu          sync.Mutex
+	middlewares []defaultMiddleware
+}{}
+
+// RegisterDefaultMiddleware registers the middleware to be added to the step
+// of every stack ApplyDefaults is called on, (e.g. logging or tracing
+// middleware shared by all clients). The step is one of the step names used
+// by StepID, (e.g. BuildStepName), and the middleware must implement the
+// step's middleware interface, (e.g. BuildMiddleware).
+//
+// Safe to call concurrently, and from init functions. Returns an error if
+// the step is unknown, the middleware does not implement the step's
+// interface, or a middleware with the same ID is already registered for the
+// step.
+func RegisterDefaultMiddleware(step string, m interface{}) error {
+	var ok bool
+	switch step {
+	case InitializeStepName:
+		_, ok = m.(InitializeMiddleware)
+	case SerializeStepName:
+		_, ok = m.(SerializeMiddleware)
+	case BuildStepName:
+		_, ok = m.(BuildMiddleware)
+	case FinalizeStepName:
+		_, ok = m.(FinalizeMiddleware)
+	case DeserializeStepName:
+		_, ok = m.(DeserializeMiddleware)
+	default:
+		return fmt.Errorf("unknown stack step %v", step)
+	}
+	if !ok {
+		return fmt.Errorf("%T is not a %v step middleware", m, step)
+	}
+	id := m.(ider).ID()
+	if len(id) == 0 {
+		return fmt.Errorf("empty ID, ID must not be empty")
+	}
+
+	defaultRegistry.mu.Lock()
+	defer defaultRegistry.mu.Unlock()
+
+	for _, d := range defaultRegistry.middlewares {
+		if d.step == step && d.m.ID() == id {
+			return fmt.Errorf("default %v middleware already registered, %v", step, id)
+		}
+	}
+	defaultRegistry.middlewares = append(defaultRegistry.middlewares, defaultMiddleware{
+		step: step,
+		m:    m.(ider),
+	})
+	return nil
+}
+
+// ApplyDefaults adds the middleware registered with RegisterDefaultMiddleware
+// to the end of their steps, in the order they were registered. Defaults
+// whose ID the step already has are not added, so ApplyDefaults may be
+// called more than once on a stack.
+func (s *Stack) ApplyDefaults() error {
+	defaultRegistry.mu.Lock()
+	defaults := append([]defaultMiddleware(nil), defaultRegistry.middlewares...)
+	defaultRegistry.mu.Unlock()
+
+	steps := map[string]stackStepList{}
+	for _, step := range s.steps() {
+		steps[step.name] = step
+	}
+
+	for _, d := range defaults {
+		ids := steps[d.step].ids
+		if _, ok := ids.items[d.m.ID()]; ok {
+			continue
+		}
+		if err := ids.Add(d.m, After); err != nil {
+			return fmt.Errorf("apply default %v middleware, %w", d.step, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"reflect"
+	"testing"
+)
+
+// resetDefaultMiddleware clears the registered default middleware once the
+// test completes.
+func resetDefaultMiddleware(t *testing.T) {
+	t.Cleanup(func() {
+		defaultRegistry.mu.Lock()
+		defaultRegistry.middlewares = nil
+		defaultRegistry.mu.Unlock()
+	})
+}
+
+func TestStackApplyDefaults(t *testing.T) {
+	resetDefaultMiddleware(t)
+
+	if err := RegisterDefaultMiddleware(FinalizeStepName, noopFinalizeMiddleware("tracing")); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if err := RegisterDefaultMiddleware(BuildStepName, noopBuildMiddleware("logging")); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	stack := newDiffTestStack(t)
+	for i := 0; i < 2; i++ {
+		if err := stack.ApplyDefaults(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+
+	expect := []StepID{
+		{Step: BuildStepName, ID: "a"},
+		{Step: BuildStepName, ID: "b"},
+		{Step: BuildStepName, ID: "c"},
+		{Step: BuildStepName, ID: "logging"},
+		{Step: FinalizeStepName, ID: "retry"},
+		{Step: FinalizeStepName, ID: "tracing"},
+	}
+	if e, a := expect, stack.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	fresh := NewStack("fresh", func() interface{} { return struct{}{} })
+	if err := fresh.ApplyDefaults(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect = []StepID{
+		{Step: BuildStepName, ID: "logging"},
+		{Step: FinalizeStepName, ID: "tracing"},
+	}
+	if e, a := expect, fresh.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestRegisterDefaultMiddleware_Invalid(t *testing.T) {
+	resetDefaultMiddleware(t)
+
+	cases := map[string]struct {
+		Step       string
+		Middleware interface{}
+	}{
+		"unknown step": {
+			Step:       "Send",
+			Middleware: noopBuildMiddleware("logging"),
+		},
+		"wrong step": {
+			Step:       FinalizeStepName,
+			Middleware: noopBuildMiddleware("logging"),
+		},
+		"duplicate": {
+			Step:       BuildStepName,
+			Middleware: noopBuildMiddleware("registered"),
+		},
+	}
+
+	if err := RegisterDefaultMiddleware(BuildStepName, noopBuildMiddleware("registered")); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := RegisterDefaultMiddleware(c.Step, c.Middleware); err == nil {
+				t.Errorf("expect error, got none")
+			}
+		})
+	}
+}
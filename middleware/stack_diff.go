@@ -0,0 +1,176 @@
+package middleware
+
+import "strings"
+
+// Names of the stack's steps used by StepID.
+const (
+	InitializeStepName  = "Initialize"
+	SerializeStepName   = "Serialize"
+	BuildStepName       = "Build"
+	FinalizeStepName    = "Finalize"
+	DeserializeStepName = "Deserialize"
+)
+
+// StepID identifies a middleware within a specific step of a stack.
+type StepID struct {
+	Step string
+	ID   string
+}
+
+func (id StepID) String() string {
+	return id.Step + "/" + id.ID
+}
+
+// List returns the IDs of all middleware in the stack, in the order they
+// will be invoked.
+func (s *Stack) List() []StepID {
+	var ids []StepID
+	for _, step := range s.steps() {
+		for _, id := range step.list() {
+			ids = append(ids, StepID{Step: step.name, ID: id})
+		}
+	}
+	return ids
+}
+
+type stackStepList struct {
+	name string
+	list func() []string
+	errs func() []error
+	ids  *orderedIDs
+}
+
+func (s *Stack) steps() []stackStepList {
+	return []stackStepList{
+		{name: InitializeStepName, list: s.Initialize.List, errs: s.Initialize.ids.Errors, ids: s.Initialize.ids},
+		{name: SerializeStepName, list: s.Serialize.List, errs: s.Serialize.ids.Errors, ids: s.Serialize.ids},
+		{name: BuildStepName, list: s.Build.List, errs: s.Build.ids.Errors, ids: s.Build.ids},
+		{name: FinalizeStepName, list: s.Finalize.List, errs: s.Finalize.ids.Errors, ids: s.Finalize.ids},
+		{name: DeserializeStepName, list: s.Deserialize.List, errs: s.Deserialize.ids.Errors, ids: s.Deserialize.ids},
+	}
+}
+
+// StackDiff is the difference between the middleware of two stacks.
+type StackDiff struct {
+	// Middleware present in the other stack, but not this stack.
+	Added []StepID
+
+	// Middleware present in this stack, but not the other stack.
+	Removed []StepID
+
+	// Middleware present in both stacks, but in a different order relative
+	// to the other middleware of the step.
+	Reordered []StepID
+}
+
+// Empty returns if the stacks compared have the same middleware in the same
+// order.
+func (d StackDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Reordered) == 0
+}
+
+// String returns a report of the differences, one middleware per line
+// prefixed with "+" if added, "-" if removed, and "~" if reordered.
+func (d StackDiff) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+
+	var sb strings.Builder
+	write := func(prefix string, ids []StepID) {
+		for _, id := range ids {
+			if sb.Len() != 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(prefix)
+			sb.WriteString(id.String())
+		}
+	}
+	write("+ ", d.Added)
+	write("- ", d.Removed)
+	write("~ ", d.Reordered)
+
+	return sb.String()
+}
+
+// Diff returns the difference between the middleware of this stack and the
+// other stack. Middleware is compared by ID within each step.
+func (s *Stack) Diff(other *Stack) StackDiff {
+	var diff StackDiff
+
+	otherSteps := other.steps()
+	for i, step := range s.steps() {
+		name := step.name
+		from, to := step.list(), otherSteps[i].list()
+
+		fromSet, toSet := idSet(from), idSet(to)
+		var fromCommon, toCommon []string
+		for _, id := range from {
+			if _, ok := toSet[id]; ok {
+				fromCommon = append(fromCommon, id)
+			} else {
+				diff.Removed = append(diff.Removed, StepID{Step: name, ID: id})
+			}
+		}
+		for _, id := range to {
+			if _, ok := fromSet[id]; ok {
+				toCommon = append(toCommon, id)
+			} else {
+				diff.Added = append(diff.Added, StepID{Step: name, ID: id})
+			}
+		}
+
+		inOrder := longestCommonSubsequence(fromCommon, toCommon)
+		for _, id := range toCommon {
+			if _, ok := inOrder[id]; !ok {
+				diff.Reordered = append(diff.Reordered, StepID{Step: name, ID: id})
+			}
+		}
+	}
+
+	return diff
+}
+
+func idSet(ids []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+// longestCommonSubsequence returns the set of IDs that make up the longest
+// subsequence common to both lists. The lists must contain the same IDs.
+func longestCommonSubsequence(a, b []string) map[string]struct{} {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	common := map[string]struct{}{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common[a[i]] = struct{}{}
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return common
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func noopBuildMiddleware(id string) BuildMiddleware {
+	return BuildMiddlewareFunc(id, func(ctx context.Context, in BuildInput, next BuildHandler) (
+		BuildOutput, Metadata, error,
+	) {
+		return next.HandleBuild(ctx, in)
+	})
+}
+
+func noopFinalizeMiddleware(id string) FinalizeMiddleware {
+	return FinalizeMiddlewareFunc(id, func(ctx context.Context, in FinalizeInput, next FinalizeHandler) (
+		FinalizeOutput, Metadata, error,
+	) {
+		return next.HandleFinalize(ctx, in)
+	})
+}
+
+func newDiffTestStack(t *testing.T) *Stack {
+	t.Helper()
+
+	stack := NewStack("diff", func() interface{} { return struct{}{} })
+	for _, id := range []string{"a", "b", "c"} {
+		if err := stack.Build.Add(noopBuildMiddleware(id), After); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if err := stack.Finalize.Add(noopFinalizeMiddleware("retry"), After); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return stack
+}
+
+func TestStackList(t *testing.T) {
+	stack := newDiffTestStack(t)
+
+	expect := []StepID{
+		{Step: BuildStepName, ID: "a"},
+		{Step: BuildStepName, ID: "b"},
+		{Step: BuildStepName, ID: "c"},
+		{Step: FinalizeStepName, ID: "retry"},
+	}
+	if e, a := expect, stack.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestStackDiff(t *testing.T) {
+	cases := map[string]struct {
+		Modify       func(*testing.T, *Stack)
+		ExpectDiff   StackDiff
+		ExpectString string
+	}{
+		"same": {
+			Modify:       func(*testing.T, *Stack) {},
+			ExpectString: "no differences",
+		},
+		"insert before": {
+			Modify: func(t *testing.T, s *Stack) {
+				if err := s.Build.Insert(noopBuildMiddleware("custom"), "b", Before); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+			},
+			ExpectDiff: StackDiff{
+				Added: []StepID{{Step: BuildStepName, ID: "custom"}},
+			},
+			ExpectString: "+ Build/custom",
+		},
+		"moved": {
+			Modify: func(t *testing.T, s *Stack) {
+				if err := s.Build.Remove("a"); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				if err := s.Build.Insert(noopBuildMiddleware("a"), "c", After); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+			},
+			ExpectDiff: StackDiff{
+				Reordered: []StepID{{Step: BuildStepName, ID: "a"}},
+			},
+			ExpectString: "~ Build/a",
+		},
+		"added and removed": {
+			Modify: func(t *testing.T, s *Stack) {
+				if err := s.Finalize.Remove("retry"); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				if err := s.Finalize.Add(noopFinalizeMiddleware("signing"), After); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+			},
+			ExpectDiff: StackDiff{
+				Added:   []StepID{{Step: FinalizeStepName, ID: "signing"}},
+				Removed: []StepID{{Step: FinalizeStepName, ID: "retry"}},
+			},
+			ExpectString: "+ Finalize/signing\n- Finalize/retry",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			base := newDiffTestStack(t)
+			other := newDiffTestStack(t)
+			c.Modify(t, other)
+
+			diff := base.Diff(other)
+			if e, a := c.ExpectDiff, diff; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v diff, got %v", e, a)
+			}
+			if e, a := c.ExpectString, diff.String(); e != a {
+				t.Errorf("expect %q report, got %q", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StackMergeConflictError is the error returned by Stack.MergeFrom if the
+// stacks have middleware with the same ID in the same step.
+type StackMergeConflictError struct {
+	StackID   string
+	OtherID   string
+	Conflicts []StepID
+}
+
+func (e *StackMergeConflictError) Error() string {
+	ids := make([]string, len(e.Conflicts))
+	for i, id := range e.Conflicts {
+		ids[i] = id.String()
+	}
+	return fmt.Sprintf("merge stack %v into %v, middleware already exist, %v",
+		e.OtherID, e.StackID, strings.Join(ids, ", "))
+}
+
+// MergeFrom adds the other stack's middleware to this stack. The middleware
+// of each of the other stack's steps are added after this stack's middleware
+// of the same step, in the same order relative to each other, (e.g. an
+// operation's customizations merged into a client's base stack).
+//
+// Returns a StackMergeConflictError listing every middleware of the other
+// stack with an ID this stack already has in the same step. If there are
+// conflicts, no middleware are merged. The other stack is not modified.
+func (s *Stack) MergeFrom(other *Stack) error {
+	steps, otherSteps := s.steps(), other.steps()
+
+	var conflicts []StepID
+	for i, step := range steps {
+		for _, id := range otherSteps[i].list() {
+			if _, ok := step.ids.items[id]; ok {
+				conflicts = append(conflicts, StepID{Step: step.name, ID: id})
+			}
+		}
+	}
+	if len(conflicts) != 0 {
+		return &StackMergeConflictError{StackID: s.id, OtherID: other.id, Conflicts: conflicts}
+	}
+
+	for i, step := range steps {
+		for _, m := range otherSteps[i].ids.GetOrder() {
+			if err := step.ids.Add(m.(ider), After); err != nil {
+				return fmt.Errorf("merge stack %v into %v, %w", other.id, s.id, err)
+			}
+		}
+	}
+	return nil
+}
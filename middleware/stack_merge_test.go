@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStackMergeFrom(t *testing.T) {
+	base := newDiffTestStack(t)
+
+	other := NewStack("operation", func() interface{} { return struct{}{} })
+	for _, id := range []string{"y", "x"} {
+		if err := other.Build.Add(noopBuildMiddleware(id), After); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if err := other.Finalize.Add(noopFinalizeMiddleware("signing"), After); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if err := base.MergeFrom(other); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []StepID{
+		{Step: BuildStepName, ID: "a"},
+		{Step: BuildStepName, ID: "b"},
+		{Step: BuildStepName, ID: "c"},
+		{Step: BuildStepName, ID: "y"},
+		{Step: BuildStepName, ID: "x"},
+		{Step: FinalizeStepName, ID: "retry"},
+		{Step: FinalizeStepName, ID: "signing"},
+	}
+	if e, a := expect, base.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+	if e, a := 3, len(other.List()); e != a {
+		t.Errorf("expect other stack unmodified with %v middleware, got %v", e, a)
+	}
+}
+
+func TestStackMergeFrom_Conflict(t *testing.T) {
+	base := newDiffTestStack(t)
+
+	other := NewStack("operation", func() interface{} { return struct{}{} })
+	for _, id := range []string{"x", "b"} {
+		if err := other.Build.Add(noopBuildMiddleware(id), After); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if err := other.Finalize.Add(noopFinalizeMiddleware("retry"), After); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	before := base.List()
+	err := base.MergeFrom(other)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+
+	var conflictErr *StackMergeConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expect conflict error, got %T, %v", err, err)
+	}
+	expect := []StepID{
+		{Step: BuildStepName, ID: "b"},
+		{Step: FinalizeStepName, ID: "retry"},
+	}
+	if e, a := expect, conflictErr.Conflicts; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v conflicts, got %v", e, a)
+	}
+	if e, a := "merge stack operation into diff, middleware already exist, Build/b, Finalize/retry", err.Error(); e != a {
+		t.Errorf("expect %q error, got %q", e, a)
+	}
+	if e, a := before, base.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect stack unmodified %v, got %v", e, a)
+	}
+}
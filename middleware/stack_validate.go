@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StackValidationError is the error returned by Stack.Validate, listing every
+// middleware that failed to be added, inserted, or swapped into the stack's
+// steps, (e.g. inserted relative to a middleware that does not exist, or
+// added with a duplicate ID).
+type StackValidationError struct {
+	StackID string
+	Errs    []StepError
+}
+
+// StepError is an error of a middleware that failed to be added to a step.
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e StepError) Error() string {
+	return e.Step + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e StepError) Unwrap() error { return e.Err }
+
+func (e *StackValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "invalid middleware stack %v, %d errors:", e.StackID, len(e.Errs))
+	for _, err := range e.Errs {
+		sb.WriteString("\n\t")
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// Validate returns if the stack's middleware were all added to the stack's
+// steps successfully. Returns a StackValidationError listing every failed
+// addition, insertion, or swap, instead of only the first.
+//
+// Errors returned by a step's Add, Insert, and Swap methods are commonly
+// ignored by functional options modifying a stack, Validate allows all of
+// the failures to be reported at once before the stack is invoked.
+func (s *Stack) Validate() error {
+	var errs []StepError
+	for _, step := range s.steps() {
+		for _, err := range step.errs() {
+			errs = append(errs, StepError{Step: step.name, Err: err})
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &StackValidationError{StackID: s.id, Errs: errs}
+}
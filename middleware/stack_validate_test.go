@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStackValidate(t *testing.T) {
+	noop := func(ctx context.Context, in FinalizeInput, next FinalizeHandler) (
+		out FinalizeOutput, metadata Metadata, err error,
+	) {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	stack := NewStack("fooStack", func() interface{} { return struct{}{} })
+	stack.Finalize.Add(FinalizeMiddlewareFunc("Retry", noop), After)
+	stack.Finalize.Add(FinalizeMiddlewareFunc("Signing", noop), After)
+
+	if err := stack.Validate(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Errors are commonly ignored when modifying a stack with options.
+	stack.Finalize.Insert(FinalizeMiddlewareFunc("Foo", noop), "Missing", After)
+	stack.Build.Insert(BuildMiddlewareFunc("Bar", func(ctx context.Context, in BuildInput, next BuildHandler) (
+		out BuildOutput, metadata Metadata, err error,
+	) {
+		return next.HandleBuild(ctx, in)
+	}), "AlsoMissing", Before)
+	stack.Finalize.Add(FinalizeMiddlewareFunc("Retry", noop), After)
+
+	err := stack.Validate()
+	var validationErr *StackValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expect stack validation error, got %v", err)
+	}
+
+	expect := []string{
+		"Build: insert Bar relative to AlsoMissing, not found, AlsoMissing",
+		"Finalize: insert Foo relative to Missing, not found, Missing",
+		"Finalize: add Retry, already exists, Retry",
+	}
+	if e, a := len(expect), len(validationErr.Errs); e != a {
+		t.Fatalf("expect %v errors, got %v, %v", e, a, err)
+	}
+	for i, e := range expect {
+		if a := validationErr.Errs[i].Error(); e != a {
+			t.Errorf("expect %q error, got %q", e, a)
+		}
+		if !strings.Contains(err.Error(), e) {
+			t.Errorf("expect %q in aggregated error, got %v", e, err)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "invalid middleware stack fooStack, 3 errors:") {
+		t.Errorf("expect stack ID in error, got %v", err)
+	}
+
+	stack.Finalize.Clear()
+	stack.Build.Clear()
+	if err := stack.Validate(); err != nil {
+		t.Errorf("expect no error after clear, got %v", err)
+	}
+}
@@ -149,6 +149,12 @@ func (s *BuildStep) Clear() {
 	s.ids.Clear()
 }
 
+// List returns the IDs of the middleware in the step, in the order they will
+// be invoked.
+func (s *BuildStep) List() []string {
+	return s.ids.List()
+}
+
 type buildWrapHandler struct {
 	Next Handler
 }
@@ -157,6 +157,12 @@ func (s *DeserializeStep) Clear() {
 	s.ids.Clear()
 }
 
+// List returns the IDs of the middleware in the step, in the order they will
+// be invoked.
+func (s *DeserializeStep) List() []string {
+	return s.ids.List()
+}
+
 type deserializeWrapHandler struct {
 	Next Handler
 }
@@ -169,6 +175,9 @@ func (w deserializeWrapHandler) HandleDeserialize(ctx context.Context, in Deseri
 	out DeserializeOutput, metadata Metadata, err error,
 ) {
 	resp, metadata, err := w.Next.Handle(ctx, in.Request)
+	if resp != nil {
+		callRawResponseHooks(ctx, resp)
+	}
 	return DeserializeOutput{
 		RawResponse: resp,
 	}, metadata, err
@@ -151,6 +151,12 @@ func (s *FinalizeStep) Clear() {
 	s.ids.Clear()
 }
 
+// List returns the IDs of the middleware in the step, in the order they will
+// be invoked.
+func (s *FinalizeStep) List() []string {
+	return s.ids.List()
+}
+
 type finalizeWrapHandler struct {
 	Next Handler
 }
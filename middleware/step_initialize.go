@@ -151,6 +151,12 @@ func (s *InitializeStep) Clear() {
 	s.ids.Clear()
 }
 
+// List returns the IDs of the middleware in the step, in the order they will
+// be invoked.
+func (s *InitializeStep) List() []string {
+	return s.ids.List()
+}
+
 type initializeWrapHandler struct {
 	Next Handler
 }
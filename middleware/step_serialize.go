@@ -159,6 +159,12 @@ func (s *SerializeStep) Clear() {
 	s.ids.Clear()
 }
 
+// List returns the IDs of the middleware in the step, in the order they will
+// be invoked.
+func (s *SerializeStep) List() []string {
+	return s.ids.List()
+}
+
 type serializeWrapHandler struct {
 	Next Handler
 }
@@ -0,0 +1,130 @@
+package smithy
+
+import (
+	"context"
+	"fmt"
+)
+
+// PageFunc invokes the paginated operation with the input, returning the
+// page's output.
+type PageFunc func(ctx context.Context, input interface{}) (output interface{}, err error)
+
+// PaginatorOptions provides the options for configuring a Paginator.
+type PaginatorOptions struct {
+	// Returns the output's token for the next page, or nil if the output is
+	// the last page. Required.
+	OutputToken func(output interface{}) *string
+
+	// Sets the token of the next page to retrieve on the input. Required.
+	SetInputToken func(input interface{}, token *string)
+
+	// Maximum number of items to retrieve from all pages. If zero, all pages
+	// are retrieved. Requires ItemCount.
+	MaxItems int
+
+	// Returns the number of items in the page's output.
+	ItemCount func(output interface{}) int
+
+	// Maximum number of items to retrieve per page. If zero, the service's
+	// default page size is used. Requires SetPageSize.
+	PageSize int32
+
+	// Sets the page size limit on the input, (e.g. the input's MaxResults
+	// member).
+	SetPageSize func(input interface{}, size int32)
+}
+
+// Paginator retrieves the pages of a paginated operation, setting the token
+// of each page's output on the input of the next page.
+//
+// Pagination stops once an output's token is nil, or is set but empty.
+// Sending an empty token would retrieve the first page again, so a present
+// but zero length token is treated the same as no token. Pagination also
+// stops once MaxItems items have been retrieved. If PageSize is set, the page
+// size of the last page is reduced to the items remaining, otherwise the last
+// page may contain more items than remaining.
+//
+// The input is modified in place by the paginator for each page.
+type Paginator struct {
+	input   interface{}
+	page    PageFunc
+	options PaginatorOptions
+
+	firstPage bool
+	nextToken *string
+	items     int
+}
+
+// NewPaginator returns a Paginator retrieving the pages of the operation
+// invoked by the page function, starting with the input, configured by the
+// functional options.
+func NewPaginator(input interface{}, page PageFunc, optFns ...func(*PaginatorOptions)) *Paginator {
+	var o PaginatorOptions
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return &Paginator{
+		input:     input,
+		page:      page,
+		options:   o,
+		firstPage: true,
+	}
+}
+
+// HasMorePages returns if there are more pages to be retrieved.
+func (p *Paginator) HasMorePages() bool {
+	if p.maxItemsReached() {
+		return false
+	}
+	return p.firstPage || (p.nextToken != nil && len(*p.nextToken) != 0)
+}
+
+// NextPage retrieves the next page's output. Returns an error if there are no
+// more pages, the options are not set, or the operation fails.
+func (p *Paginator) NextPage(ctx context.Context) (interface{}, error) {
+	if !p.HasMorePages() {
+		return nil, fmt.Errorf("no more pages available")
+	}
+	if p.options.OutputToken == nil || p.options.SetInputToken == nil {
+		return nil, fmt.Errorf("paginator output and input token functions not set")
+	}
+	if p.options.MaxItems > 0 && p.options.ItemCount == nil {
+		return nil, fmt.Errorf("paginator item count function not set for max items")
+	}
+
+	p.options.SetInputToken(p.input, p.nextToken)
+	if size := p.pageSize(); size > 0 && p.options.SetPageSize != nil {
+		p.options.SetPageSize(p.input, size)
+	}
+
+	output, err := p.page(ctx, p.input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.firstPage = false
+	p.nextToken = p.options.OutputToken(output)
+	if p.options.ItemCount != nil {
+		p.items += p.options.ItemCount(output)
+	}
+
+	return output, nil
+}
+
+// pageSize returns the page size limit of the next page, reduced to the
+// number of items remaining if MaxItems is set.
+func (p *Paginator) pageSize() int32 {
+	size := p.options.PageSize
+	if size <= 0 || p.options.MaxItems <= 0 {
+		return size
+	}
+	if remaining := p.options.MaxItems - p.items; int(size) > remaining {
+		return int32(remaining)
+	}
+	return size
+}
+
+func (p *Paginator) maxItemsReached() bool {
+	return p.options.MaxItems > 0 && p.items >= p.options.MaxItems
+}
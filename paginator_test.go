@@ -0,0 +1,177 @@
+package smithy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/awslabs/smithy-go/ptr"
+)
+
+type pageInput struct {
+	Token *string
+	Limit int32
+}
+
+type pageOutput struct {
+	Items     []string
+	NextToken *string
+}
+
+func newTestPaginator(pages map[string]*pageOutput, inputs *[]pageInput, optFns ...func(*PaginatorOptions)) *Paginator {
+	page := func(ctx context.Context, input interface{}) (interface{}, error) {
+		in := input.(*pageInput)
+		*inputs = append(*inputs, *in)
+
+		var token string
+		if in.Token != nil {
+			token = *in.Token
+		}
+		out, ok := pages[token]
+		if !ok {
+			return nil, fmt.Errorf("unknown page token %q", token)
+		}
+		if in.Limit > 0 && int(in.Limit) < len(out.Items) {
+			return &pageOutput{Items: out.Items[:in.Limit], NextToken: out.NextToken}, nil
+		}
+		return out, nil
+	}
+
+	optFns = append([]func(*PaginatorOptions){func(o *PaginatorOptions) {
+		o.OutputToken = func(output interface{}) *string {
+			return output.(*pageOutput).NextToken
+		}
+		o.SetInputToken = func(input interface{}, token *string) {
+			input.(*pageInput).Token = token
+		}
+		o.ItemCount = func(output interface{}) int {
+			return len(output.(*pageOutput).Items)
+		}
+		o.SetPageSize = func(input interface{}, size int32) {
+			input.(*pageInput).Limit = size
+		}
+	}}, optFns...)
+
+	return NewPaginator(&pageInput{}, page, optFns...)
+}
+
+func collectPages(t *testing.T, p *Paginator) [][]string {
+	t.Helper()
+	var pages [][]string
+	for p.HasMorePages() {
+		out, err := p.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		pages = append(pages, out.(*pageOutput).Items)
+	}
+	return pages
+}
+
+func TestPaginator(t *testing.T) {
+	cases := map[string]struct {
+		Pages        map[string]*pageOutput
+		Options      func(*PaginatorOptions)
+		Expect       [][]string
+		ExpectTokens []*string
+		ExpectLimits []int32
+	}{
+		"three pages": {
+			Pages: map[string]*pageOutput{
+				"":   {Items: []string{"a", "b"}, NextToken: ptr.String("t1")},
+				"t1": {Items: []string{"c", "d"}, NextToken: ptr.String("t2")},
+				"t2": {Items: []string{"e"}},
+			},
+			Expect:       [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+			ExpectTokens: []*string{nil, ptr.String("t1"), ptr.String("t2")},
+			ExpectLimits: []int32{0, 0, 0},
+		},
+		"empty token ends pagination": {
+			Pages: map[string]*pageOutput{
+				"":   {Items: []string{"a"}, NextToken: ptr.String("t1")},
+				"t1": {Items: []string{"b"}, NextToken: ptr.String("")},
+			},
+			Expect:       [][]string{{"a"}, {"b"}},
+			ExpectTokens: []*string{nil, ptr.String("t1")},
+			ExpectLimits: []int32{0, 0},
+		},
+		"max items truncation": {
+			Pages: map[string]*pageOutput{
+				"":   {Items: []string{"a", "b"}, NextToken: ptr.String("t1")},
+				"t1": {Items: []string{"c", "d"}, NextToken: ptr.String("t2")},
+				"t2": {Items: []string{"e", "f"}, NextToken: ptr.String("t3")},
+			},
+			Options: func(o *PaginatorOptions) {
+				o.MaxItems = 5
+				o.PageSize = 2
+			},
+			Expect:       [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+			ExpectTokens: []*string{nil, ptr.String("t1"), ptr.String("t2")},
+			ExpectLimits: []int32{2, 2, 1},
+		},
+		"max items without page size": {
+			Pages: map[string]*pageOutput{
+				"":   {Items: []string{"a", "b"}, NextToken: ptr.String("t1")},
+				"t1": {Items: []string{"c", "d"}, NextToken: ptr.String("t2")},
+			},
+			Options: func(o *PaginatorOptions) {
+				o.MaxItems = 3
+			},
+			Expect:       [][]string{{"a", "b"}, {"c", "d"}},
+			ExpectTokens: []*string{nil, ptr.String("t1")},
+			ExpectLimits: []int32{0, 0},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var inputs []pageInput
+			var optFns []func(*PaginatorOptions)
+			if c.Options != nil {
+				optFns = append(optFns, c.Options)
+			}
+			p := newTestPaginator(c.Pages, &inputs, optFns...)
+
+			if e, a := c.Expect, collectPages(t, p); !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v pages, got %v", e, a)
+			}
+
+			var tokens []*string
+			var limits []int32
+			for _, in := range inputs {
+				tokens = append(tokens, in.Token)
+				limits = append(limits, in.Limit)
+			}
+			if e, a := c.ExpectTokens, tokens; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v input tokens, got %v", e, a)
+			}
+			if e, a := c.ExpectLimits, limits; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v page sizes, got %v", e, a)
+			}
+
+			if _, err := p.NextPage(context.Background()); err == nil {
+				t.Errorf("expect error retrieving page after last, got none")
+			}
+		})
+	}
+}
+
+func TestPaginatorPageError(t *testing.T) {
+	var inputs []pageInput
+	p := newTestPaginator(map[string]*pageOutput{}, &inputs)
+
+	if _, err := p.NextPage(context.Background()); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if !p.HasMorePages() {
+		t.Errorf("expect failed first page to be retrievable again")
+	}
+
+	p = NewPaginator(&pageInput{}, func(context.Context, interface{}) (interface{}, error) {
+		return &pageOutput{}, nil
+	})
+	if _, err := p.NextPage(context.Background()); err == nil {
+		t.Errorf("expect error for paginator without token functions, got none")
+	}
+}
@@ -0,0 +1,69 @@
+package smithy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// paginationTokenVersion is the version of the compound pagination token
+// format. Tokens of other versions fail to decode.
+const paginationTokenVersion byte = 1
+
+// InvalidPaginationTokenError is returned by DecodePaginationToken when the
+// token is corrupt, or was encoded with an unsupported version.
+type InvalidPaginationTokenError struct {
+	Err error
+}
+
+// Unwrap returns the underlying error.
+func (e *InvalidPaginationTokenError) Unwrap() error { return e.Err }
+
+func (e *InvalidPaginationTokenError) Error() string {
+	return fmt.Sprintf("invalid pagination token, %v", e.Err)
+}
+
+// EncodePaginationToken encodes the named tokens into a single opaque,
+// versioned, base64url token, for APIs paginating on multiple fields. The
+// token is set on the input by the Paginator's SetInputToken, and decoded
+// with DecodePaginationToken.
+func EncodePaginationToken(tokens map[string]string) (string, error) {
+	if tokens == nil {
+		tokens = map[string]string{}
+	}
+	b, err := json.Marshal(tokens)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pagination token, %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(
+		append([]byte{paginationTokenVersion}, b...)), nil
+}
+
+// DecodePaginationToken decodes the named tokens of a token encoded by
+// EncodePaginationToken. Returns an InvalidPaginationTokenError if the token
+// is corrupt, or its version is not supported.
+func DecodePaginationToken(token string) (map[string]string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, &InvalidPaginationTokenError{Err: err}
+	}
+	if len(b) == 0 {
+		return nil, &InvalidPaginationTokenError{Err: fmt.Errorf("empty token")}
+	}
+	if v := b[0]; v != paginationTokenVersion {
+		return nil, &InvalidPaginationTokenError{
+			Err: fmt.Errorf("unsupported version %d", v),
+		}
+	}
+
+	var tokens map[string]string
+	if err := json.Unmarshal(b[1:], &tokens); err != nil {
+		return nil, &InvalidPaginationTokenError{Err: err}
+	}
+	if tokens == nil {
+		return nil, &InvalidPaginationTokenError{Err: fmt.Errorf("missing tokens")}
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,66 @@
+package smithy
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPaginationTokenRoundTrip(t *testing.T) {
+	cases := map[string]map[string]string{
+		"empty":  {},
+		"single": {"marker": "abc"},
+		"multiple": {
+			"keyMarker":       "photos/2020/",
+			"versionIdMarker": "3/L4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY+MTRCxf3vjVBH40Nr8X8gdRQBpUMLUo",
+		},
+	}
+
+	for name, tokens := range cases {
+		t.Run(name, func(t *testing.T) {
+			token, err := EncodePaginationToken(tokens)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			actual, err := DecodePaginationToken(token)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := tokens, actual; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v tokens, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestDecodePaginationTokenInvalid(t *testing.T) {
+	token, err := EncodePaginationToken(map[string]string{"marker": "abc"})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	raw, _ := base64.RawURLEncoding.DecodeString(token)
+
+	cases := map[string]string{
+		"empty":               "",
+		"not base64url":       "!!" + token,
+		"truncated":           token[:len(token)-4],
+		"unsupported version": base64.RawURLEncoding.EncodeToString(append([]byte{2}, raw[1:]...)),
+		"not an object":       base64.RawURLEncoding.EncodeToString([]byte("\x01null")),
+	}
+
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodePaginationToken(token)
+			if err == nil {
+				t.Fatalf("expect error, got none")
+			}
+
+			var tokenErr *InvalidPaginationTokenError
+			if !errors.As(err, &tokenErr) {
+				t.Errorf("expect %T error, got %T", tokenErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+//go:build go1.21
+// +build go1.21
+
+package smithy
+
+import (
+	"context"
+	"fmt"
+)
+
+// TypedPaginatorOptions provides the options for configuring a
+// TypedPaginator, the same as PaginatorOptions with functions of the
+// operation's input and output types.
+type TypedPaginatorOptions[In, Out any] struct {
+	// Returns the output's token for the next page, or nil if the output is
+	// the last page. Required.
+	OutputToken func(output Out) *string
+
+	// Sets the token of the next page to retrieve on the input. Required.
+	SetInputToken func(input In, token *string)
+
+	// Maximum number of items to retrieve from all pages. If zero, all pages
+	// are retrieved. Requires ItemCount.
+	MaxItems int
+
+	// Returns the number of items in the page's output.
+	ItemCount func(output Out) int
+
+	// Maximum number of items to retrieve per page. If zero, the service's
+	// default page size is used. Requires SetPageSize.
+	PageSize int32
+
+	// Sets the page size limit on the input, (e.g. the input's MaxResults
+	// member).
+	SetPageSize func(input In, size int32)
+}
+
+// TypedPaginator retrieves the pages of a paginated operation with input
+// type In and output type Out, the same as Paginator, without the type
+// assertions of Paginator's functions.
+//
+// Requires Go 1.21 or later.
+type TypedPaginator[In, Out any] struct {
+	paginator *Paginator
+}
+
+// NewTypedPaginator returns a TypedPaginator retrieving the pages of the
+// operation invoked by the page function, starting with the input,
+// configured by the functional options.
+func NewTypedPaginator[In, Out any](
+	input In, page func(ctx context.Context, input In) (Out, error),
+	optFns ...func(*TypedPaginatorOptions[In, Out]),
+) *TypedPaginator[In, Out] {
+	var o TypedPaginatorOptions[In, Out]
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	pageFn := func(ctx context.Context, input interface{}) (interface{}, error) {
+		in, _ := input.(In)
+		out, err := page(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		if interface{}(out) == nil {
+			return nil, fmt.Errorf("page function returned nil output")
+		}
+		return out, nil
+	}
+
+	return &TypedPaginator[In, Out]{
+		paginator: NewPaginator(input, pageFn, func(po *PaginatorOptions) {
+			po.MaxItems = o.MaxItems
+			po.PageSize = o.PageSize
+			if fn := o.OutputToken; fn != nil {
+				po.OutputToken = func(output interface{}) *string {
+					out, _ := output.(Out)
+					return fn(out)
+				}
+			}
+			if fn := o.SetInputToken; fn != nil {
+				po.SetInputToken = func(input interface{}, token *string) {
+					in, _ := input.(In)
+					fn(in, token)
+				}
+			}
+			if fn := o.ItemCount; fn != nil {
+				po.ItemCount = func(output interface{}) int {
+					out, _ := output.(Out)
+					return fn(out)
+				}
+			}
+			if fn := o.SetPageSize; fn != nil {
+				po.SetPageSize = func(input interface{}, size int32) {
+					in, _ := input.(In)
+					fn(in, size)
+				}
+			}
+		}),
+	}
+}
+
+// HasMorePages returns if there are more pages to be retrieved.
+func (p *TypedPaginator[In, Out]) HasMorePages() bool {
+	return p.paginator.HasMorePages()
+}
+
+// NextPage retrieves the next page's output. Returns an error if there are no
+// more pages, the options are not set, or the operation fails.
+func (p *TypedPaginator[In, Out]) NextPage(ctx context.Context) (Out, error) {
+	var out Out
+	v, err := p.paginator.NextPage(ctx)
+	if err != nil {
+		return out, err
+	}
+	out, ok := v.(Out)
+	if !ok {
+		return out, fmt.Errorf("unexpected page output type %T", v)
+	}
+	return out, nil
+}
@@ -0,0 +1,82 @@
+//go:build go1.21
+// +build go1.21
+
+package smithy
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/awslabs/smithy-go/ptr"
+)
+
+func TestTypedPaginator(t *testing.T) {
+	pages := map[string]*pageOutput{
+		"":   {Items: []string{"a", "b"}, NextToken: ptr.String("t1")},
+		"t1": {Items: []string{"c", "d"}, NextToken: ptr.String("t2")},
+		"t2": {Items: []string{"e"}},
+	}
+
+	var limits []int32
+	page := func(ctx context.Context, in *pageInput) (*pageOutput, error) {
+		limits = append(limits, in.Limit)
+
+		var token string
+		if in.Token != nil {
+			token = *in.Token
+		}
+		out, ok := pages[token]
+		if !ok {
+			return nil, fmt.Errorf("unknown page token %q", token)
+		}
+		return out, nil
+	}
+
+	p := NewTypedPaginator(&pageInput{}, page, func(o *TypedPaginatorOptions[*pageInput, *pageOutput]) {
+		o.OutputToken = func(out *pageOutput) *string { return out.NextToken }
+		o.SetInputToken = func(in *pageInput, token *string) { in.Token = token }
+		o.ItemCount = func(out *pageOutput) int { return len(out.Items) }
+		o.SetPageSize = func(in *pageInput, size int32) { in.Limit = size }
+		o.PageSize = 2
+	})
+
+	var actual [][]string
+	for p.HasMorePages() {
+		out, err := p.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		actual = append(actual, out.Items)
+	}
+
+	if e, a := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, actual; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v pages, got %v", e, a)
+	}
+	if e, a := []int32{2, 2, 2}, limits; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v limits, got %v", e, a)
+	}
+	if _, err := p.NextPage(context.Background()); err == nil {
+		t.Errorf("expect error after last page, got none")
+	}
+}
+
+type pageOutputer interface {
+	Token() *string
+}
+
+func TestTypedPaginator_NilOutput(t *testing.T) {
+	page := func(ctx context.Context, in *pageInput) (pageOutputer, error) {
+		return nil, nil
+	}
+
+	p := NewTypedPaginator(&pageInput{}, page, func(o *TypedPaginatorOptions[*pageInput, pageOutputer]) {
+		o.OutputToken = func(out pageOutputer) *string { return out.Token() }
+		o.SetInputToken = func(in *pageInput, token *string) { in.Token = token }
+	})
+
+	if _, err := p.NextPage(context.Background()); err == nil {
+		t.Errorf("expect error for nil page output, got none")
+	}
+}
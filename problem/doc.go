@@ -0,0 +1,12 @@
+/*
+Package problem provides the mapping of API errors to the problem details
+JSON structure described by RFC 7807, for services exposing operation errors
+to end users.
+
+	details, ok := problem.FromError(err)
+	if ok {
+		body, err := details.MarshalJSON()
+		// write body with the problem.ContentType content type.
+	}
+*/
+package problem
@@ -0,0 +1,129 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/encoding/json"
+)
+
+// ContentType is the media type of a problem details JSON document.
+const ContentType = "application/problem+json"
+
+// DefaultType is the problem type of problems without an error code.
+const DefaultType = "about:blank"
+
+// ProblemDetails is the RFC 7807 problem details of an API error.
+type ProblemDetails struct {
+	// URI reference identifying the problem type.
+	Type string
+
+	// Short summary of the problem type.
+	Title string
+
+	// HTTP status code of the problem.
+	Status int
+
+	// Explanation specific to this occurrence of the problem.
+	Detail string
+
+	// URI reference identifying this occurrence of the problem.
+	Instance string
+
+	// Additional members of the problem details. Members with the name of a
+	// standard member are ignored.
+	Extensions map[string]interface{}
+}
+
+// ToProblemDetails returns the problem details of the API error. The type and
+// title are the error's code, and the detail its message. The status is the
+// error's HTTP status code if it has one, otherwise 400 for client faults
+// and 500 for all other errors.
+func ToProblemDetails(err smithy.APIError) ProblemDetails {
+	p := ProblemDetails{
+		Type:   DefaultType,
+		Title:  err.ErrorCode(),
+		Status: statusCode(err),
+		Detail: err.ErrorMessage(),
+	}
+	if len(p.Title) != 0 {
+		p.Type = p.Title
+	} else {
+		p.Title = http.StatusText(p.Status)
+	}
+	return p
+}
+
+// FromError returns the problem details of the APIError wrapped by err, and
+// if an APIError was found. If the error is wrapped by an OperationError,
+// the root cause APIError is used, and the service and operation names are
+// added as the "service" and "operation" extension members.
+func FromError(err error) (ProblemDetails, bool) {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return ProblemDetails{}, false
+	}
+	p := ToProblemDetails(apiErr)
+
+	var opErr *smithy.OperationError
+	if errors.As(err, &opErr) {
+		p.Extensions = map[string]interface{}{
+			"service":   opErr.Service(),
+			"operation": opErr.Operation(),
+		}
+	}
+	return p, true
+}
+
+// statusCode returns the HTTP status code of the error, or the default status
+// code of the error's fault.
+func statusCode(err smithy.APIError) int {
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) && statusErr.HTTPStatusCode() != 0 {
+		return statusErr.HTTPStatusCode()
+	}
+	if err.ErrorFault() == smithy.FaultClient {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// MarshalJSON returns the problem details as a problem+json document. Empty
+// members are omitted, except type which defaults to DefaultType.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	v := make(map[string]interface{}, 5+len(p.Extensions))
+	for k, ev := range p.Extensions {
+		v[k] = ev
+	}
+
+	if len(p.Type) != 0 {
+		v["type"] = p.Type
+	} else {
+		v["type"] = DefaultType
+	}
+	setString(v, "title", p.Title)
+	setString(v, "detail", p.Detail)
+	setString(v, "instance", p.Instance)
+	if p.Status != 0 {
+		v["status"] = p.Status
+	} else {
+		delete(v, "status")
+	}
+
+	encoder := json.NewEncoder()
+	encoder.SortMapKeys(true)
+	encoder.EmitEmptyCollections(true)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return encoder.Bytes(), nil
+}
+
+func setString(v map[string]interface{}, key, value string) {
+	if len(value) != 0 {
+		v[key] = value
+	} else {
+		delete(v, key)
+	}
+}
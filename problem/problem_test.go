@@ -0,0 +1,142 @@
+package problem
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+)
+
+type statusError struct {
+	smithy.GenericAPIError
+	status int
+}
+
+func (e *statusError) HTTPStatusCode() int { return e.status }
+
+func TestToProblemDetails(t *testing.T) {
+	cases := map[string]struct {
+		Err    smithy.APIError
+		Expect ProblemDetails
+	}{
+		"client fault": {
+			Err: &smithy.GenericAPIError{
+				Code: "ValidationException", Message: "invalid name", Fault: smithy.FaultClient,
+			},
+			Expect: ProblemDetails{
+				Type: "ValidationException", Title: "ValidationException",
+				Status: 400, Detail: "invalid name",
+			},
+		},
+		"server fault": {
+			Err: &smithy.GenericAPIError{
+				Code: "InternalError", Message: "try again", Fault: smithy.FaultServer,
+			},
+			Expect: ProblemDetails{
+				Type: "InternalError", Title: "InternalError",
+				Status: 500, Detail: "try again",
+			},
+		},
+		"unknown fault": {
+			Err: &smithy.GenericAPIError{Code: "Unknown"},
+			Expect: ProblemDetails{
+				Type: "Unknown", Title: "Unknown", Status: 500,
+			},
+		},
+		"no code": {
+			Err: &smithy.GenericAPIError{Message: "bad", Fault: smithy.FaultClient},
+			Expect: ProblemDetails{
+				Type: DefaultType, Title: "Bad Request", Status: 400, Detail: "bad",
+			},
+		},
+		"status code": {
+			Err: &statusError{
+				GenericAPIError: smithy.GenericAPIError{Code: "NotFound", Fault: smithy.FaultClient},
+				status:          404,
+			},
+			Expect: ProblemDetails{
+				Type: "NotFound", Title: "NotFound", Status: 404,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Expect, ToProblemDetails(c.Err); !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	err := fmt.Errorf("request failed, %w", &smithy.OperationError{
+		ServiceName:   "Example",
+		OperationName: "GetItem",
+		Err: &smithy.GenericAPIError{
+			Code: "ResourceNotFound", Message: "item not found", Fault: smithy.FaultClient,
+		},
+	})
+
+	p, ok := FromError(err)
+	if !ok {
+		t.Fatalf("expect API error found")
+	}
+	expect := ProblemDetails{
+		Type:   "ResourceNotFound",
+		Title:  "ResourceNotFound",
+		Status: 400,
+		Detail: "item not found",
+		Extensions: map[string]interface{}{
+			"service":   "Example",
+			"operation": "GetItem",
+		},
+	}
+	if e, a := expect, p; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if _, ok := FromError(fmt.Errorf("not an API error")); ok {
+		t.Errorf("expect no API error found")
+	}
+}
+
+func TestProblemDetails_MarshalJSON(t *testing.T) {
+	cases := map[string]struct {
+		Details ProblemDetails
+		Expect  string
+	}{
+		"full": {
+			Details: ProblemDetails{
+				Type: "ValidationException", Title: "ValidationException",
+				Status: 400, Detail: "invalid \"name\"", Instance: "/items/1",
+				Extensions: map[string]interface{}{"operation": "PutItem"},
+			},
+			Expect: `{"detail":"invalid \"name\"","instance":"/items/1","operation":"PutItem",` +
+				`"status":400,"title":"ValidationException","type":"ValidationException"}`,
+		},
+		"empty": {
+			Expect: `{"type":"about:blank"}`,
+		},
+		"extension does not replace standard member": {
+			Details: ProblemDetails{
+				Status:     500,
+				Extensions: map[string]interface{}{"status": "ok", "title": "x"},
+			},
+			Expect: `{"status":500,"type":"about:blank"}`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := c.Details.MarshalJSON()
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, string(b); e != a {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+/*
+Package ptr provides utilities for converting values to and from pointers.
+
+Functions follow a naming convention based on the Go name of the value's
+type so generated code can refer to them without a lookup table, and the
+names remain stable as types are added:
+
+	<Type>(v) *T                  value to pointer
+	To<Type>(p) T                 pointer to value, zero value if nil
+	<Type>Slice([]T) []*T         slice of values to slice of pointers
+	To<Type>Slice([]*T) []T       slice of pointers to slice of values
+	<Type>Map(map[string]T)       map of values to map of pointers
+	To<Type>Map(map[string]*T)    map of pointers to map of values
+
+For example String, ToString, StringSlice, ToStringSlice, StringMap, and
+ToStringMap convert string values.
+*/
+package ptr
@@ -0,0 +1,203 @@
+package ptr
+
+import (
+	"time"
+)
+
+// ToBool returns bool value dereferenced if the passed in pointer was not nil.
+// Returns a bool zero value if the pointer was nil.
+func ToBool(p *bool) (v bool) {
+	if p == nil {
+		return v
+	}
+
+	return *p
+}
+
+// ToBoolSlice returns a slice of bool values, that are dereferenced
+// if the passed in pointer was not nil. The bool zero value is used if
+// the pointer was nil.
+func ToBoolSlice(vs []*bool) []bool {
+	ps := make([]bool, len(vs))
+	for i, v := range vs {
+		ps[i] = ToBool(v)
+	}
+
+	return ps
+}
+
+// ToBoolMap returns a map of bool values, that are dereferenced if the passed
+// in pointer was not nil. The bool zero value is used if the pointer was nil.
+func ToBoolMap(vs map[string]*bool) map[string]bool {
+	ps := make(map[string]bool, len(vs))
+	for k, v := range vs {
+		ps[k] = ToBool(v)
+	}
+
+	return ps
+}
+
+// ToInt32 returns int32 value dereferenced if the passed in pointer was not nil.
+// Returns a int32 zero value if the pointer was nil.
+func ToInt32(p *int32) (v int32) {
+	if p == nil {
+		return v
+	}
+
+	return *p
+}
+
+// ToInt32Slice returns a slice of int32 values, that are dereferenced
+// if the passed in pointer was not nil. The int32 zero value is used if
+// the pointer was nil.
+func ToInt32Slice(vs []*int32) []int32 {
+	ps := make([]int32, len(vs))
+	for i, v := range vs {
+		ps[i] = ToInt32(v)
+	}
+
+	return ps
+}
+
+// ToInt32Map returns a map of int32 values, that are dereferenced if the passed
+// in pointer was not nil. The int32 zero value is used if the pointer was nil.
+func ToInt32Map(vs map[string]*int32) map[string]int32 {
+	ps := make(map[string]int32, len(vs))
+	for k, v := range vs {
+		ps[k] = ToInt32(v)
+	}
+
+	return ps
+}
+
+// ToInt64 returns int64 value dereferenced if the passed in pointer was not nil.
+// Returns a int64 zero value if the pointer was nil.
+func ToInt64(p *int64) (v int64) {
+	if p == nil {
+		return v
+	}
+
+	return *p
+}
+
+// ToInt64Slice returns a slice of int64 values, that are dereferenced
+// if the passed in pointer was not nil. The int64 zero value is used if
+// the pointer was nil.
+func ToInt64Slice(vs []*int64) []int64 {
+	ps := make([]int64, len(vs))
+	for i, v := range vs {
+		ps[i] = ToInt64(v)
+	}
+
+	return ps
+}
+
+// ToInt64Map returns a map of int64 values, that are dereferenced if the passed
+// in pointer was not nil. The int64 zero value is used if the pointer was nil.
+func ToInt64Map(vs map[string]*int64) map[string]int64 {
+	ps := make(map[string]int64, len(vs))
+	for k, v := range vs {
+		ps[k] = ToInt64(v)
+	}
+
+	return ps
+}
+
+// ToFloat64 returns float64 value dereferenced if the passed in pointer was not nil.
+// Returns a float64 zero value if the pointer was nil.
+func ToFloat64(p *float64) (v float64) {
+	if p == nil {
+		return v
+	}
+
+	return *p
+}
+
+// ToFloat64Slice returns a slice of float64 values, that are dereferenced
+// if the passed in pointer was not nil. The float64 zero value is used if
+// the pointer was nil.
+func ToFloat64Slice(vs []*float64) []float64 {
+	ps := make([]float64, len(vs))
+	for i, v := range vs {
+		ps[i] = ToFloat64(v)
+	}
+
+	return ps
+}
+
+// ToFloat64Map returns a map of float64 values, that are dereferenced if the passed
+// in pointer was not nil. The float64 zero value is used if the pointer was nil.
+func ToFloat64Map(vs map[string]*float64) map[string]float64 {
+	ps := make(map[string]float64, len(vs))
+	for k, v := range vs {
+		ps[k] = ToFloat64(v)
+	}
+
+	return ps
+}
+
+// ToString returns string value dereferenced if the passed in pointer was not nil.
+// Returns a string zero value if the pointer was nil.
+func ToString(p *string) (v string) {
+	if p == nil {
+		return v
+	}
+
+	return *p
+}
+
+// ToStringSlice returns a slice of string values, that are dereferenced
+// if the passed in pointer was not nil. The string zero value is used if
+// the pointer was nil.
+func ToStringSlice(vs []*string) []string {
+	ps := make([]string, len(vs))
+	for i, v := range vs {
+		ps[i] = ToString(v)
+	}
+
+	return ps
+}
+
+// ToStringMap returns a map of string values, that are dereferenced if the passed
+// in pointer was not nil. The string zero value is used if the pointer was nil.
+func ToStringMap(vs map[string]*string) map[string]string {
+	ps := make(map[string]string, len(vs))
+	for k, v := range vs {
+		ps[k] = ToString(v)
+	}
+
+	return ps
+}
+
+// ToTime returns time.Time value dereferenced if the passed in pointer was not nil.
+// Returns a time.Time zero value if the pointer was nil.
+func ToTime(p *time.Time) (v time.Time) {
+	if p == nil {
+		return v
+	}
+
+	return *p
+}
+
+// ToTimeSlice returns a slice of time.Time values, that are dereferenced
+// if the passed in pointer was not nil. The time.Time zero value is used if
+// the pointer was nil.
+func ToTimeSlice(vs []*time.Time) []time.Time {
+	ps := make([]time.Time, len(vs))
+	for i, v := range vs {
+		ps[i] = ToTime(v)
+	}
+
+	return ps
+}
+
+// ToTimeMap returns a map of time.Time values, that are dereferenced if the passed
+// in pointer was not nil. The time.Time zero value is used if the pointer was nil.
+func ToTimeMap(vs map[string]*time.Time) map[string]time.Time {
+	ps := make(map[string]time.Time, len(vs))
+	for k, v := range vs {
+		ps[k] = ToTime(v)
+	}
+
+	return ps
+}
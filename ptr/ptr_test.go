@@ -0,0 +1,295 @@
+package ptr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBool(t *testing.T) {
+	v := true
+
+	p := Bool(v)
+	if p == nil || !reflect.DeepEqual(v, *p) {
+		t.Errorf("expect pointer to %v, got %v", v, p)
+	}
+	if &v == p {
+		t.Errorf("expect pointer to copy of value")
+	}
+	if e, a := v, ToBool(p); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var zero bool
+	if e, a := zero, ToBool(nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect zero value %v for nil, got %v", e, a)
+	}
+
+	ps := BoolSlice([]bool{v, zero})
+	if e, a := 2, len(ps); e != a {
+		t.Fatalf("expect %v pointers, got %v", e, a)
+	}
+	if ps[0] == ps[1] {
+		t.Errorf("expect unique pointers for each slice element")
+	}
+	if e, a := []bool{v, zero, zero}, ToBoolSlice(append(ps, nil)); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	pm := BoolMap(map[string]bool{"a": v, "b": zero})
+	if pm["a"] == pm["b"] {
+		t.Errorf("expect unique pointers for each map value")
+	}
+	pm["c"] = nil
+	expectMap := map[string]bool{"a": v, "b": zero, "c": zero}
+	if e, a := expectMap, ToBoolMap(pm); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 0, len(ToBoolSlice(nil)); e != a {
+		t.Errorf("expect %v length for nil slice, got %v", e, a)
+	}
+	if e, a := 0, len(ToBoolMap(nil)); e != a {
+		t.Errorf("expect %v length for nil map, got %v", e, a)
+	}
+}
+
+func TestInt32(t *testing.T) {
+	v := int32(-32)
+
+	p := Int32(v)
+	if p == nil || !reflect.DeepEqual(v, *p) {
+		t.Errorf("expect pointer to %v, got %v", v, p)
+	}
+	if &v == p {
+		t.Errorf("expect pointer to copy of value")
+	}
+	if e, a := v, ToInt32(p); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var zero int32
+	if e, a := zero, ToInt32(nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect zero value %v for nil, got %v", e, a)
+	}
+
+	ps := Int32Slice([]int32{v, zero})
+	if e, a := 2, len(ps); e != a {
+		t.Fatalf("expect %v pointers, got %v", e, a)
+	}
+	if ps[0] == ps[1] {
+		t.Errorf("expect unique pointers for each slice element")
+	}
+	if e, a := []int32{v, zero, zero}, ToInt32Slice(append(ps, nil)); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	pm := Int32Map(map[string]int32{"a": v, "b": zero})
+	if pm["a"] == pm["b"] {
+		t.Errorf("expect unique pointers for each map value")
+	}
+	pm["c"] = nil
+	expectMap := map[string]int32{"a": v, "b": zero, "c": zero}
+	if e, a := expectMap, ToInt32Map(pm); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 0, len(ToInt32Slice(nil)); e != a {
+		t.Errorf("expect %v length for nil slice, got %v", e, a)
+	}
+	if e, a := 0, len(ToInt32Map(nil)); e != a {
+		t.Errorf("expect %v length for nil map, got %v", e, a)
+	}
+}
+
+func TestInt64(t *testing.T) {
+	v := int64(1) << 40
+
+	p := Int64(v)
+	if p == nil || !reflect.DeepEqual(v, *p) {
+		t.Errorf("expect pointer to %v, got %v", v, p)
+	}
+	if &v == p {
+		t.Errorf("expect pointer to copy of value")
+	}
+	if e, a := v, ToInt64(p); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var zero int64
+	if e, a := zero, ToInt64(nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect zero value %v for nil, got %v", e, a)
+	}
+
+	ps := Int64Slice([]int64{v, zero})
+	if e, a := 2, len(ps); e != a {
+		t.Fatalf("expect %v pointers, got %v", e, a)
+	}
+	if ps[0] == ps[1] {
+		t.Errorf("expect unique pointers for each slice element")
+	}
+	if e, a := []int64{v, zero, zero}, ToInt64Slice(append(ps, nil)); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	pm := Int64Map(map[string]int64{"a": v, "b": zero})
+	if pm["a"] == pm["b"] {
+		t.Errorf("expect unique pointers for each map value")
+	}
+	pm["c"] = nil
+	expectMap := map[string]int64{"a": v, "b": zero, "c": zero}
+	if e, a := expectMap, ToInt64Map(pm); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 0, len(ToInt64Slice(nil)); e != a {
+		t.Errorf("expect %v length for nil slice, got %v", e, a)
+	}
+	if e, a := 0, len(ToInt64Map(nil)); e != a {
+		t.Errorf("expect %v length for nil map, got %v", e, a)
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	v := 1.5
+
+	p := Float64(v)
+	if p == nil || !reflect.DeepEqual(v, *p) {
+		t.Errorf("expect pointer to %v, got %v", v, p)
+	}
+	if &v == p {
+		t.Errorf("expect pointer to copy of value")
+	}
+	if e, a := v, ToFloat64(p); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var zero float64
+	if e, a := zero, ToFloat64(nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect zero value %v for nil, got %v", e, a)
+	}
+
+	ps := Float64Slice([]float64{v, zero})
+	if e, a := 2, len(ps); e != a {
+		t.Fatalf("expect %v pointers, got %v", e, a)
+	}
+	if ps[0] == ps[1] {
+		t.Errorf("expect unique pointers for each slice element")
+	}
+	if e, a := []float64{v, zero, zero}, ToFloat64Slice(append(ps, nil)); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	pm := Float64Map(map[string]float64{"a": v, "b": zero})
+	if pm["a"] == pm["b"] {
+		t.Errorf("expect unique pointers for each map value")
+	}
+	pm["c"] = nil
+	expectMap := map[string]float64{"a": v, "b": zero, "c": zero}
+	if e, a := expectMap, ToFloat64Map(pm); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 0, len(ToFloat64Slice(nil)); e != a {
+		t.Errorf("expect %v length for nil slice, got %v", e, a)
+	}
+	if e, a := 0, len(ToFloat64Map(nil)); e != a {
+		t.Errorf("expect %v length for nil map, got %v", e, a)
+	}
+}
+
+func TestString(t *testing.T) {
+	v := "foo"
+
+	p := String(v)
+	if p == nil || !reflect.DeepEqual(v, *p) {
+		t.Errorf("expect pointer to %v, got %v", v, p)
+	}
+	if &v == p {
+		t.Errorf("expect pointer to copy of value")
+	}
+	if e, a := v, ToString(p); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var zero string
+	if e, a := zero, ToString(nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect zero value %v for nil, got %v", e, a)
+	}
+
+	ps := StringSlice([]string{v, zero})
+	if e, a := 2, len(ps); e != a {
+		t.Fatalf("expect %v pointers, got %v", e, a)
+	}
+	if ps[0] == ps[1] {
+		t.Errorf("expect unique pointers for each slice element")
+	}
+	if e, a := []string{v, zero, zero}, ToStringSlice(append(ps, nil)); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	pm := StringMap(map[string]string{"a": v, "b": zero})
+	if pm["a"] == pm["b"] {
+		t.Errorf("expect unique pointers for each map value")
+	}
+	pm["c"] = nil
+	expectMap := map[string]string{"a": v, "b": zero, "c": zero}
+	if e, a := expectMap, ToStringMap(pm); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 0, len(ToStringSlice(nil)); e != a {
+		t.Errorf("expect %v length for nil slice, got %v", e, a)
+	}
+	if e, a := 0, len(ToStringMap(nil)); e != a {
+		t.Errorf("expect %v length for nil map, got %v", e, a)
+	}
+}
+
+func TestTime(t *testing.T) {
+	v := time.Unix(1600000000, 0)
+
+	p := Time(v)
+	if p == nil || !reflect.DeepEqual(v, *p) {
+		t.Errorf("expect pointer to %v, got %v", v, p)
+	}
+	if &v == p {
+		t.Errorf("expect pointer to copy of value")
+	}
+	if e, a := v, ToTime(p); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	var zero time.Time
+	if e, a := zero, ToTime(nil); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect zero value %v for nil, got %v", e, a)
+	}
+
+	ps := TimeSlice([]time.Time{v, zero})
+	if e, a := 2, len(ps); e != a {
+		t.Fatalf("expect %v pointers, got %v", e, a)
+	}
+	if ps[0] == ps[1] {
+		t.Errorf("expect unique pointers for each slice element")
+	}
+	if e, a := []time.Time{v, zero, zero}, ToTimeSlice(append(ps, nil)); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	pm := TimeMap(map[string]time.Time{"a": v, "b": zero})
+	if pm["a"] == pm["b"] {
+		t.Errorf("expect unique pointers for each map value")
+	}
+	pm["c"] = nil
+	expectMap := map[string]time.Time{"a": v, "b": zero, "c": zero}
+	if e, a := expectMap, ToTimeMap(pm); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	if e, a := 0, len(ToTimeSlice(nil)); e != a {
+		t.Errorf("expect %v length for nil slice, got %v", e, a)
+	}
+	if e, a := 0, len(ToTimeMap(nil)); e != a {
+		t.Errorf("expect %v length for nil map, got %v", e, a)
+	}
+}
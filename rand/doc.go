@@ -0,0 +1,3 @@
+// Package rand provides utilities for creating and working with random
+// values, (e.g. UUIDs used as idempotency tokens).
+package rand
@@ -0,0 +1,67 @@
+package rand
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// Reader is the default random source used to generate values, which is
+// crypto/rand's Reader.
+var Reader io.Reader = crand.Reader
+
+// UUID provides generating version 4 UUIDs from a random source.
+type UUID struct {
+	randSrc io.Reader
+}
+
+// NewUUID returns an initialized UUID generator that reads random bytes from
+// the reader. If the reader is nil, the Reader random source is used.
+func NewUUID(r io.Reader) *UUID {
+	if r == nil {
+		r = Reader
+	}
+	return &UUID{randSrc: r}
+}
+
+// GetUUID returns a random version 4 UUID formatted as a string, (e.g.
+// 6ba7b810-9dad-41d1-80b4-00c04fd430c8). Returns an error if the random
+// source fails to be read.
+func (r *UUID) GetUUID() (string, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(r.randSrc, b[:]); err != nil {
+		return "", fmt.Errorf("failed to read random bytes for UUID, %w", err)
+	}
+
+	// Set the version 4 and RFC 4122 variant bits.
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+
+	return string(s[:]), nil
+}
+
+// GetIdempotencyToken returns a random UUID to be used as an idempotency
+// token.
+func (r *UUID) GetIdempotencyToken() (string, error) {
+	return r.GetUUID()
+}
+
+// IdempotencyTokenProvider provides the interface for generating idempotency
+// tokens for operation inputs.
+type IdempotencyTokenProvider interface {
+	GetIdempotencyToken() (string, error)
+}
+
+var _ IdempotencyTokenProvider = (*UUID)(nil)
@@ -0,0 +1,49 @@
+package rand
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestUUID(t *testing.T) {
+	src := bytes.NewReader(make([]byte, 16))
+	uuid, err := NewUUID(src).GetUUID()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "00000000-0000-4000-8000-000000000000", uuid; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+
+	src = bytes.NewReader(bytes.Repeat([]byte{0xff}, 16))
+	if uuid, err = NewUUID(src).GetUUID(); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "ffffffff-ffff-4fff-bfff-ffffffffffff", uuid; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestUUIDDefaultReader(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	uuid, err := NewUUID(nil).GetUUID()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !pattern.MatchString(uuid) {
+		t.Errorf("expect version 4 UUID, got %v", uuid)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, fmt.Errorf("read error") }
+
+func TestUUIDReadError(t *testing.T) {
+	if _, err := NewUUID(errReader{}).GetUUID(); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
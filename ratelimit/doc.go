@@ -0,0 +1,6 @@
+/*
+Package ratelimit provides the token bucket used to limit the rate retries,
+and requests, are made by clients. A single TokenBucket is safe to share
+between all goroutines making requests with a client.
+*/
+package ratelimit
@@ -0,0 +1,172 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+// QuotaExceededError provides the error type for when the token bucket does
+// not have enough tokens, and will not be refilled, to satisfy the cost
+// requested.
+type QuotaExceededError struct {
+	Available uint
+	Cost      uint
+}
+
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("retry quota exceeded, %d available, %d requested",
+		e.Available, e.Cost)
+}
+
+// TokenBucketOptions provides the options for configuring a TokenBucket.
+type TokenBucketOptions struct {
+	// Maximum number of tokens the bucket can hold. The bucket starts full.
+	Capacity uint
+
+	// Number of tokens per second added to the bucket. If zero, the bucket
+	// is only refilled by AddTokens.
+	FillRate float64
+
+	// Clock used to determine how many tokens have been refilled. Defaults
+	// to the system clock.
+	Clock smithytime.Clock
+}
+
+// TokenBucket provides a concurrency safe bucket of tokens that are retrieved
+// as they are used, and refilled either over time at the fill rate, or
+// explicitly with AddTokens.
+type TokenBucket struct {
+	mu         sync.Mutex
+	capacity   float64
+	tokens     float64
+	fillRate   float64
+	lastRefill time.Time
+	clock      smithytime.Clock
+}
+
+// NewTokenBucket returns an initialized, full, TokenBucket with the
+// capacity, modified by the functional options provided.
+func NewTokenBucket(capacity uint, optFns ...func(*TokenBucketOptions)) *TokenBucket {
+	o := TokenBucketOptions{
+		Capacity: capacity,
+		Clock:    smithytime.SystemClock{},
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return &TokenBucket{
+		capacity:   float64(o.Capacity),
+		tokens:     float64(o.Capacity),
+		fillRate:   o.FillRate,
+		lastRefill: o.Clock.Now(),
+		clock:      o.Clock,
+	}
+}
+
+// GetToken retrieves the cost in tokens from the bucket. If the bucket has a
+// fill rate, GetToken blocks until enough tokens have been refilled, or the
+// context is done. Returns a QuotaExceededError if the bucket does not have
+// enough tokens and has no fill rate, or if the cost exceeds the bucket's
+// capacity.
+func (b *TokenBucket) GetToken(ctx context.Context, cost uint) error {
+	for {
+		wait, err := b.retrieve(cost)
+		if err != nil || wait == 0 {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// retrieve removes the cost from the bucket if available, otherwise returns
+// how long to wait for enough tokens to be refilled.
+func (b *TokenBucket) retrieve(cost uint) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+
+	c := float64(cost)
+	if c <= b.tokens {
+		b.tokens -= c
+		return 0, nil
+	}
+
+	if b.fillRate <= 0 || c > b.capacity {
+		return 0, QuotaExceededError{Available: uint(b.tokens), Cost: cost}
+	}
+
+	wait := time.Duration((c - b.tokens) / b.fillRate * float64(time.Second))
+	if wait <= 0 {
+		wait = time.Millisecond
+	}
+	return wait, nil
+}
+
+// AddTokens adds tokens to the bucket, up to the bucket's capacity.
+func (b *TokenBucket) AddTokens(v uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens += float64(v)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// Available returns the number of whole tokens available in the bucket.
+func (b *TokenBucket) Available() uint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return uint(b.tokens)
+}
+
+// FillRate returns the number of tokens per second added to the bucket.
+func (b *TokenBucket) FillRate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.fillRate
+}
+
+// SetFillRate updates the number of tokens per second added to the bucket.
+// Tokens refilled at the previous rate are added before the rate changes.
+func (b *TokenBucket) SetFillRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.fillRate = rate
+}
+
+// refill adds the tokens filled since the last refill. Must be called with
+// the lock held.
+func (b *TokenBucket) refill() {
+	now := b.clock.Now()
+	elapsed := now.Sub(b.lastRefill)
+	b.lastRefill = now
+
+	if b.fillRate <= 0 || elapsed <= 0 {
+		return
+	}
+
+	b.tokens += elapsed.Seconds() * b.fillRate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+type mockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *mockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(10)
+
+	if err := bucket.GetToken(context.Background(), 4); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := uint(6), bucket.Available(); e != a {
+		t.Errorf("expect %v available, got %v", e, a)
+	}
+
+	err := bucket.GetToken(context.Background(), 7)
+	var quotaErr QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expect quota exceeded error, got %v", err)
+	}
+	if e, a := uint(6), quotaErr.Available; e != a {
+		t.Errorf("expect %v available, got %v", e, a)
+	}
+
+	bucket.AddTokens(2)
+	if e, a := uint(8), bucket.Available(); e != a {
+		t.Errorf("expect %v available, got %v", e, a)
+	}
+
+	bucket.AddTokens(100)
+	if e, a := uint(10), bucket.Available(); e != a {
+		t.Errorf("expect capacity %v available, got %v", e, a)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	clock := &mockClock{now: time.Unix(0, 0)}
+	bucket := NewTokenBucket(10, func(o *TokenBucketOptions) {
+		o.FillRate = 2
+		o.Clock = clock
+	})
+
+	if err := bucket.GetToken(context.Background(), 10); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := uint(0), bucket.Available(); e != a {
+		t.Errorf("expect %v available, got %v", e, a)
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if e, a := uint(3), bucket.Available(); e != a {
+		t.Errorf("expect %v available, got %v", e, a)
+	}
+
+	bucket.SetFillRate(1)
+	clock.Advance(2 * time.Second)
+	if e, a := uint(5), bucket.Available(); e != a {
+		t.Errorf("expect %v available, got %v", e, a)
+	}
+
+	clock.Advance(time.Minute)
+	if e, a := uint(10), bucket.Available(); e != a {
+		t.Errorf("expect capacity %v available, got %v", e, a)
+	}
+}
+
+func TestTokenBucketGetTokenWaits(t *testing.T) {
+	bucket := NewTokenBucket(5, func(o *TokenBucketOptions) {
+		o.FillRate = 500
+	})
+	if err := bucket.GetToken(context.Background(), 5); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	start := time.Now()
+	if err := bucket.GetToken(context.Background(), 5); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Errorf("expect to wait for refill, waited %v", elapsed)
+	}
+
+	if err := bucket.GetToken(context.Background(), 6); err == nil {
+		t.Errorf("expect error for cost exceeding capacity, got none")
+	}
+}
+
+func TestTokenBucketGetTokenContextCanceled(t *testing.T) {
+	bucket := NewTokenBucket(1, func(o *TokenBucketOptions) {
+		o.FillRate = 0.001
+		o.Clock = smithytime.SystemClock{}
+	})
+	if err := bucket.GetToken(context.Background(), 1); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if e, a := context.DeadlineExceeded, bucket.GetToken(ctx, 1); e != a {
+		t.Errorf("expect %v error, got %v", e, a)
+	}
+}
+
+func TestTokenBucketConcurrent(t *testing.T) {
+	const (
+		goroutines = 50
+		iterations = 100
+	)
+
+	bucket := NewTokenBucket(goroutines, func(o *TokenBucketOptions) {
+		o.FillRate = 1000
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				if err := bucket.GetToken(context.Background(), 1); err != nil {
+					t.Errorf("expect no error, got %v", err)
+					return
+				}
+				bucket.AddTokens(1)
+				if j%10 == 0 {
+					bucket.SetFillRate(bucket.FillRate())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if e, a := uint(goroutines), bucket.Available(); e != a {
+		t.Errorf("expect %v available, got %v", e, a)
+	}
+}
@@ -0,0 +1,194 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/awslabs/smithy-go/ratelimit"
+)
+
+// Default values of the AdaptiveMode retryer.
+const (
+	// DefaultAdaptiveMaxSendRate is the maximum rate, in attempts per
+	// second, attempts are made at once throttling has been seen.
+	DefaultAdaptiveMaxSendRate = 100.0
+
+	// DefaultAdaptiveMinSendRate is the minimum rate, in attempts per
+	// second, attempts are made at.
+	DefaultAdaptiveMinSendRate = 0.5
+
+	// DefaultAdaptiveThrottleScale is the factor the send rate is reduced by
+	// when an attempt is throttled.
+	DefaultAdaptiveThrottleScale = 0.7
+
+	// DefaultAdaptiveRateIncrease is the amount the send rate is increased
+	// by for each successful attempt.
+	DefaultAdaptiveRateIncrease = 1.0
+
+	// DefaultAdaptiveBurst is the number of attempts that can be made at
+	// once without waiting for the send rate.
+	DefaultAdaptiveBurst uint = 10
+)
+
+// AdaptiveModeOptions provides the options for configuring the AdaptiveMode
+// retryer.
+type AdaptiveModeOptions struct {
+	// Options applied to the Standard retryer the AdaptiveMode retryer
+	// decorates.
+	StandardOptions []func(*StandardOptions)
+
+	// Maximum and minimum rate, in attempts per second, attempts are made at
+	// once throttling has been seen.
+	MaxSendRate float64
+	MinSendRate float64
+
+	// Factor the send rate is reduced by when an attempt is throttled.
+	ThrottleScale float64
+
+	// Amount the send rate is increased by for each successful attempt.
+	RateIncrease float64
+
+	// Number of attempts that can be made at once without waiting for the
+	// send rate.
+	Burst uint
+}
+
+// AdaptiveMode is a retryer that decorates the Standard retryer with client
+// side rate limiting of attempts. Attempts are not rate limited until an
+// attempt is throttled. Once throttling has been seen, attempts are made at
+// the send rate, which is reduced each time an attempt is throttled, and
+// increases as attempts succeed.
+//
+// An AdaptiveMode retryer should be shared by all operations calling the
+// same service, so that the send rate reflects the throttling seen by all
+// of them.
+type AdaptiveMode struct {
+	options  AdaptiveModeOptions
+	standard *Standard
+
+	mu         sync.Mutex
+	throttled  bool
+	sendRate   float64
+	sendBucket *ratelimit.TokenBucket
+}
+
+// NewAdaptiveMode returns an AdaptiveMode retryer initialized with the
+// default values, modified by the functional options provided.
+func NewAdaptiveMode(optFns ...func(*AdaptiveModeOptions)) *AdaptiveMode {
+	o := AdaptiveModeOptions{
+		MaxSendRate:   DefaultAdaptiveMaxSendRate,
+		MinSendRate:   DefaultAdaptiveMinSendRate,
+		ThrottleScale: DefaultAdaptiveThrottleScale,
+		RateIncrease:  DefaultAdaptiveRateIncrease,
+		Burst:         DefaultAdaptiveBurst,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return &AdaptiveMode{
+		options:  o,
+		standard: NewStandard(o.StandardOptions...),
+		sendRate: o.MaxSendRate,
+		sendBucket: ratelimit.NewTokenBucket(o.Burst, func(bo *ratelimit.TokenBucketOptions) {
+			bo.FillRate = o.MaxSendRate
+		}),
+	}
+}
+
+// IsErrorRetryable returns if the error can be retried.
+func (a *AdaptiveMode) IsErrorRetryable(err error) bool {
+	return a.standard.IsErrorRetryable(err)
+}
+
+// MaxAttempts returns the maximum number of attempts that can be made for an
+// operation before failing.
+func (a *AdaptiveMode) MaxAttempts() int {
+	return a.standard.MaxAttempts()
+}
+
+// MaxBackoff returns the maximum delay between attempts of the Standard
+// retryer.
+func (a *AdaptiveMode) MaxBackoff() time.Duration {
+	return a.standard.MaxBackoff()
+}
+
+// MaxBackoffBudget returns the maximum total time of an operation's attempts
+// of the Standard retryer.
+func (a *AdaptiveMode) MaxBackoffBudget() time.Duration {
+	return a.standard.MaxBackoffBudget()
+}
+
+// RetryDelay returns the delay of the Standard retryer.
+func (a *AdaptiveMode) RetryDelay(attempt int, err error) (time.Duration, error) {
+	return a.standard.RetryDelay(attempt, err)
+}
+
+// GetRetryToken returns the retry token of the Standard retryer.
+func (a *AdaptiveMode) GetRetryToken(ctx context.Context, opErr error) (func(error) error, error) {
+	return a.standard.GetRetryToken(ctx, opErr)
+}
+
+// GetAttemptToken blocks until the attempt can be made at the current send
+// rate, if attempts have been throttled. The returned release function
+// updates the send rate based on the attempt's result.
+func (a *AdaptiveMode) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	release, err := a.standard.GetAttemptToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	throttled := a.throttled
+	a.mu.Unlock()
+
+	if throttled {
+		if err := a.sendBucket.GetToken(ctx, 1); err != nil {
+			return nil, fmt.Errorf("failed to get send rate token, %w", err)
+		}
+	}
+
+	return func(opErr error) error {
+		a.updateSendRate(opErr)
+		return release(opErr)
+	}, nil
+}
+
+// SendRate returns the current rate, in attempts per second, attempts are
+// made at, and if the rate is being enforced because attempts have been
+// throttled.
+func (a *AdaptiveMode) SendRate() (rate float64, enabled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.sendRate, a.throttled
+}
+
+func (a *AdaptiveMode) updateSendRate(opErr error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case IsThrottleError(opErr):
+		a.throttled = true
+		a.sendRate *= a.options.ThrottleScale
+		if a.sendRate < a.options.MinSendRate {
+			a.sendRate = a.options.MinSendRate
+		}
+
+	case opErr == nil && a.throttled:
+		a.sendRate += a.options.RateIncrease
+		if a.sendRate > a.options.MaxSendRate {
+			a.sendRate = a.options.MaxSendRate
+		}
+
+	default:
+		return
+	}
+
+	a.sendBucket.SetFillRate(a.sendRate)
+}
+
+var _ Retryer = (*AdaptiveMode)(nil)
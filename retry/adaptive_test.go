@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+var errThrottle = &smithy.GenericAPIError{Code: "ThrottlingException", Throttling: true}
+
+func TestAdaptiveModeSendRate(t *testing.T) {
+	r := NewAdaptiveMode(func(o *AdaptiveModeOptions) {
+		o.MaxSendRate = 10
+		o.MinSendRate = 2
+		o.ThrottleScale = 0.5
+		o.RateIncrease = 1
+	})
+
+	attempt := func(err error) {
+		t.Helper()
+		release, tokenErr := r.GetAttemptToken(context.Background())
+		if tokenErr != nil {
+			t.Fatalf("expect no error, got %v", tokenErr)
+		}
+		if releaseErr := release(err); releaseErr != nil {
+			t.Fatalf("expect no error, got %v", releaseErr)
+		}
+	}
+
+	attempt(nil)
+	if rate, enabled := r.SendRate(); enabled || rate != 10 {
+		t.Errorf("expect send rate not enabled before throttling, got %v, %v", rate, enabled)
+	}
+
+	attempt(errThrottle)
+	if rate, enabled := r.SendRate(); !enabled || rate != 5 {
+		t.Errorf("expect reduced send rate 5, got %v, %v", rate, enabled)
+	}
+
+	attempt(errThrottle)
+	attempt(errThrottle)
+	if rate, _ := r.SendRate(); rate != 2 {
+		t.Errorf("expect minimum send rate 2, got %v", rate)
+	}
+
+	attempt(nil)
+	if rate, _ := r.SendRate(); rate != 3 {
+		t.Errorf("expect increased send rate 3, got %v", rate)
+	}
+
+	attempt(errRetryable)
+	if rate, _ := r.SendRate(); rate != 3 {
+		t.Errorf("expect unchanged send rate for non-throttle error, got %v", rate)
+	}
+}
+
+func TestAdaptiveModeConcurrent(t *testing.T) {
+	r := NewAdaptiveMode(func(o *AdaptiveModeOptions) {
+		o.MaxSendRate = 10000
+		o.MinSendRate = 1000
+		o.Burst = 100
+		o.StandardOptions = append(o.StandardOptions, func(so *StandardOptions) {
+			so.MaxBackoff = time.Millisecond
+		})
+	})
+	m := NewAttemptMiddleware(r)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				h := &mockFinalizeHandler{errs: []error{errThrottle}}
+				if _, _, err := m.HandleFinalize(context.Background(), middleware.FinalizeInput{}, h); err != nil {
+					t.Errorf("expect no error, got %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, enabled := r.SendRate(); !enabled {
+		t.Errorf("expect send rate enabled after throttling")
+	}
+}
@@ -0,0 +1,148 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// AttemptTimeoutError is returned by AttemptTimeoutMiddleware when an attempt
+// does not complete within the attempt timeout. The error is retryable, so
+// the Retry middleware will retry the timed out attempt.
+type AttemptTimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+// Error returns the error message.
+func (e *AttemptTimeoutError) Error() string {
+	return fmt.Sprintf("attempt timed out after %v, %v", e.Timeout, e.Err)
+}
+
+// Unwrap returns the underlying attempt error.
+func (e *AttemptTimeoutError) Unwrap() error { return e.Err }
+
+// RetryableError reports the timed out attempt as retryable.
+func (e *AttemptTimeoutError) RetryableError() bool { return true }
+
+// AttemptTimeoutMiddleware is a finalize middleware that limits the duration
+// of each attempt, separately from the operation's context deadline. It must
+// be added after the Retry middleware so that each attempt is given its own
+// timeout.
+//
+// The timeout only applies until the attempt's response has been returned.
+// Once an attempt succeeds its timeout is stopped, and its context is
+// canceled to release it. If the response body is owned by a streaming
+// output, see smithyhttp.SetStreamingOutputMetadata, the context is instead
+// canceled once the body is closed, so the body can continue to be read with
+// the attempt's context.
+type AttemptTimeoutMiddleware struct {
+	Timeout time.Duration
+}
+
+// AttemptTimeout returns an AttemptTimeoutMiddleware that limits each attempt
+// to the duration d. A duration of zero or less disables the timeout.
+func AttemptTimeout(d time.Duration) *AttemptTimeoutMiddleware {
+	return &AttemptTimeoutMiddleware{
+		Timeout: d,
+	}
+}
+
+// ID returns the middleware identifier.
+func (m *AttemptTimeoutMiddleware) ID() string {
+	return "AttemptTimeout"
+}
+
+// HandleFinalize handles the attempt with a context that is canceled if the
+// attempt does not complete within the timeout. Errors from a timed out
+// attempt are wrapped in an AttemptTimeoutError.
+func (m *AttemptTimeoutMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	if m.Timeout <= 0 {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	attemptCtx, cancel := newAttemptContext(ctx, m.Timeout)
+	hookCtx, resp := withCancelOnBodyClose(attemptCtx, cancel)
+
+	out, metadata, err = next.HandleFinalize(hookCtx, in)
+	if err == nil && attemptCtx.stopTimer() {
+		resp.release(ctx, metadata, cancel)
+		return out, metadata, nil
+	}
+
+	cancel()
+	if attemptCtx.timedOut() && ctx.Err() == nil {
+		if err == nil {
+			err = attemptCtx.Err()
+		}
+		return out, metadata, &AttemptTimeoutError{Timeout: m.Timeout, Err: err}
+	}
+	return out, metadata, err
+}
+
+// attemptContext is a context canceled when the attempt's timer fires. Unlike
+// context.WithTimeout, the timer can be stopped without canceling the
+// context.
+type attemptContext struct {
+	context.Context
+	deadline time.Time
+	timer    *time.Timer
+	expired  int32
+	stopped  int32
+}
+
+func newAttemptContext(parent context.Context, timeout time.Duration) (*attemptContext, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	c := &attemptContext{
+		Context:  ctx,
+		deadline: time.Now().Add(timeout),
+	}
+	c.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&c.expired, 1)
+		cancel()
+	})
+
+	return c, func() {
+		c.timer.Stop()
+		cancel()
+	}
+}
+
+// stopTimer stops the attempt's timer, returning false if the timer has
+// already fired. Once stopped the attempt's deadline no longer applies.
+func (c *attemptContext) stopTimer() bool {
+	if !c.timer.Stop() {
+		return false
+	}
+	atomic.StoreInt32(&c.stopped, 1)
+	return true
+}
+
+func (c *attemptContext) timedOut() bool {
+	return atomic.LoadInt32(&c.expired) == 1
+}
+
+// Deadline returns the attempt's deadline, or the parent's deadline if it is
+// earlier or the attempt's timer has been stopped.
+func (c *attemptContext) Deadline() (time.Time, bool) {
+	d, ok := c.Context.Deadline()
+	if atomic.LoadInt32(&c.stopped) == 1 || (ok && d.Before(c.deadline)) {
+		return d, ok
+	}
+	return c.deadline, true
+}
+
+// Err returns context.DeadlineExceeded if the attempt timed out, otherwise
+// the error of the underlying context.
+func (c *attemptContext) Err() error {
+	err := c.Context.Err()
+	if err == context.Canceled && c.timedOut() {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func TestAttemptTimeout(t *testing.T) {
+	var attempts int
+	var lastCtx context.Context
+	h := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		attempts++
+		lastCtx = ctx
+		if attempts == 1 {
+			<-ctx.Done()
+			return out, metadata, ctx.Err()
+		}
+		return out, metadata, nil
+	})
+
+	timeout := AttemptTimeout(20 * time.Millisecond)
+	retryer := NewAttemptMiddleware(NewStandard(func(o *StandardOptions) {
+		o.MaxBackoff = time.Millisecond
+	}))
+
+	_, _, err := retryer.HandleFinalize(context.Background(), middleware.FinalizeInput{},
+		finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+			middleware.FinalizeOutput, middleware.Metadata, error,
+		) {
+			return timeout.HandleFinalize(ctx, in, h)
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+
+	// The succeeded attempt's context is released, as its output is not
+	// streaming.
+	if !errors.Is(lastCtx.Err(), context.Canceled) {
+		t.Errorf("expect succeeded attempt context canceled, got %v", lastCtx.Err())
+	}
+}
+
+func TestAttemptTimeout_StreamingOutput(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("body"), closed: make(chan struct{})}
+	var attemptCtx context.Context
+
+	stack := middleware.NewStack("attempt timeout", smithyhttp.NewStackRequest)
+	stack.Finalize.Add(AttemptTimeout(20*time.Millisecond), middleware.After)
+	stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+		func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			out, metadata, err = next.HandleDeserialize(ctx, in)
+			if err != nil {
+				return out, metadata, err
+			}
+			smithyhttp.SetStreamingOutputMetadata(&metadata)
+			out.Result = out.RawResponse.(*smithyhttp.Response).Body
+			return out, metadata, nil
+		}), middleware.After)
+
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			attemptCtx = ctx
+			return &smithyhttp.Response{Response: &http.Response{
+				StatusCode: 200,
+				Body:       body,
+			}}, middleware.Metadata{}, nil
+		}), stack)
+
+	out, _, err := handler.Handle(context.Background(), struct{}{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// The attempt's context must remain usable for reading the streaming
+	// response body after the timeout would have elapsed.
+	time.Sleep(40 * time.Millisecond)
+	if err := attemptCtx.Err(); err != nil {
+		t.Errorf("expect attempt context not canceled before close, got %v", err)
+	}
+	if _, ok := attemptCtx.Deadline(); ok {
+		t.Errorf("expect no deadline after attempt succeeded")
+	}
+
+	out.(io.ReadCloser).Close()
+	if !errors.Is(attemptCtx.Err(), context.Canceled) {
+		t.Errorf("expect attempt context canceled after close, got %v", attemptCtx.Err())
+	}
+}
+
+func TestAttemptTimeout_Error(t *testing.T) {
+	cases := map[string]struct {
+		Timeout       time.Duration
+		CancelParent  bool
+		ExpectTimeout bool
+	}{
+		"timed out": {
+			Timeout:       10 * time.Millisecond,
+			ExpectTimeout: true,
+		},
+		"parent canceled": {
+			Timeout:      time.Minute,
+			CancelParent: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if c.CancelParent {
+				time.AfterFunc(10*time.Millisecond, cancel)
+			}
+
+			h := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+				out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+			) {
+				<-ctx.Done()
+				return out, metadata, ctx.Err()
+			})
+
+			_, _, err := AttemptTimeout(c.Timeout).HandleFinalize(ctx, middleware.FinalizeInput{}, h)
+
+			var timeoutErr *AttemptTimeoutError
+			if e, a := c.ExpectTimeout, errors.As(err, &timeoutErr); e != a {
+				t.Fatalf("expect timeout error %v, got %v", e, err)
+			}
+			if e, a := c.ExpectTimeout, IsErrorRetryables(DefaultRetryables).IsErrorRetryable(err); e != a {
+				t.Errorf("expect %v retryable, got %v", e, a)
+			}
+			if c.ExpectTimeout && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expect deadline exceeded error wrapped, got %v", err)
+			}
+			if !c.ExpectTimeout && err != context.Canceled {
+				t.Errorf("expect %v error, got %v", context.Canceled, err)
+			}
+		})
+	}
+}
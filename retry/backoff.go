@@ -0,0 +1,153 @@
+package retry
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultBackoffBase is the base delay of the exponential backoff used by the
+// Standard retryer.
+const DefaultBackoffBase = time.Second
+
+// BackoffDelayer provides the interface for the strategy that computes the
+// delay before retrying an attempt.
+type BackoffDelayer interface {
+	BackoffDelay(attempt int) (time.Duration, error)
+}
+
+// FullJitter is a BackoffDelayer using exponential backoff with full jitter.
+// The delay is a random duration between zero and Base*2^attempt, capped by
+// MaxBackoff.
+type FullJitter struct {
+	// The base delay that is doubled for each attempt. If zero,
+	// DefaultBackoffBase is used.
+	Base time.Duration
+
+	// The maximum delay between attempts. If zero, the delay is not capped.
+	MaxBackoff time.Duration
+
+	// Source of the jitter, (e.g. seeded by tests for reproducible delays).
+	// The source must be safe for concurrent use if the backoff is shared,
+	// see NewJitterSource. If nil, a source seeded from crypto/rand, shared
+	// by all backoffs, is used.
+	Source rand.Source
+}
+
+// BackoffDelay returns a random delay between zero and the exponential
+// backoff for the attempt.
+func (j FullJitter) BackoffDelay(attempt int) (time.Duration, error) {
+	backoff := exponentialBackoff(j.Base, j.MaxBackoff, attempt)
+
+	source := j.Source
+	if source == nil {
+		source = defaultJitterSource
+	}
+
+	delay := rand.New(source).Float64() * backoff
+	if delay >= math.MaxInt64 {
+		// float64 cannot represent MaxInt64, and rounds it up out of range.
+		return math.MaxInt64, nil
+	}
+	return time.Duration(delay), nil
+}
+
+// Exponential is a BackoffDelayer using exponential backoff without jitter.
+// The delay is Base*2^attempt, capped by MaxBackoff, so the delays between
+// attempts are predictable, (e.g. for tests and deterministic environments).
+type Exponential struct {
+	// The base delay that is doubled for each attempt. If zero,
+	// DefaultBackoffBase is used.
+	Base time.Duration
+
+	// The maximum delay between attempts. If zero, the delay is not capped.
+	MaxBackoff time.Duration
+}
+
+// BackoffDelay returns the exponential backoff for the attempt.
+func (e Exponential) BackoffDelay(attempt int) (time.Duration, error) {
+	backoff := exponentialBackoff(e.Base, e.MaxBackoff, attempt)
+	if backoff >= math.MaxInt64 {
+		// float64 cannot represent MaxInt64, and rounds it up out of range.
+		return math.MaxInt64, nil
+	}
+	return time.Duration(backoff), nil
+}
+
+// exponentialBackoff returns base*2^attempt, capped by max if set.
+func exponentialBackoff(base, max time.Duration, attempt int) float64 {
+	if base == 0 {
+		base = DefaultBackoffBase
+	}
+
+	backoff := float64(base) * math.Pow(2, float64(attempt))
+	if max > 0 && backoff > float64(max) {
+		backoff = float64(max)
+	}
+	if backoff > math.MaxInt64 {
+		backoff = math.MaxInt64
+	}
+	return backoff
+}
+
+var defaultJitterSource = NewJitterSource(randomSeed())
+
+// randomSeed returns a seed read from crypto/rand, or the current time if
+// crypto/rand fails to be read.
+func randomSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+// NewJitterSource returns a rand.Source seeded with the seed that is safe for
+// concurrent use, for FullJitter's and StandardOptions' JitterSource. Backoffs
+// using sources with the same seed compute the same sequence of delays.
+func NewJitterSource(seed int64) rand.Source {
+	return &lockedSource{src: rand.NewSource(seed)}
+}
+
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// NoDelay is a BackoffDelayer which retries attempts immediately.
+type NoDelay struct{}
+
+// BackoffDelay returns zero.
+func (NoDelay) BackoffDelay(int) (time.Duration, error) {
+	return 0, nil
+}
+
+// Fixed is a BackoffDelayer which waits the same delay before each retry.
+type Fixed struct {
+	Delay time.Duration
+}
+
+// BackoffDelay returns the fixed delay.
+func (f Fixed) BackoffDelay(int) (time.Duration, error) {
+	return f.Delay, nil
+}
+
+var _ BackoffDelayer = FullJitter{}
+var _ BackoffDelayer = Exponential{}
+var _ BackoffDelayer = NoDelay{}
+var _ BackoffDelayer = Fixed{}
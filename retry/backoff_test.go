@@ -0,0 +1,208 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFullJitter(t *testing.T) {
+	cases := map[string]struct {
+		Backoff FullJitter
+		Attempt int
+		Expect  time.Duration
+	}{
+		"first attempt": {
+			Backoff: FullJitter{Base: 10 * time.Millisecond},
+			Attempt: 1,
+			Expect:  20 * time.Millisecond,
+		},
+		"later attempt": {
+			Backoff: FullJitter{Base: 10 * time.Millisecond},
+			Attempt: 4,
+			Expect:  160 * time.Millisecond,
+		},
+		"capped": {
+			Backoff: FullJitter{Base: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+			Attempt: 4,
+			Expect:  50 * time.Millisecond,
+		},
+		"default base": {
+			Attempt: 2,
+			Expect:  4 * time.Second,
+		},
+		"overflow": {
+			Backoff: FullJitter{Base: time.Second},
+			Attempt: 100,
+			Expect:  math.MaxInt64,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay, err := c.Backoff.BackoffDelay(c.Attempt)
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				if delay < 0 || delay > c.Expect {
+					t.Fatalf("expect delay within [0, %v], got %v", c.Expect, delay)
+				}
+			}
+		})
+	}
+}
+
+// maxJitterSource returns the largest value rand.Float64 does not reject.
+type maxJitterSource struct{}
+
+func (maxJitterSource) Int63() int64 { return math.MaxInt64 - 1024 }
+func (maxJitterSource) Seed(int64)   {}
+
+func TestFullJitter_OverflowMaxJitter(t *testing.T) {
+	delay, err := FullJitter{Base: time.Second, Source: maxJitterSource{}}.BackoffDelay(100)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if delay <= 0 {
+		t.Errorf("expect positive delay, got %v", delay)
+	}
+}
+
+func TestFixedBackoff(t *testing.T) {
+	for attempt := 1; attempt < 5; attempt++ {
+		delay, err := Fixed{Delay: time.Second}.BackoffDelay(attempt)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := time.Second, delay; e != a {
+			t.Errorf("expect %v delay, got %v", e, a)
+		}
+
+		if delay, _ = (NoDelay{}).BackoffDelay(attempt); delay != 0 {
+			t.Errorf("expect no delay, got %v", delay)
+		}
+	}
+}
+
+func TestStandard_Backoff(t *testing.T) {
+	r := NewStandard(func(o *StandardOptions) {
+		o.Backoff = Fixed{Delay: 3 * time.Millisecond}
+	})
+
+	delay, err := r.RetryDelay(2, errRetryable)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 3*time.Millisecond, delay; e != a {
+		t.Errorf("expect %v delay, got %v", e, a)
+	}
+}
+
+func TestFullJitter_SeededSource(t *testing.T) {
+	delays := func() []time.Duration {
+		r := NewStandard(func(o *StandardOptions) {
+			o.JitterSource = NewJitterSource(42)
+		})
+		var ds []time.Duration
+		for attempt := 1; attempt <= 5; attempt++ {
+			delay, err := r.RetryDelay(attempt, errRetryable)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			ds = append(ds, delay)
+		}
+		return ds
+	}
+
+	first, second := delays(), delays()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expect same seed to compute same delays, got %v and %v", first, second)
+	}
+
+	src := rand.New(rand.NewSource(42))
+	for i, delay := range first {
+		backoff := float64(DefaultBackoffBase) * math.Pow(2, float64(i+1))
+		if backoff > float64(DefaultMaxBackoff) {
+			backoff = float64(DefaultMaxBackoff)
+		}
+		if e, a := time.Duration(src.Float64()*backoff), delay; e != a {
+			t.Errorf("expect attempt %d delay %v, got %v", i+1, e, a)
+		}
+	}
+
+	other, err := FullJitter{Source: NewJitterSource(7)}.BackoffDelay(1)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if other == first[0] {
+		t.Errorf("expect different seed to compute different delay, got %v", other)
+	}
+}
+
+func TestExponential(t *testing.T) {
+	cases := map[string]struct {
+		Backoff Exponential
+		Attempt int
+		Expect  time.Duration
+	}{
+		"first attempt": {
+			Backoff: Exponential{Base: 10 * time.Millisecond},
+			Attempt: 1,
+			Expect:  20 * time.Millisecond,
+		},
+		"capped": {
+			Backoff: Exponential{Base: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+			Attempt: 4,
+			Expect:  50 * time.Millisecond,
+		},
+		"default base": {
+			Attempt: 2,
+			Expect:  4 * time.Second,
+		},
+		"overflow": {
+			Backoff: Exponential{Base: time.Second},
+			Attempt: 100,
+			Expect:  math.MaxInt64,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			delay, err := c.Backoff.BackoffDelay(c.Attempt)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, delay; e != a {
+				t.Errorf("expect %v delay, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestStandard_DisableJitter(t *testing.T) {
+	r := NewStandard(DisableJitter(), func(o *StandardOptions) {
+		o.MaxBackoff = 10 * time.Second
+	})
+
+	expect := []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+	}
+	for attempt, e := range expect {
+		for i := 0; i < 10; i++ {
+			a, err := r.RetryDelay(attempt, errRetryable)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e != a {
+				t.Errorf("expect attempt %d delay %v, got %v", attempt, e, a)
+			}
+		}
+	}
+}
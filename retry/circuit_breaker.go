@@ -0,0 +1,188 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// Default values of the CircuitBreaker.
+const (
+	// DefaultCircuitBreakerThreshold is the number of consecutive failures
+	// of an operation after which its circuit is opened.
+	DefaultCircuitBreakerThreshold = 5
+
+	// DefaultCircuitBreakerCooldown is the duration a circuit stays open
+	// before a probe request is allowed.
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// CircuitOpenError is returned by the CircuitBreaker middleware, without the
+// request being sent, while the operation's circuit is open.
+type CircuitOpenError struct {
+	Operation string
+
+	// Time after which a probe request will be allowed.
+	OpenUntil time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for operation %q until %v",
+		e.Operation, e.OpenUntil.Format(time.RFC3339))
+}
+
+// CircuitBreakerOptions provides the options for configuring the
+// CircuitBreaker.
+type CircuitBreakerOptions struct {
+	// Number of consecutive failures of an operation after which its
+	// circuit is opened.
+	Threshold int
+
+	// Duration the circuit stays open before a single probe request is
+	// allowed, (half-open). If the probe succeeds the circuit is closed,
+	// otherwise it is opened for another cooldown.
+	Cooldown time.Duration
+
+	// Determines if the error is a failure counted towards opening the
+	// circuit. Defaults to errors with the server fault, FaultServer.
+	IsFailure func(error) bool
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuit struct {
+	state     circuitState
+	failures  int
+	openUntil time.Time
+}
+
+// CircuitBreaker is a finalize middleware that stops sending requests for an
+// operation after the operation has failed with consecutive server faults.
+// While an operation's circuit is open, attempts fail immediately with a
+// CircuitOpenError. After the cooldown a single probe attempt is allowed,
+// closing the circuit if it succeeds.
+//
+// Circuits are keyed by the operation name set on the context with
+// middleware.WithOperationName. A single CircuitBreaker is safe to share
+// between all operations of a client.
+//
+// The middleware should be added after the Retry middleware so each attempt
+// is counted, and retries stop once the circuit is opened.
+type CircuitBreaker struct {
+	options CircuitBreakerOptions
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// NewCircuitBreaker returns a CircuitBreaker initialized with the default
+// values, modified by the functional options provided.
+func NewCircuitBreaker(optFns ...func(*CircuitBreakerOptions)) *CircuitBreaker {
+	o := CircuitBreakerOptions{
+		Threshold: DefaultCircuitBreakerThreshold,
+		Cooldown:  DefaultCircuitBreakerCooldown,
+		IsFailure: isServerFault,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return &CircuitBreaker{
+		options:  o,
+		circuits: map[string]*circuit{},
+	}
+}
+
+// ID returns the middleware identifier.
+func (c *CircuitBreaker) ID() string {
+	return "CircuitBreaker"
+}
+
+// HandleFinalize handles the attempt if the operation's circuit is not
+// open, recording the result of the attempt.
+func (c *CircuitBreaker) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	operation := middleware.GetOperationName(ctx)
+	clock := middleware.GetClock(ctx)
+
+	if err := c.allow(operation, clock.Now()); err != nil {
+		return out, metadata, err
+	}
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+
+	c.record(operation, err, clock.Now())
+	return out, metadata, err
+}
+
+// allow returns a CircuitOpenError if the operation's circuit is open, or a
+// probe attempt is already in progress. Transitions an open circuit whose
+// cooldown is over to half-open.
+func (c *CircuitBreaker) allow(operation string, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cb, ok := c.circuits[operation]
+	if !ok {
+		return nil
+	}
+
+	switch cb.state {
+	case circuitOpen:
+		if now.Before(cb.openUntil) {
+			return &CircuitOpenError{Operation: operation, OpenUntil: cb.openUntil}
+		}
+		cb.state = circuitHalfOpen
+	case circuitHalfOpen:
+		return &CircuitOpenError{Operation: operation, OpenUntil: cb.openUntil}
+	}
+	return nil
+}
+
+// record updates the operation's circuit with the result of an attempt. A
+// half-open circuit is only closed by a successful probe; any other probe
+// error reopens it.
+func (c *CircuitBreaker) record(operation string, err error, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cb, ok := c.circuits[operation]
+	failed := err != nil && c.options.IsFailure(err)
+	if ok && cb.state == circuitHalfOpen && err != nil {
+		failed = true
+	}
+	if !failed {
+		if ok {
+			delete(c.circuits, operation)
+		}
+		return
+	}
+
+	if !ok {
+		cb = &circuit{}
+		c.circuits[operation] = cb
+	}
+	cb.failures++
+
+	if cb.state == circuitHalfOpen || cb.failures >= c.options.Threshold {
+		cb.state = circuitOpen
+		cb.openUntil = now.Add(c.options.Cooldown)
+	}
+}
+
+func isServerFault(err error) bool {
+	var v smithy.APIError
+	return errors.As(err, &v) && v.ErrorFault() == smithy.FaultServer
+}
@@ -0,0 +1,187 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+var errServerFault = &smithy.GenericAPIError{Code: "InternalError", Fault: smithy.FaultServer}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+		return now
+	}))
+	fooCtx := middleware.WithOperationName(ctx, "Foo")
+	barCtx := middleware.WithOperationName(ctx, "Bar")
+
+	cb := NewCircuitBreaker(func(o *CircuitBreakerOptions) {
+		o.Threshold = 2
+		o.Cooldown = time.Minute
+	})
+
+	var attempts int
+	var respErr error
+	h := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		attempts++
+		return out, metadata, respErr
+	})
+	call := func(ctx context.Context) error {
+		_, _, err := cb.HandleFinalize(ctx, middleware.FinalizeInput{}, h)
+		return err
+	}
+
+	respErr = errServerFault
+	for i := 0; i < 2; i++ {
+		if err := call(fooCtx); err != errServerFault {
+			t.Fatalf("attempt %d, expect server fault, got %v", i, err)
+		}
+	}
+
+	// Open circuit rejects without sending.
+	err := call(fooCtx)
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("expect circuit open error, got %v", err)
+	}
+	if e, a := now.Add(time.Minute), openErr.OpenUntil; !e.Equal(a) {
+		t.Errorf("expect open until %v, got %v", e, a)
+	}
+	if e, a := 2, attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+	if IsErrorRetryables(DefaultRetryables).IsErrorRetryable(err) {
+		t.Errorf("expect circuit open error not retryable")
+	}
+
+	// Other operations are not affected.
+	respErr = nil
+	if err := call(barCtx); err != nil {
+		t.Errorf("expect no error for other operation, got %v", err)
+	}
+
+	// Failed probe after cooldown re-opens the circuit.
+	now = now.Add(time.Minute)
+	respErr = errServerFault
+	if err := call(fooCtx); err != errServerFault {
+		t.Fatalf("expect probe server fault, got %v", err)
+	}
+	if err := call(fooCtx); !errors.As(err, &openErr) {
+		t.Fatalf("expect circuit re-opened, got %v", err)
+	}
+
+	// Successful probe closes the circuit.
+	now = now.Add(time.Minute)
+	respErr = nil
+	if err := call(fooCtx); err != nil {
+		t.Fatalf("expect probe success, got %v", err)
+	}
+	respErr = errServerFault
+	if err := call(fooCtx); err != errServerFault {
+		t.Fatalf("expect circuit closed, got %v", err)
+	}
+}
+
+func TestCircuitBreakerResetOnSuccess(t *testing.T) {
+	cb := NewCircuitBreaker(func(o *CircuitBreakerOptions) {
+		o.Threshold = 2
+	})
+
+	errs := []error{
+		errServerFault,
+		&smithy.GenericAPIError{Code: "ValidationError", Fault: smithy.FaultClient},
+		errServerFault,
+		nil,
+		errServerFault,
+	}
+	h := &mockFinalizeHandler{errs: errs}
+	for range errs {
+		cb.HandleFinalize(context.Background(), middleware.FinalizeInput{}, h)
+	}
+
+	if e, a := len(errs), h.attempts; e != a {
+		t.Errorf("expect non-consecutive faults to not open circuit, %v attempts, got %v", e, a)
+	}
+}
+
+func TestCircuitBreakerHalfOpenSingleProbe(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+		return now
+	}))
+
+	cb := NewCircuitBreaker(func(o *CircuitBreakerOptions) {
+		o.Threshold = 1
+		o.Cooldown = time.Second
+	})
+	cb.HandleFinalize(ctx, middleware.FinalizeInput{}, &mockFinalizeHandler{errs: []error{errServerFault}})
+	now = now.Add(time.Second)
+
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	probe := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		close(probing)
+		<-release
+		return out, metadata, nil
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cb.HandleFinalize(ctx, middleware.FinalizeInput{}, probe)
+	}()
+	<-probing
+
+	var openErr *CircuitOpenError
+	_, _, err := cb.HandleFinalize(ctx, middleware.FinalizeInput{}, &mockFinalizeHandler{})
+	if !errors.As(err, &openErr) {
+		t.Errorf("expect concurrent attempt rejected during probe, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if _, _, err := cb.HandleFinalize(ctx, middleware.FinalizeInput{}, &mockFinalizeHandler{}); err != nil {
+		t.Errorf("expect circuit closed after probe, got %v", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeError(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+		return now
+	}))
+
+	cb := NewCircuitBreaker(func(o *CircuitBreakerOptions) {
+		o.Threshold = 1
+		o.Cooldown = time.Second
+	})
+	cb.HandleFinalize(ctx, middleware.FinalizeInput{}, &mockFinalizeHandler{errs: []error{errServerFault}})
+	now = now.Add(time.Second)
+
+	clientFault := &smithy.GenericAPIError{Code: "ValidationError", Fault: smithy.FaultClient}
+	cb.HandleFinalize(ctx, middleware.FinalizeInput{}, &mockFinalizeHandler{errs: []error{clientFault}})
+
+	var openErr *CircuitOpenError
+	_, _, err := cb.HandleFinalize(ctx, middleware.FinalizeInput{}, &mockFinalizeHandler{})
+	if !errors.As(err, &openErr) {
+		t.Errorf("expect circuit reopened after failed probe, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, _, err := cb.HandleFinalize(ctx, middleware.FinalizeInput{}, &mockFinalizeHandler{}); err != nil {
+		t.Errorf("expect probe allowed after cooldown, got %v", err)
+	}
+}
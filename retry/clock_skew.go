@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// DefaultClockSkewErrorCodes is the default set of error codes services
+// return when a request's signing time is too far from the service's clock.
+var DefaultClockSkewErrorCodes = map[string]bool{
+	"RequestTimeTooSkewed":      true,
+	"RequestExpired":            true,
+	"RequestInTheFuture":        true,
+	"InvalidSignatureException": true,
+	"SignatureDoesNotMatch":     true,
+	"AuthFailure":               true,
+}
+
+// RetryableClockSkew is an IsErrorRetryable implementation which considers
+// API errors retryable if their error code is a clock skew error code. Add it
+// to a retryer's retryables with the ClockSkew middleware, so the retried
+// attempt is signed with the corrected time.
+type RetryableClockSkew struct {
+	// Clock skew error codes. If nil, DefaultClockSkewErrorCodes is used.
+	Codes map[string]bool
+}
+
+// IsErrorRetryable returns if the error is a clock skew error.
+func (r RetryableClockSkew) IsErrorRetryable(err error) bool {
+	return isClockSkewError(err, r.Codes)
+}
+
+func isClockSkewError(err error, codes map[string]bool) bool {
+	if codes == nil {
+		codes = DefaultClockSkewErrorCodes
+	}
+	var v smithy.APIError
+	if !errors.As(err, &v) {
+		return false
+	}
+	return codes[v.ErrorCode()]
+}
+
+type clockSkewKey struct{}
+
+// SetClockSkewMetadata sets the offset applied to the client's clock to
+// correct its skew from the service's clock in the metadata.
+func SetClockSkewMetadata(metadata *middleware.Metadata, skew time.Duration) {
+	metadata.Set(clockSkewKey{}, skew)
+}
+
+// GetClockSkewMetadata returns the offset applied to the client's clock to
+// correct its skew from the service's clock from the metadata, and if the
+// offset was set.
+func GetClockSkewMetadata(metadata middleware.MetadataReader) (time.Duration, bool) {
+	v, ok := metadata.Get(clockSkewKey{}).(time.Duration)
+	return v, ok
+}
+
+// ClockSkew is a finalize middleware that corrects the skew of the client's
+// clock from the service's clock. When an attempt fails with a clock skew
+// error, the skew is computed from the Date header of the error response,
+// and the clock of subsequent attempts, and operations, is offset by the
+// skew, see middleware.GetClock. The offset applied is set in the metadata,
+// see GetClockSkewMetadata.
+//
+// The middleware must be added after the retry middleware, and before the
+// signing middleware, see AddClockSkew, so each attempt is signed with the
+// corrected time. The errors are only retried if the retryer's retryables
+// include RetryableClockSkew.
+type ClockSkew struct {
+	// skew is accessed atomically, and must be the first field so it is 64-bit
+	// aligned on 32-bit platforms.
+	skew int64
+
+	// Clock skew error codes. If nil, DefaultClockSkewErrorCodes is used.
+	Codes map[string]bool
+}
+
+// AddClockSkew inserts the ClockSkew middleware into the stack's Finalize
+// step immediately before the signing middleware.
+func AddClockSkew(stack *middleware.Stack, m *ClockSkew) error {
+	return stack.Finalize.Insert(m, smithyhttp.SigningMiddlewareID, middleware.Before)
+}
+
+// ID returns the middleware identifier.
+func (m *ClockSkew) ID() string {
+	return "ClockSkew"
+}
+
+// Skew returns the offset applied to the client's clock.
+func (m *ClockSkew) Skew() time.Duration {
+	return time.Duration(atomic.LoadInt64(&m.skew))
+}
+
+// HandleFinalize offsets the context's clock by the skew, and computes the
+// skew from the response when the attempt fails with a clock skew error.
+func (m *ClockSkew) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	clock := middleware.GetClock(ctx)
+	if skew := m.Skew(); skew != 0 {
+		ctx = middleware.SetClock(ctx, smithytime.ClockFunc(func() time.Time {
+			return clock.Now().Add(skew)
+		}))
+	}
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+	if err != nil && isClockSkewError(err, m.Codes) {
+		if serverTime, ok := responseDate(err); ok {
+			atomic.StoreInt64(&m.skew, int64(serverTime.Sub(clock.Now())))
+		}
+	}
+
+	if skew := m.Skew(); skew != 0 {
+		SetClockSkewMetadata(&metadata, skew)
+	}
+	return out, metadata, err
+}
+
+// responseDate returns the time of the Date header of the error's HTTP
+// response, and if the header is set and valid.
+func responseDate(err error) (time.Time, bool) {
+	var respErr *smithyhttp.ResponseError
+	if !errors.As(err, &respErr) || respErr.Response == nil || respErr.Response.Response == nil {
+		return time.Time{}, false
+	}
+
+	v := respErr.Response.Header.Get("Date")
+	if len(v) == 0 {
+		return time.Time{}, false
+	}
+	t, parseErr := smithytime.ParseHTTPDate(v)
+	if parseErr != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+var _ IsErrorRetryable = RetryableClockSkew{}
@@ -0,0 +1,121 @@
+package retry
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func TestClockSkew(t *testing.T) {
+	clientTime := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	serverTime := clientTime.Add(15 * time.Minute)
+
+	req := smithyhttp.NewStackRequest().(*smithyhttp.Request)
+	req.URL, _ = url.Parse("https://example.com")
+
+	stack := middleware.NewStack("clock skew", func() interface{} { return req })
+	stack.Finalize.Add(NewAttemptMiddleware(NewStandard(func(o *StandardOptions) {
+		o.Backoff = NoDelay{}
+		o.Retryables = append(o.Retryables, RetryableClockSkew{})
+	})), middleware.After)
+
+	var signingTimes []time.Time
+	stack.Finalize.Add(&smithyhttp.SignRequest{
+		Signer: auth.SignerFunc(func(ctx context.Context, creds auth.Credentials, r *http.Request,
+			payloadHash, service string, regionSet []string, signingTime time.Time,
+		) error {
+			signingTimes = append(signingTimes, signingTime)
+			return nil
+		}),
+	}, middleware.After)
+
+	skew := &ClockSkew{}
+	if err := AddClockSkew(stack, skew); err != nil {
+		t.Fatalf("expect no error adding middleware, got %v", err)
+	}
+
+	stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("Deserializer", func(
+		ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+	) (
+		out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+	) {
+		out, metadata, err = next.HandleDeserialize(ctx, in)
+		if err != nil {
+			return out, metadata, err
+		}
+		resp := out.RawResponse.(*smithyhttp.Response)
+		if resp.StatusCode != 200 {
+			return out, metadata, &smithyhttp.ResponseError{
+				Response: resp,
+				Err:      &smithy.GenericAPIError{Code: "RequestTimeTooSkewed", Message: "signature expired"},
+			}
+		}
+		return out, metadata, nil
+	}), middleware.After)
+
+	var attempts int
+	handler := middleware.DecorateHandler(smithyhttp.NewClientHandler(smithyhttp.ClientDoFunc(
+		func(r *http.Request) (*http.Response, error) {
+			attempts++
+			header := http.Header{}
+			header.Set("Date", smithytime.FormatHTTPDate(serverTime))
+			status := 200
+			if attempts == 1 {
+				status = 403
+			}
+			return &http.Response{StatusCode: status, Header: header, Body: http.NoBody}, nil
+		})), stack)
+
+	ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+		return clientTime
+	}))
+	_, metadata, err := handler.Handle(ctx, struct{}{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := []time.Time{clientTime, serverTime}, signingTimes; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v signing times, got %v", e, a)
+	}
+	if v, ok := GetClockSkewMetadata(metadata); !ok || v != 15*time.Minute {
+		t.Errorf("expect %v clock skew metadata, got %v, %v", 15*time.Minute, v, ok)
+	}
+	if e, a := 15*time.Minute, skew.Skew(); e != a {
+		t.Errorf("expect %v skew, got %v", e, a)
+	}
+}
+
+func TestRetryableClockSkew(t *testing.T) {
+	cases := map[string]struct {
+		Err    error
+		Expect bool
+	}{
+		"clock skew code": {
+			Err:    &smithy.GenericAPIError{Code: "RequestTimeTooSkewed"},
+			Expect: true,
+		},
+		"other code": {
+			Err: &smithy.GenericAPIError{Code: "AccessDenied"},
+		},
+		"not api error": {
+			Err: context.Canceled,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Expect, (RetryableClockSkew{}).IsErrorRetryable(c.Err); e != a {
+				t.Errorf("expect %v retryable, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+/*
+Package retry provides the components needed to determine if an operation's
+failed attempt can be retried, and the middleware to retry those attempts.
+*/
+package retry
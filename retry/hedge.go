@@ -0,0 +1,133 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// HedgeMiddleware is a finalize middleware that reduces the tail latency of
+// idempotent operations by sending backup requests. If an attempt's request
+// has not completed within the delay, another copy of the request is sent,
+// up to MaxParallel requests in flight. The first request to succeed is
+// returned, and the context of the other requests is canceled. The context
+// of the request returned is canceled once the response is deserialized, or
+// if the response body is owned by a streaming output, see
+// smithyhttp.SetStreamingOutputMetadata, once the body is closed. The
+// response bodies of the other requests are closed by the middleware.
+//
+// Only operations marked idempotent with middleware.WithIdempotentOperation
+// are hedged, and only if the request has no body stream, which cannot be
+// sent more than once concurrently. If every request fails, the error of the
+// last request to fail is returned.
+//
+// The middleware must be added after the Retry middleware, so that the
+// hedged requests of an attempt are counted as a single attempt, and do not
+// consume retry tokens.
+type HedgeMiddleware struct {
+	Delay       time.Duration
+	MaxParallel int
+}
+
+// Hedge returns a HedgeMiddleware that sends a backup request each time
+// delay elapses without a response, up to maxParallel requests in flight.
+// A maxParallel of less than 2 disables hedging.
+func Hedge(delay time.Duration, maxParallel int) *HedgeMiddleware {
+	return &HedgeMiddleware{
+		Delay:       delay,
+		MaxParallel: maxParallel,
+	}
+}
+
+// ID returns the middleware identifier.
+func (m *HedgeMiddleware) ID() string {
+	return "Hedge"
+}
+
+type hedgeResult struct {
+	index    int
+	resp     *attemptResponse
+	out      middleware.FinalizeOutput
+	metadata middleware.Metadata
+	err      error
+}
+
+// HandleFinalize sends the request, hedging it with backup requests if the
+// operation is idempotent, and returns the first successful response.
+func (m *HedgeMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*smithyhttp.Request)
+	if m.Delay <= 0 || m.MaxParallel < 2 || !ok || req.GetStream() != nil ||
+		!middleware.IsIdempotentOperation(ctx) {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	logger := middleware.GetLogger(ctx)
+	results := make(chan hedgeResult, m.MaxParallel)
+	cancels := make([]context.CancelFunc, 0, m.MaxParallel)
+
+	send := func() {
+		index := len(cancels)
+		hedgeCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		hedgeCtx, resp := withCancelOnBodyClose(hedgeCtx, cancel)
+		if index > 0 {
+			hedgeCtx = middleware.SetLogger(hedgeCtx, logging.With(logger, "hedge", index))
+		}
+
+		hedgeIn := in
+		hedgeIn.Request = req.Clone()
+		go func() {
+			out, metadata, err := next.HandleFinalize(hedgeCtx, hedgeIn)
+			results <- hedgeResult{index: index, resp: resp, out: out, metadata: metadata, err: err}
+		}()
+	}
+
+	timer := time.NewTimer(m.Delay)
+	defer timer.Stop()
+
+	send()
+	inFlight := 1
+	for {
+		select {
+		case r := <-results:
+			inFlight--
+			if r.err == nil {
+				for i, cancel := range cancels {
+					if i != r.index {
+						cancel()
+					}
+				}
+				r.resp.release(ctx, r.metadata, cancels[r.index])
+				go drainHedgeResults(results, inFlight)
+				return r.out, r.metadata, nil
+			}
+			r.resp.close()
+			cancels[r.index]()
+			if inFlight == 0 {
+				return r.out, r.metadata, r.err
+			}
+
+		case <-timer.C:
+			if len(cancels) < m.MaxParallel {
+				send()
+				inFlight++
+				timer.Reset(m.Delay)
+			}
+		}
+	}
+}
+
+// drainHedgeResults receives the results of the requests still in flight
+// after a request succeeded, closing their response bodies so their
+// connections are released.
+func drainHedgeResults(results <-chan hedgeResult, inFlight int) {
+	for ; inFlight > 0; inFlight-- {
+		r := <-results
+		r.resp.close()
+	}
+}
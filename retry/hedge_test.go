@@ -0,0 +1,230 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func TestHedgeMiddleware(t *testing.T) {
+	cases := map[string]struct {
+		Idempotent     bool
+		ExpectResult   string
+		ExpectRequests int32
+	}{
+		"faster hedge wins": {
+			Idempotent:     true,
+			ExpectResult:   "hedge",
+			ExpectRequests: 2,
+		},
+		"not idempotent": {
+			ExpectResult:   "first",
+			ExpectRequests: 1,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if c.Idempotent {
+				ctx = middleware.WithIdempotentOperation(ctx)
+			}
+
+			var requests int32
+			var firstCanceled sync.WaitGroup
+			firstCanceled.Add(1)
+			var canceled int32
+
+			h := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+				out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+			) {
+				if atomic.AddInt32(&requests, 1) == 1 {
+					defer firstCanceled.Done()
+					select {
+					case <-ctx.Done():
+						atomic.StoreInt32(&canceled, 1)
+						return out, metadata, ctx.Err()
+					case <-time.After(200 * time.Millisecond):
+						out.Result = "first"
+						return out, metadata, nil
+					}
+				}
+				out.Result = "hedge"
+				return out, metadata, nil
+			})
+
+			m := Hedge(10*time.Millisecond, 3)
+			out, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{
+				Request: smithyhttp.NewStackRequest(),
+			}, h)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectResult, out.Result; e != a {
+				t.Errorf("expect %v result, got %v", e, a)
+			}
+
+			firstCanceled.Wait()
+			if e, a := c.ExpectRequests, atomic.LoadInt32(&requests); e != a {
+				t.Errorf("expect %v requests, got %v", e, a)
+			}
+			if e, a := c.Idempotent, atomic.LoadInt32(&canceled) == 1; e != a {
+				t.Errorf("expect first request canceled %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestHedgeMiddleware_CountedAsOneAttempt(t *testing.T) {
+	ctx := middleware.WithIdempotentOperation(context.Background())
+
+	var requests int32
+	h := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		n := atomic.AddInt32(&requests, 1)
+		select {
+		case <-ctx.Done():
+			return out, metadata, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+		if n <= 2 {
+			return out, metadata, errRetryable
+		}
+		return out, metadata, nil
+	})
+
+	hedge := Hedge(5*time.Millisecond, 2)
+	retry := NewAttemptMiddleware(mockRetryer{maxAttempts: 3})
+
+	var attempts int
+	_, _, err := retry.HandleFinalize(ctx, middleware.FinalizeInput{Request: smithyhttp.NewStackRequest()},
+		finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+			middleware.FinalizeOutput, middleware.Metadata, error,
+		) {
+			attempts++
+			return hedge.HandleFinalize(ctx, in, h)
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 2, attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func (r *closeRecorder) Close() error {
+	select {
+	case <-r.closed:
+	default:
+		close(r.closed)
+	}
+	return nil
+}
+
+func TestHedgeMiddleware_ResponseBodies(t *testing.T) {
+	cases := map[string]struct {
+		Streaming bool
+	}{
+		"deserialized output": {},
+		"streaming output":    {Streaming: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests int32
+			bodies := make([]*closeRecorder, 2)
+			for i := range bodies {
+				bodies[i] = &closeRecorder{Reader: strings.NewReader("body"), closed: make(chan struct{})}
+			}
+			release := make(chan struct{})
+			winnerCtx := make(chan context.Context, 1)
+
+			stack := middleware.NewStack("hedge", smithyhttp.NewStackRequest)
+			stack.Finalize.Add(Hedge(10*time.Millisecond, 2), middleware.After)
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+				func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out, metadata, err = next.HandleDeserialize(ctx, in)
+					if err != nil {
+						return out, metadata, err
+					}
+					resp := out.RawResponse.(*smithyhttp.Response)
+					if c.Streaming {
+						smithyhttp.SetStreamingOutputMetadata(&metadata)
+						out.Result = resp.Body
+						return out, metadata, nil
+					}
+					b, err := ioutil.ReadAll(resp.Body)
+					out.Result = string(b)
+					return out, metadata, err
+				}), middleware.After)
+
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					n := atomic.AddInt32(&requests, 1)
+					if n == 1 {
+						// Ignores cancellation, succeeding after the hedge.
+						<-release
+					} else {
+						winnerCtx <- ctx
+					}
+					return &smithyhttp.Response{Response: &http.Response{
+						StatusCode: 200,
+						Body:       bodies[n-1],
+					}}, middleware.Metadata{}, nil
+				}), stack)
+
+			ctx := middleware.WithIdempotentOperation(context.Background())
+			out, _, err := handler.Handle(ctx, struct{}{})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			close(release)
+
+			select {
+			case <-bodies[0].closed:
+			case <-time.After(time.Second):
+				t.Fatalf("expect loser body closed")
+			}
+
+			ctx = <-winnerCtx
+			if !c.Streaming {
+				if e, a := "body", out; e != a {
+					t.Errorf("expect %v output, got %v", e, a)
+				}
+				if ctx.Err() == nil {
+					t.Errorf("expect winner context canceled once deserialized")
+				}
+				return
+			}
+
+			body := out.(io.ReadCloser)
+			if b, err := ioutil.ReadAll(body); err != nil || string(b) != "body" {
+				t.Fatalf("expect body read, got %q, %v", b, err)
+			}
+			if err := ctx.Err(); err != nil {
+				t.Errorf("expect winner context not canceled before close, got %v", err)
+			}
+			body.Close()
+			if ctx.Err() == nil {
+				t.Errorf("expect winner context canceled after close")
+			}
+		})
+	}
+}
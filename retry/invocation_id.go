@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/awslabs/smithy-go/middleware"
+	"github.com/awslabs/smithy-go/rand"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// Headers set by the InvocationID middleware.
+const (
+	InvocationIDHeader   = "amz-sdk-invocation-id"
+	RequestAttemptHeader = "amz-sdk-request"
+)
+
+// AttemptInfo is the attempt of the operation's call being made.
+type AttemptInfo struct {
+	// The attempt number, starting at 1 for the initial attempt.
+	Attempt int
+
+	// The maximum number of attempts of the retryer.
+	MaxAttempts int
+}
+
+type attemptInfoKey struct{}
+
+func withAttemptInfo(ctx context.Context, info AttemptInfo) context.Context {
+	return context.WithValue(ctx, attemptInfoKey{}, info)
+}
+
+// GetAttemptInfo returns the attempt being made, set on the context of each
+// attempt by the AttemptMiddleware, and if it was set.
+func GetAttemptInfo(ctx context.Context) (AttemptInfo, bool) {
+	v, ok := ctx.Value(attemptInfoKey{}).(AttemptInfo)
+	return v, ok
+}
+
+// InvocationID is a finalize middleware that sets the headers servers use to
+// correlate the attempts of an operation's call. The amz-sdk-invocation-id
+// header is a random UUID generated for the first attempt, and sent unchanged
+// with each retry. The amz-sdk-request header is the attempt number and
+// maximum attempts, (e.g. "attempt=2; max=3").
+//
+// The middleware must be added after the Retry middleware, see
+// AddInvocationID. Without the Retry middleware, the request is sent as
+// attempt 1 of 1.
+type InvocationID struct {
+	// Random source of the invocation ID. Defaults to rand.Reader.
+	Rand io.Reader
+}
+
+// AddInvocationID inserts the InvocationID middleware into the stack's
+// Finalize step, immediately after the Retry middleware. Returns an error if
+// the stack does not have a Retry middleware.
+func AddInvocationID(stack *middleware.Stack, m *InvocationID) error {
+	return stack.Finalize.Insert(m, AttemptMiddlewareID, middleware.After)
+}
+
+// ID returns the middleware identifier.
+func (m *InvocationID) ID() string {
+	return "InvocationID"
+}
+
+// HandleFinalize sets the invocation and attempt headers of the request.
+func (m *InvocationID) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*smithyhttp.Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	// The request is reused by each retry, so the ID of the first attempt is
+	// kept.
+	if len(req.Header.Get(InvocationIDHeader)) == 0 {
+		id, err := rand.NewUUID(m.Rand).GetUUID()
+		if err != nil {
+			return out, metadata, fmt.Errorf("failed to generate invocation ID, %w", err)
+		}
+		req.Header.Set(InvocationIDHeader, id)
+	}
+
+	info, ok := GetAttemptInfo(ctx)
+	if !ok {
+		info = AttemptInfo{Attempt: 1, MaxAttempts: 1}
+	}
+	req.Header.Set(RequestAttemptHeader,
+		"attempt="+strconv.Itoa(info.Attempt)+"; max="+strconv.Itoa(info.MaxAttempts))
+
+	return next.HandleFinalize(ctx, in)
+}
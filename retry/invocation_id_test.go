@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func TestInvocationID(t *testing.T) {
+	stack := middleware.NewStack("invocation id", smithyhttp.NewStackRequest)
+	stack.Finalize.Add(NewAttemptMiddleware(mockRetryer{maxAttempts: 3}), middleware.After)
+	err := AddInvocationID(stack, &InvocationID{
+		Rand: bytes.NewReader(bytes.Repeat([]byte{0xab}, 16)),
+	})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var invocationIDs, attempts []string
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			req := in.(*smithyhttp.Request)
+			invocationIDs = append(invocationIDs, req.Header.Get(InvocationIDHeader))
+			attempts = append(attempts, req.Header.Get(RequestAttemptHeader))
+			return nil, middleware.Metadata{}, errRetryable
+		}), stack)
+
+	if _, _, err := handler.Handle(context.Background(), struct{}{}); err == nil {
+		t.Fatalf("expect error, got none")
+	}
+
+	if e, a := 3, len(attempts); e != a {
+		t.Fatalf("expect %v attempts, got %v", e, a)
+	}
+	for i := range attempts {
+		if e, a := "abababab-abab-4bab-abab-abababababab", invocationIDs[i]; e != a {
+			t.Errorf("expect attempt %d invocation ID %v, got %v", i+1, e, a)
+		}
+		if e, a := fmt.Sprintf("attempt=%d; max=3", i+1), attempts[i]; e != a {
+			t.Errorf("expect %q attempt header, got %q", e, a)
+		}
+	}
+}
+
+func TestInvocationID_NoRetry(t *testing.T) {
+	req := smithyhttp.NewStackRequest().(*smithyhttp.Request)
+
+	var m InvocationID
+	_, _, err := m.HandleFinalize(context.Background(), middleware.FinalizeInput{Request: req},
+		finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+			out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+		) {
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 36, len(req.Header.Get(InvocationIDHeader)); e != a {
+		t.Errorf("expect %v length invocation ID, got %v", e, a)
+	}
+	if e, a := "attempt=1; max=1", req.Header.Get(RequestAttemptHeader); e != a {
+		t.Errorf("expect %q attempt header, got %q", e, a)
+	}
+}
@@ -0,0 +1,219 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// StreamNotRewindableError is returned by the AttemptMiddleware when an
+// attempt failed with a retryable error, but the request's body stream is
+// not seekable, and so cannot be rewound to be sent again. The attempt's
+// error is wrapped.
+type StreamNotRewindableError struct {
+	Err error
+}
+
+func (e *StreamNotRewindableError) Error() string {
+	return fmt.Sprintf("unable to retry, request body stream is not seekable, %v", e.Err)
+}
+
+// Unwrap returns the attempt's error.
+func (e *StreamNotRewindableError) Unwrap() error { return e.Err }
+
+type skipRetryKey struct{}
+
+// WithSkipRetry returns a context marking that the operation's request is not
+// retried by the AttemptMiddleware, (e.g. internal health check calls).
+func WithSkipRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipRetryKey{}, true)
+}
+
+// IsSkipRetry returns if the context marks the operation's request as not
+// retried.
+func IsSkipRetry(ctx context.Context) bool {
+	v, _ := ctx.Value(skipRetryKey{}).(bool)
+	return v
+}
+
+// AttemptMiddlewareID is the ID of the AttemptMiddleware in the Finalize
+// step. Middleware run for each attempt must be added after it.
+const AttemptMiddlewareID = "Retry"
+
+// AttemptMiddleware is a finalize middleware that retries the remainder of
+// the stack when an attempt fails with a retryable error, waiting the delay
+// given by the Retryer between attempts.
+//
+// If the context has a deadline which would be exceeded by the delay before
+// the next attempt, the attempt will not be retried.
+//
+// If the failed attempt's HTTP response includes a Retry-After header, the
+// delay requested by the service is used when it is longer than the
+// Retryer's delay, capped by the Retryer's maximum backoff.
+//
+// If the Retryer has a backoff budget, (e.g. StandardOptions.MaxBackoffBudget),
+// the attempt is not retried if the time elapsed since the first attempt
+// started, plus the delay, would exceed the budget. The elapsed time is
+// measured with the stack's clock, see middleware.GetClock.
+//
+// The number of attempts made, and the total delay between them, are set in
+// the operation's metadata, see middleware.GetAttemptCountMetadata and
+// middleware.GetRetryDelayMetadata.
+//
+// Seekable request body streams are rewound to their start before each
+// retry. Attempts of requests with a body stream that is not seekable are not
+// retried, failing with a StreamNotRewindableError instead of sending a
+// partially consumed body.
+//
+// If the context is marked with WithSkipRetry, the request is sent once,
+// without attempt tokens or retries.
+type AttemptMiddleware struct {
+	retryer Retryer
+}
+
+// NewAttemptMiddleware returns a new AttemptMiddleware using the retryer to
+// determine if and when attempts are retried.
+func NewAttemptMiddleware(retryer Retryer) *AttemptMiddleware {
+	return &AttemptMiddleware{
+		retryer: retryer,
+	}
+}
+
+// ID returns the middleware identifier.
+func (r *AttemptMiddleware) ID() string {
+	return AttemptMiddlewareID
+}
+
+// HandleFinalize attempts to handle the request, retrying the attempt while
+// the error is retryable and the maximum attempts have not been made.
+func (r *AttemptMiddleware) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	if IsSkipRetry(ctx) {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	maxAttempts := r.retryer.MaxAttempts()
+
+	logger := middleware.GetLogger(ctx)
+
+	clock := middleware.GetClock(ctx)
+	budget := r.backoffBudget()
+	var start time.Time
+	if budget > 0 {
+		start = clock.Now()
+	}
+
+	var totalDelay time.Duration
+	releaseRetryToken := nopReleaseToken
+	for attempt := 1; ; attempt++ {
+		releaseAttemptToken, tokenErr := r.retryer.GetAttemptToken(ctx)
+		if tokenErr != nil {
+			return out, metadata, fmt.Errorf("failed to get attempt token, %w", tokenErr)
+		}
+
+		attemptCtx := middleware.SetLogger(ctx, logging.With(logger, "attempt", attempt))
+		attemptCtx = withAttemptInfo(attemptCtx, AttemptInfo{Attempt: attempt, MaxAttempts: maxAttempts})
+		out, metadata, err = next.HandleFinalize(attemptCtx, in)
+		middleware.SetAttemptCountMetadata(&metadata, attempt)
+		middleware.SetRetryDelayMetadata(&metadata, totalDelay)
+
+		if releaseErr := releaseAttemptToken(err); releaseErr != nil && err == nil {
+			err = fmt.Errorf("failed to release attempt token, %w", releaseErr)
+		}
+		if releaseErr := releaseRetryToken(err); releaseErr != nil && err == nil {
+			err = fmt.Errorf("failed to release retry token, %w", releaseErr)
+		}
+		if err == nil {
+			return out, metadata, nil
+		}
+
+		if attempt >= maxAttempts || !r.retryer.IsErrorRetryable(err) {
+			return out, metadata, err
+		}
+
+		req, isHTTPRequest := in.Request.(*smithyhttp.Request)
+		if isHTTPRequest && req.GetStream() != nil && !req.IsStreamSeekable() {
+			return out, metadata, &StreamNotRewindableError{Err: err}
+		}
+
+		delay, delayErr := r.retryer.RetryDelay(attempt, err)
+		if delayErr != nil {
+			return out, metadata, delayErr
+		}
+		delay = r.retryAfterDelay(ctx, delay, err)
+
+		if budget > 0 {
+			if elapsed := clock.Now().Sub(start); elapsed+delay > budget {
+				logger.Logf(logging.Debug, "not retrying attempt %d, elapsed %v and delay %v exceed backoff budget %v",
+					attempt, elapsed, delay, budget)
+				return out, metadata, err
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return out, metadata, fmt.Errorf("retry delay %v exceeds context deadline, %w", delay, err)
+		}
+
+		releaseRetryToken, tokenErr = r.retryer.GetRetryToken(ctx, err)
+		if tokenErr != nil {
+			return out, metadata, fmt.Errorf("%v, %w", tokenErr, err)
+		}
+
+		if sleepErr := sleepWithContext(ctx, delay); sleepErr != nil {
+			// The retry was not attempted, so the token's cost is returned.
+			releaseRetryToken(nil)
+			return out, metadata, sleepErr
+		}
+		totalDelay += delay
+
+		if isHTTPRequest && req.GetStream() != nil {
+			if err = req.RewindStream(); err != nil {
+				return out, metadata, fmt.Errorf("failed to rewind request stream, %w", err)
+			}
+		}
+	}
+}
+
+// retryAfterDelay returns the larger of the computed delay and the delay
+// requested by the response's Retry-After header. The server's delay is
+// capped by the retryer's maximum backoff, if the retryer has one.
+func (r *AttemptMiddleware) retryAfterDelay(ctx context.Context, delay time.Duration, err error) time.Duration {
+	serverDelay, ok := RetryAfterDelay(err, middleware.GetClock(ctx).Now())
+	if !ok || serverDelay <= delay {
+		return delay
+	}
+
+	if v, ok := r.retryer.(interface{ MaxBackoff() time.Duration }); ok && serverDelay > v.MaxBackoff() {
+		serverDelay = v.MaxBackoff()
+	}
+	if serverDelay > delay {
+		return serverDelay
+	}
+	return delay
+}
+
+// backoffBudget returns the retryer's maximum total time of the attempts, or
+// zero if the retryer does not have one.
+func (r *AttemptMiddleware) backoffBudget() time.Duration {
+	if v, ok := r.retryer.(interface{ MaxBackoffBudget() time.Duration }); ok {
+		return v.MaxBackoffBudget()
+	}
+	return 0
+}
+
+// sleepWithContext blocks for the delay, or until the context is done.
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,469 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+	"github.com/awslabs/smithy-go/ratelimit"
+	smithytime "github.com/awslabs/smithy-go/time"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+type mockRetryer struct {
+	maxAttempts int
+	delay       time.Duration
+}
+
+func (m mockRetryer) MaxAttempts() int { return m.maxAttempts }
+
+func (m mockRetryer) IsErrorRetryable(err error) bool {
+	return RetryableError{}.IsErrorRetryable(err)
+}
+
+func (m mockRetryer) RetryDelay(int, error) (time.Duration, error) { return m.delay, nil }
+
+func (m mockRetryer) GetAttemptToken(context.Context) (func(error) error, error) {
+	return nopReleaseToken, nil
+}
+
+func (m mockRetryer) GetRetryToken(context.Context, error) (func(error) error, error) {
+	return nopReleaseToken, nil
+}
+
+type mockFinalizeHandler struct {
+	errs     []error
+	attempts int
+}
+
+func (h *mockFinalizeHandler) HandleFinalize(ctx context.Context, in middleware.FinalizeInput) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	h.attempts++
+	if len(h.errs) != 0 {
+		err, h.errs = h.errs[0], h.errs[1:]
+	}
+	return out, metadata, err
+}
+
+var errRetryable = &smithy.GenericAPIError{Code: "RetryableException", Retryable: true}
+
+func TestAttemptMiddleware(t *testing.T) {
+	cases := map[string]struct {
+		Retryer        Retryer
+		Errs           []error
+		ExpectAttempts int
+		ExpectErr      error
+	}{
+		"success": {
+			Retryer:        mockRetryer{maxAttempts: 3},
+			ExpectAttempts: 1,
+		},
+		"retried success": {
+			Retryer:        mockRetryer{maxAttempts: 3},
+			Errs:           []error{errRetryable, errRetryable},
+			ExpectAttempts: 3,
+		},
+		"max attempts": {
+			Retryer:        mockRetryer{maxAttempts: 2},
+			Errs:           []error{errRetryable, errRetryable, errRetryable},
+			ExpectAttempts: 2,
+			ExpectErr:      errRetryable,
+		},
+		"not retryable": {
+			Retryer:        mockRetryer{maxAttempts: 3},
+			Errs:           []error{&smithy.GenericAPIError{Code: "FooException"}},
+			ExpectAttempts: 1,
+			ExpectErr:      &smithy.GenericAPIError{Code: "FooException"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := &mockFinalizeHandler{errs: c.Errs}
+			_, metadata, err := NewAttemptMiddleware(c.Retryer).HandleFinalize(context.Background(),
+				middleware.FinalizeInput{}, h)
+
+			if c.ExpectErr == nil && err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			} else if c.ExpectErr != nil && (err == nil || err.Error() != c.ExpectErr.Error()) {
+				t.Fatalf("expect %v error, got %v", c.ExpectErr, err)
+			}
+			if e, a := c.ExpectAttempts, h.attempts; e != a {
+				t.Errorf("expect %v attempts, got %v", e, a)
+			}
+			if v, ok := middleware.GetAttemptCountMetadata(metadata); !ok || v != c.ExpectAttempts {
+				t.Errorf("expect %v attempt count metadata, got %v, %v", c.ExpectAttempts, v, ok)
+			}
+		})
+	}
+}
+
+func TestAttemptMiddleware_SkipRetry(t *testing.T) {
+	h := &mockFinalizeHandler{errs: []error{errRetryable, errRetryable}}
+	m := NewAttemptMiddleware(mockRetryer{maxAttempts: 3})
+
+	var attemptInfo bool
+	_, _, err := m.HandleFinalize(WithSkipRetry(context.Background()), middleware.FinalizeInput{},
+		finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+			out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+		) {
+			_, attemptInfo = GetAttemptInfo(ctx)
+			return h.HandleFinalize(ctx, in)
+		}))
+	if !errors.Is(err, errRetryable) {
+		t.Fatalf("expect attempt error, got %v", err)
+	}
+	if e, a := 1, h.attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+	if attemptInfo {
+		t.Errorf("expect no attempt info for skipped retry")
+	}
+}
+
+func TestAttemptMiddleware_DeadlineExceedsDelay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	h := &mockFinalizeHandler{errs: []error{errRetryable, errRetryable}}
+	m := NewAttemptMiddleware(mockRetryer{maxAttempts: 3, delay: time.Minute})
+
+	start := time.Now()
+	_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{}, h)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect attempt not delayed, took %v", elapsed)
+	}
+	if !errors.Is(err, errRetryable) {
+		t.Errorf("expect attempt error wrapped, got %v", err)
+	}
+	if e, a := 1, h.attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+}
+
+func TestAttemptMiddleware_ContextCanceledDuringDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	h := &mockFinalizeHandler{errs: []error{errRetryable, errRetryable}}
+	m := NewAttemptMiddleware(mockRetryer{maxAttempts: 3, delay: time.Minute})
+
+	start := time.Now()
+	_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{}, h)
+	if e, a := context.Canceled, err; e != a {
+		t.Fatalf("expect %v error, got %v", e, a)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect prompt return on cancel, took %v", elapsed)
+	}
+	if e, a := 1, h.attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+}
+
+func TestAttemptMiddleware_AttemptLogger(t *testing.T) {
+	var logged []string
+	ctx := middleware.SetLogger(context.Background(), logging.LoggerFunc(
+		func(classification logging.Classification, format string, v ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, v...))
+		}))
+
+	h := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		middleware.GetLogger(ctx).Logf(logging.Debug, "sending")
+		if len(logged) < 2 {
+			err = errRetryable
+		}
+		return out, metadata, err
+	})
+
+	m := NewAttemptMiddleware(mockRetryer{maxAttempts: 3})
+	if _, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{}, h); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := []string{"sending attempt=1", "sending attempt=2"}
+	if e, a := expect, logged; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v logged, got %v", e, a)
+	}
+}
+
+type finalizeHandlerFunc func(context.Context, middleware.FinalizeInput) (
+	middleware.FinalizeOutput, middleware.Metadata, error,
+)
+
+func (fn finalizeHandlerFunc) HandleFinalize(ctx context.Context, in middleware.FinalizeInput) (
+	middleware.FinalizeOutput, middleware.Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+func TestAttemptMiddleware_RetryQuota(t *testing.T) {
+	bucket := ratelimit.NewTokenBucket(10)
+	r := NewStandard(func(o *StandardOptions) {
+		o.MaxAttempts = 5
+		o.MaxBackoff = time.Millisecond
+		o.RateLimiter = bucket
+	})
+	m := NewAttemptMiddleware(r)
+
+	h := &mockFinalizeHandler{errs: []error{errRetryable, errRetryable, errRetryable}}
+	_, _, err := m.HandleFinalize(context.Background(), middleware.FinalizeInput{}, h)
+	if !errors.Is(err, errRetryable) {
+		t.Fatalf("expect attempt error wrapped, got %v", err)
+	}
+	if e, a := 3, h.attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+	if e, a := uint(0), bucket.Available(); e != a {
+		t.Errorf("expect %v tokens available, got %v", e, a)
+	}
+
+	bucket.AddTokens(5)
+	h = &mockFinalizeHandler{errs: []error{errRetryable}}
+	if _, _, err = m.HandleFinalize(context.Background(), middleware.FinalizeInput{}, h); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := uint(6), bucket.Available(); e != a {
+		t.Errorf("expect retry and success tokens returned, %v available, got %v", e, a)
+	}
+}
+
+func TestAttemptMiddleware_RetryQuotaDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bucket := ratelimit.NewTokenBucket(10)
+	r := NewStandard(func(o *StandardOptions) {
+		o.MaxAttempts = 3
+		o.MaxBackoff = time.Minute
+		o.RateLimiter = bucket
+	})
+	m := NewAttemptMiddleware(r)
+
+	h := &mockFinalizeHandler{errs: []error{retryAfterError("30")}}
+	_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{}, h)
+	if err == nil {
+		t.Fatalf("expect error, got none")
+	}
+	if e, a := 1, h.attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+	if e, a := uint(10), bucket.Available(); e != a {
+		t.Errorf("expect no retry tokens taken, %v available, got %v", e, a)
+	}
+}
+
+func TestAttemptMiddleware_RetryQuotaCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	bucket := ratelimit.NewTokenBucket(10)
+	r := NewStandard(func(o *StandardOptions) {
+		o.MaxAttempts = 3
+		o.MaxBackoff = time.Minute
+		o.RateLimiter = bucket
+	})
+	m := NewAttemptMiddleware(r)
+
+	h := &mockFinalizeHandler{errs: []error{retryAfterError("30")}}
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{}, h)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context canceled error, got %v", err)
+	}
+	if e, a := uint(10), bucket.Available(); e != a {
+		t.Errorf("expect retry tokens returned, %v available, got %v", e, a)
+	}
+}
+
+func TestStandard(t *testing.T) {
+	r := NewStandard(func(o *StandardOptions) {
+		o.MaxAttempts = 5
+		o.MaxBackoff = 2 * time.Second
+	})
+
+	if e, a := 5, r.MaxAttempts(); e != a {
+		t.Errorf("expect %v max attempts, got %v", e, a)
+	}
+	if !r.IsErrorRetryable(errRetryable) {
+		t.Errorf("expect error to be retryable")
+	}
+
+	for attempt := 1; attempt < 10; attempt++ {
+		delay, err := r.RetryDelay(attempt, errRetryable)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if delay < 0 || delay > 2*time.Second {
+			t.Errorf("expect delay within max backoff, got %v", delay)
+		}
+	}
+}
+
+func TestAttemptMiddleware_RewindsSeekableStream(t *testing.T) {
+	req, err := smithyhttp.NewStackRequest().(*smithyhttp.Request).SetStream(strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var bodies []string
+	errs := []error{errRetryable, errRetryable}
+	h := finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		b, _ := ioutil.ReadAll(in.Request.(*smithyhttp.Request).GetStream())
+		bodies = append(bodies, string(b))
+		if len(errs) != 0 {
+			err, errs = errs[0], errs[1:]
+		}
+		return out, metadata, err
+	})
+
+	_, _, err = NewAttemptMiddleware(mockRetryer{maxAttempts: 3}).HandleFinalize(context.Background(),
+		middleware.FinalizeInput{Request: req}, h)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"payload", "payload", "payload"}, bodies; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v bodies sent, got %v", e, a)
+	}
+}
+
+func TestAttemptMiddleware_RewindsAWSChunkedStream(t *testing.T) {
+	stack := middleware.NewStack("aws-chunked", smithyhttp.NewStackRequest)
+	stack.Serialize.Add(middleware.SerializeMiddlewareFunc("serialize",
+		func(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (
+			out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+		) {
+			req := in.Request.(*smithyhttp.Request)
+			if in.Request, err = req.SetStream(strings.NewReader("payload")); err != nil {
+				return out, metadata, err
+			}
+			return next.HandleSerialize(ctx, in)
+		}), middleware.After)
+	stack.Build.Add(&smithyhttp.AWSChunkedEncoding{
+		ChecksumAlgorithm: smithyhttp.ChecksumAlgorithmCRC32,
+		ChecksumTrailer:   "x-amz-checksum-crc32",
+	}, middleware.After)
+	stack.Finalize.Add(NewAttemptMiddleware(mockRetryer{maxAttempts: 3}), middleware.After)
+
+	var bodies []string
+	errs := []error{errRetryable, errRetryable}
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			b, _ := ioutil.ReadAll(in.(*smithyhttp.Request).GetStream())
+			bodies = append(bodies, string(b))
+			var err error
+			if len(errs) != 0 {
+				err, errs = errs[0], errs[1:]
+			}
+			return nil, middleware.Metadata{}, err
+		}), stack)
+
+	if _, _, err := handler.Handle(context.Background(), struct{}{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := "7\r\npayload\r\n0\r\nx-amz-checksum-crc32:QixqFQ==\r\n\r\n"
+	if e, a := []string{expect, expect, expect}, bodies; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %q bodies sent, got %q", e, a)
+	}
+}
+
+func TestAttemptMiddleware_NonSeekableStream(t *testing.T) {
+	req, err := smithyhttp.NewStackRequest().(*smithyhttp.Request).SetStream(
+		ioutil.NopCloser(bytes.NewBufferString("payload")))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	h := &mockFinalizeHandler{errs: []error{errRetryable, nil}}
+	_, _, err = NewAttemptMiddleware(mockRetryer{maxAttempts: 3}).HandleFinalize(context.Background(),
+		middleware.FinalizeInput{Request: req}, h)
+
+	var streamErr *StreamNotRewindableError
+	if !errors.As(err, &streamErr) {
+		t.Fatalf("expect stream not rewindable error, got %v", err)
+	}
+	if !errors.Is(err, errRetryable) {
+		t.Errorf("expect attempt error wrapped, got %v", err)
+	}
+	if e, a := 1, h.attempts; e != a {
+		t.Errorf("expect %v attempts, got %v", e, a)
+	}
+}
+
+func TestAttemptMiddleware_MaxBackoffBudget(t *testing.T) {
+	cases := map[string]struct {
+		Budget         time.Duration
+		AttemptTime    time.Duration
+		ExpectAttempts int
+	}{
+		"within budget": {
+			Budget:         time.Minute,
+			AttemptTime:    10 * time.Second,
+			ExpectAttempts: 5,
+		},
+		"budget boundary": {
+			Budget:         30 * time.Second,
+			AttemptTime:    10 * time.Second,
+			ExpectAttempts: 3,
+		},
+		"first attempt exceeds budget": {
+			Budget:         30 * time.Second,
+			AttemptTime:    time.Minute,
+			ExpectAttempts: 1,
+		},
+		"no budget": {
+			AttemptTime:    time.Hour,
+			ExpectAttempts: 5,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+			ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+				return now
+			}))
+
+			m := NewAttemptMiddleware(NewStandard(func(o *StandardOptions) {
+				o.MaxAttempts = 5
+				o.MaxBackoffBudget = c.Budget
+				o.Backoff = Fixed{Delay: time.Nanosecond}
+			}))
+
+			var attempts int
+			_, metadata, err := m.HandleFinalize(ctx, middleware.FinalizeInput{},
+				finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					attempts++
+					now = now.Add(c.AttemptTime)
+					return out, metadata, errRetryable
+				}))
+			if e, a := errRetryable, err; e != a {
+				t.Errorf("expect %v error, got %v", e, a)
+			}
+			if e, a := c.ExpectAttempts, attempts; e != a {
+				t.Errorf("expect %v attempts, got %v", e, a)
+			}
+			if v, _ := middleware.GetAttemptCountMetadata(metadata); v != attempts {
+				t.Errorf("expect %v attempt count metadata, got %v", attempts, v)
+			}
+		})
+	}
+}
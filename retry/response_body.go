@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"io"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// attemptResponse is the HTTP response of an attempt, recorded by the raw
+// response hook before the response is deserialized.
+type attemptResponse struct {
+	resp *smithyhttp.Response
+}
+
+// withCancelOnBodyClose returns a context with a raw response hook wrapping
+// the body of the attempt's HTTP response, so closing the body calls cancel.
+// The response is recorded in the returned attemptResponse.
+func withCancelOnBodyClose(ctx context.Context, cancel context.CancelFunc) (context.Context, *attemptResponse) {
+	r := &attemptResponse{}
+	ctx = middleware.WithRawResponseHook(ctx, func(raw interface{}) {
+		resp, ok := raw.(*smithyhttp.Response)
+		if !ok || resp == nil || resp.Response == nil || resp.Body == nil {
+			return
+		}
+		resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+		r.resp = resp
+	})
+	return ctx, r
+}
+
+// release calls cancel once the attempt has succeeded. If the response body
+// is owned by a streaming output, cancel is left to be called when the body
+// is closed, so the body can still be read.
+func (r *attemptResponse) release(ctx context.Context, metadata middleware.Metadata, cancel context.CancelFunc) {
+	if r.resp != nil && (smithyhttp.IsStreamingOutputMetadata(metadata) || smithyhttp.IsStreamingOutput(ctx)) {
+		return
+	}
+	cancel()
+}
+
+// close closes the attempt's response body, if it has one.
+func (r *attemptResponse) close() {
+	if r.resp != nil {
+		r.resp.Body.Close()
+	}
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,55 @@
+package retry
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	smithytime "github.com/awslabs/smithy-go/time"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+const retryAfterHeader = "Retry-After"
+
+// RetryAfterDelay returns the delay requested by the Retry-After header of
+// the HTTP response the error was deserialized from, and if the header was
+// present and valid. Both the delta-seconds and HTTP-date forms of the header
+// are supported. HTTP-dates in the past return a delay of zero.
+func RetryAfterDelay(err error, now time.Time) (time.Duration, bool) {
+	var respErr *smithyhttp.ResponseError
+	if !errors.As(err, &respErr) || respErr.Response == nil || respErr.Response.Response == nil {
+		return 0, false
+	}
+	return parseRetryAfter(respErr.Response.Header.Get(retryAfterHeader), now)
+}
+
+// parseRetryAfter parses the Retry-After header value, returning false if
+// the header is empty or malformed.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if v[0] >= '0' && v[0] <= '9' {
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seconds > int64(maxRetryAfter/time.Second) {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := smithytime.ParseHTTPDate(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// maxRetryAfter bounds delta-seconds values so they do not overflow
+// time.Duration.
+const maxRetryAfter = 24 * time.Hour
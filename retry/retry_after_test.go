@@ -0,0 +1,153 @@
+package retry
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func retryAfterError(v string) error {
+	resp := &http.Response{StatusCode: 429, Header: http.Header{}}
+	if len(v) != 0 {
+		resp.Header.Set("Retry-After", v)
+	}
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: resp},
+		Err:      &smithy.GenericAPIError{Code: "Throttling", Retryable: true, Throttling: true},
+	}
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	now := time.Date(2014, 4, 29, 18, 30, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		Err         error
+		Expect      time.Duration
+		ExpectFound bool
+	}{
+		"delta seconds": {
+			Err:         retryAfterError("120"),
+			Expect:      2 * time.Minute,
+			ExpectFound: true,
+		},
+		"zero seconds": {
+			Err:         retryAfterError("0"),
+			ExpectFound: true,
+		},
+		"http date": {
+			Err:         retryAfterError("Tue, 29 Apr 2014 18:30:38 GMT"),
+			Expect:      38 * time.Second,
+			ExpectFound: true,
+		},
+		"http date in past": {
+			Err:         retryAfterError("Tue, 29 Apr 2014 18:29:00 GMT"),
+			ExpectFound: true,
+		},
+		"negative seconds": {
+			Err: retryAfterError("-5"),
+		},
+		"fractional seconds": {
+			Err: retryAfterError("1.5"),
+		},
+		"malformed": {
+			Err: retryAfterError("soon"),
+		},
+		"overflow": {
+			Err: retryAfterError("99999999999999999999"),
+		},
+		"no header": {
+			Err: retryAfterError(""),
+		},
+		"not response error": {
+			Err: errRetryable,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, ok := RetryAfterDelay(c.Err, now)
+			if e, a := c.ExpectFound, ok; e != a {
+				t.Fatalf("expect found %v, got %v", e, a)
+			}
+			if e, a := c.Expect, d; e != a {
+				t.Errorf("expect %v delay, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestAttemptMiddleware_RetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		Retryer     Retryer
+		RetryAfter  string
+		ExpectDelay time.Duration
+	}{
+		"server delay longer": {
+			Retryer:     mockRetryer{maxAttempts: 3, delay: time.Second},
+			RetryAfter:  "5",
+			ExpectDelay: 5 * time.Second,
+		},
+		"computed delay longer": {
+			Retryer:     mockRetryer{maxAttempts: 3, delay: 10 * time.Second},
+			RetryAfter:  "5",
+			ExpectDelay: 10 * time.Second,
+		},
+		"capped by max backoff": {
+			Retryer: NewStandard(func(o *StandardOptions) {
+				o.MaxBackoff = 3 * time.Second
+			}),
+			RetryAfter:  "60",
+			ExpectDelay: 3 * time.Second,
+		},
+		"malformed ignored": {
+			Retryer:     mockRetryer{maxAttempts: 3, delay: time.Second},
+			RetryAfter:  "later",
+			ExpectDelay: time.Second,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(time.Now))
+			m := NewAttemptMiddleware(c.Retryer)
+
+			delay := m.retryAfterDelay(ctx, mustRetryDelay(t, c.Retryer), retryAfterError(c.RetryAfter))
+			if e, a := c.ExpectDelay, delay; e != a {
+				t.Errorf("expect %v delay, got %v", e, a)
+			}
+		})
+	}
+}
+
+func mustRetryDelay(t *testing.T, r Retryer) time.Duration {
+	t.Helper()
+	d, err := r.RetryDelay(1, errRetryable)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return d
+}
+
+func TestAttemptMiddleware_RetryAfterExceedsDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	h := &mockFinalizeHandler{errs: []error{retryAfterError("30")}}
+	m := NewAttemptMiddleware(mockRetryer{maxAttempts: 3})
+
+	start := time.Now()
+	_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{}, h)
+	if err == nil || !strings.Contains(err.Error(), "retry delay 30s exceeds context deadline") {
+		t.Fatalf("expect retry after delay to exceed deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expect attempt not delayed, took %v", elapsed)
+	}
+}
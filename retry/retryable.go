@@ -0,0 +1,131 @@
+package retry
+
+import (
+	"errors"
+
+	"github.com/awslabs/smithy-go"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// IsErrorRetryable provides the interface of an implementation to determine if
+// an error as the result of an operation is retryable.
+type IsErrorRetryable interface {
+	IsErrorRetryable(error) bool
+}
+
+// IsErrorRetryables is a collection of checks to determine if the error is
+// retryable. The error is retryable if any of the checks report it as
+// retryable.
+type IsErrorRetryables []IsErrorRetryable
+
+// IsErrorRetryable returns if any of the checks determine the error is
+// retryable.
+func (r IsErrorRetryables) IsErrorRetryable(err error) bool {
+	for _, re := range r {
+		if re.IsErrorRetryable(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsErrorRetryableFunc wraps a function with the IsErrorRetryable interface.
+type IsErrorRetryableFunc func(error) bool
+
+// IsErrorRetryable returns if the error is retryable.
+func (fn IsErrorRetryableFunc) IsErrorRetryable(err error) bool {
+	return fn(err)
+}
+
+// RetryableError is an IsErrorRetryable implementation which uses the
+// optional smithy.RetryableError interface of the error, or any error it
+// wraps, to determine if the error is retryable.
+type RetryableError struct{}
+
+// IsErrorRetryable returns if the error's RetryableError method reports the
+// error as retryable.
+func (RetryableError) IsErrorRetryable(err error) bool {
+	var v smithy.RetryableError
+	if !errors.As(err, &v) {
+		return false
+	}
+	return v.RetryableError()
+}
+
+// ThrottleError is an IsErrorRetryable implementation which considers errors
+// implementing the optional smithy.ThrottlingError interface as retryable
+// when they report being the result of throttling.
+type ThrottleError struct{}
+
+// IsErrorRetryable returns if the error is a throttling error.
+func (ThrottleError) IsErrorRetryable(err error) bool {
+	return IsThrottleError(err)
+}
+
+// IsThrottleError returns if the error, or any error it wraps, reports being
+// the result of throttling.
+func IsThrottleError(err error) bool {
+	var v smithy.ThrottlingError
+	if !errors.As(err, &v) {
+		return false
+	}
+	return v.ThrottlingError()
+}
+
+// DefaultRetryableHTTPStatusCodes is the default set of HTTP response status
+// codes considered retryable.
+var DefaultRetryableHTTPStatusCodes = map[int]bool{
+	500: true,
+	502: true,
+	503: true,
+	504: true,
+}
+
+// RetryableHTTPStatusCode is an IsErrorRetryable implementation which
+// considers errors wrapping a smithyhttp.ResponseError retryable if the
+// response's status code is in the set of codes.
+type RetryableHTTPStatusCode struct {
+	Codes map[int]bool
+}
+
+// IsErrorRetryable returns if the error's HTTP response status code is
+// retryable.
+func (r RetryableHTTPStatusCode) IsErrorRetryable(err error) bool {
+	var v *smithyhttp.ResponseError
+	if !errors.As(err, &v) || v.Response == nil {
+		return false
+	}
+	return r.Codes[v.Response.StatusCode]
+}
+
+// RetryableErrorCode is an IsErrorRetryable implementation which considers
+// API errors retryable if their error code is in the set of codes,
+// regardless of the HTTP status code of the response, (e.g. application
+// defined retryable errors returned with a 400 status code).
+type RetryableErrorCode struct {
+	Codes map[string]bool
+}
+
+// RetryErrorCodes returns a RetryableErrorCode for the set of error codes.
+// Add it to a retryer's retryables for the codes to be retried in addition
+// to the errors the other retryables consider retryable.
+func RetryErrorCodes(set map[string]bool) RetryableErrorCode {
+	return RetryableErrorCode{Codes: set}
+}
+
+// IsErrorRetryable returns if the API error's code is retryable.
+func (r RetryableErrorCode) IsErrorRetryable(err error) bool {
+	var v smithy.APIError
+	if !errors.As(err, &v) {
+		return false
+	}
+	return r.Codes[v.ErrorCode()]
+}
+
+// DefaultRetryables provides the set of retryable checks that are used by
+// default.
+var DefaultRetryables = []IsErrorRetryable{
+	RetryableError{},
+	ThrottleError{},
+	RetryableHTTPStatusCode{Codes: DefaultRetryableHTTPStatusCodes},
+}
@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+func TestIsErrorRetryables(t *testing.T) {
+	cases := map[string]struct {
+		Err    error
+		Expect bool
+	}{
+		"nil error": {},
+		"not API error": {
+			Err: fmt.Errorf("some error"),
+		},
+		"not retryable": {
+			Err: &smithy.GenericAPIError{Code: "FooException"},
+		},
+		"retryable": {
+			Err:    &smithy.GenericAPIError{Code: "FooException", Retryable: true},
+			Expect: true,
+		},
+		"throttling": {
+			Err:    &smithy.GenericAPIError{Code: "SlowDown", Throttling: true},
+			Expect: true,
+		},
+		"wrapped retryable": {
+			Err: &smithy.OperationError{
+				ServiceName:   "FooService",
+				OperationName: "FooOperation",
+				Err: fmt.Errorf("wrapped, %w", &smithy.GenericAPIError{
+					Code: "FooException", Retryable: true,
+				}),
+			},
+			Expect: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			retryable := IsErrorRetryables(DefaultRetryables).IsErrorRetryable(c.Err)
+			if e, a := c.Expect, retryable; e != a {
+				t.Errorf("expect %v retryable, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestIsThrottleError(t *testing.T) {
+	if IsThrottleError(&smithy.GenericAPIError{Retryable: true}) {
+		t.Errorf("expect retryable error to not be throttle error")
+	}
+	if !IsThrottleError(&smithy.GenericAPIError{Throttling: true}) {
+		t.Errorf("expect throttle error")
+	}
+}
+
+func TestRetryErrorCodes(t *testing.T) {
+	responseErr := func(status int, code string) error {
+		return &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      &smithy.GenericAPIError{Code: code, Fault: smithy.FaultClient},
+		}
+	}
+
+	retryables := IsErrorRetryables(append(DefaultRetryables,
+		RetryErrorCodes(map[string]bool{"TransactionConflict": true})))
+
+	cases := map[string]struct {
+		Err    error
+		Expect bool
+	}{
+		"listed code on 400": {
+			Err:    responseErr(400, "TransactionConflict"),
+			Expect: true,
+		},
+		"unlisted code on 400": {
+			Err: responseErr(400, "ValidationException"),
+		},
+		"unlisted code on retryable status": {
+			Err:    responseErr(503, "ValidationException"),
+			Expect: true,
+		},
+		"wrapped listed code": {
+			Err: &smithy.OperationError{
+				ServiceName:   "FooService",
+				OperationName: "FooOperation",
+				Err:           responseErr(400, "TransactionConflict"),
+			},
+			Expect: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Expect, retryables.IsErrorRetryable(c.Err); e != a {
+				t.Errorf("expect %v retryable, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Retryer provides the interface the retry middleware uses to determine if a
+// failed attempt can be retried, and how long to wait before retrying it.
+type Retryer interface {
+	// IsErrorRetryable returns if the failed attempt is retryable.
+	IsErrorRetryable(error) bool
+
+	// MaxAttempts returns the maximum number of attempts that can be made
+	// for an operation before failing.
+	MaxAttempts() int
+
+	// RetryDelay returns the delay that should be used before retrying the
+	// attempt. Returns an error if the attempt should not be retried.
+	RetryDelay(attempt int, opErr error) (time.Duration, error)
+
+	// GetAttemptToken is called before each attempt is made, and may block
+	// until the attempt is allowed to be made. The returned release function
+	// must be called with the attempt's result error.
+	GetAttemptToken(ctx context.Context) (releaseToken func(error) error, err error)
+
+	// GetRetryToken is called before a failed attempt is retried. Returns an
+	// error if the attempt should not be retried, (e.g. the retry quota has
+	// been exhausted). The returned release function must be called with the
+	// retried attempt's result error.
+	GetRetryToken(ctx context.Context, opErr error) (releaseToken func(error) error, err error)
+}
+
+// RateLimiter provides the interface for the token bucket the Standard
+// retryer retrieves retry tokens from, (e.g. ratelimit.TokenBucket).
+type RateLimiter interface {
+	GetToken(ctx context.Context, cost uint) error
+	AddTokens(v uint)
+}
+
+// Default values of the Standard retryer.
+const (
+	// DefaultMaxAttempts is the maximum number of attempts made for an
+	// operation, including the initial attempt.
+	DefaultMaxAttempts = 3
+
+	// DefaultMaxBackoff is the maximum delay between attempts.
+	DefaultMaxBackoff = 20 * time.Second
+
+	// DefaultRetryCost is the number of tokens retrieved from the
+	// RateLimiter for each retry.
+	DefaultRetryCost uint = 5
+
+	// DefaultNoRetryIncrement is the number of tokens returned to the
+	// RateLimiter when an attempt succeeds.
+	DefaultNoRetryIncrement uint = 1
+)
+
+// StandardOptions provides the options for configuring the Standard
+// retryer.
+type StandardOptions struct {
+	// Maximum number of attempts that should be made, including the initial
+	// attempt.
+	MaxAttempts int
+
+	// Maximum delay between attempts.
+	MaxBackoff time.Duration
+
+	// Maximum total time of an operation's attempts and the delays between
+	// them, measured from the start of the first attempt. An attempt is not
+	// retried if the time elapsed plus the delay before the retry would
+	// exceed the budget, even if MaxAttempts has not been reached. The first
+	// attempt is always made. If zero, the time is not limited.
+	MaxBackoffBudget time.Duration
+
+	// Strategy computing the delay before retrying an attempt. If nil,
+	// FullJitter is used with the DefaultBackoffBase and MaxBackoff.
+	Backoff BackoffDelayer
+
+	// Source of the jitter of the default FullJitter Backoff, (e.g. seeded
+	// for reproducible retry delays, see NewJitterSource). If nil, a source
+	// seeded from crypto/rand is used. Not used if Backoff is set.
+	JitterSource rand.Source
+
+	// Disables the jitter of the default Backoff, using Exponential backoff
+	// with the DefaultBackoffBase and MaxBackoff instead of FullJitter, so
+	// the delays between attempts are exact. Not used if Backoff is set.
+	DisableJitter bool
+
+	// Set of checks used to determine if an attempt's error is retryable.
+	Retryables []IsErrorRetryable
+
+	// Token bucket retries are retrieved from, limiting the number of retries
+	// made by all operations sharing the retryer. If nil, retries are not
+	// limited.
+	RateLimiter RateLimiter
+
+	// Number of tokens retrieved from the RateLimiter for each retry.
+	RetryCost uint
+
+	// Number of tokens returned to the RateLimiter when an attempt succeeds.
+	NoRetryIncrement uint
+}
+
+// Standard is the standard retry implementation, using exponential backoff
+// with full jitter between attempts by default.
+type Standard struct {
+	options StandardOptions
+}
+
+// NewStandard returns a Standard retryer initialized with the default values,
+// modified by the functional options provided.
+func NewStandard(optFns ...func(*StandardOptions)) *Standard {
+	o := StandardOptions{
+		MaxAttempts: DefaultMaxAttempts,
+		MaxBackoff:  DefaultMaxBackoff,
+		Retryables:  append([]IsErrorRetryable{}, DefaultRetryables...),
+
+		RetryCost:        DefaultRetryCost,
+		NoRetryIncrement: DefaultNoRetryIncrement,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if o.Backoff == nil && o.DisableJitter {
+		o.Backoff = Exponential{
+			Base:       DefaultBackoffBase,
+			MaxBackoff: o.MaxBackoff,
+		}
+	} else if o.Backoff == nil {
+		o.Backoff = FullJitter{
+			Base:       DefaultBackoffBase,
+			MaxBackoff: o.MaxBackoff,
+			Source:     o.JitterSource,
+		}
+	}
+
+	return &Standard{
+		options: o,
+	}
+}
+
+// DisableJitter returns the Standard retryer option disabling the jitter of
+// the default backoff, see StandardOptions.DisableJitter.
+func DisableJitter() func(*StandardOptions) {
+	return func(o *StandardOptions) {
+		o.DisableJitter = true
+	}
+}
+
+// MaxAttempts returns the maximum number of attempts that can be made for an
+// operation before failing.
+func (s *Standard) MaxAttempts() int {
+	return s.options.MaxAttempts
+}
+
+// MaxBackoff returns the maximum delay between attempts.
+func (s *Standard) MaxBackoff() time.Duration {
+	return s.options.MaxBackoff
+}
+
+// MaxBackoffBudget returns the maximum total time of an operation's attempts
+// and the delays between them, or zero if not limited.
+func (s *Standard) MaxBackoffBudget() time.Duration {
+	return s.options.MaxBackoffBudget
+}
+
+// IsErrorRetryable returns if the error can be retried.
+func (s *Standard) IsErrorRetryable(err error) bool {
+	return IsErrorRetryables(s.options.Retryables).IsErrorRetryable(err)
+}
+
+// RetryDelay returns the delay for the attempt computed by the Backoff
+// option, by default a random delay between zero and the exponential backoff
+// for the attempt, capped by the MaxBackoff option.
+func (s *Standard) RetryDelay(attempt int, err error) (time.Duration, error) {
+	return s.options.Backoff.BackoffDelay(attempt)
+}
+
+// GetAttemptToken returns the release function which returns the
+// NoRetryIncrement tokens to the RateLimiter when the attempt succeeds.
+func (s *Standard) GetAttemptToken(context.Context) (func(error) error, error) {
+	return s.releaseToken(s.options.NoRetryIncrement), nil
+}
+
+// GetRetryToken retrieves the RetryCost tokens from the RateLimiter, returning
+// an error if the retry quota has been exhausted. The tokens are returned to
+// the RateLimiter when the retried attempt succeeds.
+func (s *Standard) GetRetryToken(ctx context.Context, opErr error) (func(error) error, error) {
+	if s.options.RateLimiter == nil {
+		return nopReleaseToken, nil
+	}
+
+	if err := s.options.RateLimiter.GetToken(ctx, s.options.RetryCost); err != nil {
+		return nil, fmt.Errorf("failed to get retry token, %w", err)
+	}
+	return s.releaseToken(s.options.RetryCost), nil
+}
+
+func (s *Standard) releaseToken(v uint) func(error) error {
+	if s.options.RateLimiter == nil {
+		return nopReleaseToken
+	}
+	return func(err error) error {
+		if err == nil {
+			s.options.RateLimiter.AddTokens(v)
+		}
+		return nil
+	}
+}
+
+func nopReleaseToken(error) error { return nil }
+
+var _ Retryer = (*Standard)(nil)
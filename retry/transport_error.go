@@ -0,0 +1,121 @@
+package retry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// TransportErrorKind is the kind of failure of a request's transport, as
+// classified by ClassifyTransportError.
+type TransportErrorKind int
+
+// Enumeration values of TransportErrorKind.
+const (
+	// Not a transport error, or a transport error of an unknown kind.
+	TransportErrorKindOther TransportErrorKind = iota
+
+	// Failed to connect to the endpoint, (e.g. connection refused, or the
+	// host could not be resolved).
+	TransportErrorKindDial
+
+	// The request or connection timed out, or the context's deadline was
+	// exceeded.
+	TransportErrorKindTimeout
+
+	// The TLS handshake failed, (e.g. the certificate is not trusted).
+	TransportErrorKindTLSHandshake
+
+	// The connection was reset by the endpoint.
+	TransportErrorKindResetByPeer
+)
+
+func (k TransportErrorKind) String() string {
+	switch k {
+	case TransportErrorKindDial:
+		return "dial"
+	case TransportErrorKindTimeout:
+		return "timeout"
+	case TransportErrorKindTLSHandshake:
+		return "tls handshake"
+	case TransportErrorKindResetByPeer:
+		return "reset by peer"
+	default:
+		return "other"
+	}
+}
+
+// ClassifyTransportError returns the kind of transport failure of the error,
+// searching the errors it wraps. Errors matching multiple kinds are
+// classified in the order reset by peer, timeout, dial, then TLS handshake,
+// (e.g. a dial that timed out is a timeout).
+func ClassifyTransportError(err error) TransportErrorKind {
+	if err == nil {
+		return TransportErrorKindOther
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) {
+		return TransportErrorKindResetByPeer
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return TransportErrorKindTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return TransportErrorKindTimeout
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return TransportErrorKindDial
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return TransportErrorKindDial
+	}
+
+	if isTLSHandshakeError(err) {
+		return TransportErrorKindTLSHandshake
+	}
+
+	return TransportErrorKindOther
+}
+
+func isTLSHandshakeError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var certErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	switch {
+	case errors.As(err, &recordErr),
+		errors.As(err, &authorityErr),
+		errors.As(err, &certErr),
+		errors.As(err, &hostnameErr):
+		return true
+	}
+
+	// Alerts received from the endpoint during the handshake are not
+	// exported as a type, (e.g. "remote error: tls: handshake failure").
+	return strings.Contains(err.Error(), "tls: ")
+}
+
+// RetryableTransportError is an IsErrorRetryable implementation which
+// considers transport errors of the kinds in the set as retryable, see
+// ClassifyTransportError.
+type RetryableTransportError struct {
+	Kinds map[TransportErrorKind]bool
+}
+
+// IsErrorRetryable returns if the error's transport error kind is in the set
+// of retryable kinds.
+func (r RetryableTransportError) IsErrorRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return r.Kinds[ClassifyTransportError(err)]
+}
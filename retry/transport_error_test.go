@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type mockNetError struct {
+	timeout bool
+}
+
+func (e mockNetError) Error() string   { return "mock net error" }
+func (e mockNetError) Timeout() bool   { return e.timeout }
+func (e mockNetError) Temporary() bool { return false }
+
+func TestClassifyTransportError(t *testing.T) {
+	cases := map[string]struct {
+		Err    error
+		Expect TransportErrorKind
+	}{
+		"nil": {
+			Expect: TransportErrorKindOther,
+		},
+		"not transport error": {
+			Err:    fmt.Errorf("some error"),
+			Expect: TransportErrorKindOther,
+		},
+		"connection refused": {
+			Err: &net.OpError{Op: "dial", Net: "tcp",
+				Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			Expect: TransportErrorKindDial,
+		},
+		"host not found": {
+			Err:    &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "example.com"}},
+			Expect: TransportErrorKindDial,
+		},
+		"net timeout": {
+			Err:    fmt.Errorf("request failed, %w", mockNetError{timeout: true}),
+			Expect: TransportErrorKindTimeout,
+		},
+		"dial timeout": {
+			Err:    &net.OpError{Op: "dial", Err: mockNetError{timeout: true}},
+			Expect: TransportErrorKindTimeout,
+		},
+		"not timeout": {
+			Err:    mockNetError{},
+			Expect: TransportErrorKindOther,
+		},
+		"context deadline": {
+			Err:    fmt.Errorf("request failed, %w", context.DeadlineExceeded),
+			Expect: TransportErrorKindTimeout,
+		},
+		"reset by peer": {
+			Err: &net.OpError{Op: "read", Net: "tcp",
+				Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			Expect: TransportErrorKindResetByPeer,
+		},
+		"tls record header": {
+			Err:    fmt.Errorf("request failed, %w", tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}),
+			Expect: TransportErrorKindTLSHandshake,
+		},
+		"tls unknown authority": {
+			Err:    fmt.Errorf("request failed, %w", x509.UnknownAuthorityError{}),
+			Expect: TransportErrorKindTLSHandshake,
+		},
+		"tls hostname": {
+			Err:    x509.HostnameError{Certificate: &x509.Certificate{}, Host: "example.com"},
+			Expect: TransportErrorKindTLSHandshake,
+		},
+		"tls alert": {
+			Err:    &net.OpError{Op: "remote error", Err: errors.New("tls: handshake failure")},
+			Expect: TransportErrorKindTLSHandshake,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if e, a := c.Expect, ClassifyTransportError(c.Err); e != a {
+				t.Errorf("expect %v kind, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestRetryableTransportError(t *testing.T) {
+	r := RetryableTransportError{Kinds: map[TransportErrorKind]bool{
+		TransportErrorKindResetByPeer: true,
+	}}
+
+	if r.IsErrorRetryable(nil) {
+		t.Errorf("expect nil error not retryable")
+	}
+	if !r.IsErrorRetryable(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}) {
+		t.Errorf("expect reset by peer retryable")
+	}
+	if r.IsErrorRetryable(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}) {
+		t.Errorf("expect dial error not retryable")
+	}
+}
@@ -0,0 +1,29 @@
+package smithytime
+
+import "time"
+
+// Clock provides the interface for retrieving the current time. Middleware
+// should use a Clock instead of calling time.Now directly, so that the time
+// can be controlled in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock that returns the current time of the system.
+type SystemClock struct{}
+
+// Now returns the current system time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ClockFunc wraps a function with the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns the time returned by the wrapped function.
+func (fn ClockFunc) Now() time.Time {
+	return fn()
+}
+
+var _ Clock = SystemClock{}
+var _ Clock = ClockFunc(nil)
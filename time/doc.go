@@ -0,0 +1,8 @@
+// Package smithytime provides the time related helpers for Smithy clients and
+// middleware, including the Clock abstraction for retrieving the current time,
+// and the formatting and parsing of the Smithy timestamp formats, date-time,
+// http-date, and epoch-seconds.
+//
+// ParseTimestamp parses a timestamp in a preferred format, falling back to the
+// other formats for services emitting timestamps inconsistently.
+package smithytime
@@ -0,0 +1,78 @@
+package smithytime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a Smithy timestamp format, as set by the timestampFormat trait.
+type Format string
+
+// Enumeration of the Smithy timestamp formats.
+const (
+	DateTime     Format = "date-time"
+	HTTPDate     Format = "http-date"
+	EpochSeconds Format = "epoch-seconds"
+)
+
+// formatFallbacks is the order formats are tried in after the preferred
+// format.
+var formatFallbacks = []Format{EpochSeconds, HTTPDate, DateTime}
+
+// Range of epoch seconds parsed by ParseTimestamp, 0001-01-01T00:00:00Z to
+// 9999-12-31T23:59:59Z, the range of the date-time and http-date formats.
+const (
+	minEpochSeconds = -62135596800
+	maxEpochSeconds = 253402300799
+)
+
+// ParseTimestamp parses the timestamp in the preferred format, falling back
+// to the other formats, epoch-seconds, http-date, and date-time, if the
+// value is not in the preferred format, (e.g. a service emitting epoch
+// seconds for a member bound as a date-time). The time is returned in UTC.
+//
+// Returns an error if the value is not in any of the formats, or the
+// preferred format is unknown.
+func ParseTimestamp(value string, preferred Format) (time.Time, error) {
+	formats := make([]Format, 0, len(formatFallbacks)+1)
+	switch preferred {
+	case DateTime, HTTPDate, EpochSeconds:
+		formats = append(formats, preferred)
+	default:
+		return time.Time{}, fmt.Errorf("unknown timestamp format %q", preferred)
+	}
+	for _, f := range formatFallbacks {
+		if f != preferred {
+			formats = append(formats, f)
+		}
+	}
+
+	for _, f := range formats {
+		if t, err := parseFormat(value, f); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse timestamp %q, not in %s or fallback formats", value, preferred)
+}
+
+func parseFormat(value string, format Format) (time.Time, error) {
+	switch format {
+	case DateTime:
+		return ParseDateTime(value)
+	case HTTPDate:
+		return ParseHTTPDate(value)
+	default:
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse epoch-seconds %q, %w", value, err)
+		}
+		// ParseFloat accepts NaN and Inf, and exponents out of time's range.
+		if math.IsNaN(v) || v < minEpochSeconds || v > maxEpochSeconds {
+			return time.Time{}, fmt.Errorf("epoch-seconds %q out of range", value)
+		}
+		return ParseEpochSeconds(v), nil
+	}
+}
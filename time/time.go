@@ -0,0 +1,91 @@
+package smithytime
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// dateTimeFormat is the RFC 3339 format timestamps are serialized with,
+	// in UTC with as many fractional second digits as needed.
+	dateTimeFormat = "2006-01-02T15:04:05.999999999Z"
+
+	// dateTimeNoZoneLayout parses date-times missing their zone, as UTC.
+	dateTimeNoZoneLayout = "2006-01-02T15:04:05.999999999"
+
+	// httpDateFormat is the IMF-fixdate format of RFC 7231 section 7.1.1.1.
+	httpDateFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+)
+
+// httpDateLayouts are the formats accepted when parsing HTTP dates. In
+// addition to IMF-fixdate, services emit single digit days, fractional
+// seconds, and the obsolete RFC 850 and ANSI C formats.
+var httpDateLayouts = []string{
+	"Mon, 2 Jan 2006 15:04:05 GMT",
+	"Mon, 2 Jan 2006 15:04:05.999999999 GMT",
+	"Mon, 2 Jan 2006 15:04:05 UTC",
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Monday, 02-Jan-06 15:04:05 GMT",
+	"Mon Jan _2 15:04:05 2006",
+}
+
+// FormatDateTime formats the time as an RFC 3339 date-time in UTC, with
+// fractional seconds only when the time has them, (e.g.
+// 1985-04-12T23:20:50.52Z).
+func FormatDateTime(value time.Time) string {
+	return value.UTC().Format(dateTimeFormat)
+}
+
+// ParseDateTime parses an RFC 3339 date-time, with or without fractional
+// seconds, and with either a Z or numeric offset. Lowercase t and z
+// separators are accepted, and date-times without a zone, (e.g. missing the
+// trailing Z), are parsed as UTC. The time is returned in UTC.
+func ParseDateTime(value string) (time.Time, error) {
+	upper := strings.ToUpper(value)
+	t, err := time.Parse(time.RFC3339Nano, upper)
+	if err != nil {
+		var zoneErr error
+		if t, zoneErr = time.Parse(dateTimeNoZoneLayout, upper); zoneErr != nil {
+			return time.Time{}, fmt.Errorf("failed to parse date-time %q, %w", value, err)
+		}
+	}
+	return t.UTC(), nil
+}
+
+// FormatHTTPDate formats the time as an RFC 7231 IMF-fixdate, (e.g. Tue, 29
+// Apr 2014 18:30:38 GMT).
+func FormatHTTPDate(value time.Time) string {
+	return value.UTC().Format(httpDateFormat)
+}
+
+// ParseHTTPDate parses an RFC 7231 HTTP date. IMF-fixdate, with or without
+// a leading zero day and fractional seconds, and the obsolete RFC 850 and
+// ANSI C formats are accepted. The time is returned in UTC.
+func ParseHTTPDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range httpDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse http-date %q", value)
+}
+
+// FormatEpochSeconds returns the time as the number of seconds since the
+// Unix epoch, with the fractional part rounded to microseconds.
+func FormatEpochSeconds(value time.Time) float64 {
+	us := value.Round(time.Microsecond).UnixNano() / int64(time.Microsecond)
+	return float64(us) / 1e6
+}
+
+// ParseEpochSeconds returns the time for the number of seconds since the
+// Unix epoch. Fractional seconds are rounded to microseconds, the precision
+// a float64 retains for present day timestamps. The time is returned in
+// UTC.
+func ParseEpochSeconds(value float64) time.Time {
+	sec, frac := math.Modf(value)
+	usec := math.Round(frac * 1e6)
+	return time.Unix(int64(sec), int64(usec)*int64(time.Microsecond)).UTC()
+}
@@ -0,0 +1,271 @@
+package smithytime
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateTime(t *testing.T) {
+	cases := map[string]struct {
+		Value     string
+		Expect    time.Time
+		Format    string
+		ExpectErr string
+	}{
+		"no fractional seconds": {
+			Value:  "1985-04-12T23:20:50Z",
+			Expect: time.Date(1985, 4, 12, 23, 20, 50, 0, time.UTC),
+			Format: "1985-04-12T23:20:50Z",
+		},
+		"milliseconds": {
+			Value:  "1985-04-12T23:20:50.520Z",
+			Expect: time.Date(1985, 4, 12, 23, 20, 50, 520e6, time.UTC),
+			Format: "1985-04-12T23:20:50.52Z",
+		},
+		"nanoseconds": {
+			Value:  "2020-06-01T12:00:00.123456789Z",
+			Expect: time.Date(2020, 6, 1, 12, 0, 0, 123456789, time.UTC),
+			Format: "2020-06-01T12:00:00.123456789Z",
+		},
+		"offset": {
+			Value:  "1996-12-19T16:39:57-08:00",
+			Expect: time.Date(1996, 12, 20, 0, 39, 57, 0, time.UTC),
+			Format: "1996-12-20T00:39:57Z",
+		},
+		"lowercase separators": {
+			Value:  "2019-12-16t23:48:18.5z",
+			Expect: time.Date(2019, 12, 16, 23, 48, 18, 5e8, time.UTC),
+			Format: "2019-12-16T23:48:18.5Z",
+		},
+		"missing zone": {
+			Value:  "2019-12-16T23:48:18",
+			Expect: time.Date(2019, 12, 16, 23, 48, 18, 0, time.UTC),
+			Format: "2019-12-16T23:48:18Z",
+		},
+		"missing zone fractional": {
+			Value:  "2019-12-16T23:48:18.25",
+			Expect: time.Date(2019, 12, 16, 23, 48, 18, 25e7, time.UTC),
+			Format: "2019-12-16T23:48:18.25Z",
+		},
+		"not a date-time": {
+			Value:     "2019-12-16 23:48",
+			ExpectErr: "failed to parse date-time",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := ParseDateTime(c.Value)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect %q error, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !e.Equal(a) || a.Location() != time.UTC {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+			if e, a := c.Format, FormatDateTime(v); e != a {
+				t.Errorf("expect %v formatted, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestHTTPDate(t *testing.T) {
+	expect := time.Date(2014, 4, 29, 18, 30, 38, 0, time.UTC)
+
+	cases := map[string]struct {
+		Value     string
+		Expect    time.Time
+		ExpectErr string
+	}{
+		"IMF-fixdate": {
+			Value:  "Tue, 29 Apr 2014 18:30:38 GMT",
+			Expect: expect,
+		},
+		"single digit day": {
+			Value:  "Sat, 5 Apr 2014 18:30:38 GMT",
+			Expect: time.Date(2014, 4, 5, 18, 30, 38, 0, time.UTC),
+		},
+		"fractional seconds": {
+			Value:  "Tue, 29 Apr 2014 18:30:38.123 GMT",
+			Expect: time.Date(2014, 4, 29, 18, 30, 38, 123e6, time.UTC),
+		},
+		"UTC zone": {
+			Value:  "Tue, 29 Apr 2014 18:30:38 UTC",
+			Expect: expect,
+		},
+		"numeric offset": {
+			Value:  "Tue, 29 Apr 2014 20:30:38 +0200",
+			Expect: expect,
+		},
+		"RFC 850": {
+			Value:  "Tuesday, 29-Apr-14 18:30:38 GMT",
+			Expect: expect,
+		},
+		"ANSI C": {
+			Value:  "Tue Apr 29 18:30:38 2014",
+			Expect: expect,
+		},
+		"surrounding whitespace": {
+			Value:  " Tue, 29 Apr 2014 18:30:38 GMT ",
+			Expect: expect,
+		},
+		"invalid": {
+			Value:     "2014-04-29T18:30:38Z",
+			ExpectErr: "failed to parse http-date",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := ParseHTTPDate(c.Value)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect %q error, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, v; !e.Equal(a) || a.Location() != time.UTC {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+		})
+	}
+
+	if e, a := "Tue, 29 Apr 2014 18:30:38 GMT", FormatHTTPDate(expect.In(time.FixedZone("x", 3600))); e != a {
+		t.Errorf("expect %v formatted, got %v", e, a)
+	}
+}
+
+func TestEpochSeconds(t *testing.T) {
+	cases := map[string]struct {
+		Value  float64
+		Expect time.Time
+	}{
+		"whole seconds": {
+			Value:  1515531081,
+			Expect: time.Date(2018, 1, 9, 20, 51, 21, 0, time.UTC),
+		},
+		"milliseconds": {
+			Value:  1515531081.123,
+			Expect: time.Date(2018, 1, 9, 20, 51, 21, 123e6, time.UTC),
+		},
+		"microseconds": {
+			Value:  1515531081.123456,
+			Expect: time.Date(2018, 1, 9, 20, 51, 21, 123456e3, time.UTC),
+		},
+		"before epoch": {
+			Value:  -1.5,
+			Expect: time.Date(1969, 12, 31, 23, 59, 58, 5e8, time.UTC),
+		},
+		"epoch": {
+			Value:  0,
+			Expect: time.Unix(0, 0).UTC(),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := ParseEpochSeconds(c.Value)
+			if e, a := c.Expect, v; !e.Equal(a) {
+				t.Errorf("expect %v, got %v", e, a)
+			}
+			if e, a := c.Value, FormatEpochSeconds(v); e != a {
+				t.Errorf("expect %v formatted, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	expect := time.Date(2014, 4, 29, 18, 30, 38, 0, time.UTC)
+
+	cases := map[string]struct {
+		Value     string
+		Preferred Format
+		Expect    time.Time
+		ExpectErr string
+	}{
+		"preferred date-time": {
+			Value:     "2014-04-29T18:30:38Z",
+			Preferred: DateTime,
+			Expect:    expect,
+		},
+		"epoch when date-time preferred": {
+			Value:     "1398796238",
+			Preferred: DateTime,
+			Expect:    expect,
+		},
+		"fractional epoch when date-time preferred": {
+			Value:     "1398796238.5",
+			Preferred: DateTime,
+			Expect:    expect.Add(500 * time.Millisecond),
+		},
+		"http-date when epoch preferred": {
+			Value:     "Tue, 29 Apr 2014 18:30:38 GMT",
+			Preferred: EpochSeconds,
+			Expect:    expect,
+		},
+		"date-time when http-date preferred": {
+			Value:     "2014-04-29T18:30:38Z",
+			Preferred: HTTPDate,
+			Expect:    expect,
+		},
+		"no format matches": {
+			Value:     "yesterday",
+			Preferred: DateTime,
+			ExpectErr: "not in date-time or fallback formats",
+		},
+		"NaN epoch": {
+			Value:     "NaN",
+			Preferred: DateTime,
+			ExpectErr: "not in date-time or fallback formats",
+		},
+		"Inf epoch": {
+			Value:     "Inf",
+			Preferred: EpochSeconds,
+			ExpectErr: "not in epoch-seconds or fallback formats",
+		},
+		"negative Inf epoch": {
+			Value:     "-Inf",
+			Preferred: EpochSeconds,
+			ExpectErr: "not in epoch-seconds or fallback formats",
+		},
+		"epoch out of range": {
+			Value:     "1e300",
+			Preferred: EpochSeconds,
+			ExpectErr: "not in epoch-seconds or fallback formats",
+		},
+		"unknown preferred format": {
+			Value:     "1398796238",
+			Preferred: Format("unix"),
+			ExpectErr: "unknown timestamp format",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := ParseTimestamp(c.Value, c.Preferred)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect error containing %q, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if !c.Expect.Equal(v) {
+				t.Errorf("expect %v, got %v", c.Expect, v)
+			}
+		})
+	}
+}
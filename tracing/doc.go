@@ -0,0 +1,17 @@
+/*
+Package tracing provides the interfaces for creating distributed tracing spans
+of operation calls, without depending on a specific tracing library.
+
+A TracerProvider returns the Tracer for an instrumentation scope, which
+starts Spans. Implementations adapt the interfaces to a tracing library,
+(e.g. OpenTelemetry). The span context of the attempt's span is propagated
+into the request's headers by a pluggable Propagator.
+
+AddMiddleware adds the middleware that starts a span for each operation call,
+and a child span for each of the call's attempts, to an operation's stack.
+
+The XRayTraceHeader middleware propagates AWS-style trace context in the
+X-Amzn-Trace-Id header, continuing the trace of the context or the request,
+or starting a new trace with a generated root.
+*/
+package tracing
@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// Scope is the instrumentation scope of the tracer the middleware's spans
+// are started with.
+const Scope = "github.com/awslabs/smithy-go"
+
+// AddMiddleware adds the OperationSpan middleware to the front of the
+// stack's initialize step, and the AttemptSpan middleware to the end of the
+// finalize step, so each attempt's span is a child of the operation's span.
+// If the provider is nil, no middleware is added. The propagator may be nil
+// if span context should not be propagated.
+func AddMiddleware(stack *middleware.Stack, provider TracerProvider, propagator Propagator) error {
+	if provider == nil {
+		return nil
+	}
+	tracer := provider.Tracer(Scope)
+
+	if err := stack.Initialize.Add(&OperationSpan{Tracer: tracer}, middleware.Before); err != nil {
+		return err
+	}
+	return stack.Finalize.Add(&AttemptSpan{Tracer: tracer, Propagator: propagator}, middleware.After)
+}
+
+// OperationSpan is an initialize middleware that starts a client span for
+// the operation's call, named after the operation, see
+// middleware.WithOperationName. The span's status is set to error if the
+// call fails.
+type OperationSpan struct {
+	Tracer Tracer
+}
+
+// ID returns the middleware identifier.
+func (m *OperationSpan) ID() string {
+	return "OperationSpan"
+}
+
+// HandleInitialize starts the operation's span, ending it once the call has
+// completed.
+func (m *OperationSpan) HandleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+	out middleware.InitializeOutput, metadata middleware.Metadata, err error,
+) {
+	name := middleware.GetOperationName(ctx)
+	if len(name) == 0 {
+		name = "Operation"
+	}
+
+	ctx, span := m.Tracer.StartSpan(ctx, name, func(o *SpanOptions) {
+		o.Kind = SpanKindClient
+	})
+	defer span.End()
+
+	out, metadata, err = next.HandleInitialize(ctx, in)
+	setSpanStatus(span, err)
+
+	return out, metadata, err
+}
+
+// AttemptSpan is a finalize middleware that starts a span for each attempt
+// of the operation's call. The middleware must be added after the Retry
+// middleware so each attempt has its own span.
+//
+// If a Propagator is set, the attempt span's context is injected into the
+// request's headers.
+type AttemptSpan struct {
+	Tracer     Tracer
+	Propagator Propagator
+}
+
+// ID returns the middleware identifier.
+func (m *AttemptSpan) ID() string {
+	return "AttemptSpan"
+}
+
+// HandleFinalize starts the attempt's span, ending it once the attempt has
+// completed.
+func (m *AttemptSpan) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	ctx, span := m.Tracer.StartSpan(ctx, "Attempt")
+	defer span.End()
+
+	if req, ok := in.Request.(*smithyhttp.Request); ok && m.Propagator != nil {
+		m.Propagator.Inject(ctx, req.Header)
+	}
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+
+	if code, ok := middleware.GetResponseStatusCodeMetadata(metadata); ok {
+		span.SetProperty("http.response.status_code", code)
+	}
+	setSpanStatus(span, err)
+
+	return out, metadata, err
+}
+
+func setSpanStatus(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(SpanStatusError)
+		return
+	}
+	span.SetStatus(SpanStatusOK)
+}
@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+	"github.com/awslabs/smithy-go/retry"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Tracer(string) Tracer { return t }
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string, optFns ...func(*SpanOptions)) (
+	context.Context, Span,
+) {
+	var options SpanOptions
+	for _, fn := range optFns {
+		fn(&options)
+	}
+
+	span := &recordingSpan{
+		name:       name,
+		kind:       options.Kind,
+		id:         fmt.Sprintf("span-%d", len(t.spans)+1),
+		properties: map[string]interface{}{},
+	}
+	if parent, ok := GetSpan(ctx); ok {
+		span.parent = parent.Context().SpanID
+	}
+	t.spans = append(t.spans, span)
+	return WithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	name       string
+	kind       SpanKind
+	id         string
+	parent     string
+	properties map[string]interface{}
+	status     SpanStatus
+	errs       []error
+	ended      bool
+}
+
+func (s *recordingSpan) Name() string { return s.name }
+func (s *recordingSpan) Context() SpanContext {
+	return SpanContext{TraceID: "trace", SpanID: s.id, Sampled: true}
+}
+func (s *recordingSpan) SetProperty(k string, v interface{}) { s.properties[k] = v }
+func (s *recordingSpan) SetStatus(status SpanStatus)         { s.status = status }
+func (s *recordingSpan) RecordError(err error)               { s.errs = append(s.errs, err) }
+func (s *recordingSpan) End()                                { s.ended = true }
+
+type headerPropagator struct{}
+
+func (headerPropagator) Inject(ctx context.Context, carrier Carrier) {
+	span, _ := GetSpan(ctx)
+	carrier.Set("Traceparent", span.Context().TraceID+"-"+span.Context().SpanID)
+}
+
+func TestAddMiddleware(t *testing.T) {
+	retryable := &smithy.GenericAPIError{Code: "Unavailable", Retryable: true}
+
+	stack := middleware.NewStack("tracing", smithyhttp.NewStackRequest)
+	stack.Finalize.Add(retry.NewAttemptMiddleware(retry.NewStandard(func(o *retry.StandardOptions) {
+		o.MaxAttempts = 3
+		o.MaxBackoff = time.Nanosecond
+	})), middleware.After)
+
+	tracer := &recordingTracer{}
+	if err := AddMiddleware(stack, tracer, headerPropagator{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var traceparents []string
+	statusCodes := []int{503, 200}
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			traceparents = append(traceparents, in.(*smithyhttp.Request).Header.Get("Traceparent"))
+
+			var metadata middleware.Metadata
+			code := statusCodes[0]
+			statusCodes = statusCodes[1:]
+			middleware.SetResponseStatusCodeMetadata(&metadata, code)
+			if code != 200 {
+				return nil, metadata, retryable
+			}
+			return &smithyhttp.Response{Response: &http.Response{StatusCode: code}}, metadata, nil
+		}), stack)
+
+	ctx := middleware.WithOperationName(context.Background(), "GetItem")
+	if _, _, err := handler.Handle(ctx, struct{}{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := 3, len(tracer.spans); e != a {
+		t.Fatalf("expect %v spans, got %v", e, a)
+	}
+	operation, first, second := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+
+	if e, a := "GetItem", operation.name; e != a {
+		t.Errorf("expect %v operation span, got %v", e, a)
+	}
+	if e, a := SpanKindClient, operation.kind; e != a {
+		t.Errorf("expect %v span kind, got %v", e, a)
+	}
+	if e, a := SpanStatusOK, operation.status; e != a {
+		t.Errorf("expect %v operation status, got %v", e, a)
+	}
+
+	for _, attempt := range []*recordingSpan{first, second} {
+		if e, a := operation.id, attempt.parent; e != a {
+			t.Errorf("expect attempt span child of %v, got %v", e, a)
+		}
+		if !attempt.ended {
+			t.Errorf("expect attempt span ended")
+		}
+	}
+	if e, a := SpanStatusError, first.status; e != a {
+		t.Errorf("expect %v first attempt status, got %v", e, a)
+	}
+	if e, a := []error{retryable}, first.errs; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v recorded, got %v", e, a)
+	}
+	if e, a := 503, first.properties["http.response.status_code"]; e != a {
+		t.Errorf("expect %v status code property, got %v", e, a)
+	}
+	if e, a := SpanStatusOK, second.status; e != a {
+		t.Errorf("expect %v second attempt status, got %v", e, a)
+	}
+	if !operation.ended {
+		t.Errorf("expect operation span ended")
+	}
+
+	if e, a := []string{"trace-" + first.id, "trace-" + second.id}, traceparents; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v propagated, got %v", e, a)
+	}
+}
@@ -0,0 +1,120 @@
+package tracing
+
+import "context"
+
+// SpanStatus is the status of a span's operation.
+type SpanStatus int
+
+// Enumeration of span statuses.
+const (
+	SpanStatusUnset SpanStatus = iota
+	SpanStatusOK
+	SpanStatusError
+)
+
+// SpanKind is the kind of a span's operation.
+type SpanKind int
+
+// Enumeration of span kinds.
+const (
+	SpanKindInternal SpanKind = iota
+	SpanKindClient
+)
+
+// SpanContext identifies a span, and the trace the span is part of.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+
+	// If the trace is sampled, and should be recorded.
+	Sampled bool
+}
+
+// SpanOptions are the options of a span started by a Tracer.
+type SpanOptions struct {
+	Kind SpanKind
+
+	// Properties the span is started with.
+	Properties map[string]interface{}
+}
+
+// TracerProvider provides the Tracers of instrumentation scopes.
+type TracerProvider interface {
+	Tracer(scope string) Tracer
+}
+
+// Tracer starts spans. The span is started as a child of the span of the
+// context, see GetSpan, and the context returned includes the started span.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, optFns ...func(*SpanOptions)) (context.Context, Span)
+}
+
+// Span is a traced operation.
+type Span interface {
+	Name() string
+	Context() SpanContext
+
+	// SetProperty sets a property describing the span, (e.g. the status
+	// code of an attempt's response).
+	SetProperty(key string, value interface{})
+
+	// SetStatus sets the status of the span's operation.
+	SetStatus(status SpanStatus)
+
+	// RecordError records that the span's operation failed with the error.
+	RecordError(err error)
+
+	// End ends the span.
+	End()
+}
+
+// Carrier is the carrier of span context propagated to a remote service,
+// (e.g. an http.Header).
+type Carrier interface {
+	Set(key, value string)
+}
+
+// Propagator injects the span context of the context's span into a carrier,
+// (e.g. as W3C traceparent headers).
+type Propagator interface {
+	Inject(ctx context.Context, carrier Carrier)
+}
+
+type spanKey struct{}
+
+// WithSpan returns a context with the span set as the current span. Tracers
+// should set started spans on the returned context with WithSpan.
+func WithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// GetSpan returns the current span of the context, and if one was set.
+func GetSpan(ctx context.Context) (Span, bool) {
+	v, ok := ctx.Value(spanKey{}).(Span)
+	return v, ok
+}
+
+// NopTracerProvider is a TracerProvider whose spans are not recorded.
+type NopTracerProvider struct{}
+
+// Tracer returns a Tracer whose spans are not recorded.
+func (NopTracerProvider) Tracer(string) Tracer { return nopTracer{} }
+
+type nopTracer struct{}
+
+func (nopTracer) StartSpan(ctx context.Context, name string, optFns ...func(*SpanOptions)) (
+	context.Context, Span,
+) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) Name() string                    { return "" }
+func (nopSpan) Context() SpanContext            { return SpanContext{} }
+func (nopSpan) SetProperty(string, interface{}) {}
+func (nopSpan) SetStatus(SpanStatus)            {}
+func (nopSpan) RecordError(error)               {}
+func (nopSpan) End()                            {}
+
+var _ TracerProvider = NopTracerProvider{}
@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+	"github.com/awslabs/smithy-go/rand"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+// XRayTraceIDHeader is the header AWS-style trace context is propagated
+// with.
+const XRayTraceIDHeader = "X-Amzn-Trace-Id"
+
+// XRayTrace is the AWS-style trace context of a request, propagated in the
+// X-Amzn-Trace-Id header as Root=...;Parent=...;Sampled=....
+type XRayTrace struct {
+	// ID of the trace, (e.g. 1-5759e988-bd862e3fe1be46a994272793).
+	Root string
+
+	// ID of the segment the request is made from. Optional.
+	Parent string
+
+	// If the trace is sampled, and should be recorded.
+	Sampled bool
+}
+
+// String returns the trace formatted as the X-Amzn-Trace-Id header value.
+// The parent is omitted if not set.
+func (t XRayTrace) String() string {
+	var b strings.Builder
+	b.WriteString("Root=" + t.Root)
+	if len(t.Parent) != 0 {
+		b.WriteString(";Parent=" + t.Parent)
+	}
+	if t.Sampled {
+		b.WriteString(";Sampled=1")
+	} else {
+		b.WriteString(";Sampled=0")
+	}
+	return b.String()
+}
+
+// ParseXRayTrace parses the X-Amzn-Trace-Id header value. Fields other than
+// Root, Parent, and Sampled are ignored. Returns an error if the value does
+// not have a Root.
+func ParseXRayTrace(v string) (XRayTrace, error) {
+	var t XRayTrace
+	for _, field := range strings.Split(v, ";") {
+		i := strings.IndexByte(field, '=')
+		if i == -1 {
+			continue
+		}
+		key, value := strings.TrimSpace(field[:i]), strings.TrimSpace(field[i+1:])
+		switch key {
+		case "Root":
+			t.Root = value
+		case "Parent":
+			t.Parent = value
+		case "Sampled":
+			t.Sampled = value == "1"
+		}
+	}
+	if len(t.Root) == 0 {
+		return XRayTrace{}, fmt.Errorf("trace header %q has no Root", v)
+	}
+	return t, nil
+}
+
+type xrayTraceKey struct{}
+
+// WithXRayTrace returns a context with the trace requests made with the
+// context continue, (e.g. the trace of the inbound request being served).
+func WithXRayTrace(ctx context.Context, t XRayTrace) context.Context {
+	return context.WithValue(ctx, xrayTraceKey{}, t)
+}
+
+// GetXRayTrace returns the trace set on the context, and if one was set.
+func GetXRayTrace(ctx context.Context) (XRayTrace, bool) {
+	v, ok := ctx.Value(xrayTraceKey{}).(XRayTrace)
+	return v, ok
+}
+
+// XRayTraceHeader is a build middleware that sets the X-Amzn-Trace-Id header
+// of the request, continuing the trace of the context, see WithXRayTrace.
+// Without a trace on the context, the trace of the request's existing
+// header, (e.g. set by the caller), is continued.
+//
+// If neither have a trace, and GenerateRoot is set, the request starts a new
+// trace with a generated root, otherwise the header is not set.
+type XRayTraceHeader struct {
+	// Starts a new trace for requests without a trace to continue.
+	GenerateRoot bool
+
+	// If generated traces are sampled.
+	Sampled bool
+
+	// Random source of generated trace IDs. Defaults to rand.Reader.
+	Rand io.Reader
+}
+
+// ID returns the middleware identifier.
+func (m *XRayTraceHeader) ID() string {
+	return "XRayTraceHeader"
+}
+
+// HandleBuild sets the request's trace header.
+func (m *XRayTraceHeader) HandleBuild(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*smithyhttp.Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	trace, ok := GetXRayTrace(ctx)
+	if !ok {
+		if v := req.Header.Get(XRayTraceIDHeader); len(v) != 0 {
+			trace, err = ParseXRayTrace(v)
+			ok = err == nil
+		}
+	}
+	if !ok && m.GenerateRoot {
+		root, err := m.newRoot(ctx)
+		if err != nil {
+			return out, metadata, err
+		}
+		trace, ok = XRayTrace{Root: root, Sampled: m.Sampled}, true
+	}
+	if ok {
+		req.Header.Set(XRayTraceIDHeader, trace.String())
+	}
+
+	return next.HandleBuild(ctx, in)
+}
+
+// newRoot returns a trace ID of the version, the hex encoded epoch seconds of
+// the current time, and 96 random bits, (e.g.
+// 1-5759e988-bd862e3fe1be46a994272793).
+func (m *XRayTraceHeader) newRoot(ctx context.Context) (string, error) {
+	r := m.Rand
+	if r == nil {
+		r = rand.Reader
+	}
+
+	var id [12]byte
+	if _, err := io.ReadFull(r, id[:]); err != nil {
+		return "", fmt.Errorf("failed to generate trace ID, %w", err)
+	}
+
+	epoch := strconv.FormatInt(middleware.GetClock(ctx).Now().Unix(), 16)
+	return "1-" + fmt.Sprintf("%08s", epoch) + "-" + hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+	smithyhttp "github.com/awslabs/smithy-go/transport/http"
+)
+
+type buildHandlerFunc func(context.Context, middleware.BuildInput) (
+	middleware.BuildOutput, middleware.Metadata, error,
+)
+
+func (fn buildHandlerFunc) HandleBuild(ctx context.Context, in middleware.BuildInput) (
+	middleware.BuildOutput, middleware.Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+func TestXRayTraceHeader(t *testing.T) {
+	now := time.Unix(0x5759e988, 0)
+	random := bytes.Repeat([]byte{0xab}, 12)
+
+	cases := map[string]struct {
+		Middleware XRayTraceHeader
+		Trace      *XRayTrace
+		Header     string
+		Expect     string
+	}{
+		"continue context trace": {
+			Trace: &XRayTrace{
+				Root:    "1-5759e988-bd862e3fe1be46a994272793",
+				Parent:  "53995c3f42cd8ad8",
+				Sampled: true,
+			},
+			Expect: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		"context trace over header": {
+			Trace:  &XRayTrace{Root: "1-5759e988-bd862e3fe1be46a994272793"},
+			Header: "Root=1-00000000-000000000000000000000000;Sampled=1",
+			Expect: "Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=0",
+		},
+		"continue inbound header": {
+			Header: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1;Lineage=a87bd80c:0",
+			Expect: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		"generate root": {
+			Middleware: XRayTraceHeader{
+				GenerateRoot: true,
+				Sampled:      true,
+				Rand:         bytes.NewReader(random),
+			},
+			Expect: "Root=1-5759e988-abababababababababababab;Sampled=1",
+		},
+		"no trace": {},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time { return now }))
+			if c.Trace != nil {
+				ctx = WithXRayTrace(ctx, *c.Trace)
+			}
+
+			req := smithyhttp.NewStackRequest().(*smithyhttp.Request)
+			if len(c.Header) != 0 {
+				req.Header.Set(XRayTraceIDHeader, c.Header)
+			}
+
+			var actual string
+			_, _, err := c.Middleware.HandleBuild(ctx, middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					middleware.BuildOutput, middleware.Metadata, error,
+				) {
+					actual = in.Request.(*smithyhttp.Request).Header.Get(XRayTraceIDHeader)
+					return middleware.BuildOutput{}, middleware.Metadata{}, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, actual; e != a {
+				t.Errorf("expect %q trace header, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestXRayTraceHeader_GeneratedRootsUnique(t *testing.T) {
+	m := XRayTraceHeader{GenerateRoot: true}
+	roots := map[string]struct{}{}
+	for i := 0; i < 10; i++ {
+		root, err := m.newRoot(context.Background())
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := 35, len(root); e != a {
+			t.Errorf("expect %v length root, got %v, %v", e, a, root)
+		}
+		roots[root] = struct{}{}
+	}
+	if e, a := 10, len(roots); e != a {
+		t.Errorf("expect %v unique roots, got %v", e, a)
+	}
+}
+
+func TestParseXRayTrace_NoRoot(t *testing.T) {
+	if _, err := ParseXRayTrace("Parent=53995c3f42cd8ad8;Sampled=1"); err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
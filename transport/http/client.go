@@ -0,0 +1,558 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// ClientDo provides the interface for custom HTTP client implementations.
+type ClientDo interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// ClientDoFunc provides a helper to wrap a function as an HTTP client for
+// round tripping requests.
+type ClientDoFunc func(*http.Request) (*http.Response, error)
+
+// Do will invoke the underlying func, returning the result.
+func (fn ClientDoFunc) Do(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+// ClientHandler wraps a client that implements the HTTP Do method into a
+// middleware Handler, sending the stack's request and returning the response.
+type ClientHandler struct {
+	client ClientDo
+}
+
+// NewClientHandler returns an initialized middleware handler for the client.
+func NewClientHandler(client ClientDo) ClientHandler {
+	return ClientHandler{
+		client: client,
+	}
+}
+
+// Handle implements the middleware Handler interface, that will invoke the
+// underlying HTTP client. Requires the input to be a Smithy *Request. Returns
+// a Smithy *Response, or error if the request failed.
+//
+// If the operation is a dry run, see WithDryRun, the request is not sent and
+// a *DryRunError is returned with the built request.
+func (c ClientHandler) Handle(ctx context.Context, input interface{}) (
+	out interface{}, metadata middleware.Metadata, err error,
+) {
+	req, ok := input.(*Request)
+	if !ok {
+		return nil, metadata, fmt.Errorf("expect Smithy http.Request value as input, got unsupported type %T", input)
+	}
+
+	if IsDryRun(ctx) {
+		built := req.Build(ctx)
+		SetDryRunRequest(&metadata, built)
+		return nil, metadata, &DryRunError{Request: built}
+	}
+
+	resp, err := c.client.Do(req.Build(ctx))
+	if err != nil {
+		return nil, metadata, &RequestSendError{Err: err}
+	}
+
+	middleware.SetResponseStatusCodeMetadata(&metadata, resp.StatusCode)
+
+	return &Response{Response: resp}, metadata, nil
+}
+
+// RequestSendError provides the error type for when the HTTP client failed
+// to send the request, (e.g. connection or timeout failures).
+type RequestSendError struct {
+	Err error
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestSendError) Unwrap() error { return e.Err }
+
+func (e *RequestSendError) Error() string {
+	return fmt.Sprintf("request send failed, %v", e.Err)
+}
+
+// ResponseHeaderTooLargeError is returned by the BuildableClient when the
+// response's headers exceed the client's limit, see
+// WithMaxResponseHeaderBytes.
+type ResponseHeaderTooLargeError struct {
+	// The client's limit, or zero if net/http's default limit was used.
+	Limit int64
+	Err   error
+}
+
+// Unwrap returns the underlying error.
+func (e *ResponseHeaderTooLargeError) Unwrap() error { return e.Err }
+
+func (e *ResponseHeaderTooLargeError) Error() string {
+	if e.Limit == 0 {
+		return fmt.Sprintf("response headers exceed the default limit, %v", e.Err)
+	}
+	return fmt.Sprintf("response headers exceed the %d byte limit, %v", e.Limit, e.Err)
+}
+
+// isResponseHeaderTooLarge returns if the error is net/http's error for
+// response headers exceeding the transport's limit, which is not exported.
+func isResponseHeaderTooLarge(err error) bool {
+	return strings.Contains(err.Error(), "server response headers exceeded")
+}
+
+// Defaults for the BuildableClient's HTTP transport and dialer.
+const (
+	DefaultDialConnectTimeout    = 30 * time.Second
+	DefaultDialKeepAliveTimeout  = 30 * time.Second
+	DefaultIdleConnTimeout       = 90 * time.Second
+	DefaultMaxIdleConns          = 100
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultExpectContinueTimeout = 1 * time.Second
+
+	// DefaultMaxIdleConnsPerHost is the maximum number of idle connections
+	// kept for each host, raised from net/http's default of 2 so that
+	// concurrent requests to a service's endpoint reuse their connections.
+	DefaultMaxIdleConnsPerHost = 10
+
+	// DefaultMaxConnsPerHost is the maximum number of connections opened to
+	// each host. Zero means no limit.
+	DefaultMaxConnsPerHost = 0
+)
+
+// BuildableClient provides an HTTP client that can be configured with
+// options, and then used to round trip requests. Each With method returns a
+// copy of the client with the option applied, leaving the original client
+// unmodified. The underlying http.Client is built the first time a request
+// is sent.
+//
+// If a custom http.Client is provided with WithHTTPClient, requests are sent
+// with it, and the transport, dialer, timeout, redirect policy, minimum TLS
+// version, and HTTP/2 prior knowledge options are not applied.
+type BuildableClient struct {
+	transport *http.Transport
+	dialer    *net.Dialer
+
+	dialContext     DialContextFunc
+	addressResolver AddressResolver
+
+	redirectPolicy *RedirectPolicy
+	minTLSVersion  uint16
+
+	clientTimeout       time.Duration
+	http2PriorKnowledge bool
+	httpClient          *http.Client
+
+	initOnce sync.Once
+	client   ClientDo
+	buildErr error
+
+	// limit of the built transport's response headers, reported by
+	// ResponseHeaderTooLargeError.
+	maxResponseHeaderBytes int64
+}
+
+// NewBuildableClient returns an initialized client for sending HTTP requests
+// with the default transport and dialer options.
+func NewBuildableClient() *BuildableClient {
+	return &BuildableClient{}
+}
+
+// Do sends the request, returning the response or error.
+func (b *BuildableClient) Do(req *http.Request) (*http.Response, error) {
+	b.initOnce.Do(b.build)
+	if b.buildErr != nil {
+		return nil, b.buildErr
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil && isResponseHeaderTooLarge(err) {
+		return nil, &ResponseHeaderTooLargeError{Limit: b.maxResponseHeaderBytes, Err: err}
+	}
+	return resp, err
+}
+
+func (b *BuildableClient) build() {
+	if b.httpClient != nil {
+		b.client = b.httpClient
+		return
+	}
+
+	transport := b.GetTransport()
+	if b.minTLSVersion != 0 {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.MinVersion = b.minTLSVersion
+	}
+	if b.dialContext != nil {
+		transport.DialContext = b.dialContext
+	}
+	if b.addressResolver != nil {
+		transport.DialContext = resolvingDialContext(b.addressResolver, transport.DialContext)
+	}
+	b.maxResponseHeaderBytes = transport.MaxResponseHeaderBytes
+	if b.http2PriorKnowledge {
+		if err := enableHTTP2PriorKnowledge(transport); err != nil {
+			b.buildErr = err
+			return
+		}
+	}
+
+	b.client = &http.Client{
+		Timeout:       b.clientTimeout,
+		Transport:     transport,
+		CheckRedirect: b.checkRedirect,
+	}
+}
+
+// checkRedirect returns the redirect response without following it if the
+// request's context disables following redirects, otherwise applies the
+// client's redirect policy, or http.Client's default policy if none is set.
+func (b *BuildableClient) checkRedirect(req *http.Request, via []*http.Request) error {
+	if IsNoFollowRedirects(req.Context()) {
+		return http.ErrUseLastResponse
+	}
+	if b.redirectPolicy != nil {
+		return b.redirectPolicy.checkRedirect(req, via)
+	}
+	if len(via) >= defaultMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", defaultMaxRedirects)
+	}
+	return nil
+}
+
+func (b *BuildableClient) clone() *BuildableClient {
+	cpy := NewBuildableClient()
+	cpy.transport = b.GetTransport()
+	cpy.dialer = b.GetDialer()
+	cpy.dialContext = b.dialContext
+	cpy.addressResolver = b.addressResolver
+	cpy.redirectPolicy = b.redirectPolicy
+	cpy.minTLSVersion = b.minTLSVersion
+	cpy.clientTimeout = b.clientTimeout
+	cpy.http2PriorKnowledge = b.http2PriorKnowledge
+	cpy.httpClient = b.httpClient
+
+	return cpy
+}
+
+// WithTransportOptions returns a copy of the client with the options applied
+// to its http.Transport.
+func (b *BuildableClient) WithTransportOptions(opts ...func(*http.Transport)) *BuildableClient {
+	cpy := b.clone()
+
+	tr := cpy.GetTransport()
+	for _, opt := range opts {
+		opt(tr)
+	}
+	cpy.transport = tr
+
+	return cpy
+}
+
+// WithDialerOptions returns a copy of the client with the options applied to
+// the net.Dialer used by its transport.
+func (b *BuildableClient) WithDialerOptions(opts ...func(*net.Dialer)) *BuildableClient {
+	cpy := b.clone()
+
+	dialer := cpy.GetDialer()
+	for _, opt := range opts {
+		opt(dialer)
+	}
+	cpy.dialer = dialer
+
+	tr := cpy.GetTransport()
+	tr.DialContext = cpy.dialer.DialContext
+	cpy.transport = tr
+
+	return cpy
+}
+
+// WithDialContext returns a copy of the client that opens connections with
+// the dial function instead of the net.Dialer configured by
+// WithDialerOptions, (e.g. to connect through a custom network).
+func (b *BuildableClient) WithDialContext(fn DialContextFunc) *BuildableClient {
+	cpy := b.clone()
+	cpy.dialContext = fn
+	return cpy
+}
+
+// WithAddressResolver returns a copy of the client that resolves the address
+// of each connection with the resolver before dialing it, (e.g. to pin an
+// endpoint's hostname to a specific IP address). The request's URL and TLS
+// server name are not modified.
+func (b *BuildableClient) WithAddressResolver(resolver AddressResolver) *BuildableClient {
+	cpy := b.clone()
+	cpy.addressResolver = resolver
+	return cpy
+}
+
+// WithRedirectPolicy returns a copy of the client that follows redirects
+// according to the policy. Without a policy, redirects are followed as
+// described by http.Client.
+func (b *BuildableClient) WithRedirectPolicy(policy RedirectPolicy) *BuildableClient {
+	cpy := b.clone()
+	cpy.redirectPolicy = &policy
+	return cpy
+}
+
+// WithMinTLSVersion returns a copy of the client that fails to connect to
+// endpoints that do not support at least the TLS version, (e.g.
+// tls.VersionTLS13). Defaults to tls.VersionTLS12.
+func (b *BuildableClient) WithMinTLSVersion(version uint16) *BuildableClient {
+	cpy := b.clone()
+	cpy.minTLSVersion = version
+	return cpy
+}
+
+// WithExpectContinueTimeout returns a copy of the client that waits at most
+// the timeout for a service's 100 Continue response to a request sent with
+// the Expect: 100-continue header, before sending the request's body
+// regardless. Defaults to DefaultExpectContinueTimeout.
+func (b *BuildableClient) WithExpectContinueTimeout(timeout time.Duration) *BuildableClient {
+	return b.WithTransportOptions(func(tr *http.Transport) {
+		tr.ExpectContinueTimeout = timeout
+	})
+}
+
+// WithMaxIdleConns returns a copy of the client that keeps at most n idle
+// connections across all hosts. Zero means no limit. Defaults to
+// DefaultMaxIdleConns.
+func (b *BuildableClient) WithMaxIdleConns(n int) *BuildableClient {
+	return b.WithTransportOptions(func(tr *http.Transport) {
+		tr.MaxIdleConns = n
+	})
+}
+
+// WithMaxIdleConnsPerHost returns a copy of the client that keeps at most n
+// idle connections for each host. High throughput clients should raise the
+// limit to the number of requests made to a host concurrently. Defaults to
+// DefaultMaxIdleConnsPerHost.
+func (b *BuildableClient) WithMaxIdleConnsPerHost(n int) *BuildableClient {
+	return b.WithTransportOptions(func(tr *http.Transport) {
+		tr.MaxIdleConnsPerHost = n
+	})
+}
+
+// WithIdleConnTimeout returns a copy of the client that closes connections
+// idle for longer than the timeout. Zero means no timeout. Defaults to
+// DefaultIdleConnTimeout.
+func (b *BuildableClient) WithIdleConnTimeout(timeout time.Duration) *BuildableClient {
+	return b.WithTransportOptions(func(tr *http.Transport) {
+		tr.IdleConnTimeout = timeout
+	})
+}
+
+// WithMaxConnsPerHost returns a copy of the client that opens at most n
+// connections to each host, including connections in use. Requests made
+// once the limit is reached wait for a connection. Defaults to
+// DefaultMaxConnsPerHost, no limit.
+func (b *BuildableClient) WithMaxConnsPerHost(n int) *BuildableClient {
+	return b.WithTransportOptions(func(tr *http.Transport) {
+		tr.MaxConnsPerHost = n
+	})
+}
+
+// WithMaxResponseHeaderBytes returns a copy of the client that fails
+// requests whose response headers are larger than n bytes with a
+// ResponseHeaderTooLargeError, protecting against endpoints sending
+// unbounded headers. Zero uses net/http's default limit.
+func (b *BuildableClient) WithMaxResponseHeaderBytes(n int64) *BuildableClient {
+	return b.WithTransportOptions(func(tr *http.Transport) {
+		tr.MaxResponseHeaderBytes = n
+	})
+}
+
+// WithTimeout returns a copy of the client with the timeout of each request
+// sent, including reading the response body. Zero means no timeout.
+func (b *BuildableClient) WithTimeout(timeout time.Duration) *BuildableClient {
+	cpy := b.clone()
+	cpy.clientTimeout = timeout
+	return cpy
+}
+
+// WithHTTP2PriorKnowledge returns a copy of the client that, if enabled,
+// sends requests to http endpoints with unencrypted HTTP/2 (h2c) without
+// negotiating the protocol, for services known to support HTTP/2. Requires
+// the client to be built with go1.24 or later, otherwise sending a request
+// returns an error.
+func (b *BuildableClient) WithHTTP2PriorKnowledge(v bool) *BuildableClient {
+	cpy := b.clone()
+	cpy.http2PriorKnowledge = v
+	return cpy
+}
+
+// WithHTTPClient returns a copy of the client that sends requests with the
+// custom http.Client.
+func (b *BuildableClient) WithHTTPClient(client *http.Client) *BuildableClient {
+	cpy := b.clone()
+	cpy.httpClient = client
+	return cpy
+}
+
+// GetTransport returns a copy of the client's HTTP transport.
+func (b *BuildableClient) GetTransport() *http.Transport {
+	var tr *http.Transport
+	if b.transport != nil {
+		tr = b.transport.Clone()
+	} else {
+		tr = defaultHTTPTransport()
+	}
+
+	return tr
+}
+
+// GetDialer returns a copy of the client's network dialer.
+func (b *BuildableClient) GetDialer() *net.Dialer {
+	var dialer *net.Dialer
+	if b.dialer != nil {
+		cpy := *b.dialer
+		dialer = &cpy
+	} else {
+		dialer = defaultDialer()
+	}
+
+	return dialer
+}
+
+// GetTimeout returns a copy of the client's timeout to cancel requests with.
+func (b *BuildableClient) GetTimeout() time.Duration {
+	return b.clientTimeout
+}
+
+// defaultMaxRedirects is the number of redirects http.Client follows without
+// a CheckRedirect policy.
+const defaultMaxRedirects = 10
+
+// DefaultRedirectSensitiveHeaders is the default set of headers removed from
+// a redirected request sent to a different host than the original request.
+var DefaultRedirectSensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Amz-Security-Token",
+}
+
+// RedirectPolicy configures how the BuildableClient follows redirect
+// responses.
+type RedirectPolicy struct {
+	// The maximum number of redirects followed for a request. Once reached,
+	// the redirect response is returned without being followed. If zero,
+	// redirects are not followed.
+	MaxRedirects int
+
+	// Removes the sensitive headers from a redirected request if its host
+	// differs from the host of the original request, so credentials are not
+	// sent to a host they were not intended for.
+	StripSensitiveHeaders bool
+
+	// The headers removed from a redirected request sent to a different
+	// host. If nil, DefaultRedirectSensitiveHeaders is used.
+	SensitiveHeaders []string
+}
+
+func (p *RedirectPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > p.MaxRedirects {
+		return http.ErrUseLastResponse
+	}
+
+	if p.StripSensitiveHeaders && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		headers := p.SensitiveHeaders
+		if headers == nil {
+			headers = DefaultRedirectSensitiveHeaders
+		}
+		for _, h := range headers {
+			req.Header.Del(h)
+		}
+	}
+
+	return nil
+}
+
+// DialContextFunc opens a network connection to the address, matching the
+// signature of net.Dialer's DialContext.
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// AddressResolver resolves the host:port address a connection is dialed with.
+type AddressResolver interface {
+	ResolveAddress(ctx context.Context, network, address string) (string, error)
+}
+
+// AddressResolverFunc wraps a function with the AddressResolver interface.
+type AddressResolverFunc func(ctx context.Context, network, address string) (string, error)
+
+// ResolveAddress resolves the address with the wrapped function.
+func (fn AddressResolverFunc) ResolveAddress(ctx context.Context, network, address string) (string, error) {
+	return fn(ctx, network, address)
+}
+
+// PinnedHosts is an AddressResolver mapping hostnames to the IP address, or
+// host, to connect to instead. The address's port is kept. Hosts not in the
+// map are not modified.
+type PinnedHosts map[string]string
+
+// ResolveAddress returns the address with its host replaced if the host is
+// pinned.
+func (p PinnedHosts) ResolveAddress(ctx context.Context, network, address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid dial address %q, %w", address, err)
+	}
+	if pinned, ok := p[host]; ok {
+		return net.JoinHostPort(pinned, port), nil
+	}
+	return address, nil
+}
+
+func resolvingDialContext(resolver AddressResolver, dial DialContextFunc) DialContextFunc {
+	if dial == nil {
+		dial = defaultDialer().DialContext
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		resolved, err := resolver.ResolveAddress(ctx, network, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve dial address, %w", err)
+		}
+		return dial(ctx, network, resolved)
+	}
+}
+
+func defaultDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   DefaultDialConnectTimeout,
+		KeepAlive: DefaultDialKeepAliveTimeout,
+	}
+}
+
+func defaultHTTPTransport() *http.Transport {
+	dialer := defaultDialer()
+
+	tr := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		MaxIdleConns:          DefaultMaxIdleConns,
+		MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+		MaxConnsPerHost:       DefaultMaxConnsPerHost,
+		IdleConnTimeout:       DefaultIdleConnTimeout,
+		ExpectContinueTimeout: DefaultExpectContinueTimeout,
+		ForceAttemptHTTP2:     true,
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	return tr
+}
+
+var _ ClientDo = (*BuildableClient)(nil)
+var _ ClientDo = ClientDoFunc(nil)
+var _ middleware.Handler = ClientHandler{}
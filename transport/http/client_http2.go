@@ -0,0 +1,15 @@
+//go:build go1.24
+// +build go1.24
+
+package http
+
+import "net/http"
+
+// enableHTTP2PriorKnowledge configures the transport to send requests to http
+// endpoints with unencrypted HTTP/2, without protocol negotiation.
+func enableHTTP2PriorKnowledge(tr *http.Transport) error {
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	tr.Protocols = protocols
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build go1.24
+// +build go1.24
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBuildableClientHTTP2PriorKnowledge(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		w.WriteHeader(200)
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	client := NewBuildableClient().
+		WithTimeout(5 * time.Second).
+		WithHTTP2PriorKnowledge(true)
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if e, a := 2, resp.ProtoMajor; e != a {
+		t.Errorf("expect HTTP/%v response, got %v", e, resp.Proto)
+	}
+	if e, a := "HTTP/2.0", resp.Header.Get("X-Proto"); e != a {
+		t.Errorf("expect %v request protocol, got %v", e, a)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !go1.24
+// +build !go1.24
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func enableHTTP2PriorKnowledge(*http.Transport) error {
+	return fmt.Errorf("HTTP/2 prior knowledge requires go1.24 or later")
+}
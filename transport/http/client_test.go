@@ -0,0 +1,358 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestBuildableClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewBuildableClient().
+		WithTimeout(5 * time.Second).
+		WithDialerOptions(func(d *net.Dialer) {
+			d.Timeout = time.Second
+		}).
+		WithTransportOptions(func(tr *http.Transport) {
+			tr.MaxIdleConns = 1
+		})
+
+	if e, a := 5*time.Second, client.GetTimeout(); e != a {
+		t.Errorf("expect %v timeout, got %v", e, a)
+	}
+	if e, a := time.Second, client.GetDialer().Timeout; e != a {
+		t.Errorf("expect %v dial timeout, got %v", e, a)
+	}
+	if e, a := 1, client.GetTransport().MaxIdleConns; e != a {
+		t.Errorf("expect %v max idle conns, got %v", e, a)
+	}
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	defer resp.Body.Close()
+	if e, a := "HTTP/1.1", resp.Header.Get("X-Proto"); e != a {
+		t.Errorf("expect %v protocol, got %v", e, a)
+	}
+}
+
+func TestBuildableClientAddressResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Host", r.Host)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	_, port, _ := net.SplitHostPort(serverURL.Host)
+
+	var dialed []string
+	dialer := &net.Dialer{Timeout: time.Second}
+	client := NewBuildableClient().
+		WithDialContext(func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = append(dialed, address)
+			return dialer.DialContext(ctx, network, address)
+		}).
+		WithAddressResolver(PinnedHosts{"pinned.example.com": "127.0.0.1"}).
+		WithTransportOptions(func(tr *http.Transport) {
+			tr.DisableKeepAlives = true
+		})
+
+	req, _ := http.NewRequest("GET", "http://pinned.example.com:"+port+"/", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if e, a := []string{"127.0.0.1:" + port}, dialed; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v dialed, got %v", e, a)
+	}
+	if e, a := "pinned.example.com:"+port, resp.Header.Get("X-Host"); e != a {
+		t.Errorf("expect %v host header, got %v", e, a)
+	}
+
+	// Hosts not pinned are dialed unmodified.
+	dialed = nil
+	req, _ = http.NewRequest("GET", server.URL, nil)
+	if resp, err = client.Do(req); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+	if e, a := []string{serverURL.Host}, dialed; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v dialed, got %v", e, a)
+	}
+}
+
+func TestBuildableClientOptionsCopy(t *testing.T) {
+	base := NewBuildableClient()
+	withTimeout := base.WithTimeout(time.Second)
+
+	if e, a := time.Duration(0), base.GetTimeout(); e != a {
+		t.Errorf("expect base client unmodified, got %v timeout", a)
+	}
+	if e, a := time.Second, withTimeout.GetTimeout(); e != a {
+		t.Errorf("expect %v timeout, got %v", e, a)
+	}
+}
+
+func TestBuildableClientCustomHTTPClient(t *testing.T) {
+	var called bool
+	custom := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			called = true
+			return &http.Response{StatusCode: 204, Body: http.NoBody}, nil
+		}),
+	}
+
+	client := NewBuildableClient().WithTimeout(time.Second).WithHTTPClient(custom)
+
+	req, _ := http.NewRequest("GET", "https://example.com", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !called {
+		t.Errorf("expect custom client to be used")
+	}
+	if e, a := 204, resp.StatusCode; e != a {
+		t.Errorf("expect %v status, got %v", e, a)
+	}
+}
+
+func TestClientHandler(t *testing.T) {
+	sendErr := fmt.Errorf("connection reset")
+
+	handler := NewClientHandler(ClientDoFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/fail" {
+			return nil, sendErr
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	}))
+
+	req := NewStackRequest().(*Request)
+	req.URL, _ = url.Parse("https://example.com/ok")
+	out, metadata, err := handler.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := 200, out.(*Response).StatusCode; e != a {
+		t.Errorf("expect %v status, got %v", e, a)
+	}
+	if v, ok := middleware.GetResponseStatusCodeMetadata(metadata); !ok || v != 200 {
+		t.Errorf("expect 200 status code metadata, got %v, %v", v, ok)
+	}
+
+	req.URL, _ = url.Parse("https://example.com/fail")
+	_, _, err = handler.Handle(context.Background(), req)
+	if sendErr, ok := err.(*RequestSendError); !ok || sendErr.Unwrap() == nil {
+		t.Errorf("expect request send error, got %v", err)
+	}
+
+	if _, _, err = handler.Handle(context.Background(), struct{}{}); err == nil {
+		t.Errorf("expect error for unknown input type, got none")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+func TestBuildableClientRedirectPolicy(t *testing.T) {
+	var received http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(200)
+	}))
+	defer target.Close()
+
+	var origin *httptest.Server
+	origin = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cross-host":
+			http.Redirect(w, r, target.URL+"/end", http.StatusFound)
+		case "/same-host":
+			http.Redirect(w, r, origin.URL+"/end", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, origin.URL+"/loop", http.StatusFound)
+		default:
+			received = r.Header.Clone()
+			w.WriteHeader(200)
+		}
+	}))
+	defer origin.Close()
+
+	cases := map[string]struct {
+		Policy          RedirectPolicy
+		Path            string
+		ExpectStatus    int
+		ExpectAuth      string
+		ExpectSecurity  string
+		ExpectUserAgent string
+	}{
+		"cross host stripped": {
+			Policy:          RedirectPolicy{MaxRedirects: 3, StripSensitiveHeaders: true},
+			Path:            "/cross-host",
+			ExpectStatus:    200,
+			ExpectUserAgent: "custom-agent",
+		},
+		"same host kept": {
+			Policy:          RedirectPolicy{MaxRedirects: 3, StripSensitiveHeaders: true},
+			Path:            "/same-host",
+			ExpectStatus:    200,
+			ExpectAuth:      "secret",
+			ExpectSecurity:  "token",
+			ExpectUserAgent: "custom-agent",
+		},
+		"max redirects": {
+			Policy:       RedirectPolicy{MaxRedirects: 2},
+			Path:         "/loop",
+			ExpectStatus: http.StatusFound,
+		},
+		"not followed": {
+			Policy:       RedirectPolicy{},
+			Path:         "/same-host",
+			ExpectStatus: http.StatusFound,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			received = nil
+			client := NewBuildableClient().WithRedirectPolicy(c.Policy)
+
+			req, _ := http.NewRequest("GET", origin.URL+c.Path, nil)
+			req.Header.Set("Authorization", "secret")
+			req.Header.Set("X-Amz-Security-Token", "token")
+			req.Header.Set("User-Agent", "custom-agent")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			resp.Body.Close()
+
+			if e, a := c.ExpectStatus, resp.StatusCode; e != a {
+				t.Fatalf("expect %v status, got %v", e, a)
+			}
+			if c.ExpectStatus != 200 {
+				return
+			}
+			if e, a := c.ExpectAuth, received.Get("Authorization"); e != a {
+				t.Errorf("expect %q authorization, got %q", e, a)
+			}
+			if e, a := c.ExpectSecurity, received.Get("X-Amz-Security-Token"); e != a {
+				t.Errorf("expect %q security token, got %q", e, a)
+			}
+			if e, a := c.ExpectUserAgent, received.Get("User-Agent"); e != a {
+				t.Errorf("expect %q user agent, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestBuildableClientConnectionPool(t *testing.T) {
+	base := NewBuildableClient()
+	tr := base.GetTransport()
+	if e, a := DefaultMaxIdleConns, tr.MaxIdleConns; e != a {
+		t.Errorf("expect %v default max idle conns, got %v", e, a)
+	}
+	if e, a := DefaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost; e != a {
+		t.Errorf("expect %v default max idle conns per host, got %v", e, a)
+	}
+	if e, a := DefaultIdleConnTimeout, tr.IdleConnTimeout; e != a {
+		t.Errorf("expect %v default idle conn timeout, got %v", e, a)
+	}
+	if e, a := DefaultMaxConnsPerHost, tr.MaxConnsPerHost; e != a {
+		t.Errorf("expect %v default max conns per host, got %v", e, a)
+	}
+
+	client := base.
+		WithMaxIdleConns(500).
+		WithMaxIdleConnsPerHost(250).
+		WithIdleConnTimeout(30 * time.Second).
+		WithMaxConnsPerHost(300)
+
+	client.initOnce.Do(client.build)
+	built, ok := client.client.(*http.Client).Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expect built client to use *http.Transport")
+	}
+	if e, a := 500, built.MaxIdleConns; e != a {
+		t.Errorf("expect %v max idle conns, got %v", e, a)
+	}
+	if e, a := 250, built.MaxIdleConnsPerHost; e != a {
+		t.Errorf("expect %v max idle conns per host, got %v", e, a)
+	}
+	if e, a := 30*time.Second, built.IdleConnTimeout; e != a {
+		t.Errorf("expect %v idle conn timeout, got %v", e, a)
+	}
+	if e, a := 300, built.MaxConnsPerHost; e != a {
+		t.Errorf("expect %v max conns per host, got %v", e, a)
+	}
+
+	if e, a := DefaultMaxIdleConnsPerHost, base.GetTransport().MaxIdleConnsPerHost; e != a {
+		t.Errorf("expect base client unmodified, got %v max idle conns per host", a)
+	}
+}
+
+func TestBuildableClientMaxResponseHeaderBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Large", strings.Repeat("a", 8*1024))
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	cases := map[string]struct {
+		Client    *BuildableClient
+		ExpectErr bool
+	}{
+		"default limit": {
+			Client: NewBuildableClient(),
+		},
+		"exceeds limit": {
+			Client:    NewBuildableClient().WithMaxResponseHeaderBytes(4 * 1024),
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			resp, err := c.Client.Do(req)
+			if !c.ExpectErr {
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				resp.Body.Close()
+				return
+			}
+
+			var headerErr *ResponseHeaderTooLargeError
+			if !errors.As(err, &headerErr) {
+				t.Fatalf("expect response header too large error, got %v", err)
+			}
+			if e, a := int64(4*1024), headerErr.Limit; e != a {
+				t.Errorf("expect %v limit, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context marking the operation as a dry run. The
+// ClientHandler does not send the requests of a dry run operation, returning
+// a *DryRunError with the built request instead, after the request has been
+// validated, serialized, and signed by the operation's middleware.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun returns if the operation of the context is a dry run.
+func IsDryRun(ctx context.Context) bool {
+	v, _ := ctx.Value(dryRunKey{}).(bool)
+	return v
+}
+
+// DryRunError is returned by the ClientHandler for dry run operations in
+// place of sending the request. The request that would have been sent is set
+// on the error, and in the operation's metadata, see GetDryRunRequest.
+type DryRunError struct {
+	Request *http.Request
+}
+
+func (e *DryRunError) Error() string {
+	return "dry run, request not sent"
+}
+
+type dryRunRequestKey struct{}
+
+// SetDryRunRequest sets the request built for a dry run operation in the
+// metadata.
+func SetDryRunRequest(metadata *middleware.Metadata, req *http.Request) {
+	metadata.Set(dryRunRequestKey{}, req)
+}
+
+// GetDryRunRequest returns the request built for a dry run operation, and if
+// it was set in the metadata.
+func GetDryRunRequest(metadata middleware.MetadataReader) (*http.Request, bool) {
+	v, ok := metadata.Get(dryRunRequestKey{}).(*http.Request)
+	return v, ok
+}
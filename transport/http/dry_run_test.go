@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestDryRun(t *testing.T) {
+	stack := middleware.NewStack("dry run", NewStackRequest)
+	stack.Serialize.Add(middleware.SerializeMiddlewareFunc("serialize", func(
+		ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler,
+	) (
+		middleware.SerializeOutput, middleware.Metadata, error,
+	) {
+		req := in.Request.(*Request)
+		req.Method = "GET"
+		req.URL, _ = url.Parse("https://example.com/path")
+		return next.HandleSerialize(ctx, in)
+	}), middleware.After)
+	stack.Finalize.Add(&SignRequest{
+		Signer: auth.SignerFunc(func(
+			ctx context.Context, creds auth.Credentials, r *http.Request, payloadHash, service string,
+			regionSet []string, signingTime time.Time,
+		) error {
+			r.Header.Set("Authorization", "signed "+creds.AccessKeyID)
+			return nil
+		}),
+		Credentials: auth.StaticCredentialsProvider{
+			Value: auth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"},
+		},
+		Service:   "svc",
+		RegionSet: []string{"us-east-1"},
+	}, middleware.After)
+
+	var sent bool
+	handler := middleware.DecorateHandler(NewClientHandler(ClientDoFunc(func(r *http.Request) (*http.Response, error) {
+		sent = true
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})), stack)
+
+	_, metadata, err := handler.Handle(WithDryRun(context.Background()), struct{}{})
+	var dryRunErr *DryRunError
+	if !errors.As(err, &dryRunErr) {
+		t.Fatalf("expect dry run error, got %v", err)
+	}
+	if sent {
+		t.Errorf("expect no request sent")
+	}
+
+	req, ok := GetDryRunRequest(metadata)
+	if !ok {
+		t.Fatalf("expect dry run request in metadata")
+	}
+	if e, a := dryRunErr.Request, req; e != a {
+		t.Errorf("expect error and metadata request to match")
+	}
+	if e, a := "signed AKID", req.Header.Get("Authorization"); e != a {
+		t.Errorf("expect %q authorization, got %q", e, a)
+	}
+	if e, a := "https://example.com/path", req.URL.String(); e != a {
+		t.Errorf("expect %v URL, got %v", e, a)
+	}
+
+	if _, _, err = handler.Handle(context.Background(), struct{}{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !sent {
+		t.Errorf("expect request sent without dry run")
+	}
+}
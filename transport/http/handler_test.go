@@ -0,0 +1,49 @@
+package http
+
+import (
+	"context"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// Handler func adapters for invoking a single step middleware in tests.
+
+type serializeHandlerFunc func(context.Context, middleware.SerializeInput) (
+	middleware.SerializeOutput, middleware.Metadata, error,
+)
+
+func (fn serializeHandlerFunc) HandleSerialize(ctx context.Context, in middleware.SerializeInput) (
+	middleware.SerializeOutput, middleware.Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+type buildHandlerFunc func(context.Context, middleware.BuildInput) (
+	middleware.BuildOutput, middleware.Metadata, error,
+)
+
+func (fn buildHandlerFunc) HandleBuild(ctx context.Context, in middleware.BuildInput) (
+	middleware.BuildOutput, middleware.Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+type finalizeHandlerFunc func(context.Context, middleware.FinalizeInput) (
+	middleware.FinalizeOutput, middleware.Metadata, error,
+)
+
+func (fn finalizeHandlerFunc) HandleFinalize(ctx context.Context, in middleware.FinalizeInput) (
+	middleware.FinalizeOutput, middleware.Metadata, error,
+) {
+	return fn(ctx, in)
+}
+
+type deserializeHandlerFunc func(context.Context, middleware.DeserializeInput) (
+	middleware.DeserializeOutput, middleware.Metadata, error,
+)
+
+func (fn deserializeHandlerFunc) HandleDeserialize(ctx context.Context, in middleware.DeserializeInput) (
+	middleware.DeserializeOutput, middleware.Metadata, error,
+) {
+	return fn(ctx, in)
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultAttemptRecorderMaxBodyBytes is the default maximum number of bytes
+// of each attempt's response body captured by the AttemptRecorder.
+const DefaultAttemptRecorderMaxBodyBytes int64 = 64 * 1024
+
+// AttemptSnapshot is a copy of the request and response of a single
+// operation attempt, recorded by the AttemptRecorder.
+type AttemptSnapshot struct {
+	Method         string
+	URL            string
+	RequestHeader  http.Header
+	StatusCode     int
+	ResponseHeader http.Header
+
+	// Copy of the start of the response body, if body capture is enabled.
+	ResponseBody []byte
+
+	// If the response body was larger than the captured copy.
+	ResponseBodyTruncated bool
+
+	// Error of the attempt, if the request could not be sent.
+	Err error
+}
+
+type attemptSnapshotsKey struct{}
+
+// GetAttemptSnapshots returns the snapshots of each attempt recorded by the
+// AttemptRecorder from the metadata, in the order the attempts were made.
+func GetAttemptSnapshots(metadata middleware.MetadataReader) ([]AttemptSnapshot, bool) {
+	v, ok := metadata.Get(attemptSnapshotsKey{}).([]AttemptSnapshot)
+	return v, ok
+}
+
+type attemptRecording struct {
+	mu        sync.Mutex
+	snapshots []AttemptSnapshot
+}
+
+type attemptRecordingKey struct{}
+
+// AttemptRecorder records a snapshot of the request and response of every
+// attempt an operation makes, for debugging failed or retried operations.
+// The snapshots are returned in the operation's metadata, and retrieved with
+// GetAttemptSnapshots.
+//
+// The recorder is made up of a finalize middleware, which must be added
+// before the Retry middleware so that it sees every attempt, and a
+// deserialize middleware invoked for each attempt. Use AddAttemptRecorder to
+// add both to a stack.
+type AttemptRecorder struct {
+	// Enables capturing a copy of each attempt's response body. The body
+	// remains readable by the middleware deserializing the response.
+	CaptureBody bool
+
+	// Maximum number of response body bytes to capture. Defaults to
+	// DefaultAttemptRecorderMaxBodyBytes.
+	MaxBodyBytes int64
+}
+
+// AddAttemptRecorder adds the AttemptRecorder's middleware to the stack. The
+// finalize middleware is added first in the Finalize step, and the
+// deserialize middleware last in the Deserialize step, closest to the
+// response.
+func AddAttemptRecorder(stack *middleware.Stack, r *AttemptRecorder) error {
+	if err := stack.Finalize.Add(r, middleware.Before); err != nil {
+		return err
+	}
+	return stack.Deserialize.Add(&attemptSnapshotter{recorder: r}, middleware.After)
+}
+
+// ID returns the middleware identifier.
+func (r *AttemptRecorder) ID() string {
+	return "AttemptRecorder"
+}
+
+// HandleFinalize records the snapshots of the attempts made by the
+// remainder of the stack into the metadata.
+func (r *AttemptRecorder) HandleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	recording := &attemptRecording{}
+	ctx = context.WithValue(ctx, attemptRecordingKey{}, recording)
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+
+	recording.mu.Lock()
+	metadata.Set(attemptSnapshotsKey{}, append([]AttemptSnapshot(nil), recording.snapshots...))
+	recording.mu.Unlock()
+
+	return out, metadata, err
+}
+
+// attemptSnapshotter is the deserialize middleware of the AttemptRecorder,
+// taking a snapshot of each attempt.
+type attemptSnapshotter struct {
+	recorder *AttemptRecorder
+}
+
+func (m *attemptSnapshotter) ID() string {
+	return "AttemptSnapshot"
+}
+
+func (m *attemptSnapshotter) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	recording, ok := ctx.Value(attemptRecordingKey{}).(*attemptRecording)
+	if !ok {
+		return next.HandleDeserialize(ctx, in)
+	}
+
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	snapshot := AttemptSnapshot{
+		Method:        req.Method,
+		RequestHeader: req.Header.Clone(),
+	}
+	if req.URL != nil {
+		snapshot.URL = req.URL.String()
+	}
+
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+
+	if resp, ok := out.RawResponse.(*Response); ok && resp != nil && resp.Response != nil {
+		snapshot.StatusCode = resp.StatusCode
+		snapshot.ResponseHeader = resp.Header.Clone()
+		if m.recorder.CaptureBody && resp.Body != nil {
+			if captureErr := m.captureBody(resp, &snapshot); captureErr != nil && err == nil {
+				err = captureErr
+			}
+		}
+	} else {
+		snapshot.Err = err
+	}
+
+	recording.mu.Lock()
+	recording.snapshots = append(recording.snapshots, snapshot)
+	recording.mu.Unlock()
+
+	return out, metadata, err
+}
+
+// captureBody copies up to the maximum body bytes of the response body into
+// the snapshot, replacing the response's body so the copied bytes can still
+// be read.
+func (m *attemptSnapshotter) captureBody(resp *Response, snapshot *AttemptSnapshot) error {
+	limit := m.recorder.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultAttemptRecorderMaxBodyBytes
+	}
+
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, resp.Body, limit+1)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to capture response body, %w", err)
+	}
+
+	captured := buf.Bytes()
+	if n > limit {
+		snapshot.ResponseBodyTruncated = true
+		snapshot.ResponseBody = append([]byte(nil), captured[:limit]...)
+	} else {
+		snapshot.ResponseBody = append([]byte(nil), captured...)
+	}
+
+	resp.Body = &replayBody{
+		Reader: io.MultiReader(bytes.NewReader(captured), resp.Body),
+		Closer: resp.Body,
+	}
+	return nil
+}
+
+// replayBody replays the captured bytes of a response body before the
+// remainder of the original body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
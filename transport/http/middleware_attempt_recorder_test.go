@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestAttemptRecorder(t *testing.T) {
+	cases := map[string]struct {
+		Recorder         AttemptRecorder
+		ExpectBodies     []string
+		ExpectTruncated  []bool
+		ExpectReadBodies []string
+	}{
+		"no body capture": {
+			ExpectBodies:     []string{"", "", ""},
+			ExpectTruncated:  []bool{false, false, false},
+			ExpectReadBodies: []string{"", "", `{"ok":true}`},
+		},
+		"bounded body capture": {
+			Recorder:         AttemptRecorder{CaptureBody: true, MaxBodyBytes: 8},
+			ExpectBodies:     []string{"internal", "", `{"ok":tr`},
+			ExpectTruncated:  []bool{true, false, true},
+			ExpectReadBodies: []string{"", "", `{"ok":true}`},
+		},
+	}
+
+	responses := []struct {
+		StatusCode int
+		Body       string
+		SendErr    error
+	}{
+		{StatusCode: 500, Body: "internal error"},
+		{SendErr: fmt.Errorf("connection reset")},
+		{StatusCode: 200, Body: `{"ok":true}`},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stack := middleware.NewStack("recorder", NewStackRequest)
+			stack.Serialize.Add(middleware.SerializeMiddlewareFunc("serialize",
+				func(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (
+					out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+				) {
+					req := in.Request.(*Request)
+					req.Method = "PUT"
+					req.URL, _ = url.Parse("https://example.com/bucket/key")
+					req.Header.Set("X-Foo", "bar")
+					return next.HandleSerialize(ctx, in)
+				}), middleware.After)
+
+			// Simple retry, attempting until success.
+			stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("retry",
+				func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					for i := 0; i < len(responses); i++ {
+						if out, metadata, err = next.HandleFinalize(ctx, in); err == nil {
+							break
+						}
+					}
+					return out, metadata, err
+				}), middleware.After)
+
+			var readBodies []string
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+				func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out, metadata, err = next.HandleDeserialize(ctx, in)
+					if err != nil {
+						readBodies = append(readBodies, "")
+						return out, metadata, err
+					}
+					resp := out.RawResponse.(*Response)
+					if resp.StatusCode != 200 {
+						readBodies = append(readBodies, "")
+						return out, metadata, &smithy.GenericAPIError{Code: "InternalError"}
+					}
+					b, _ := ioutil.ReadAll(resp.Body)
+					readBodies = append(readBodies, string(b))
+					return out, metadata, nil
+				}), middleware.After)
+
+			recorder := c.Recorder
+			if err := AddAttemptRecorder(stack, &recorder); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			var attempt int
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					r := responses[attempt]
+					attempt++
+					if r.SendErr != nil {
+						return nil, middleware.Metadata{}, r.SendErr
+					}
+					return &Response{
+						Response: &http.Response{
+							StatusCode: r.StatusCode,
+							Header:     http.Header{"X-Attempt": []string{fmt.Sprint(attempt)}},
+							Body:       ioutil.NopCloser(strings.NewReader(r.Body)),
+						},
+					}, middleware.Metadata{}, nil
+				}), stack)
+
+			_, metadata, err := handler.Handle(context.Background(), struct{}{})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			snapshots, ok := GetAttemptSnapshots(metadata)
+			if !ok {
+				t.Fatalf("expect attempt snapshots in metadata")
+			}
+			if e, a := len(responses), len(snapshots); e != a {
+				t.Fatalf("expect %v snapshots, got %v", e, a)
+			}
+
+			for i, s := range snapshots {
+				if e, a := "PUT", s.Method; e != a {
+					t.Errorf("attempt %d, expect %v method, got %v", i, e, a)
+				}
+				if e, a := "https://example.com/bucket/key", s.URL; e != a {
+					t.Errorf("attempt %d, expect %v URL, got %v", i, e, a)
+				}
+				if e, a := "bar", s.RequestHeader.Get("X-Foo"); e != a {
+					t.Errorf("attempt %d, expect %v request header, got %v", i, e, a)
+				}
+				if e, a := responses[i].StatusCode, s.StatusCode; e != a {
+					t.Errorf("attempt %d, expect %v status, got %v", i, e, a)
+				}
+				if responses[i].SendErr != nil {
+					if s.Err == nil || s.ResponseHeader != nil {
+						t.Errorf("attempt %d, expect send error and no response, got %v, %v", i, s.Err, s.ResponseHeader)
+					}
+				} else if e, a := fmt.Sprint(i+1), s.ResponseHeader.Get("X-Attempt"); e != a {
+					t.Errorf("attempt %d, expect %v response header, got %v", i, e, a)
+				}
+				if e, a := c.ExpectBodies[i], string(s.ResponseBody); e != a {
+					t.Errorf("attempt %d, expect %q body, got %q", i, e, a)
+				}
+				if e, a := c.ExpectTruncated[i], s.ResponseBodyTruncated; e != a {
+					t.Errorf("attempt %d, expect truncated %v, got %v", i, e, a)
+				}
+			}
+
+			for i, e := range c.ExpectReadBodies {
+				if a := readBodies[i]; e != a {
+					t.Errorf("attempt %d, expect deserializer to read %q, got %q", i, e, a)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+
+	smithyio "github.com/awslabs/smithy-go/io"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// AWSChunkedEncoding is a build middleware that sends the request body with
+// the aws-chunked content encoding, framing the body into chunks that are
+// each signed by the ChunkSigner, followed by an optional trailing checksum
+// of the body.
+//
+// The Content-Encoding header is updated to include aws-chunked, and the
+// X-Amz-Decoded-Content-Length header is set to the body's length if known.
+// The encoded body is sent with an unknown content length. If the body is
+// seekable, the encoded body can be rewound, (e.g. by the Retry middleware),
+// which rewinds the body and restarts the encoding and trailing checksum.
+//
+// The context's payload hash is set to the hash of the encoded payload, see
+// SetPayloadHash, StreamingPayload or StreamingPayloadTrailer if the chunks
+// are signed, otherwise UnsignedPayload or StreamingUnsignedPayloadTrailer,
+// so the request is signed with it.
+type AWSChunkedEncoding struct {
+	// Size of the body's chunks. Defaults to smithyio.DefaultAWSChunkSize.
+	ChunkSize int
+
+	// Returns the signer of the request's chunks. The signer is first used
+	// once the request is sent, after the request has been signed, so the
+	// signer can be seeded from the request's signature. If nil, chunks are
+	// not signed.
+	NewChunkSigner func(r *Request) smithyio.ChunkSigner
+
+	// Algorithm of the trailing checksum, and the name of the trailing
+	// header the base64 encoded checksum is sent in, (e.g.
+	// x-amz-checksum-crc32). If the algorithm is empty, no trailing
+	// checksum is sent.
+	ChecksumAlgorithm ChecksumAlgorithm
+	ChecksumTrailer   string
+}
+
+// ID returns the middleware identifier.
+func (m *AWSChunkedEncoding) ID() string {
+	return "AWSChunkedEncoding"
+}
+
+// HandleBuild wraps the request body with the aws-chunked encoding.
+func (m *AWSChunkedEncoding) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+	if req.GetStream() == nil {
+		return next.HandleBuild(ctx, in)
+	}
+
+	size, ok, err := req.StreamLength()
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to determine request body length, %w", err)
+	}
+
+	var checksum hash.Hash
+	if len(m.ChecksumAlgorithm) != 0 {
+		if len(m.ChecksumTrailer) == 0 {
+			return out, metadata, fmt.Errorf("checksum trailer name not set for %v checksum", m.ChecksumAlgorithm)
+		}
+		if checksum, err = newChecksumHash(m.ChecksumAlgorithm); err != nil {
+			return out, metadata, err
+		}
+	}
+
+	source := req.GetStream()
+	newEncoded := func() io.Reader {
+		body := source
+		if checksum != nil {
+			checksum.Reset()
+			body = io.TeeReader(body, checksum)
+		}
+		return smithyio.NewAWSChunkedReader(body, func(o *smithyio.AWSChunkedReaderOptions) {
+			o.ChunkSize = m.ChunkSize
+			if m.NewChunkSigner != nil {
+				o.Signer = m.NewChunkSigner(req)
+			}
+			if checksum != nil {
+				o.Trailers = func() ([]smithyio.ChunkTrailer, error) {
+					return []smithyio.ChunkTrailer{{
+						Name:  m.ChecksumTrailer,
+						Value: base64.StdEncoding.EncodeToString(checksum.Sum(nil)),
+					}}, nil
+				}
+			}
+		})
+	}
+
+	encoded := newEncoded()
+	if req.IsStreamSeekable() {
+		encoded = &awsChunkedBody{
+			source:     source.(io.Seeker),
+			start:      req.streamStartPos,
+			newEncoded: newEncoded,
+			encoded:    encoded,
+		}
+	}
+
+	if req, err = req.SetStream(encoded); err != nil {
+		return out, metadata, fmt.Errorf("failed to set aws-chunked request body, %w", err)
+	}
+	req.ContentLength = -1
+	req.Header.Del("Content-Length")
+
+	if ok {
+		req.Header.Set("X-Amz-Decoded-Content-Length", strconv.FormatInt(size, 10))
+	}
+	if checksum != nil {
+		req.Header.Set("X-Amz-Trailer", m.ChecksumTrailer)
+	}
+	if v := req.Header.Get("Content-Encoding"); len(v) != 0 {
+		req.Header.Set("Content-Encoding", "aws-chunked, "+v)
+	} else {
+		req.Header.Set("Content-Encoding", "aws-chunked")
+	}
+
+	ctx = SetPayloadHash(ctx, awsChunkedPayloadHash(m.NewChunkSigner != nil, checksum != nil))
+
+	in.Request = req
+	return next.HandleBuild(ctx, in)
+}
+
+// awsChunkedPayloadHash returns the payload hash of an aws-chunked payload.
+func awsChunkedPayloadHash(signed, trailer bool) string {
+	switch {
+	case signed && trailer:
+		return StreamingPayloadTrailer
+	case signed:
+		return StreamingPayload
+	case trailer:
+		return StreamingUnsignedPayloadTrailer
+	default:
+		return UnsignedPayload
+	}
+}
+
+// awsChunkedBody is an aws-chunked encoded request body of a seekable source,
+// which can be rewound to its start, restarting the encoding, so the request
+// can be retried.
+type awsChunkedBody struct {
+	source     io.Seeker
+	start      int64
+	newEncoded func() io.Reader
+
+	encoded io.Reader
+	read    int64
+}
+
+func (b *awsChunkedBody) Read(p []byte) (int, error) {
+	n, err := b.encoded.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+// Seek only supports returning the current offset, and rewinding to the
+// start of the encoded body, as the encoded length is not known.
+func (b *awsChunkedBody) Seek(offset int64, whence int) (int64, error) {
+	switch {
+	case offset == 0 && whence == io.SeekCurrent:
+		return b.read, nil
+	case offset == 0 && whence == io.SeekStart:
+		if _, err := b.source.Seek(b.start, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("failed to rewind aws-chunked body source, %w", err)
+		}
+		b.encoded = b.newEncoded()
+		b.read = 0
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("aws-chunked body can only be rewound to its start")
+	}
+}
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	smithyio "github.com/awslabs/smithy-go/io"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestAWSChunkedEncoding(t *testing.T) {
+	req := NewStackRequest().(*Request)
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Content-Length", "10")
+	req, err := req.SetStream(bytes.NewReader([]byte("abcdefghij")))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	m := AWSChunkedEncoding{
+		ChunkSize:         4,
+		ChecksumAlgorithm: ChecksumAlgorithmCRC32,
+		ChecksumTrailer:   "x-amz-checksum-crc32",
+	}
+
+	var built *Request
+	_, _, err = m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+		buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+			out middleware.BuildOutput, metadata middleware.Metadata, err error,
+		) {
+			built = in.Request.(*Request)
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expectHeaders := map[string]string{
+		"Content-Encoding":             "aws-chunked, gzip",
+		"X-Amz-Decoded-Content-Length": "10",
+		"X-Amz-Trailer":                "x-amz-checksum-crc32",
+		"Content-Length":               "",
+	}
+	for k, e := range expectHeaders {
+		if a := built.Header.Get(k); e != a {
+			t.Errorf("expect %v %v header, got %v", e, k, a)
+		}
+	}
+	if e, a := int64(-1), built.ContentLength; e != a {
+		t.Errorf("expect %v content length, got %v", e, a)
+	}
+
+	body, err := ioutil.ReadAll(built.GetStream())
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	expect := "4\r\nabcd\r\n4\r\nefgh\r\n2\r\nij\r\n0\r\n" +
+		"x-amz-checksum-crc32:OYFwOg==\r\n\r\n"
+	if e, a := expect, string(body); e != a {
+		t.Errorf("expect %q body, got %q", e, a)
+	}
+}
+
+func TestAWSChunkedEncoding_Rewind(t *testing.T) {
+	req := NewStackRequest().(*Request)
+	req, err := req.SetStream(bytes.NewReader([]byte("abcdefghij")))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	m := AWSChunkedEncoding{
+		ChunkSize:         4,
+		ChecksumAlgorithm: ChecksumAlgorithmCRC32,
+		ChecksumTrailer:   "x-amz-checksum-crc32",
+	}
+
+	var built *Request
+	_, _, err = m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+		buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+			out middleware.BuildOutput, metadata middleware.Metadata, err error,
+		) {
+			built = in.Request.(*Request)
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !built.IsStreamSeekable() {
+		t.Fatalf("expect encoded body of seekable stream to be seekable")
+	}
+
+	expect := "4\r\nabcd\r\n4\r\nefgh\r\n2\r\nij\r\n0\r\n" +
+		"x-amz-checksum-crc32:OYFwOg==\r\n\r\n"
+
+	// Partially read the body, as a failed attempt would.
+	partial := make([]byte, 7)
+	if _, err = built.GetStream().Read(partial); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err = built.RewindStream(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		body, err := ioutil.ReadAll(built.GetStream())
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := expect, string(body); e != a {
+			t.Errorf("expect %q body on attempt %d, got %q", e, i, a)
+		}
+	}
+}
+
+func TestAWSChunkedEncoding_NotSeekable(t *testing.T) {
+	req := NewStackRequest().(*Request)
+	req, err := req.SetStream(ioutil.NopCloser(bytes.NewReader([]byte("abc"))))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	m := AWSChunkedEncoding{}
+	_, _, err = m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+		buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+			out middleware.BuildOutput, metadata middleware.Metadata, err error,
+		) {
+			if in.Request.(*Request).IsStreamSeekable() {
+				t.Errorf("expect encoded body of unseekable stream not seekable")
+			}
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+}
+
+func TestAWSChunkedEncoding_NoBody(t *testing.T) {
+	req := NewStackRequest().(*Request)
+
+	m := AWSChunkedEncoding{}
+	_, _, err := m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+		buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+			out middleware.BuildOutput, metadata middleware.Metadata, err error,
+		) {
+			if v := in.Request.(*Request).Header.Get("Content-Encoding"); len(v) != 0 {
+				t.Errorf("expect no content encoding, got %v", v)
+			}
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+}
+
+func TestAWSChunkedEncoding_PayloadHash(t *testing.T) {
+	cases := map[string]struct {
+		Signed     bool
+		Trailer    bool
+		ExpectHash string
+	}{
+		"signed chunks":              {Signed: true, ExpectHash: StreamingPayload},
+		"signed chunks with trailer": {Signed: true, Trailer: true, ExpectHash: StreamingPayloadTrailer},
+		"unsigned chunks":            {ExpectHash: UnsignedPayload},
+		"unsigned chunks with trailer": {
+			Trailer:    true,
+			ExpectHash: StreamingUnsignedPayloadTrailer,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req, err := req.SetStream(bytes.NewReader([]byte("abcdefghij")))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			var m AWSChunkedEncoding
+			if c.Signed {
+				m.NewChunkSigner = func(*Request) smithyio.ChunkSigner { return nil }
+			}
+			if c.Trailer {
+				m.ChecksumAlgorithm = ChecksumAlgorithmCRC32
+				m.ChecksumTrailer = "x-amz-checksum-crc32"
+			}
+
+			var hash string
+			_, _, err = m.HandleBuild(SetPayloadHash(context.Background(), "abc123"),
+				middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					hash = GetPayloadHash(ctx)
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectHash, hash; e != a {
+				t.Errorf("expect %v payload hash, got %v", e, a)
+			}
+		})
+	}
+}
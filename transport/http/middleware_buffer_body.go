@@ -0,0 +1,120 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// NewResponseBufferPool returns a sync.Pool of *bytes.Buffer for the
+// BufferResponseBody middleware's Pool.
+func NewResponseBufferPool() *sync.Pool {
+	return &sync.Pool{
+		New: func() interface{} {
+			return new(bytes.Buffer)
+		},
+	}
+}
+
+// BufferResponseBody is a deserialize middleware that reads non-streaming
+// response bodies into a buffer borrowed from the Pool, instead of the
+// deserializer allocating a buffer for each response, (e.g. for clients
+// receiving a high volume of small responses). The response's body is
+// replaced by a reader of the buffer, and the buffer is reset and returned to
+// the Pool when the body is closed, (e.g. by the DrainBody middleware, once
+// the response has been deserialized).
+//
+// The bodies of operations whose output streams the body, see
+// WithStreamingOutput, are not buffered.
+//
+// The middleware should be added after the operation's deserializer, so it
+// handles the response first.
+type BufferResponseBody struct {
+	// Pool of *bytes.Buffer response bodies are read into. Required, see
+	// NewResponseBufferPool.
+	Pool *sync.Pool
+}
+
+// ID returns the middleware identifier.
+func (m *BufferResponseBody) ID() string {
+	return "BufferResponseBody"
+}
+
+// HandleDeserialize reads the response body into a pooled buffer.
+func (m *BufferResponseBody) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil || m.Pool == nil || IsStreamingOutput(ctx) {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return out, metadata, nil
+	}
+
+	buf, ok := m.Pool.Get().(*bytes.Buffer)
+	if !ok {
+		return out, metadata, fmt.Errorf("expect response buffer pool to return *bytes.Buffer")
+	}
+	buf.Reset()
+	if resp.ContentLength > 0 {
+		buf.Grow(int(resp.ContentLength))
+	}
+
+	_, readErr := buf.ReadFrom(resp.Body)
+	closeErr := resp.Body.Close()
+	if readErr != nil {
+		m.Pool.Put(buf)
+		return out, metadata, fmt.Errorf("failed to read response body, %w", readErr)
+	}
+	if closeErr != nil {
+		m.Pool.Put(buf)
+		return out, metadata, fmt.Errorf("failed to close response body, %w", closeErr)
+	}
+
+	resp.Body = &pooledBody{buf: buf, pool: m.Pool}
+	return out, metadata, nil
+}
+
+// pooledBody is a response body reading from a pooled buffer, returning the
+// buffer to the pool when closed.
+type pooledBody struct {
+	mu   sync.Mutex
+	buf  *bytes.Buffer
+	pool *sync.Pool
+}
+
+func (b *pooledBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.buf == nil {
+		return 0, fmt.Errorf("read of closed response body")
+	}
+	return b.buf.Read(p)
+}
+
+func (b *pooledBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.buf == nil {
+		return nil
+	}
+	b.buf.Reset()
+	b.pool.Put(b.buf)
+	b.buf = nil
+	return nil
+}
+
+var _ io.ReadCloser = (*pooledBody)(nil)
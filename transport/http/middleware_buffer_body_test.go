@@ -0,0 +1,119 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func bufferResponse(ctx context.Context, m *BufferResponseBody, body string) (*Response, error) {
+	out, _, err := m.HandleDeserialize(ctx, middleware.DeserializeInput{},
+		deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			out.RawResponse = &Response{Response: &http.Response{
+				StatusCode:    200,
+				ContentLength: int64(len(body)),
+				Body:          ioutil.NopCloser(strings.NewReader(body)),
+			}}
+			return out, metadata, nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+	return out.RawResponse.(*Response), nil
+}
+
+func TestBufferResponseBody(t *testing.T) {
+	var buffers []*bytes.Buffer
+	pool := &sync.Pool{
+		New: func() interface{} {
+			buf := new(bytes.Buffer)
+			buffers = append(buffers, buf)
+			return buf
+		},
+	}
+	m := &BufferResponseBody{Pool: pool}
+
+	for i, body := range []string{"first response body", "second"} {
+		resp, err := bufferResponse(context.Background(), m, body)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if _, ok := resp.Body.(*pooledBody); !ok {
+			t.Fatalf("expect pooled body, got %T", resp.Body)
+		}
+
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if e, a := body, string(b); e != a {
+			t.Errorf("expect response %d body %q, got %q", i, e, a)
+		}
+		if err := resp.Body.Close(); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+		if err := resp.Body.Close(); err != nil {
+			t.Fatalf("expect no error on second close, got %v", err)
+		}
+		if _, err := resp.Body.Read(make([]byte, 1)); err == nil {
+			t.Errorf("expect error reading closed body")
+		}
+	}
+
+	for _, buf := range buffers {
+		if buf.Len() != 0 {
+			t.Errorf("expect buffer reset when returned to the pool, got %q", buf.String())
+		}
+	}
+
+	// Streaming output bodies are not buffered.
+	resp, err := bufferResponse(WithStreamingOutput(context.Background()), m, "streamed")
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if _, ok := resp.Body.(*pooledBody); ok {
+		t.Errorf("expect streaming body not to be buffered")
+	}
+}
+
+func BenchmarkBufferResponseBody(b *testing.B) {
+	body := strings.Repeat("a", 4*1024)
+	ctx := context.Background()
+
+	b.Run("read all", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r := ioutil.NopCloser(strings.NewReader(body))
+			if _, err := ioutil.ReadAll(r); err != nil {
+				b.Fatal(err)
+			}
+			r.Close()
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		m := &BufferResponseBody{Pool: NewResponseBufferPool()}
+		p := make([]byte, 512)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			resp, err := bufferResponse(ctx, m, body)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for {
+				if _, err := resp.Body.Read(p); err != nil {
+					break
+				}
+			}
+			resp.Body.Close()
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// ChecksumAlgorithm is the name of an algorithm used to compute the checksum
+// of a message body.
+type ChecksumAlgorithm string
+
+// Enumeration values for supported checksum algorithms.
+const (
+	ChecksumAlgorithmCRC32C ChecksumAlgorithm = "CRC32C"
+	ChecksumAlgorithmCRC32  ChecksumAlgorithm = "CRC32"
+	ChecksumAlgorithmSHA1   ChecksumAlgorithm = "SHA1"
+	ChecksumAlgorithmSHA256 ChecksumAlgorithm = "SHA256"
+)
+
+// checksumAlgorithmPriority is the order algorithms are selected in when the
+// response includes checksums for more than one algorithm.
+var checksumAlgorithmPriority = []ChecksumAlgorithm{
+	ChecksumAlgorithmCRC32C,
+	ChecksumAlgorithmCRC32,
+	ChecksumAlgorithmSHA1,
+	ChecksumAlgorithmSHA256,
+}
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+func newChecksumHash(algorithm ChecksumAlgorithm) (hash.Hash, error) {
+	switch algorithm {
+	case ChecksumAlgorithmCRC32C:
+		return crc32.New(crc32cTable), nil
+	case ChecksumAlgorithmCRC32:
+		return crc32.NewIEEE(), nil
+	case ChecksumAlgorithmSHA1:
+		return sha1.New(), nil
+	case ChecksumAlgorithmSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown checksum algorithm %v", algorithm)
+	}
+}
+
+// ChecksumMismatchError provides the error type for when the checksum
+// computed from the response body does not match the checksum returned by
+// the service.
+type ChecksumMismatchError struct {
+	Algorithm ChecksumAlgorithm
+	Expect    string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("response body %v checksum mismatch, expect %v, got %v",
+		e.Algorithm, e.Expect, e.Actual)
+}
+
+// ResponseChecksumValidation is a deserialize middleware that validates the
+// response body against the checksum returned in the response's headers.
+//
+// Headers maps each checksum algorithm to the name of the header the
+// service returns the base64 encoded checksum in, (e.g. x-amz-checksum-crc32).
+// If the response includes checksums for multiple algorithms, only one is
+// validated, preferring CRC32C, CRC32, SHA1, then SHA256.
+//
+// The response body is wrapped so that the checksum is computed as the body
+// is read. A ChecksumMismatchError is returned from the body's Read once the
+// end of the body is reached if the checksums do not match.
+type ResponseChecksumValidation struct {
+	Headers map[ChecksumAlgorithm]string
+}
+
+// ID returns the middleware identifier.
+func (m *ResponseChecksumValidation) ID() string {
+	return "ResponseChecksumValidation"
+}
+
+// HandleDeserialize wraps the response body to validate its checksum as it
+// is read.
+func (m *ResponseChecksumValidation) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+	if resp.Body == nil {
+		return out, metadata, err
+	}
+
+	for _, algorithm := range checksumAlgorithmPriority {
+		header, ok := m.Headers[algorithm]
+		if !ok {
+			continue
+		}
+		expect := resp.Header.Get(header)
+		if len(expect) == 0 {
+			continue
+		}
+
+		h, err := newChecksumHash(algorithm)
+		if err != nil {
+			return out, metadata, err
+		}
+		resp.Body = &checksumValidatingReader{
+			body:      resp.Body,
+			hash:      h,
+			algorithm: algorithm,
+			expect:    expect,
+		}
+		break
+	}
+
+	return out, metadata, err
+}
+
+// checksumValidatingReader computes the checksum of the body as it is read,
+// and compares it to the expected checksum once the body has been read to
+// the end.
+type checksumValidatingReader struct {
+	body      io.ReadCloser
+	hash      hash.Hash
+	algorithm ChecksumAlgorithm
+	expect    string
+}
+
+func (r *checksumValidatingReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if n > 0 {
+		r.hash.Write(p[:n])
+	}
+	if err == io.EOF {
+		actual := base64.StdEncoding.EncodeToString(r.hash.Sum(nil))
+		if actual != r.expect {
+			return n, &ChecksumMismatchError{
+				Algorithm: r.algorithm,
+				Expect:    r.expect,
+				Actual:    actual,
+			}
+		}
+	}
+	return n, err
+}
+
+func (r *checksumValidatingReader) Close() error {
+	return r.body.Close()
+}
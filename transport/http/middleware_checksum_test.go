@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+var testChecksumHeaders = map[ChecksumAlgorithm]string{
+	ChecksumAlgorithmCRC32C: "X-Amz-Checksum-Crc32c",
+	ChecksumAlgorithmCRC32:  "X-Amz-Checksum-Crc32",
+	ChecksumAlgorithmSHA1:   "X-Amz-Checksum-Sha1",
+	ChecksumAlgorithmSHA256: "X-Amz-Checksum-Sha256",
+}
+
+func base64Checksum(h hash.Hash, body string) string {
+	h.Write([]byte(body))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+type countingReader struct {
+	io.Reader
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func TestResponseChecksumValidation(t *testing.T) {
+	const body = "hello world, this is the response body"
+
+	cases := map[string]struct {
+		Header         http.Header
+		ExpectMismatch ChecksumAlgorithm
+	}{
+		"crc32c": {
+			Header: http.Header{
+				"X-Amz-Checksum-Crc32c": []string{base64Checksum(crc32.New(crc32.MakeTable(crc32.Castagnoli)), body)},
+			},
+		},
+		"crc32": {
+			Header: http.Header{
+				"X-Amz-Checksum-Crc32": []string{base64Checksum(crc32.NewIEEE(), body)},
+			},
+		},
+		"sha1": {
+			Header: http.Header{
+				"X-Amz-Checksum-Sha1": []string{base64Checksum(sha1.New(), body)},
+			},
+		},
+		"sha256": {
+			Header: http.Header{
+				"X-Amz-Checksum-Sha256": []string{base64Checksum(sha256.New(), body)},
+			},
+		},
+		"no checksum": {
+			Header: http.Header{},
+		},
+		"mismatch": {
+			Header: http.Header{
+				"X-Amz-Checksum-Sha256": []string{base64Checksum(sha256.New(), "other body")},
+			},
+			ExpectMismatch: ChecksumAlgorithmSHA256,
+		},
+		"priority": {
+			Header: http.Header{
+				"X-Amz-Checksum-Crc32":  []string{base64Checksum(crc32.NewIEEE(), "other body")},
+				"X-Amz-Checksum-Sha256": []string{base64Checksum(sha256.New(), body)},
+			},
+			ExpectMismatch: ChecksumAlgorithmCRC32,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			src := &countingReader{Reader: strings.NewReader(body)}
+
+			m := ResponseChecksumValidation{Headers: testChecksumHeaders}
+			out, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out.RawResponse = &Response{
+						Response: &http.Response{
+							StatusCode: 200,
+							Header:     c.Header,
+							Body:       ioutil.NopCloser(src),
+						},
+					}
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := 0, src.reads; e != a {
+				t.Errorf("expect body not read by middleware, got %v reads", a)
+			}
+
+			resp := out.RawResponse.(*Response)
+			b, err := ioutil.ReadAll(resp.Body)
+			if len(c.ExpectMismatch) != 0 {
+				var mismatchErr *ChecksumMismatchError
+				if !errors.As(err, &mismatchErr) {
+					t.Fatalf("expect checksum mismatch error, got %v", err)
+				}
+				if e, a := c.ExpectMismatch, mismatchErr.Algorithm; e != a {
+					t.Errorf("expect %v algorithm, got %v", e, a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := body, string(b); e != a {
+				t.Errorf("expect %v body, got %v", e, a)
+			}
+			if err := resp.Body.Close(); err != nil {
+				t.Errorf("expect no close error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultRequestMinCompressSizeBytes is the default minimum size, in bytes, a
+// request body must be for it to be compressed.
+const DefaultRequestMinCompressSizeBytes = 10240
+
+// RequestCompression is a build middleware that compresses the request body
+// with gzip when the body is at least MinCompressSizeBytes in size. The
+// Content-Encoding header is updated to include gzip, and the request's
+// content length is set to the size of the compressed body.
+//
+// Streaming bodies, whose length cannot be determined, are only compressed if
+// AllowStreaming is set. Compressed streaming bodies are sent with an unknown
+// content length, (e.g. chunked transfer encoding).
+type RequestCompression struct {
+	// Minimum size in bytes of the request body for it to be compressed.
+	// If zero, DefaultRequestMinCompressSizeBytes is used.
+	MinCompressSizeBytes int64
+
+	// Enables compression of streaming bodies, whose length cannot be
+	// determined.
+	AllowStreaming bool
+}
+
+// ID returns the middleware identifier.
+func (m *RequestCompression) ID() string {
+	return "RequestCompression"
+}
+
+// HandleBuild compresses the request body if it meets the configured
+// requirements.
+func (m *RequestCompression) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	if req.GetStream() == nil {
+		return next.HandleBuild(ctx, in)
+	}
+
+	minSize := m.MinCompressSizeBytes
+	if minSize == 0 {
+		minSize = DefaultRequestMinCompressSizeBytes
+	}
+
+	size, ok, err := req.StreamLength()
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to determine request body length, %w", err)
+	}
+
+	var compressed io.Reader
+	var compressedLen int64 = -1
+	switch {
+	case ok && size < minSize:
+		return next.HandleBuild(ctx, in)
+
+	case ok:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := io.Copy(w, req.GetStream()); err != nil {
+			return out, metadata, fmt.Errorf("failed to compress request body, %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return out, metadata, fmt.Errorf("failed to compress request body, %w", err)
+		}
+		compressed = bytes.NewReader(buf.Bytes())
+		compressedLen = int64(buf.Len())
+
+	case m.AllowStreaming:
+		compressed = newGzipStreamReader(req.GetStream())
+
+	default:
+		return next.HandleBuild(ctx, in)
+	}
+
+	if req, err = req.SetStream(compressed); err != nil {
+		return out, metadata, fmt.Errorf("failed to set compressed request body, %w", err)
+	}
+	req.ContentLength = compressedLen
+	req.Header.Del("Content-Length")
+
+	if v := req.Header.Get("Content-Encoding"); len(v) != 0 {
+		req.Header.Set("Content-Encoding", v+", gzip")
+	} else {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	in.Request = req
+	return next.HandleBuild(ctx, in)
+}
+
+// gzipStreamReader compresses the underlying reader as it is read from,
+// without buffering the full stream.
+type gzipStreamReader struct {
+	src   io.Reader
+	chunk []byte
+	buf   bytes.Buffer
+	w     *gzip.Writer
+	done  bool
+	err   error
+}
+
+func newGzipStreamReader(src io.Reader) *gzipStreamReader {
+	r := &gzipStreamReader{
+		src:   src,
+		chunk: make([]byte, 32*1024),
+	}
+	r.w = gzip.NewWriter(&r.buf)
+	return r
+}
+
+func (r *gzipStreamReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 && !r.done {
+		n, err := r.src.Read(r.chunk)
+		if n > 0 {
+			if _, werr := r.w.Write(r.chunk[:n]); werr != nil {
+				return 0, werr
+			}
+		}
+		if err == io.EOF {
+			r.done = true
+			r.err = r.w.Close()
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	if r.buf.Len() != 0 {
+		return r.buf.Read(p)
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
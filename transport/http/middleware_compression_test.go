@@ -0,0 +1,121 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestRequestCompression(t *testing.T) {
+	largeBody := strings.Repeat("abcdefghij", 100)
+
+	cases := map[string]struct {
+		Middleware       RequestCompression
+		Body             io.Reader
+		ContentEncoding  string
+		ExpectCompressed bool
+		ExpectEncoding   string
+		ExpectLength     bool
+	}{
+		"small body": {
+			Middleware: RequestCompression{MinCompressSizeBytes: 2000},
+			Body:       strings.NewReader(largeBody),
+		},
+		"large body": {
+			Middleware:       RequestCompression{MinCompressSizeBytes: 100},
+			Body:             strings.NewReader(largeBody),
+			ExpectCompressed: true,
+			ExpectEncoding:   "gzip",
+			ExpectLength:     true,
+		},
+		"existing content encoding": {
+			Middleware:       RequestCompression{MinCompressSizeBytes: 100},
+			Body:             strings.NewReader(largeBody),
+			ContentEncoding:  "custom",
+			ExpectCompressed: true,
+			ExpectEncoding:   "custom, gzip",
+			ExpectLength:     true,
+		},
+		"streaming body not allowed": {
+			Middleware: RequestCompression{MinCompressSizeBytes: 100},
+			Body:       ioutil.NopCloser(strings.NewReader(largeBody)),
+		},
+		"streaming body allowed": {
+			Middleware: RequestCompression{
+				MinCompressSizeBytes: 100,
+				AllowStreaming:       true,
+			},
+			Body:             ioutil.NopCloser(strings.NewReader(largeBody)),
+			ExpectCompressed: true,
+			ExpectEncoding:   "gzip",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			if len(c.ContentEncoding) != 0 {
+				req.Header.Set("Content-Encoding", c.ContentEncoding)
+			}
+			req, err := req.SetStream(c.Body)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			var sent *Request
+			_, _, err = c.Middleware.HandleBuild(context.Background(),
+				middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					sent = in.Request.(*Request)
+					return out, metadata, nil
+				}),
+			)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			expectEncoding := c.ContentEncoding
+			if c.ExpectCompressed {
+				expectEncoding = c.ExpectEncoding
+			}
+			if e, a := expectEncoding, sent.Header.Get("Content-Encoding"); e != a {
+				t.Errorf("expect %q content encoding, got %q", e, a)
+			}
+
+			body, err := ioutil.ReadAll(sent.GetStream())
+			if err != nil {
+				t.Fatalf("expect no read error, got %v", err)
+			}
+
+			if c.ExpectCompressed {
+				if c.ExpectLength {
+					if e, a := int64(len(body)), sent.ContentLength; e != a {
+						t.Errorf("expect %v content length, got %v", e, a)
+					}
+				} else if e, a := int64(-1), sent.ContentLength; e != a {
+					t.Errorf("expect %v content length, got %v", e, a)
+				}
+
+				zr, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatalf("expect gzip body, got %v", err)
+				}
+				if body, err = ioutil.ReadAll(zr); err != nil {
+					t.Fatalf("expect no decompress error, got %v", err)
+				}
+			}
+
+			if e, a := largeBody, string(body); e != a {
+				t.Errorf("expect body to match, got %v", a)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+// ETagWildcard is the If-Match and If-None-Match value matching any current
+// representation of the resource.
+const ETagWildcard = "*"
+
+// QuoteETag returns the entity tag quoted as required by conditional request
+// headers, (e.g. abc becomes "abc"). Tags already quoted, weak tags, (e.g.
+// W/"abc"), and the wildcard are returned unchanged.
+func QuoteETag(etag string) string {
+	if etag == ETagWildcard || strings.HasPrefix(etag, `W/"`) ||
+		(len(etag) >= 2 && strings.HasPrefix(etag, `"`) && strings.HasSuffix(etag, `"`)) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// FormatETagList returns the comma separated list of the quoted entity tags,
+// see QuoteETag. If any of the tags is the wildcard, only the wildcard is
+// returned, as it cannot be combined with other tags.
+func FormatETagList(etags []string) string {
+	quoted := make([]string, 0, len(etags))
+	for _, etag := range etags {
+		if etag == ETagWildcard {
+			return ETagWildcard
+		}
+		quoted = append(quoted, QuoteETag(etag))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// ConditionalHeaders is a build middleware that sets the conditional request
+// headers of the request, (e.g. for optimistic concurrency, only updating a
+// resource if its ETag has not changed). Conditions not set are not added to
+// the request.
+type ConditionalHeaders struct {
+	// Entity tags of the If-Match and If-None-Match headers. Tags are quoted
+	// if needed, see FormatETagList.
+	IfMatch     []string
+	IfNoneMatch []string
+
+	// Times of the If-Modified-Since and If-Unmodified-Since headers,
+	// formatted as http-date.
+	IfModifiedSince   time.Time
+	IfUnmodifiedSince time.Time
+}
+
+// AddConditionalHeaders adds the ConditionalHeaders middleware to the end of
+// the stack's Build step.
+func AddConditionalHeaders(stack *middleware.Stack, m *ConditionalHeaders) error {
+	return stack.Build.Add(m, middleware.After)
+}
+
+// ID returns the middleware identifier.
+func (m *ConditionalHeaders) ID() string {
+	return "ConditionalHeaders"
+}
+
+// HandleBuild sets the conditional headers on the request.
+func (m *ConditionalHeaders) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	if len(m.IfMatch) != 0 {
+		req.Header.Set("If-Match", FormatETagList(m.IfMatch))
+	}
+	if len(m.IfNoneMatch) != 0 {
+		req.Header.Set("If-None-Match", FormatETagList(m.IfNoneMatch))
+	}
+	if !m.IfModifiedSince.IsZero() {
+		req.Header.Set("If-Modified-Since", smithytime.FormatHTTPDate(m.IfModifiedSince))
+	}
+	if !m.IfUnmodifiedSince.IsZero() {
+		req.Header.Set("If-Unmodified-Since", smithytime.FormatHTTPDate(m.IfUnmodifiedSince))
+	}
+
+	return next.HandleBuild(ctx, in)
+}
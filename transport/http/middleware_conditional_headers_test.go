@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestQuoteETag(t *testing.T) {
+	cases := map[string]string{
+		`abc`:     `"abc"`,
+		`"abc"`:   `"abc"`,
+		`W/"abc"`: `W/"abc"`,
+		`*`:       `*`,
+		`W/abc`:   `"W/abc"`,
+		``:        `""`,
+	}
+
+	for etag, expect := range cases {
+		if e, a := expect, QuoteETag(etag); e != a {
+			t.Errorf("expect %q quoted as %q, got %q", etag, e, a)
+		}
+	}
+}
+
+func TestConditionalHeaders(t *testing.T) {
+	modified := time.Date(2014, 4, 29, 18, 30, 38, 0, time.UTC)
+
+	cases := map[string]struct {
+		Middleware ConditionalHeaders
+		Expect     http.Header
+	}{
+		"etags quoted": {
+			Middleware: ConditionalHeaders{
+				IfMatch:     []string{"abc", `"def"`},
+				IfNoneMatch: []string{`W/"ghi"`},
+			},
+			Expect: http.Header{
+				"If-Match":      []string{`"abc", "def"`},
+				"If-None-Match": []string{`W/"ghi"`},
+			},
+		},
+		"wildcard": {
+			Middleware: ConditionalHeaders{
+				IfNoneMatch: []string{"abc", ETagWildcard},
+			},
+			Expect: http.Header{
+				"If-None-Match": []string{"*"},
+			},
+		},
+		"times": {
+			Middleware: ConditionalHeaders{
+				IfModifiedSince:   modified,
+				IfUnmodifiedSince: modified.Add(time.Hour),
+			},
+			Expect: http.Header{
+				"If-Modified-Since":   []string{"Tue, 29 Apr 2014 18:30:38 GMT"},
+				"If-Unmodified-Since": []string{"Tue, 29 Apr 2014 19:30:38 GMT"},
+			},
+		},
+		"none": {
+			Expect: http.Header{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+
+			_, _, err := c.Middleware.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, req.Header; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v headers, got %v", e, a)
+			}
+		})
+	}
+}
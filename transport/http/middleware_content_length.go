@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// ComputeContentLength is a build middleware that sets the request's content
+// length from the length of its seekable body, for services that reject
+// requests sent with chunked transfer encoding. The length is the number of
+// bytes from the stream's start offset to its end, as returned by
+// Request.StreamLength. Zero length bodies are sent as http.NoBody by
+// Request.Build, so they are not sent chunked either.
+//
+// Requests with a content length already set, or without a body, are not
+// modified. Returns an error if the body is not seekable, and does not
+// report its length.
+type ComputeContentLength struct{}
+
+// ID returns the middleware identifier.
+func (m *ComputeContentLength) ID() string {
+	return "ComputeContentLength"
+}
+
+// HandleBuild sets the request's content length from its body.
+func (m *ComputeContentLength) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	if req.GetStream() == nil || req.ContentLength > 0 || len(req.Header.Get("Content-Length")) != 0 {
+		return next.HandleBuild(ctx, in)
+	}
+
+	n, ok, err := req.StreamLength()
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to compute request content length, %w", err)
+	}
+	if !ok {
+		return out, metadata, fmt.Errorf("failed to compute request content length, stream %T is not seekable",
+			req.GetStream())
+	}
+	req.ContentLength = n
+
+	return next.HandleBuild(ctx, in)
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestComputeContentLength(t *testing.T) {
+	cases := map[string]struct {
+		Body          io.Reader
+		Offset        int64
+		ContentLength int64
+		Expect        int64
+		ExpectErr     bool
+	}{
+		"seekable": {
+			Body:   bytes.NewReader([]byte("abcdefghij")),
+			Expect: 10,
+		},
+		"seekable not at start": {
+			Body:   bytes.NewReader([]byte("abcdefghij")),
+			Offset: 3,
+			Expect: 7,
+		},
+		"already set": {
+			Body:          bytes.NewReader([]byte("abcdefghij")),
+			ContentLength: 4,
+			Expect:        4,
+		},
+		"empty seekable": {
+			Body:   bytes.NewReader([]byte{}),
+			Expect: 0,
+		},
+		"no body": {},
+		"length": {
+			Body:   bytes.NewBuffer([]byte("abc")),
+			Expect: 3,
+		},
+		"not seekable": {
+			Body:      io.LimitReader(bytes.NewBuffer([]byte("abc")), 3),
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req.ContentLength = c.ContentLength
+			if c.Body != nil {
+				if c.Offset != 0 {
+					c.Body.(io.Seeker).Seek(c.Offset, io.SeekStart)
+				}
+				var err error
+				if req, err = req.SetStream(c.Body); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+			}
+
+			m := ComputeContentLength{}
+			_, _, err := m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, req.ContentLength; e != a {
+				t.Errorf("expect %v content length, got %v", e, a)
+			}
+			if c.Offset != 0 {
+				if pos, _ := c.Body.(io.Seeker).Seek(0, io.SeekCurrent); pos != c.Offset {
+					t.Errorf("expect body position %v restored, got %v", c.Offset, pos)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeContentLength_EmptyBodyNotChunked(t *testing.T) {
+	var transferEncoding []string
+	var contentLength int64 = -1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transferEncoding = r.TransferEncoding
+		contentLength = r.ContentLength
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	stack := middleware.NewStack("empty body", NewStackRequest)
+	stack.Serialize.Add(middleware.SerializeMiddlewareFunc("serialize",
+		func(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (
+			out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+		) {
+			req := in.Request.(*Request)
+			req.Method = "PUT"
+			req.URL, _ = url.Parse(server.URL)
+			if in.Request, err = req.SetStream(bytes.NewReader([]byte{})); err != nil {
+				return out, metadata, err
+			}
+			return next.HandleSerialize(ctx, in)
+		}), middleware.After)
+	stack.Build.Add(&ComputeContentLength{}, middleware.After)
+
+	handler := middleware.DecorateHandler(NewClientHandler(http.DefaultClient), stack)
+	if _, _, err := handler.Handle(context.Background(), struct{}{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if len(transferEncoding) != 0 {
+		t.Errorf("expect no transfer encoding, got %v", transferEncoding)
+	}
+	if e, a := int64(0), contentLength; e != a {
+		t.Errorf("expect %v content length, got %v", e, a)
+	}
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// CtxBody wraps a response body, failing reads with the context's error once
+// the context is canceled. A read blocked on the underlying body when the
+// context is canceled is unblocked by closing the underlying body.
+//
+// Errors of the underlying body are returned unchanged, unless the context
+// has been canceled. The body must be closed to release the goroutine
+// watching the context.
+type CtxBody struct {
+	ctx  context.Context
+	body io.ReadCloser
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewCtxBody returns a CtxBody wrapping body with the context.
+func NewCtxBody(ctx context.Context, body io.ReadCloser) *CtxBody {
+	b := &CtxBody{
+		ctx:  ctx,
+		body: body,
+		done: make(chan struct{}),
+	}
+	if ctx.Done() != nil {
+		go b.watch()
+	}
+	return b
+}
+
+func (b *CtxBody) watch() {
+	select {
+	case <-b.ctx.Done():
+		b.closeBody()
+	case <-b.done:
+	}
+}
+
+// Read reads from the underlying body, returning the context's error if the
+// context has been canceled.
+func (b *CtxBody) Read(p []byte) (int, error) {
+	if err := b.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := b.body.Read(p)
+	if err != nil && err != io.EOF {
+		if ctxErr := b.ctx.Err(); ctxErr != nil {
+			return n, ctxErr
+		}
+	}
+	return n, err
+}
+
+// Close closes the underlying body, and stops watching the context.
+func (b *CtxBody) Close() error {
+	return b.closeBody()
+}
+
+func (b *CtxBody) closeBody() error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		b.closeErr = b.body.Close()
+	})
+	return b.closeErr
+}
+
+// ContextBody is a deserialize middleware that wraps the response body in a
+// CtxBody with the operation's context, so reads of a streaming output's
+// body fail promptly when the context is canceled.
+//
+// The middleware should be added to the stacks of operations with streaming
+// outputs, after the middleware that deserializes the response, so the body
+// is wrapped before it is handed to the operation's output.
+type ContextBody struct{}
+
+// ID returns the middleware identifier.
+func (m *ContextBody) ID() string {
+	return "ContextBody"
+}
+
+// HandleDeserialize wraps the response body in a CtxBody.
+func (m *ContextBody) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+	if resp.Body != nil {
+		resp.Body = NewCtxBody(ctx, resp.Body)
+	}
+
+	return out, metadata, err
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// blockingBody blocks reads until the body is closed.
+type blockingBody struct {
+	closed chan struct{}
+}
+
+func (b *blockingBody) Read(p []byte) (int, error) {
+	<-b.closed
+	return 0, fmt.Errorf("read on closed body")
+}
+
+func (b *blockingBody) Close() error {
+	close(b.closed)
+	return nil
+}
+
+func TestCtxBody_CanceledDuringRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	body := NewCtxBody(ctx, &blockingBody{closed: make(chan struct{})})
+
+	start := time.Now()
+	_, err := body.Read(make([]byte, 10))
+	if e, a := context.Canceled, err; e != a {
+		t.Fatalf("expect %v error, got %v", e, a)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect prompt return on cancel, took %v", elapsed)
+	}
+
+	if _, err = body.Read(make([]byte, 10)); err != context.Canceled {
+		t.Errorf("expect %v error, got %v", context.Canceled, err)
+	}
+	if err = body.Close(); err != nil {
+		t.Errorf("expect no close error, got %v", err)
+	}
+}
+
+func TestCtxBody_ReadError(t *testing.T) {
+	readErr := fmt.Errorf("connection reset")
+	body := NewCtxBody(context.Background(), ioutil.NopCloser(
+		io.MultiReader(strings.NewReader("abc"), errReader{err: readErr})))
+	defer body.Close()
+
+	b, err := ioutil.ReadAll(body)
+	if e, a := readErr, err; e != a {
+		t.Errorf("expect %v error, got %v", e, a)
+	}
+	if e, a := "abc", string(b); e != a {
+		t.Errorf("expect %q read, got %q", e, a)
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestContextBody(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := ContextBody{}
+	out, _, err := m.HandleDeserialize(ctx, middleware.DeserializeInput{},
+		deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			out.RawResponse = &Response{Response: &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader("abc")),
+			}}
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	body, ok := out.RawResponse.(*Response).Body.(*CtxBody)
+	if !ok {
+		t.Fatalf("expect body wrapped, got %T", out.RawResponse.(*Response).Body)
+	}
+	cancel()
+	if _, err = body.Read(make([]byte, 10)); err != context.Canceled {
+		t.Errorf("expect %v error, got %v", context.Canceled, err)
+	}
+}
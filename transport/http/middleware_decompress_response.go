@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DecompressResponse is a deserialize middleware that decompresses the
+// response body according to the response's Content-Encoding header. The
+// gzip, deflate, and identity encodings are supported, and may be combined,
+// (e.g. "deflate, gzip"). The body is decompressed as it is read, without
+// buffering the full response.
+//
+// Once the body is wrapped the Content-Encoding and Content-Length headers
+// are removed, and the response's ContentLength is set to unknown, since it
+// no longer describes the decompressed body. Responses with an encoding that
+// is not supported are passed through unmodified.
+//
+// The middleware should be added after the middleware that deserializes the
+// response, so the body is decompressed before it is read.
+type DecompressResponse struct{}
+
+// ID returns the middleware identifier.
+func (m *DecompressResponse) ID() string {
+	return "DecompressResponse"
+}
+
+// HandleDeserialize wraps the response body with the decoders of its
+// content encodings.
+func (m *DecompressResponse) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil && out.RawResponse == nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+
+	contentEncoding := resp.Header.Get("Content-Encoding")
+	if len(contentEncoding) == 0 || resp.Body == nil {
+		return out, metadata, err
+	}
+
+	encodings := strings.Split(contentEncoding, ",")
+	for i, encoding := range encodings {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		switch encoding {
+		case "gzip", "x-gzip", "deflate", "identity":
+		default:
+			middleware.GetLogger(ctx).Logf(logging.Debug,
+				"response content encoding %q not supported, body not decompressed", encoding)
+			return out, metadata, err
+		}
+		encodings[i] = encoding
+	}
+
+	// Encodings are listed in the order they were applied, and are decoded
+	// in reverse.
+	for i := len(encodings) - 1; i >= 0; i-- {
+		if encodings[i] == "identity" {
+			continue
+		}
+		resp.Body = &decompressBody{
+			encoding: encodings[i],
+			raw:      resp.Body,
+		}
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return out, metadata, err
+}
+
+// decompressBody decodes the underlying body as it is read. The decoder is
+// created on the first read, so reading the encoding's header does not block
+// the middleware.
+type decompressBody struct {
+	encoding string
+	raw      io.ReadCloser
+	decoder  io.ReadCloser
+	err      error
+}
+
+func (b *decompressBody) Read(p []byte) (int, error) {
+	if b.decoder == nil && b.err == nil {
+		b.decoder, b.err = newContentDecoder(b.encoding, b.raw)
+		// An empty body, (e.g. of a 204 or HEAD response), has no encoding
+		// header to read, and is the end of the body.
+		if b.err != nil && b.err != io.EOF {
+			b.err = fmt.Errorf("failed to decompress %s response body, %w", b.encoding, b.err)
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.decoder.Read(p)
+}
+
+func (b *decompressBody) Close() error {
+	if b.decoder != nil {
+		b.decoder.Close()
+	}
+	return b.raw.Close()
+}
+
+func newContentDecoder(encoding string, r io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr, nil
+	case "deflate":
+		// The deflate content encoding is zlib wrapped, but some services
+		// send raw deflate data without the zlib header.
+		br := bufio.NewReader(r)
+		header, err := br.Peek(2)
+		if err == io.EOF && len(header) != 0 {
+			return nil, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return nil, err
+		}
+		if isZlibHeader(header) {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
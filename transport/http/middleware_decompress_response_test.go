@@ -0,0 +1,200 @@
+package http
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestDecompressResponse(t *testing.T) {
+	const content = "hello world, hello world, hello world"
+
+	gzipped := func(b []byte) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write(b)
+		w.Close()
+		return buf.Bytes()
+	}
+	zlibbed := func(b []byte) []byte {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(b)
+		w.Close()
+		return buf.Bytes()
+	}
+	deflated := func(b []byte) []byte {
+		var buf bytes.Buffer
+		w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+		w.Write(b)
+		w.Close()
+		return buf.Bytes()
+	}
+
+	cases := map[string]struct {
+		ContentEncoding string
+		Body            []byte
+		ExpectBody      string
+		ExpectEncoding  string
+	}{
+		"gzip": {
+			ContentEncoding: "gzip",
+			Body:            gzipped([]byte(content)),
+			ExpectBody:      content,
+		},
+		"deflate": {
+			ContentEncoding: "deflate",
+			Body:            zlibbed([]byte(content)),
+			ExpectBody:      content,
+		},
+		"raw deflate": {
+			ContentEncoding: "Deflate",
+			Body:            deflated([]byte(content)),
+			ExpectBody:      content,
+		},
+		"identity": {
+			ContentEncoding: "identity",
+			Body:            []byte(content),
+			ExpectBody:      content,
+		},
+		"multiple encodings": {
+			ContentEncoding: "deflate, gzip",
+			Body:            gzipped(zlibbed([]byte(content))),
+			ExpectBody:      content,
+		},
+		"unknown encoding": {
+			ContentEncoding: "br",
+			Body:            []byte("compressed"),
+			ExpectBody:      "compressed",
+			ExpectEncoding:  "br",
+		},
+		"no encoding": {
+			Body:       []byte(content),
+			ExpectBody: content,
+		},
+		"empty gzip": {
+			ContentEncoding: "gzip",
+		},
+		"empty deflate": {
+			ContentEncoding: "deflate",
+		},
+		"empty multiple encodings": {
+			ContentEncoding: "deflate, gzip",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := DecompressResponse{}
+			out, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					resp := &http.Response{
+						StatusCode:    200,
+						Header:        http.Header{},
+						ContentLength: int64(len(c.Body)),
+						Body:          ioutil.NopCloser(bytes.NewReader(c.Body)),
+					}
+					if len(c.ContentEncoding) != 0 {
+						resp.Header.Set("Content-Encoding", c.ContentEncoding)
+					}
+					out.RawResponse = &Response{Response: resp}
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			resp := out.RawResponse.(*Response)
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("expect no read error, got %v", err)
+			}
+			if e, a := c.ExpectBody, string(body); e != a {
+				t.Errorf("expect %q body, got %q", e, a)
+			}
+			if e, a := c.ExpectEncoding, resp.Header.Get("Content-Encoding"); e != a {
+				t.Errorf("expect %q content encoding, got %q", e, a)
+			}
+			if err := resp.Body.Close(); err != nil {
+				t.Errorf("expect no close error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecompressResponse_Truncated(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			m := DecompressResponse{}
+			out, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					resp := &http.Response{
+						StatusCode: 200,
+						Header:     http.Header{"Content-Encoding": []string{encoding}},
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte{0x1f})),
+					}
+					out.RawResponse = &Response{Response: resp}
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			_, err = ioutil.ReadAll(out.RawResponse.(*Response).Body)
+			if err == nil {
+				t.Fatalf("expect error reading truncated body, got none")
+			}
+		})
+	}
+}
+
+func TestDecompressResponse_Streaming(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	m := DecompressResponse{}
+	out, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+		deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			// Nothing is written to the body until after the middleware
+			// returns, so wrapping the body must not read from it.
+			out.RawResponse = &Response{Response: &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+				Body:       pr,
+			}}
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	go func() {
+		w := gzip.NewWriter(pw)
+		w.Write([]byte(strings.Repeat("a", 1024)))
+		w.Close()
+		pw.Close()
+	}()
+
+	body, err := ioutil.ReadAll(out.RawResponse.(*Response).Body)
+	if err != nil {
+		t.Fatalf("expect no read error, got %v", err)
+	}
+	if e, a := 1024, len(body); e != a {
+		t.Errorf("expect %v bytes, got %v", e, a)
+	}
+}
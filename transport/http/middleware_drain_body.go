@@ -0,0 +1,140 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultMaxDrainBytes is the default maximum number of remaining response
+// body bytes the DrainBody middleware will read before closing the body.
+const DefaultMaxDrainBytes int64 = 4 * 1024
+
+type streamingOutputKey struct{}
+
+// SetStreamingOutputMetadata marks in the metadata that the response body
+// has been handed to the operation's output, and is owned by the caller.
+// Deserializers of operations with streaming outputs must set this so the
+// DrainBody middleware does not close the body.
+func SetStreamingOutputMetadata(metadata *middleware.Metadata) {
+	metadata.Set(streamingOutputKey{}, true)
+}
+
+// IsStreamingOutputMetadata returns if the metadata marks the response body
+// as owned by the operation's output.
+func IsStreamingOutputMetadata(metadata middleware.MetadataReader) bool {
+	v, _ := metadata.Get(streamingOutputKey{}).(bool)
+	return v
+}
+
+type streamingOutputContextKey struct{}
+
+// WithStreamingOutput returns a context marking that the caller takes
+// ownership of the operation's response body, (e.g. to stream it). The
+// DrainBody middleware does not drain or close the body of a successful
+// operation with the context, and the caller must Close the body once done
+// reading it, otherwise the underlying connection is leaked. The body of a
+// failed operation is still drained and closed.
+func WithStreamingOutput(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamingOutputContextKey{}, true)
+}
+
+// IsStreamingOutput returns if the context marks the response body as owned
+// by the caller.
+func IsStreamingOutput(ctx context.Context) bool {
+	v, _ := ctx.Value(streamingOutputContextKey{}).(bool)
+	return v
+}
+
+type responseBodyCloseErrorKey struct{}
+
+// ResponseBodyCloseError is the error returned by the DrainBody middleware
+// when the response body fails to close, and FailOnCloseError is set.
+type ResponseBodyCloseError struct {
+	Err error
+}
+
+// Error returns the error message.
+func (e *ResponseBodyCloseError) Error() string {
+	return fmt.Sprintf("failed to close response body, %v", e.Err)
+}
+
+// Unwrap returns the underlying close error.
+func (e *ResponseBodyCloseError) Unwrap() error { return e.Err }
+
+// GetResponseBodyCloseError returns the error the response body failed to
+// close with, recorded in the metadata by the DrainBody middleware.
+func GetResponseBodyCloseError(metadata middleware.MetadataReader) (error, bool) {
+	v, ok := metadata.Get(responseBodyCloseErrorKey{}).(error)
+	return v, ok
+}
+
+// DrainBody is a deserialize middleware that drains and closes the response
+// body once the response has been deserialized, so that the underlying
+// connection can be reused. At most MaxDrainBytes of the remaining body are
+// read, larger bodies are closed without being drained.
+//
+// The middleware must be added before the middleware that deserializes the
+// response, so that it is invoked after the response has been deserialized.
+// Responses whose body is owned by a streaming output, marked with
+// SetStreamingOutputMetadata, or by the caller of a successful operation
+// with a WithStreamingOutput context, are not modified.
+//
+// An error closing the body is recorded in the operation's metadata, see
+// GetResponseBodyCloseError, and logged at debug. If FailOnCloseError is set
+// the close error is instead returned as a ResponseBodyCloseError, unless
+// the operation already failed with another error.
+type DrainBody struct {
+	// Maximum number of remaining body bytes to read. Defaults to
+	// DefaultMaxDrainBytes.
+	MaxDrainBytes int64
+
+	// Return the error closing the response body as the operation's error.
+	FailOnCloseError bool
+}
+
+// ID returns the middleware identifier.
+func (m *DrainBody) ID() string {
+	return "DrainBody"
+}
+
+// HandleDeserialize drains and closes the response body after the response
+// has been deserialized.
+func (m *DrainBody) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok || resp == nil || resp.Response == nil || resp.Body == nil || IsStreamingOutputMetadata(metadata) {
+		return out, metadata, err
+	}
+	if err == nil && IsStreamingOutput(ctx) {
+		return out, metadata, err
+	}
+
+	limit := m.MaxDrainBytes
+	if limit <= 0 {
+		limit = DefaultMaxDrainBytes
+	}
+
+	logger := middleware.GetLogger(ctx)
+	if _, drainErr := io.CopyN(ioutil.Discard, resp.Body, limit); drainErr != nil && drainErr != io.EOF {
+		logger.Logf(logging.Debug, "failed to drain response body, %v", drainErr)
+	}
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		metadata.Set(responseBodyCloseErrorKey{}, closeErr)
+		if err == nil && m.FailOnCloseError {
+			return out, metadata, &ResponseBodyCloseError{Err: closeErr}
+		}
+		logger.Logf(logging.Debug, "failed to close response body, %v", closeErr)
+	}
+
+	return out, metadata, err
+}
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type drainTrackingBody struct {
+	r        io.Reader
+	read     int
+	closed   bool
+	closeErr error
+}
+
+func (b *drainTrackingBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.read += n
+	return n, err
+}
+
+func (b *drainTrackingBody) Close() error {
+	b.closed = true
+	return b.closeErr
+}
+
+func TestDrainBody(t *testing.T) {
+	cases := map[string]struct {
+		BodySize      int
+		DeserializeN  int
+		MaxDrainBytes int64
+		Streaming     bool
+		ExpectRead    int
+		ExpectClosed  bool
+	}{
+		"drained remaining body": {
+			BodySize:     100,
+			DeserializeN: 10,
+			ExpectRead:   100,
+			ExpectClosed: true,
+		},
+		"drain capped": {
+			BodySize:      100,
+			DeserializeN:  10,
+			MaxDrainBytes: 20,
+			ExpectRead:    30,
+			ExpectClosed:  true,
+		},
+		"streaming output skipped": {
+			BodySize:     100,
+			DeserializeN: 10,
+			Streaming:    true,
+			ExpectRead:   10,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			body := &drainTrackingBody{r: strings.NewReader(strings.Repeat("x", c.BodySize))}
+
+			m := DrainBody{MaxDrainBytes: c.MaxDrainBytes}
+			_, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					// Deserializer reads only part of the body.
+					b := make([]byte, c.DeserializeN)
+					if _, err := io.ReadFull(body, b); err != nil {
+						return out, metadata, err
+					}
+					if c.Streaming {
+						SetStreamingOutputMetadata(&metadata)
+					}
+					out.RawResponse = &Response{Response: &http.Response{StatusCode: 200, Body: body}}
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectRead, body.read; e != a {
+				t.Errorf("expect %v bytes read, got %v", e, a)
+			}
+			if e, a := c.ExpectClosed, body.closed; e != a {
+				t.Errorf("expect body closed %v, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestDrainBody_CloseError(t *testing.T) {
+	closeErr := fmt.Errorf("disk full")
+	deserializeErr := fmt.Errorf("deserialize failed")
+
+	cases := map[string]struct {
+		FailOnCloseError bool
+		DeserializeErr   error
+		ExpectErr        error
+		ExpectCloseErr   bool
+	}{
+		"recorded": {},
+		"promoted": {
+			FailOnCloseError: true,
+			ExpectCloseErr:   true,
+		},
+		"operation error not replaced": {
+			FailOnCloseError: true,
+			DeserializeErr:   deserializeErr,
+			ExpectErr:        deserializeErr,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var logged []string
+			ctx := middleware.SetLogger(context.Background(), logging.LoggerFunc(
+				func(classification logging.Classification, format string, v ...interface{}) {
+					logged = append(logged, fmt.Sprintf(format, v...))
+				}))
+
+			body := &drainTrackingBody{r: strings.NewReader("abc"), closeErr: closeErr}
+
+			m := DrainBody{FailOnCloseError: c.FailOnCloseError}
+			_, metadata, err := m.HandleDeserialize(ctx, middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out.RawResponse = &Response{Response: &http.Response{StatusCode: 200, Body: body}}
+					return out, metadata, c.DeserializeErr
+				}))
+
+			if c.ExpectCloseErr {
+				var closeErrAs *ResponseBodyCloseError
+				if !errors.As(err, &closeErrAs) {
+					t.Fatalf("expect close error, got %v", err)
+				}
+				if e, a := closeErr, closeErrAs.Err; e != a {
+					t.Errorf("expect %v wrapped, got %v", e, a)
+				}
+			} else {
+				if e, a := c.ExpectErr, err; e != a {
+					t.Errorf("expect %v error, got %v", e, a)
+				}
+				if e, a := 1, len(logged); e != a {
+					t.Errorf("expect %v logged, got %v", e, a)
+				}
+			}
+
+			recorded, ok := GetResponseBodyCloseError(metadata)
+			if !ok {
+				t.Fatalf("expect close error recorded in metadata")
+			}
+			if e, a := closeErr, recorded; e != a {
+				t.Errorf("expect %v recorded, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestDrainBody_StreamingOutputContext(t *testing.T) {
+	cases := map[string]struct {
+		DeserializeErr error
+		ExpectClosed   bool
+	}{
+		"body owned by caller": {},
+		"failed operation": {
+			DeserializeErr: fmt.Errorf("deserialize failed"),
+			ExpectClosed:   true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			body := &drainTrackingBody{r: strings.NewReader("streamed content")}
+
+			m := DrainBody{}
+			out, _, err := m.HandleDeserialize(WithStreamingOutput(context.Background()), middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out.RawResponse = &Response{Response: &http.Response{StatusCode: 200, Body: body}}
+					return out, metadata, c.DeserializeErr
+				}))
+			if e, a := c.DeserializeErr, err; e != a {
+				t.Fatalf("expect %v error, got %v", e, a)
+			}
+			if e, a := c.ExpectClosed, body.closed; e != a {
+				t.Fatalf("expect body closed %v, got %v", e, a)
+			}
+			if c.ExpectClosed {
+				return
+			}
+
+			b, err := ioutil.ReadAll(out.RawResponse.(*Response).Body)
+			if err != nil {
+				t.Fatalf("expect no read error, got %v", err)
+			}
+			if e, a := "streamed content", string(b); e != a {
+				t.Errorf("expect %q read by caller, got %q", e, a)
+			}
+			if err := out.RawResponse.(*Response).Body.Close(); err != nil {
+				t.Errorf("expect no close error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDrainBody_NoHTTPResponse(t *testing.T) {
+	var m DrainBody
+	_, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+		deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			out.RawResponse = &Response{}
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/endpoints"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type resolvedEndpointKey struct{}
+
+// GetResolvedEndpoint returns the endpoint resolved by the ResolveEndpoint
+// middleware set on the context, and if an endpoint was resolved. Allows
+// later middleware, (e.g. signing), to use the endpoint's properties.
+func GetResolvedEndpoint(ctx context.Context) (endpoints.Endpoint, bool) {
+	v, ok := ctx.Value(resolvedEndpointKey{}).(endpoints.Endpoint)
+	return v, ok
+}
+
+// ResolveEndpoint is a serialize middleware that resolves the operation's
+// endpoint, and applies it to the request. The request URL's scheme and
+// host are replaced with the endpoint's, and the operation's path is joined
+// to endpoint's base path. The endpoint's headers are merged into the
+// request's headers.
+//
+// The endpoint's signing name and region properties, if set, are set on the
+// context, see auth.WithSigningName and auth.WithSigningRegion.
+//
+// The middleware must be added after the middleware serializing the
+// operation's path, so the path has been set before it is joined.
+type ResolveEndpoint struct {
+	Resolver endpoints.EndpointResolverV2
+
+	// Returns the endpoint parameters for the operation's input. If nil, the
+	// input parameters are passed to the resolver.
+	ParamsFunc func(ctx context.Context, input interface{}) (interface{}, error)
+}
+
+// ID returns the middleware identifier.
+func (m *ResolveEndpoint) ID() string {
+	return "ResolveEndpointV2"
+}
+
+// HandleSerialize resolves the endpoint and applies it to the request.
+func (m *ResolveEndpoint) HandleSerialize(
+	ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler,
+) (
+	out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+	if m.Resolver == nil {
+		return out, metadata, fmt.Errorf("endpoint resolver not set")
+	}
+
+	params := in.Parameters
+	if m.ParamsFunc != nil {
+		if params, err = m.ParamsFunc(ctx, in.Parameters); err != nil {
+			return out, metadata, fmt.Errorf("failed to get endpoint parameters, %w", err)
+		}
+	}
+
+	endpoint, err := m.Resolver.ResolveEndpoint(ctx, params)
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to resolve endpoint, %w", err)
+	}
+
+	if req.URL == nil {
+		req.URL = &url.URL{}
+	}
+	applyEndpointURL(req.URL, &endpoint.URI)
+
+	for k, vs := range endpoint.Headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	if v, ok := endpoint.Properties[endpoints.SigningNameProperty].(string); ok && len(v) != 0 {
+		ctx = auth.WithSigningName(ctx, v)
+	}
+	if v, ok := endpoint.Properties[endpoints.SigningRegionProperty].(string); ok && len(v) != 0 {
+		ctx = auth.WithSigningRegion(ctx, v)
+	}
+
+	ctx = context.WithValue(ctx, resolvedEndpointKey{}, endpoint)
+	return next.HandleSerialize(ctx, in)
+}
+
+// applyEndpointURL rewrites the request URL with the endpoint's scheme and
+// host, joining the endpoint's base path and the request's path. Query
+// values of the endpoint are added to the request's query string.
+func applyEndpointURL(u *url.URL, endpoint *url.URL) {
+	u.Scheme = endpoint.Scheme
+	u.Host = endpoint.Host
+	if endpoint.User != nil {
+		u.User = endpoint.User
+	}
+
+	if len(endpoint.Path) != 0 {
+		rawPath := len(endpoint.RawPath) != 0 || len(u.RawPath) != 0
+		escaped := u.EscapedPath()
+
+		u.Path = joinURLPath(endpoint.Path, u.Path)
+		if rawPath {
+			u.RawPath = joinURLPath(endpoint.EscapedPath(), escaped)
+		}
+	}
+
+	if len(endpoint.RawQuery) != 0 {
+		if len(u.RawQuery) != 0 {
+			u.RawQuery = endpoint.RawQuery + "&" + u.RawQuery
+		} else {
+			u.RawQuery = endpoint.RawQuery
+		}
+	}
+}
+
+// joinURLPath joins the base path and path with a single slash, preserving
+// a trailing slash of the path.
+func joinURLPath(base, path string) string {
+	if len(path) == 0 || path == "/" {
+		if !strings.HasSuffix(base, "/") && path == "/" {
+			return base + "/"
+		}
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
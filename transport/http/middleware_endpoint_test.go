@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/endpoints"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestResolveEndpoint_URL(t *testing.T) {
+	cases := map[string]struct {
+		Endpoint   string
+		RequestURL string
+		ExpectURL  string
+		ExpectPath string
+	}{
+		"no base path": {
+			Endpoint:   "https://example.com",
+			RequestURL: "http://localhost/bucket/key?x-id=GetObject",
+			ExpectURL:  "https://example.com/bucket/key?x-id=GetObject",
+		},
+		"base path": {
+			Endpoint:   "https://example.com/base",
+			RequestURL: "http://localhost/bucket/key",
+			ExpectURL:  "https://example.com/base/bucket/key",
+		},
+		"base path trailing slash": {
+			Endpoint:   "https://example.com/base/",
+			RequestURL: "http://localhost/bucket/key",
+			ExpectURL:  "https://example.com/base/bucket/key",
+		},
+		"base path root operation path": {
+			Endpoint:   "https://example.com/base",
+			RequestURL: "http://localhost/",
+			ExpectURL:  "https://example.com/base/",
+		},
+		"base path empty operation path": {
+			Endpoint:   "https://example.com/base",
+			RequestURL: "http://localhost",
+			ExpectURL:  "https://example.com/base",
+		},
+		"escaped operation path": {
+			Endpoint:   "https://example.com/base",
+			RequestURL: "http://localhost/bucket/a%2Fb",
+			ExpectURL:  "https://example.com/base/bucket/a%2Fb",
+			ExpectPath: "/base/bucket/a/b",
+		},
+		"endpoint query": {
+			Endpoint:   "https://example.com:8443/base?a=b",
+			RequestURL: "http://localhost/key?c=d",
+			ExpectURL:  "https://example.com:8443/base/key?a=b&c=d",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			endpointURL, err := url.Parse(c.Endpoint)
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			req := NewStackRequest().(*Request)
+			if req.URL, err = url.Parse(c.RequestURL); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			m := &ResolveEndpoint{
+				Resolver: endpoints.EndpointResolverV2Func(
+					func(ctx context.Context, params interface{}) (endpoints.Endpoint, error) {
+						return endpoints.Endpoint{URI: *endpointURL}, nil
+					}),
+			}
+			_, _, err = m.HandleSerialize(context.Background(),
+				middleware.SerializeInput{Request: req},
+				serializeHandlerFunc(func(ctx context.Context, in middleware.SerializeInput) (
+					out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := c.ExpectURL, req.URL.String(); e != a {
+				t.Errorf("expect %v URL, got %v", e, a)
+			}
+			if len(c.ExpectPath) != 0 {
+				if e, a := c.ExpectPath, req.URL.Path; e != a {
+					t.Errorf("expect %v path, got %v", e, a)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveEndpoint_HeadersAndProperties(t *testing.T) {
+	type params struct{ Region string }
+
+	req := NewStackRequest().(*Request)
+	req.URL, _ = url.Parse("http://localhost/op")
+	req.Header.Set("X-Amz-Target", "Op")
+	req.Header.Set("X-Multi", "a")
+
+	m := &ResolveEndpoint{
+		Resolver: endpoints.EndpointResolverV2Func(
+			func(ctx context.Context, p interface{}) (endpoints.Endpoint, error) {
+				u, _ := url.Parse("https://" + p.(params).Region + ".example.com")
+				return endpoints.Endpoint{
+					URI: *u,
+					Headers: http.Header{
+						"X-Multi":    []string{"b"},
+						"X-Endpoint": []string{"c"},
+					},
+					Properties: map[string]interface{}{
+						endpoints.SigningNameProperty:   "example",
+						endpoints.SigningRegionProperty: "us-west-2",
+					},
+				}, nil
+			}),
+		ParamsFunc: func(ctx context.Context, input interface{}) (interface{}, error) {
+			return params{Region: input.(string)}, nil
+		},
+	}
+
+	var resolved endpoints.Endpoint
+	var signingName, signingRegion string
+	_, _, err := m.HandleSerialize(context.Background(),
+		middleware.SerializeInput{Parameters: "us-west-2", Request: req},
+		serializeHandlerFunc(func(ctx context.Context, in middleware.SerializeInput) (
+			out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+		) {
+			resolved, _ = GetResolvedEndpoint(ctx)
+			signingName, signingRegion = auth.GetSigningName(ctx), auth.GetSigningRegion(ctx)
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "https://us-west-2.example.com/op", req.URL.String(); e != a {
+		t.Errorf("expect %v URL, got %v", e, a)
+	}
+	expectHeader := http.Header{
+		"X-Amz-Target": []string{"Op"},
+		"X-Multi":      []string{"a", "b"},
+		"X-Endpoint":   []string{"c"},
+	}
+	if e, a := expectHeader, req.Header; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v headers, got %v", e, a)
+	}
+	if e, a := "example", resolved.Properties[endpoints.SigningNameProperty]; e != a {
+		t.Errorf("expect %v signing name property, got %v", e, a)
+	}
+	if e, a := "example", signingName; e != a {
+		t.Errorf("expect %v signing name, got %v", e, a)
+	}
+	if e, a := "us-west-2", signingRegion; e != a {
+		t.Errorf("expect %v signing region, got %v", e, a)
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	smithyio "github.com/awslabs/smithy-go/io"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultMaxErrorBodyBytes is the default maximum number of bytes of an error
+// response body that will be read.
+const DefaultMaxErrorBodyBytes int64 = 1024 * 1024
+
+// ErrorResponseBodyLimit is a deserialize middleware that limits how much of
+// an error response's body is read, so that an unexpectedly large error
+// response is not buffered in full when deserializing the error. Responses
+// with a successful status code, see IsSuccessStatus, are not modified.
+//
+// The middleware must be added after the middleware that deserializes the
+// error response, so the body is wrapped before it is read. Deserializers
+// should use IsResponseBodyTruncated to set GenericAPIError's BodyTruncated
+// once the body has been read.
+type ErrorResponseBodyLimit struct {
+	// Maximum number of bytes of the error response body to read. Defaults
+	// to DefaultMaxErrorBodyBytes.
+	MaxErrorBodyBytes int64
+}
+
+// ID returns the middleware identifier.
+func (m *ErrorResponseBodyLimit) ID() string {
+	return "ErrorResponseBodyLimit"
+}
+
+// HandleDeserialize wraps the body of error responses in a limited reader.
+func (m *ErrorResponseBodyLimit) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+	if resp.Body == nil || IsSuccessStatus(ctx, resp.StatusCode) {
+		return out, metadata, err
+	}
+
+	limit := m.MaxErrorBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxErrorBodyBytes
+	}
+	resp.Body = smithyio.NewLimitedReadCloser(resp.Body, limit)
+
+	return out, metadata, err
+}
+
+// IsResponseBodyTruncated returns if the response's body was limited by the
+// ErrorResponseBodyLimit middleware, and was larger than the limit. Only
+// valid once the body has been read to the end.
+func IsResponseBodyTruncated(resp *Response) bool {
+	body, ok := resp.Body.(*smithyio.LimitedReadCloser)
+	return ok && body.Truncated()
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestErrorResponseBodyLimit(t *testing.T) {
+	largeBody := strings.Repeat("x", 100)
+
+	cases := map[string]struct {
+		StatusCode      int
+		Predicate       SuccessStatusPredicate
+		Body            string
+		ExpectBody      string
+		ExpectTruncated bool
+		ExpectAPIError  bool
+	}{
+		"success not limited": {
+			StatusCode: 200,
+			Body:       largeBody,
+			ExpectBody: largeBody,
+		},
+		"error over limit": {
+			StatusCode:      500,
+			Body:            largeBody,
+			ExpectBody:      largeBody[:10],
+			ExpectTruncated: true,
+			ExpectAPIError:  true,
+		},
+		"error under limit": {
+			StatusCode:     400,
+			Body:           "short",
+			ExpectBody:     "short",
+			ExpectAPIError: true,
+		},
+		"not modified error": {
+			StatusCode:      304,
+			Body:            largeBody,
+			ExpectBody:      largeBody[:10],
+			ExpectTruncated: true,
+			ExpectAPIError:  true,
+		},
+		"not modified success predicate": {
+			StatusCode: 304,
+			Predicate: func(code int) bool {
+				return DefaultSuccessStatus(code) || code == 304
+			},
+			Body:       largeBody,
+			ExpectBody: largeBody,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stack := middleware.NewStack("limit", NewStackRequest)
+
+			var readBody string
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+				func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out, metadata, err = next.HandleDeserialize(ctx, in)
+					if err != nil {
+						return out, metadata, err
+					}
+
+					resp := out.RawResponse.(*Response)
+					b, err := ioutil.ReadAll(resp.Body)
+					if err != nil {
+						return out, metadata, err
+					}
+					readBody = string(b)
+
+					if !IsSuccessStatus(ctx, resp.StatusCode) {
+						return out, metadata, &smithy.GenericAPIError{
+							Code:          "SomeError",
+							Message:       readBody,
+							BodyTruncated: IsResponseBodyTruncated(resp),
+						}
+					}
+					return out, metadata, nil
+				}), middleware.After)
+			stack.Deserialize.Add(&ErrorResponseBodyLimit{MaxErrorBodyBytes: 10}, middleware.After)
+
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					return &Response{
+						Response: &http.Response{
+							StatusCode: c.StatusCode,
+							Body:       ioutil.NopCloser(strings.NewReader(c.Body)),
+						},
+					}, middleware.Metadata{}, nil
+				}), stack)
+
+			ctx := context.Background()
+			if c.Predicate != nil {
+				ctx = WithSuccessStatusPredicate(ctx, c.Predicate)
+			}
+			_, _, err := handler.Handle(ctx, struct{}{})
+
+			if e, a := c.ExpectBody, readBody; e != a {
+				t.Errorf("expect %q body, got %q", e, a)
+			}
+
+			var apiErr *smithy.GenericAPIError
+			if e, a := c.ExpectAPIError, errors.As(err, &apiErr); e != a {
+				t.Fatalf("expect API error %v, got %v", e, err)
+			}
+			if apiErr != nil {
+				if e, a := c.ExpectTruncated, apiErr.BodyTruncated; e != a {
+					t.Errorf("expect truncated %v, got %v", e, a)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"errors"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// ErrorFaultFromStatusCode is a deserialize middleware that populates the
+// fault of a GenericAPIError deserialized from the response when the error
+// response body did not carry one. The fault is derived from the HTTP
+// status code of the response with smithy.FaultFromHTTPStatusCode.
+//
+// The middleware should be added before the middleware that deserializes the
+// error response, so that it is invoked after the response has been
+// deserialized, and observes the deserialized error.
+type ErrorFaultFromStatusCode struct{}
+
+// ID returns the middleware identifier.
+func (m *ErrorFaultFromStatusCode) ID() string {
+	return "ErrorFaultFromStatusCode"
+}
+
+// HandleDeserialize sets the fault of the returned GenericAPIError if unset.
+func (m *ErrorFaultFromStatusCode) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err == nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok || resp.Response == nil {
+		return out, metadata, err
+	}
+
+	var apiErr *smithy.GenericAPIError
+	if errors.As(err, &apiErr) && apiErr.Fault == smithy.FaultUnknown {
+		apiErr.Fault = smithy.FaultFromHTTPStatusCode(resp.StatusCode)
+	}
+
+	return out, metadata, err
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestErrorFaultFromStatusCode(t *testing.T) {
+	cases := map[string]struct {
+		StatusCode  int
+		Fault       smithy.ErrorFault
+		ExpectFault smithy.ErrorFault
+	}{
+		"client fault": {
+			StatusCode:  404,
+			ExpectFault: smithy.FaultClient,
+		},
+		"server fault": {
+			StatusCode:  503,
+			ExpectFault: smithy.FaultServer,
+		},
+		"fault from body": {
+			StatusCode:  500,
+			Fault:       smithy.FaultClient,
+			ExpectFault: smithy.FaultClient,
+		},
+		"unknown status code": {
+			StatusCode:  302,
+			ExpectFault: smithy.FaultUnknown,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stack := middleware.NewStack("error fault", NewStackRequest)
+			stack.Deserialize.Add(&ErrorFaultFromStatusCode{}, middleware.After)
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+				func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out, metadata, err = next.HandleDeserialize(ctx, in)
+					if err != nil {
+						return out, metadata, err
+					}
+					return out, metadata, &smithy.GenericAPIError{
+						Code:  "SomeError",
+						Fault: c.Fault,
+					}
+				}), middleware.After)
+
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					return &Response{
+						Response: &http.Response{StatusCode: c.StatusCode},
+					}, middleware.Metadata{}, nil
+				}), stack)
+			_, _, err := handler.Handle(context.Background(), struct{}{})
+
+			var apiErr smithy.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expect API error, got %v", err)
+			}
+			if e, a := c.ExpectFault, apiErr.ErrorFault(); e != a {
+				t.Errorf("expect %v fault, got %v", e, a)
+			}
+		})
+	}
+}
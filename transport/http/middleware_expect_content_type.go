@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// ContentTypeMismatchError is returned by the ExpectContentType middleware
+// when the response's Content-Type is not one of the protocol's, (e.g. an
+// HTML page returned by a proxy the request was misrouted to). The response's
+// body is not deserialized.
+type ContentTypeMismatchError struct {
+	Response    *Response
+	ContentType string
+	Allowed     []string
+}
+
+// HTTPResponse returns the HTTP response rejected.
+func (e *ContentTypeMismatchError) HTTPResponse() *Response { return e.Response }
+
+// HTTPStatusCode returns the status code of the HTTP response rejected.
+func (e *ContentTypeMismatchError) HTTPStatusCode() int {
+	if e.Response == nil || e.Response.Response == nil {
+		return 0
+	}
+	return e.Response.StatusCode
+}
+
+func (e *ContentTypeMismatchError) Error() string {
+	return fmt.Sprintf("unexpected response content type %q, expect one of %s",
+		e.ContentType, strings.Join(e.Allowed, ", "))
+}
+
+// ExpectContentType returns a deserialize middleware that rejects responses
+// whose Content-Type media type is not one of the allowed media types, with
+// a *ContentTypeMismatchError, before the response is deserialized. Media
+// types are compared case insensitively, ignoring their parameters, (e.g.
+// charset).
+//
+// Responses with a 204 No Content status code, or an empty body, are not
+// checked. The middleware must be added to the end of the stack's
+// Deserialize step, after the protocol's deserializer, so it handles the
+// response first.
+func ExpectContentType(allowed ...string) middleware.DeserializeMiddleware {
+	return middleware.DeserializeMiddlewareFunc("ExpectContentType", func(
+		ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+	) (
+		out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+	) {
+		out, metadata, err = next.HandleDeserialize(ctx, in)
+		if err != nil {
+			return out, metadata, err
+		}
+
+		resp, ok := out.RawResponse.(*Response)
+		if !ok {
+			return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+		}
+		if resp.StatusCode == http.StatusNoContent || resp.ContentLength == 0 ||
+			resp.Body == nil || resp.Body == http.NoBody {
+			return out, metadata, nil
+		}
+
+		contentType := resp.Header.Get("Content-Type")
+		mediaType, _, parseErr := mime.ParseMediaType(contentType)
+		if parseErr == nil {
+			for _, v := range allowed {
+				if strings.EqualFold(mediaType, v) {
+					return out, metadata, nil
+				}
+			}
+		}
+
+		return out, metadata, &ContentTypeMismatchError{
+			Response:    resp,
+			ContentType: contentType,
+			Allowed:     allowed,
+		}
+	})
+}
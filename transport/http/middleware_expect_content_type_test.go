@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestExpectContentType(t *testing.T) {
+	cases := map[string]struct {
+		StatusCode  int
+		ContentType string
+		Body        string
+		ExpectErr   bool
+	}{
+		"matching": {
+			StatusCode:  200,
+			ContentType: "application/json",
+			Body:        `{}`,
+		},
+		"matching with parameters": {
+			StatusCode:  200,
+			ContentType: "Application/JSON; charset=utf-8",
+			Body:        `{}`,
+		},
+		"mismatch": {
+			StatusCode:  200,
+			ContentType: "text/html",
+			Body:        `<html>login</html>`,
+			ExpectErr:   true,
+		},
+		"missing content type": {
+			StatusCode: 200,
+			Body:       `{}`,
+			ExpectErr:  true,
+		},
+		"no content": {
+			StatusCode:  204,
+			ContentType: "text/html",
+		},
+		"empty body": {
+			StatusCode:  200,
+			ContentType: "text/html",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := ExpectContentType("application/json", "application/x-amz-json-1.1")
+			_, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					resp := &http.Response{
+						StatusCode:    c.StatusCode,
+						Header:        http.Header{},
+						ContentLength: int64(len(c.Body)),
+						Body:          http.NoBody,
+					}
+					if len(c.ContentType) != 0 {
+						resp.Header.Set("Content-Type", c.ContentType)
+					}
+					if len(c.Body) != 0 {
+						resp.Body = ioutil.NopCloser(strings.NewReader(c.Body))
+					}
+					out.RawResponse = &Response{Response: resp}
+					return out, metadata, nil
+				}))
+
+			if !c.ExpectErr {
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				return
+			}
+			var mismatchErr *ContentTypeMismatchError
+			if !errors.As(err, &mismatchErr) {
+				t.Fatalf("expect content type mismatch error, got %v", err)
+			}
+			if e, a := c.ContentType, mismatchErr.ContentType; e != a {
+				t.Errorf("expect %q content type, got %q", e, a)
+			}
+			if e, a := c.StatusCode, mismatchErr.HTTPStatusCode(); e != a {
+				t.Errorf("expect %v status code, got %v", e, a)
+			}
+		})
+	}
+}
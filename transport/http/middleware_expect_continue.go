@@ -0,0 +1,129 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultExpectContinueThresholdBytes is the default minimum size, in bytes,
+// a request body must be for the ExpectContinue middleware to set the
+// Expect: 100-continue header.
+const DefaultExpectContinueThresholdBytes int64 = 2 * 1024 * 1024
+
+// ExpectContinue is a build middleware that sets the Expect: 100-continue
+// header on requests with a body of at least ContinueHeaderThresholdBytes,
+// or of unknown length. The HTTP client waits for the service to accept the
+// request before sending the body, so the body of a request that is
+// rejected, (e.g. a 403 authorization failure), is not sent. The time the
+// client waits before sending the body regardless is configured by the
+// transport's ExpectContinueTimeout, see BuildableClient's
+// WithExpectContinueTimeout.
+//
+// Use AddExpectContinue to add the middleware to a stack, along with the
+// deserialize middleware that resends requests rejected with a 417
+// Expectation Failed response without the Expect header.
+type ExpectContinue struct {
+	// Minimum size in bytes of the request body for the header to be set.
+	// If zero, DefaultExpectContinueThresholdBytes is used. If negative, the
+	// header is never set.
+	ContinueHeaderThresholdBytes int64
+}
+
+// AddExpectContinue adds the ExpectContinue build middleware to the stack,
+// and the deserialize middleware resending requests rejected with a 417
+// response without the Expect header.
+func AddExpectContinue(stack *middleware.Stack, m *ExpectContinue) error {
+	if err := stack.Build.Add(m, middleware.After); err != nil {
+		return err
+	}
+	return stack.Deserialize.Add(&expectationFailedRetry{}, middleware.After)
+}
+
+// ID returns the middleware identifier.
+func (m *ExpectContinue) ID() string {
+	return "ExpectContinue"
+}
+
+// HandleBuild sets the Expect header if the request's body meets the
+// threshold.
+func (m *ExpectContinue) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	threshold := m.ContinueHeaderThresholdBytes
+	if threshold == 0 {
+		threshold = DefaultExpectContinueThresholdBytes
+	}
+	if threshold < 0 || req.GetStream() == nil {
+		return next.HandleBuild(ctx, in)
+	}
+
+	size, ok, err := req.StreamLength()
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to determine request body length, %w", err)
+	}
+	if !ok || size >= threshold {
+		req.Header.Set("Expect", "100-continue")
+	}
+
+	return next.HandleBuild(ctx, in)
+}
+
+// expectationFailedRetry is the deserialize middleware resending a request
+// rejected with a 417 Expectation Failed response without its Expect header,
+// as the service does not support the expectation.
+type expectationFailedRetry struct{}
+
+func (m *expectationFailedRetry) ID() string {
+	return "ExpectationFailedRetry"
+}
+
+func (m *expectationFailedRetry) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+
+	req, ok := in.Request.(*Request)
+	if !ok || len(req.Header.Get("Expect")) == 0 {
+		return out, metadata, err
+	}
+	resp, ok := out.RawResponse.(*Response)
+	if !ok || resp == nil || resp.Response == nil || resp.StatusCode != http.StatusExpectationFailed {
+		return out, metadata, err
+	}
+	if req.GetStream() != nil && !req.IsStreamSeekable() {
+		return out, metadata, err
+	}
+
+	if resp.Body != nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	req = req.Clone()
+	req.Header.Del("Expect")
+	if req.GetStream() != nil {
+		if rewindErr := req.RewindStream(); rewindErr != nil {
+			return out, metadata, fmt.Errorf("failed to rewind request stream, %w", rewindErr)
+		}
+	}
+	middleware.GetLogger(ctx).Logf(logging.Debug,
+		"request rejected with expectation failed, resending without Expect header")
+
+	in.Request = req
+	return next.HandleDeserialize(ctx, in)
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// readCountingBody is a seekable request body counting the bytes read from
+// it.
+type readCountingBody struct {
+	*strings.Reader
+	mu   sync.Mutex
+	read int
+}
+
+func (b *readCountingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.mu.Lock()
+	b.read += n
+	b.mu.Unlock()
+	return n, err
+}
+
+func (b *readCountingBody) bytesRead() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.read
+}
+
+func TestExpectContinue(t *testing.T) {
+	cases := map[string]struct {
+		Threshold    int64
+		Body         io.Reader
+		ExpectHeader string
+	}{
+		"below threshold": {
+			Threshold: 100,
+			Body:      strings.NewReader("abc"),
+		},
+		"at threshold": {
+			Threshold:    3,
+			Body:         strings.NewReader("abc"),
+			ExpectHeader: "100-continue",
+		},
+		"unknown length": {
+			Threshold:    100,
+			Body:         ioutil.NopCloser(strings.NewReader("abc")),
+			ExpectHeader: "100-continue",
+		},
+		"disabled": {
+			Threshold: -1,
+			Body:      strings.NewReader("abc"),
+		},
+		"no body": {
+			Threshold: 1,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			if c.Body != nil {
+				req, _ = req.SetStream(c.Body)
+			}
+
+			m := ExpectContinue{ContinueHeaderThresholdBytes: c.Threshold}
+			_, _, err := m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectHeader, req.Header.Get("Expect"); e != a {
+				t.Errorf("expect %q Expect header, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestExpectContinue_Server(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/forbidden":
+			// Rejected before the body is read.
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/expectation" && len(r.Header.Get("Expect")) != 0:
+			w.WriteHeader(http.StatusExpectationFailed)
+		default:
+			b, _ := ioutil.ReadAll(r.Body)
+			mu.Lock()
+			received = append(received, string(b))
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := NewBuildableClient().WithExpectContinueTimeout(10 * time.Second)
+
+	cases := map[string]struct {
+		Path           string
+		ExpectStatus   int
+		ExpectRead     bool
+		ExpectReceived []string
+	}{
+		"rejected before body sent": {
+			Path:         "/forbidden",
+			ExpectStatus: http.StatusForbidden,
+		},
+		"expectation failed resent": {
+			Path:           "/expectation",
+			ExpectStatus:   http.StatusOK,
+			ExpectRead:     true,
+			ExpectReceived: []string{"upload payload"},
+		},
+		"continued": {
+			Path:           "/ok",
+			ExpectStatus:   http.StatusOK,
+			ExpectRead:     true,
+			ExpectReceived: []string{"upload payload"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			mu.Lock()
+			received = nil
+			mu.Unlock()
+
+			body := &readCountingBody{Reader: strings.NewReader("upload payload")}
+			req := NewStackRequest().(*Request)
+			req.Method = "PUT"
+			req.URL, _ = url.Parse(server.URL + c.Path)
+			req.ContentLength = int64(body.Len())
+			req, _ = req.SetStream(body)
+			stack := middleware.NewStack("expect continue", func() interface{} { return req })
+			if err := AddExpectContinue(stack, &ExpectContinue{ContinueHeaderThresholdBytes: 1}); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("status", func(
+				ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+			) (
+				out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+			) {
+				out, metadata, err = next.HandleDeserialize(ctx, in)
+				if err == nil {
+					resp := out.RawResponse.(*Response)
+					resp.Body.Close()
+					out.Result = resp.StatusCode
+				}
+				return out, metadata, err
+			}), middleware.Before)
+
+			handler := middleware.DecorateHandler(NewClientHandler(client), stack)
+			result, _, err := handler.Handle(context.Background(), struct{}{})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := c.ExpectStatus, result.(int); e != a {
+				t.Errorf("expect %v status, got %v", e, a)
+			}
+			if e, a := c.ExpectRead, body.bytesRead() != 0; e != a {
+				t.Errorf("expect body read %v, got %v bytes read", e, body.bytesRead())
+			}
+			mu.Lock()
+			if e, a := strings.Join(c.ExpectReceived, ","), strings.Join(received, ","); e != a {
+				t.Errorf("expect %q received, got %q", e, a)
+			}
+			mu.Unlock()
+		})
+	}
+}
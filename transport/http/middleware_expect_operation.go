@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// OperationMismatchError is returned by the ExpectOperation middleware when
+// the operation echoed by the response's header does not match the operation
+// invoked, (e.g. a response of another request returned by a misbehaving
+// proxy reusing connections). The response's body is not deserialized.
+type OperationMismatchError struct {
+	Response *Response
+	Header   string
+	Expected string
+	Actual   string
+}
+
+// HTTPResponse returns the HTTP response rejected.
+func (e *OperationMismatchError) HTTPResponse() *Response { return e.Response }
+
+// HTTPStatusCode returns the status code of the HTTP response rejected.
+func (e *OperationMismatchError) HTTPStatusCode() int {
+	if e.Response == nil || e.Response.Response == nil {
+		return 0
+	}
+	return e.Response.StatusCode
+}
+
+func (e *OperationMismatchError) Error() string {
+	return fmt.Sprintf("response %s header operation %q does not match expected operation %q",
+		e.Header, e.Actual, e.Expected)
+}
+
+// ExpectOperation is a deserialize middleware that rejects responses whose
+// Header echoes an operation other than the operation invoked, with a
+// *OperationMismatchError, before the response is deserialized. Responses
+// without the header are not checked.
+//
+// The echoed operation matches if it is equal to the expected operation name,
+// or is qualified by a service name prefix, (e.g. "Service.Operation" of the
+// X-Amz-Target header). If OperationName is empty, the operation name set on
+// the context with middleware.WithOperationName is expected. If neither is
+// set, the response is not checked.
+//
+// Use AddExpectOperation to add the middleware to a stack, after the
+// protocol's deserializer, so it handles the response first.
+type ExpectOperation struct {
+	// The response header echoing the operation.
+	Header string
+
+	// The expected operation name.
+	OperationName string
+}
+
+// AddExpectOperation adds the ExpectOperation middleware for the header as
+// the last middleware of the stack's Deserialize step, expecting the
+// operation name set on the context.
+func AddExpectOperation(stack *middleware.Stack, header string) error {
+	return stack.Deserialize.Add(&ExpectOperation{Header: header}, middleware.After)
+}
+
+// ID returns the middleware identifier.
+func (m *ExpectOperation) ID() string {
+	return "ExpectOperation"
+}
+
+// HandleDeserialize compares the operation echoed by the response returned
+// by the remainder of the stack to the expected operation.
+func (m *ExpectOperation) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+
+	expect := m.OperationName
+	if len(expect) == 0 {
+		expect = middleware.GetOperationName(ctx)
+	}
+	actual := strings.TrimSpace(resp.Header.Get(m.Header))
+	if len(expect) == 0 || len(actual) == 0 {
+		return out, metadata, nil
+	}
+
+	if actual == expect {
+		return out, metadata, nil
+	}
+	if i := strings.LastIndex(actual, "."); i >= 0 && actual[i+1:] == expect {
+		return out, metadata, nil
+	}
+
+	return out, metadata, &OperationMismatchError{
+		Response: resp,
+		Header:   m.Header,
+		Expected: expect,
+		Actual:   actual,
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestExpectOperation(t *testing.T) {
+	cases := map[string]struct {
+		OperationName    string
+		ContextOperation string
+		Header           string
+		ExpectErr        bool
+	}{
+		"matching": {
+			OperationName: "GetItem",
+			Header:        "GetItem",
+		},
+		"matching qualified": {
+			OperationName: "GetItem",
+			Header:        "DynamoDB_20120810.GetItem",
+		},
+		"matching context operation": {
+			ContextOperation: "GetItem",
+			Header:           "GetItem",
+		},
+		"mismatch": {
+			OperationName: "GetItem",
+			Header:        "DeleteItem",
+			ExpectErr:     true,
+		},
+		"mismatch qualified": {
+			OperationName: "GetItem",
+			Header:        "DynamoDB_20120810.DeleteItem",
+			ExpectErr:     true,
+		},
+		"mismatch context operation": {
+			ContextOperation: "GetItem",
+			Header:           "DeleteItem",
+			ExpectErr:        true,
+		},
+		"header absent": {
+			OperationName: "GetItem",
+		},
+		"operation not set": {
+			Header: "DeleteItem",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &ExpectOperation{Header: "X-Amz-Operation", OperationName: c.OperationName}
+
+			ctx := context.Background()
+			if len(c.ContextOperation) != 0 {
+				ctx = middleware.WithOperationName(ctx, c.ContextOperation)
+			}
+
+			_, _, err := m.HandleDeserialize(ctx, middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					resp := &http.Response{StatusCode: 200, Header: http.Header{}}
+					if len(c.Header) != 0 {
+						resp.Header.Set("X-Amz-Operation", c.Header)
+					}
+					out.RawResponse = &Response{Response: resp}
+					return out, metadata, nil
+				}))
+
+			if !c.ExpectErr {
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+				return
+			}
+
+			var mismatchErr *OperationMismatchError
+			if !errors.As(err, &mismatchErr) {
+				t.Fatalf("expect %T error, got %v", mismatchErr, err)
+			}
+			if e, a := c.Header, mismatchErr.Actual; e != a {
+				t.Errorf("expect %v actual operation, got %v", e, a)
+			}
+			if e, a := 200, mismatchErr.HTTPStatusCode(); e != a {
+				t.Errorf("expect %v status code, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultProtectedHeaders are the headers set by request signing, which the
+// ExtraHeaders middleware will not modify.
+var DefaultProtectedHeaders = []string{
+	"Authorization",
+	"X-Amz-Date",
+	"X-Amz-Security-Token",
+	"X-Amz-Content-Sha256",
+}
+
+type extraHeadersKey struct{}
+
+// WithExtraHeaders returns a context with headers the ExtraHeaders middleware
+// will add to requests made with the context, (e.g. a correlation ID for a
+// single operation call). Headers are merged with extra headers already set
+// on the context, replacing the values of headers set on both.
+func WithExtraHeaders(ctx context.Context, header http.Header) context.Context {
+	merged := GetExtraHeaders(ctx).Clone()
+	if merged == nil {
+		merged = http.Header{}
+	}
+	for k, vs := range header {
+		merged[http.CanonicalHeaderKey(k)] = append([]string(nil), vs...)
+	}
+	return context.WithValue(ctx, extraHeadersKey{}, merged)
+}
+
+// GetExtraHeaders returns the extra headers set on the context, or nil if
+// none were set. The returned header must not be modified.
+func GetExtraHeaders(ctx context.Context) http.Header {
+	v, _ := ctx.Value(extraHeadersKey{}).(http.Header)
+	return v
+}
+
+// ExtraHeaders is a build middleware that sets the extra headers of the
+// context, see WithExtraHeaders, on the request.
+//
+// Extra headers replace the values of headers set by the operation's
+// serializers and default middleware. Protected headers, set by request
+// signing, are never modified by extra headers, and signing middleware added
+// to the finalize step always sets its headers after extra headers are
+// applied.
+type ExtraHeaders struct {
+	// Names of headers extra headers must not modify. Defaults to
+	// DefaultProtectedHeaders.
+	ProtectedHeaders []string
+}
+
+// ID returns the middleware identifier.
+func (m *ExtraHeaders) ID() string {
+	return "ExtraHeaders"
+}
+
+// HandleBuild sets the context's extra headers on the request.
+func (m *ExtraHeaders) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	extra := GetExtraHeaders(ctx)
+	if len(extra) == 0 {
+		return next.HandleBuild(ctx, in)
+	}
+
+	protected := m.ProtectedHeaders
+	if protected == nil {
+		protected = DefaultProtectedHeaders
+	}
+
+	for k, vs := range extra {
+		if isProtectedHeader(protected, k) {
+			continue
+		}
+		req.Header[k] = append([]string(nil), vs...)
+	}
+
+	return next.HandleBuild(ctx, in)
+}
+
+func isProtectedHeader(protected []string, key string) bool {
+	for _, p := range protected {
+		if http.CanonicalHeaderKey(p) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestWithExtraHeaders(t *testing.T) {
+	ctx := WithExtraHeaders(context.Background(), http.Header{
+		"x-correlation-id": []string{"a"},
+		"X-Other":          []string{"b"},
+	})
+	ctx = WithExtraHeaders(ctx, http.Header{"X-Correlation-Id": []string{"c"}})
+
+	expect := http.Header{
+		"X-Correlation-Id": []string{"c"},
+		"X-Other":          []string{"b"},
+	}
+	if e, a := expect, GetExtraHeaders(ctx); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v extra headers, got %v", e, a)
+	}
+}
+
+func TestExtraHeaders(t *testing.T) {
+	ctx := WithExtraHeaders(context.Background(), http.Header{
+		"X-Correlation-Id": []string{"abc"},
+		"Content-Type":     []string{"application/custom"},
+		"Authorization":    []string{"forged"},
+		"X-Amz-Date":       []string{"forged"},
+	})
+
+	req := NewStackRequest().(*Request)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Default", "default")
+
+	stack := middleware.NewStack("extra headers", func() interface{} { return req })
+	stack.Build.Add(&ExtraHeaders{}, middleware.After)
+	stack.Finalize.Add(&SignRequest{
+		Signer: auth.SignerFunc(func(ctx context.Context, creds auth.Credentials, r *http.Request,
+			payloadHash, service string, regionSet []string, signingTime time.Time,
+		) error {
+			r.Header.Set("Authorization", "signed")
+			return nil
+		}),
+	}, middleware.After)
+
+	var sent http.Header
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			sent = in.(*Request).Header.Clone()
+			return nil, middleware.Metadata{}, nil
+		}), stack)
+	if _, _, err := handler.Handle(ctx, struct{}{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	expect := map[string]string{
+		"X-Correlation-Id": "abc",
+		"Content-Type":     "application/custom",
+		"X-Default":        "default",
+		"Authorization":    "signed",
+		"X-Amz-Date":       "",
+	}
+	for k, e := range expect {
+		if a := sent.Get(k); e != a {
+			t.Errorf("expect %q %v header, got %q", e, k, a)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// FinalURLHook returns a finalize middleware that calls fn with the URL of
+// each request attempt, after the operation's endpoint has been resolved and
+// applied, (e.g. to route requests through a local proxy, or to verify a
+// FIPS endpoint is used). Changes fn makes to the URL are sent with the
+// request. If fn returns an error, the request is not sent.
+//
+// Use AddFinalURLHook to add the hook to a stack before the SignRequest
+// middleware. Changing the URL of a request after it has been signed, (e.g.
+// by adding the hook to the end of the Finalize step), invalidates the
+// request's signature.
+func FinalURLHook(fn func(ctx context.Context, u *url.URL) error) middleware.FinalizeMiddleware {
+	return middleware.FinalizeMiddlewareFunc("FinalURLHook", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		req, ok := in.Request.(*Request)
+		if !ok {
+			return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+		}
+		if req.URL == nil {
+			return out, metadata, fmt.Errorf("request URL not set")
+		}
+
+		if err := fn(ctx, req.URL); err != nil {
+			return out, metadata, fmt.Errorf("final URL hook failed, %w", err)
+		}
+
+		return next.HandleFinalize(ctx, in)
+	})
+}
+
+// AddFinalURLHook inserts the FinalURLHook for fn into the stack's Finalize
+// step, immediately before the SignRequest middleware, so the URL is signed
+// after fn has inspected or changed it. If the stack does not have a
+// SignRequest middleware, the hook is added to the end of the Finalize step.
+func AddFinalURLHook(stack *middleware.Stack, fn func(ctx context.Context, u *url.URL) error) error {
+	for _, id := range stack.Finalize.List() {
+		if id == SigningMiddlewareID {
+			return stack.Finalize.Insert(FinalURLHook(fn), SigningMiddlewareID, middleware.Before)
+		}
+	}
+	return stack.Finalize.Add(FinalURLHook(fn), middleware.After)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/endpoints"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestAddFinalURLHook(t *testing.T) {
+	req := NewStackRequest().(*Request)
+	req.URL, _ = url.Parse("http://localhost/bucket/key?x-id=GetObject")
+
+	stack := middleware.NewStack("final url hook", func() interface{} { return req })
+	stack.Serialize.Add(&ResolveEndpoint{
+		Resolver: endpoints.EndpointResolverV2Func(
+			func(ctx context.Context, params interface{}) (endpoints.Endpoint, error) {
+				u, _ := url.Parse("https://fips.example.com/base")
+				return endpoints.Endpoint{URI: *u}, nil
+			}),
+	}, middleware.After)
+
+	var signedURL string
+	stack.Finalize.Add(&SignRequest{
+		Signer: auth.SignerFunc(func(ctx context.Context, creds auth.Credentials, r *http.Request,
+			payloadHash, service string, regionSet []string, signingTime time.Time,
+		) error {
+			signedURL = r.URL.String()
+			return nil
+		}),
+	}, middleware.After)
+
+	var hookURL string
+	err := AddFinalURLHook(stack, func(ctx context.Context, u *url.URL) error {
+		hookURL = u.String()
+		u.Host = "localhost:8080"
+		u.Scheme = "http"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expect no error adding hook, got %v", err)
+	}
+	if e, a := []string{"FinalURLHook", SigningMiddlewareID}, stack.Finalize.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v finalize middleware, got %v", e, a)
+	}
+
+	var sentURL string
+	handler := middleware.DecorateHandler(NewClientHandler(ClientDoFunc(func(r *http.Request) (*http.Response, error) {
+		sentURL = r.URL.String()
+		return &http.Response{StatusCode: 200, Header: http.Header{}, Body: http.NoBody}, nil
+	})), stack)
+	if _, _, err := handler.Handle(context.Background(), struct{}{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := "https://fips.example.com/base/bucket/key?x-id=GetObject", hookURL; e != a {
+		t.Errorf("expect hook to see resolved URL %v, got %v", e, a)
+	}
+	expect := "http://localhost:8080/base/bucket/key?x-id=GetObject"
+	if e, a := expect, signedURL; e != a {
+		t.Errorf("expect signed URL %v, got %v", e, a)
+	}
+	if e, a := expect, sentURL; e != a {
+		t.Errorf("expect sent URL %v, got %v", e, a)
+	}
+}
+
+func TestAddFinalURLHook_NoSigning(t *testing.T) {
+	stack := middleware.NewStack("final url hook", NewStackRequest)
+	err := AddFinalURLHook(stack, func(ctx context.Context, u *url.URL) error { return nil })
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := []string{"FinalURLHook"}, stack.Finalize.List(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v finalize middleware, got %v", e, a)
+	}
+}
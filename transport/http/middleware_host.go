@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// SetHostHeader is a build middleware that sets the request's Host header
+// to the host of the request's URL, keeping the two consistent when the
+// endpoint host has been rewritten, (e.g. virtual-hosted style addressing).
+//
+// If StripDefaultPort is set, the port is removed from both the URL host and
+// Host header when it is the default port of the URL's scheme, 80 for http
+// and 443 for https.
+type SetHostHeader struct {
+	StripDefaultPort bool
+}
+
+// ID returns the middleware identifier.
+func (m *SetHostHeader) ID() string {
+	return "SetHostHeader"
+}
+
+// HandleBuild sets the request's Host header from its URL.
+func (m *SetHostHeader) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+	if req.URL == nil || len(req.URL.Host) == 0 {
+		return out, metadata, fmt.Errorf("request URL host not set")
+	}
+
+	if m.StripDefaultPort {
+		req.URL.Host = stripDefaultPort(req.URL)
+	}
+	req.Host = req.URL.Host
+
+	return next.HandleBuild(ctx, in)
+}
+
+// stripDefaultPort returns the URL's host without its port if the port is
+// the default port for the URL's scheme. IPv6 literals remain bracketed.
+func stripDefaultPort(u *url.URL) string {
+	port := u.Port()
+	if len(port) == 0 {
+		return u.Host
+	}
+
+	switch {
+	case strings.EqualFold(u.Scheme, "http") && port == "80":
+	case strings.EqualFold(u.Scheme, "https") && port == "443":
+	default:
+		return u.Host
+	}
+
+	host := u.Hostname()
+	if strings.Contains(host, ":") {
+		return "[" + host + "]"
+	}
+	return host
+}
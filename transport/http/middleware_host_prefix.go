@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// InvalidHostLabelError provides the error for a host prefix label value
+// that is not a valid host label.
+type InvalidHostLabelError struct {
+	Label string
+	Value string
+}
+
+func (e *InvalidHostLabelError) Error() string {
+	if len(e.Value) == 0 {
+		return fmt.Sprintf("host label %q value must not be empty", e.Label)
+	}
+	return fmt.Sprintf("host label %q value %q is not a valid host label", e.Label, e.Value)
+}
+
+// ValidHostLabel returns if the value is a valid host label, between 1 and
+// 63 characters of letters, digits, or hyphens, not starting with a hyphen.
+func ValidHostLabel(v string) bool {
+	if len(v) == 0 || len(v) > 63 || v[0] == '-' {
+		return false
+	}
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+		case c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9':
+		case c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ExpandHostPrefix returns the host prefix template with its labels, (e.g.
+// "{accountId}" of "data-{accountId}."), replaced by the label values. Returns
+// an error if a label has no value or its value is not a valid host label.
+func ExpandHostPrefix(template string, labels map[string]string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(template))
+
+	for len(template) != 0 {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			b.WriteString(template)
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("host prefix label not closed, %q", template[start:])
+		}
+		end += start
+
+		b.WriteString(template[:start])
+		label := template[start+1 : end]
+		value, ok := labels[label]
+		if !ok {
+			return "", fmt.Errorf("host prefix label %q value not set", label)
+		}
+		if !ValidHostLabel(value) {
+			return "", &InvalidHostLabelError{Label: label, Value: value}
+		}
+		b.WriteString(value)
+
+		template = template[end+1:]
+	}
+
+	return b.String(), nil
+}
+
+// HostPrefix is a serialize middleware that prepends the operation's host
+// prefix, bound from members with the hostLabel trait, to the request URL's
+// host. The middleware must be added after the endpoint has been resolved,
+// so the prefix is prepended to the endpoint's host.
+type HostPrefix struct {
+	// Host prefix template of the operation's endpoint trait, (e.g.
+	// "data-{accountId}.").
+	Prefix string
+
+	// Values of the prefix's labels, keyed by label name.
+	Labels map[string]string
+}
+
+// ID returns the middleware identifier.
+func (m *HostPrefix) ID() string {
+	return "HostPrefix"
+}
+
+// HandleSerialize prepends the expanded host prefix to the request's host.
+func (m *HostPrefix) HandleSerialize(
+	ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler,
+) (
+	out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+	if req.URL == nil || len(req.URL.Host) == 0 {
+		return out, metadata, fmt.Errorf("request URL host not set")
+	}
+
+	prefix, err := ExpandHostPrefix(m.Prefix, m.Labels)
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to expand host prefix, %w", err)
+	}
+	req.URL.Host = prefix + req.URL.Host
+
+	return next.HandleSerialize(ctx, in)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestHostPrefix(t *testing.T) {
+	cases := map[string]struct {
+		Prefix         string
+		Labels         map[string]string
+		ExpectHost     string
+		ExpectErr      bool
+		ExpectLabelErr bool
+	}{
+		"static prefix": {
+			Prefix:     "data.",
+			ExpectHost: "data.service.example.com",
+		},
+		"label prefix": {
+			Prefix:     "data-{accountId}.",
+			Labels:     map[string]string{"accountId": "123456789012"},
+			ExpectHost: "data-123456789012.service.example.com",
+		},
+		"multiple labels": {
+			Prefix: "{bucket}.{accountId}.",
+			Labels: map[string]string{
+				"bucket":    "my-bucket",
+				"accountId": "123456789012",
+			},
+			ExpectHost: "my-bucket.123456789012.service.example.com",
+		},
+		"empty label": {
+			Prefix:         "data-{accountId}.",
+			Labels:         map[string]string{"accountId": ""},
+			ExpectErr:      true,
+			ExpectLabelErr: true,
+		},
+		"invalid label": {
+			Prefix:         "data-{accountId}.",
+			Labels:         map[string]string{"accountId": "evil.com/"},
+			ExpectErr:      true,
+			ExpectLabelErr: true,
+		},
+		"label starts with hyphen": {
+			Prefix:         "{accountId}.",
+			Labels:         map[string]string{"accountId": "-123"},
+			ExpectErr:      true,
+			ExpectLabelErr: true,
+		},
+		"missing label": {
+			Prefix:    "data-{accountId}.",
+			ExpectErr: true,
+		},
+		"unclosed label": {
+			Prefix:    "data-{accountId.",
+			Labels:    map[string]string{"accountId": "123456789012"},
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req.URL, _ = url.Parse("https://service.example.com/path")
+
+			var host string
+			m := HostPrefix{Prefix: c.Prefix, Labels: c.Labels}
+			_, _, err := m.HandleSerialize(context.Background(), middleware.SerializeInput{Request: req},
+				serializeHandlerFunc(func(ctx context.Context, in middleware.SerializeInput) (
+					out middleware.SerializeOutput, metadata middleware.Metadata, err error,
+				) {
+					host = in.Request.(*Request).URL.Host
+					return out, metadata, nil
+				}))
+
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				var labelErr *InvalidHostLabelError
+				if e, a := c.ExpectLabelErr, errors.As(err, &labelErr); e != a {
+					t.Errorf("expect invalid host label error %v, got %v", e, err)
+				}
+				if e, a := "service.example.com", req.URL.Host; e != a {
+					t.Errorf("expect %v host unchanged, got %v", e, a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectHost, host; e != a {
+				t.Errorf("expect %v host, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestSetHostHeader(t *testing.T) {
+	cases := map[string]struct {
+		URL        string
+		Host       string
+		StripPort  bool
+		ExpectHost string
+	}{
+		"hostname": {
+			URL:        "https://bucket.example.com/key",
+			ExpectHost: "bucket.example.com",
+		},
+		"rewritten host": {
+			URL:        "https://bucket.example.com/key",
+			Host:       "example.com",
+			ExpectHost: "bucket.example.com",
+		},
+		"hostname with port": {
+			URL:        "https://bucket.example.com:8443/key",
+			StripPort:  true,
+			ExpectHost: "bucket.example.com:8443",
+		},
+		"hostname default port kept": {
+			URL:        "https://bucket.example.com:443/key",
+			ExpectHost: "bucket.example.com:443",
+		},
+		"hostname default port stripped": {
+			URL:        "https://bucket.example.com:443/key",
+			StripPort:  true,
+			ExpectHost: "bucket.example.com",
+		},
+		"http default port stripped": {
+			URL:        "http://bucket.example.com:80/key",
+			StripPort:  true,
+			ExpectHost: "bucket.example.com",
+		},
+		"https non default port": {
+			URL:        "https://bucket.example.com:80/key",
+			StripPort:  true,
+			ExpectHost: "bucket.example.com:80",
+		},
+		"ipv4": {
+			URL:        "http://127.0.0.1/key",
+			StripPort:  true,
+			ExpectHost: "127.0.0.1",
+		},
+		"ipv4 with port": {
+			URL:        "http://127.0.0.1:8080/key",
+			StripPort:  true,
+			ExpectHost: "127.0.0.1:8080",
+		},
+		"ipv4 default port stripped": {
+			URL:        "http://127.0.0.1:80/key",
+			StripPort:  true,
+			ExpectHost: "127.0.0.1",
+		},
+		"ipv6": {
+			URL:        "https://[2001:db8::1]/key",
+			StripPort:  true,
+			ExpectHost: "[2001:db8::1]",
+		},
+		"ipv6 with port": {
+			URL:        "https://[2001:db8::1]:8443/key",
+			StripPort:  true,
+			ExpectHost: "[2001:db8::1]:8443",
+		},
+		"ipv6 default port stripped": {
+			URL:        "https://[2001:db8::1]:443/key",
+			StripPort:  true,
+			ExpectHost: "[2001:db8::1]",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			var err error
+			if req.URL, err = url.Parse(c.URL); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			req.Host = c.Host
+
+			m := SetHostHeader{StripDefaultPort: c.StripPort}
+			_, _, err = m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := c.ExpectHost, req.Host; e != a {
+				t.Errorf("expect %v Host header, got %v", e, a)
+			}
+			if e, a := c.ExpectHost, req.URL.Host; e != a {
+				t.Errorf("expect %v URL host, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestSetHostHeaderNoHost(t *testing.T) {
+	req := NewStackRequest().(*Request)
+	req.URL = &url.URL{Path: "/key"}
+
+	var m SetHostHeader
+	_, _, err := m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+		buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+			out middleware.BuildOutput, metadata middleware.Metadata, err error,
+		) {
+			return out, metadata, nil
+		}))
+	if err == nil {
+		t.Errorf("expect error, got none")
+	}
+}
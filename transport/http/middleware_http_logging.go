@@ -0,0 +1,161 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type skipLoggingKey struct{}
+
+// WithSkipLogging returns a context marking that the operation's request and
+// response messages are not logged by the RequestResponseLogger middleware,
+// (e.g. internal health check calls).
+func WithSkipLogging(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipLoggingKey{}, true)
+}
+
+// IsSkipLogging returns if the context marks the operation's messages as not
+// logged.
+func IsSkipLogging(ctx context.Context) bool {
+	v, _ := ctx.Value(skipLoggingKey{}).(bool)
+	return v
+}
+
+// RequestResponseLogger is a deserialize middleware that will log the
+// request and response HTTP messages and optionally their respective bodies.
+// Will not perform any logging if none of the options are set.
+//
+// The logged representation of the messages will be redacted if the
+// RedactMiddleware was added to the stack.
+//
+// If the context is marked with WithSkipLogging, the messages are not logged.
+type RequestResponseLogger struct {
+	LogRequest         bool
+	LogRequestWithBody bool
+
+	LogResponse         bool
+	LogResponseWithBody bool
+}
+
+// ID is the middleware identifier.
+func (r *RequestResponseLogger) ID() string {
+	return "RequestResponseLogger"
+}
+
+// HandleDeserialize logs the HTTP request and response messages.
+func (r *RequestResponseLogger) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	if IsSkipLogging(ctx) {
+		return next.HandleDeserialize(ctx, in)
+	}
+
+	logger := middleware.GetLogger(ctx)
+	redactor := getRedactor(ctx)
+
+	if r.LogRequest || r.LogRequestWithBody {
+		req, ok := in.Request.(*Request)
+		if !ok {
+			return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+		}
+
+		dump, err := dumpRequest(req, r.LogRequestWithBody, redactor, logger)
+		if err != nil {
+			return out, metadata, fmt.Errorf("failed to dump request, %w", err)
+		}
+		logger.Logf(logging.Debug, "Request\n%v", string(dump))
+	}
+
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	if r.LogResponse || r.LogResponseWithBody {
+		resp, ok := out.RawResponse.(*Response)
+		if !ok {
+			return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+		}
+
+		dump, err := dumpResponse(resp, r.LogResponseWithBody, redactor, logger)
+		if err != nil {
+			return out, metadata, fmt.Errorf("failed to dump response, %w", err)
+		}
+		logger.Logf(logging.Debug, "Response\n%v", string(dump))
+	}
+
+	return out, metadata, err
+}
+
+// dumpRequest returns the logged representation of the request. The request
+// is not modified, its stream is rewound after being read if it is seekable.
+// Bodies of unseekable streams are not read.
+func dumpRequest(req *Request, withBody bool, redactor *redactor, logger logging.Logger) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s %s\r\n", req.Method, req.URL.RequestURI(), "HTTP/1.1")
+	fmt.Fprintf(&b, "Host: %s\r\n", req.URL.Host)
+	if err := redactor.Header(req.Header).Write(&b); err != nil {
+		return nil, err
+	}
+	b.WriteString("\r\n")
+
+	if !withBody || req.GetStream() == nil {
+		return b.Bytes(), nil
+	}
+
+	if !req.isStreamSeekable {
+		logger.Logf(logging.Debug, "request body not logged, streaming body cannot be redacted")
+		return b.Bytes(), nil
+	}
+
+	body, err := ioutil.ReadAll(req.GetStream())
+	if err != nil {
+		return nil, err
+	}
+	if err := req.RewindStream(); err != nil {
+		return nil, err
+	}
+
+	b.Write(redactor.Body(body))
+	return b.Bytes(), nil
+}
+
+// dumpResponse returns the logged representation of the response. If the
+// body is logged it will be read, and replaced with a copy of its contents.
+// Bodies of unknown length are treated as streaming and are not read when
+// body redaction is enabled.
+func dumpResponse(resp *Response, withBody bool, redactor *redactor, logger logging.Logger) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s\r\n", resp.Proto, resp.Status)
+	if err := redactor.Header(resp.Header).Write(&b); err != nil {
+		return nil, err
+	}
+	b.WriteString("\r\n")
+
+	if !withBody || resp.Body == nil || resp.Body == http.NoBody {
+		return b.Bytes(), nil
+	}
+
+	if resp.ContentLength < 0 && redactor.RedactsBody() {
+		logger.Logf(logging.Debug, "response body not logged, streaming body cannot be redacted")
+		return b.Bytes(), nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	b.Write(redactor.Body(body))
+	return b.Bytes(), nil
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// RequestBodyTooLargeError is returned when a request's body is larger than
+// the MaxRequestBodyBytes middleware's limit.
+type RequestBodyTooLargeError struct {
+	Limit int64
+
+	// Length of the body, or -1 if the length was not known, and the limit
+	// was exceeded while the body was being sent.
+	Length int64
+}
+
+func (e *RequestBodyTooLargeError) Error() string {
+	if e.Length < 0 {
+		return fmt.Sprintf("request body exceeds the %d byte limit", e.Limit)
+	}
+	return fmt.Sprintf("request body length %d exceeds the %d byte limit", e.Length, e.Limit)
+}
+
+type requestBodyLengthKey struct{}
+
+// GetRequestBodyLengthMetadata returns the length of the request body checked
+// by the MaxRequestBodyBytes middleware, and if it was set. For bodies whose
+// length was not known before the request was sent, the number of bytes read
+// from the body is set.
+func GetRequestBodyLengthMetadata(metadata middleware.MetadataReader) (int64, bool) {
+	v, ok := metadata.Get(requestBodyLengthKey{}).(int64)
+	return v, ok
+}
+
+// MaxRequestBodyBytes is a build middleware that rejects requests whose body
+// is larger than the limit, to prevent unintentionally large uploads.
+//
+// Requests with a content length set, or a body whose length is known, (see
+// Request.StreamLength), over the limit fail with a *RequestBodyTooLargeError
+// before they are sent. Bodies of unknown length are wrapped so reading them
+// fails with a *RequestBodyTooLargeError once more than the limit has been
+// read, aborting the request mid-stream.
+type MaxRequestBodyBytes struct {
+	Limit int64
+}
+
+// ID returns the middleware identifier.
+func (m *MaxRequestBodyBytes) ID() string {
+	return "MaxRequestBodyBytes"
+}
+
+// HandleBuild checks the length of the request body against the limit.
+func (m *MaxRequestBodyBytes) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	stream := req.GetStream()
+	if stream == nil {
+		return next.HandleBuild(ctx, in)
+	}
+
+	length, ok := req.ContentLength, req.ContentLength > 0
+	if !ok {
+		if length, ok, err = req.StreamLength(); err != nil {
+			return out, metadata, fmt.Errorf("failed to compute request body length, %w", err)
+		}
+	}
+	if ok {
+		if length > m.Limit {
+			return out, metadata, &RequestBodyTooLargeError{Limit: m.Limit, Length: length}
+		}
+		out, metadata, err = next.HandleBuild(ctx, in)
+		metadata.Set(requestBodyLengthKey{}, length)
+		return out, metadata, err
+	}
+
+	body := &maxBytesReader{r: stream, limit: m.Limit}
+	if req, err = req.SetStream(body); err != nil {
+		return out, metadata, fmt.Errorf("failed to set limited request body, %w", err)
+	}
+	in.Request = req
+
+	out, metadata, err = next.HandleBuild(ctx, in)
+	metadata.Set(requestBodyLengthKey{}, body.read)
+	return out, metadata, err
+}
+
+// maxBytesReader fails with a RequestBodyTooLargeError once more than limit
+// bytes have been read from the underlying reader.
+type maxBytesReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+	err   error
+}
+
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	// Read at most one byte past the limit to detect the limit was exceeded.
+	if remaining := r.limit - r.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		r.err = &RequestBodyTooLargeError{Limit: r.limit, Length: -1}
+		return n - int(r.read-r.limit), r.err
+	}
+	return n, err
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestMaxRequestBodyBytes(t *testing.T) {
+	cases := map[string]struct {
+		Body            io.Reader
+		ContentLength   int64
+		ExpectErr       bool
+		ExpectSent      bool
+		ExpectReadErr   bool
+		ExpectBody      string
+		ExpectBodyBytes int64
+	}{
+		"seekable within limit": {
+			Body:            bytes.NewReader([]byte("0123456789")),
+			ExpectSent:      true,
+			ExpectBody:      "0123456789",
+			ExpectBodyBytes: 10,
+		},
+		"seekable over limit": {
+			Body:      bytes.NewReader([]byte("0123456789abcdef")),
+			ExpectErr: true,
+		},
+		"content length over limit": {
+			Body:          ioutil.NopCloser(strings.NewReader("0123456789abcdef")),
+			ContentLength: 16,
+			ExpectErr:     true,
+		},
+		"streaming within limit": {
+			Body:            ioutil.NopCloser(strings.NewReader("0123456789")),
+			ExpectSent:      true,
+			ExpectBody:      "0123456789",
+			ExpectBodyBytes: 10,
+		},
+		"streaming over limit": {
+			Body:          ioutil.NopCloser(strings.NewReader(strings.Repeat("a", 1024))),
+			ExpectSent:    true,
+			ExpectReadErr: true,
+			ExpectBody:    "aaaaaaaaaa",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req.ContentLength = c.ContentLength
+			var err error
+			if req, err = req.SetStream(c.Body); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			var sent bool
+			var body []byte
+			var readErr error
+			m := MaxRequestBodyBytes{Limit: 10}
+			_, metadata, err := m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					sent = true
+					stream := in.Request.(*Request).GetStream()
+					var p [3]byte
+					for {
+						n, err := stream.Read(p[:])
+						body = append(body, p[:n]...)
+						if err != nil {
+							if err != io.EOF {
+								readErr = err
+							}
+							break
+						}
+					}
+					return out, metadata, nil
+				}))
+
+			if c.ExpectErr {
+				var tooLargeErr *RequestBodyTooLargeError
+				if !errors.As(err, &tooLargeErr) {
+					t.Fatalf("expect request body too large error, got %v", err)
+				}
+				if e, a := int64(16), tooLargeErr.Length; e != a {
+					t.Errorf("expect %v length, got %v", e, a)
+				}
+				if sent {
+					t.Errorf("expect request not sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectSent, sent; e != a {
+				t.Errorf("expect sent %v, got %v", e, a)
+			}
+			if e, a := c.ExpectBody, string(body); e != a {
+				t.Errorf("expect %q body read, got %q", e, a)
+			}
+
+			if c.ExpectReadErr {
+				var tooLargeErr *RequestBodyTooLargeError
+				if !errors.As(readErr, &tooLargeErr) {
+					t.Fatalf("expect request body too large read error, got %v", readErr)
+				}
+				if e, a := int64(-1), tooLargeErr.Length; e != a {
+					t.Errorf("expect %v length, got %v", e, a)
+				}
+				return
+			}
+			if readErr != nil {
+				t.Fatalf("expect no read error, got %v", readErr)
+			}
+			if v, _ := GetRequestBodyLengthMetadata(metadata); v != c.ExpectBodyBytes {
+				t.Errorf("expect %v body length metadata, got %v", c.ExpectBodyBytes, v)
+			}
+		})
+	}
+}
+
+func TestMaxBytesReaderAfterLimit(t *testing.T) {
+	r := &maxBytesReader{r: strings.NewReader("abcdefghij"), limit: 4}
+
+	b := make([]byte, 8)
+	n, err := r.Read(b)
+	if e, a := 4, n; e != a {
+		t.Errorf("expect %v bytes read, got %v", e, a)
+	}
+	var tooLargeErr *RequestBodyTooLargeError
+	if !errors.As(err, &tooLargeErr) {
+		t.Fatalf("expect %T error, got %v", tooLargeErr, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		n, err = r.Read(b)
+		if e, a := 0, n; e != a {
+			t.Errorf("expect %v bytes read after limit, got %v", e, a)
+		}
+		if !errors.As(err, &tooLargeErr) {
+			t.Errorf("expect %T error after limit, got %v", tooLargeErr, err)
+		}
+	}
+}
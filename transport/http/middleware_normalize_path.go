@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// NormalizePath is a build middleware that normalizes the request's URL path
+// before it is signed, so the path signed is the path the service receives.
+// Empty segments, (e.g. "/a//b"), are collapsed, and "." and ".." segments are
+// resolved, (e.g. "/a/../b" becomes "/b").
+//
+// If RejectDotSegments is set, paths with "." or ".." segments fail instead
+// of being resolved, for services requiring the literal path be sent, (e.g.
+// object keys).
+//
+// The path is normalized in its escaped form, so encoded characters, (e.g. an
+// encoded slash "%2F"), are left intact and are not treated as segment
+// separators.
+type NormalizePath struct {
+	RejectDotSegments bool
+}
+
+// ID returns the middleware identifier.
+func (m *NormalizePath) ID() string {
+	return "NormalizePath"
+}
+
+// HandleBuild normalizes the request's URL path.
+func (m *NormalizePath) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+	if req.URL == nil {
+		return next.HandleBuild(ctx, in)
+	}
+
+	escaped, err := normalizeEscapedPath(req.URL.EscapedPath(), m.RejectDotSegments)
+	if err != nil {
+		return out, metadata, err
+	}
+	path, err := url.PathUnescape(escaped)
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to unescape request path %q, %w", escaped, err)
+	}
+	req.URL.Path = path
+	req.URL.RawPath = escaped
+
+	return next.HandleBuild(ctx, in)
+}
+
+// normalizeEscapedPath returns the escaped path with empty segments removed
+// and dot segments resolved, or an error if reject is set and the path has
+// dot segments. The leading and trailing slashes of the path are kept.
+func normalizeEscapedPath(escaped string, reject bool) (string, error) {
+	if len(escaped) == 0 {
+		return escaped, nil
+	}
+
+	segments := strings.Split(escaped, "/")
+	normalized := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		switch segment {
+		case "":
+			continue
+		case ".", "..":
+			if reject {
+				return "", fmt.Errorf("request path %q contains %q segment", escaped, segment)
+			}
+			if segment == ".." && len(normalized) != 0 {
+				normalized = normalized[:len(normalized)-1]
+			}
+			continue
+		}
+		normalized = append(normalized, segment)
+	}
+
+	var b strings.Builder
+	if strings.HasPrefix(escaped, "/") {
+		b.WriteByte('/')
+	}
+	b.WriteString(strings.Join(normalized, "/"))
+	if len(normalized) != 0 && trailingSlash(segments) {
+		b.WriteByte('/')
+	}
+	return b.String(), nil
+}
+
+// trailingSlash returns if the path's last segment is empty, or a dot
+// segment referring to a directory.
+func trailingSlash(segments []string) bool {
+	switch segments[len(segments)-1] {
+	case "", ".", "..":
+		return true
+	}
+	return false
+}
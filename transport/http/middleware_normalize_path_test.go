@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestNormalizePath(t *testing.T) {
+	cases := map[string]struct {
+		URL        string
+		Reject     bool
+		ExpectPath string
+		ExpectURL  string
+		ExpectErr  string
+	}{
+		"double slash": {
+			URL:        "https://example.com/a//b",
+			ExpectPath: "/a/b",
+			ExpectURL:  "https://example.com/a/b",
+		},
+		"parent segment": {
+			URL:        "https://example.com/a/../b",
+			ExpectPath: "/b",
+			ExpectURL:  "https://example.com/b",
+		},
+		"current segment": {
+			URL:        "https://example.com/a/./b/",
+			ExpectPath: "/a/b/",
+			ExpectURL:  "https://example.com/a/b/",
+		},
+		"parent above root": {
+			URL:        "https://example.com/../a",
+			ExpectPath: "/a",
+			ExpectURL:  "https://example.com/a",
+		},
+		"encoded slash": {
+			URL:        "https://example.com/a%2Fb//c",
+			ExpectPath: "/a/b/c",
+			ExpectURL:  "https://example.com/a%2Fb/c",
+		},
+		"encoded dot segment literal": {
+			URL:        "https://example.com/a/%2E%2E/b",
+			ExpectPath: "/a/../b",
+			ExpectURL:  "https://example.com/a/%2E%2E/b",
+		},
+		"root": {
+			URL:        "https://example.com/",
+			ExpectPath: "/",
+			ExpectURL:  "https://example.com/",
+		},
+		"reject parent segment": {
+			URL:       "https://example.com/a/../b",
+			Reject:    true,
+			ExpectErr: `contains ".." segment`,
+		},
+		"reject keeps double slash collapse": {
+			URL:        "https://example.com/a//b",
+			Reject:     true,
+			ExpectPath: "/a/b",
+			ExpectURL:  "https://example.com/a/b",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			var err error
+			if req.URL, err = url.Parse(c.URL); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			m := NormalizePath{RejectDotSegments: c.Reject}
+			_, _, err = m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect error containing %q, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := c.ExpectPath, req.URL.Path; e != a {
+				t.Errorf("expect %v path, got %v", e, a)
+			}
+			if e, a := c.ExpectURL, req.URL.String(); e != a {
+				t.Errorf("expect %v URL, got %v", e, a)
+			}
+		})
+	}
+}
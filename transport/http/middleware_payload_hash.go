@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// ComputePayloadHash is a finalize middleware that computes the hex encoded
+// SHA256 hash of the request payload the SignRequest middleware signs the
+// request with, see SetPayloadHash.
+//
+// If a payload hash is already set on the context, (e.g. precomputed by the
+// caller, or UnsignedPayload for a streaming payload), the payload is not
+// hashed. Seekable payloads are read to compute the hash, and rewound to
+// their start. Payloads that are not seekable cannot be read without
+// consuming them, and are sent unsigned with UnsignedPayload.
+type ComputePayloadHash struct{}
+
+// ID returns the middleware identifier.
+func (m *ComputePayloadHash) ID() string {
+	return "ComputePayloadHash"
+}
+
+// HandleFinalize sets the payload hash of the request on the context.
+func (m *ComputePayloadHash) HandleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	if v, _ := ctx.Value(payloadHashKey{}).(string); len(v) != 0 {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	stream := req.GetStream()
+	if stream != nil && !req.IsStreamSeekable() {
+		middleware.GetLogger(ctx).Logf(logging.Debug,
+			"request payload is not seekable, using %s payload hash", UnsignedPayload)
+		return next.HandleFinalize(SetPayloadHash(ctx, UnsignedPayload), in)
+	}
+
+	h := sha256.New()
+	if stream != nil {
+		if _, err := io.Copy(h, stream); err != nil {
+			return out, metadata, fmt.Errorf("failed to compute payload hash, %w", err)
+		}
+		if err := req.RewindStream(); err != nil {
+			return out, metadata, fmt.Errorf("failed to rewind payload after computing hash, %w", err)
+		}
+	}
+
+	ctx = SetPayloadHash(ctx, hex.EncodeToString(h.Sum(nil)))
+	return next.HandleFinalize(ctx, in)
+}
+
+// AddComputePayloadHash inserts the ComputePayloadHash middleware into the
+// stack's Finalize step, immediately before the SignRequest middleware.
+// Returns an error if the stack does not have a SignRequest middleware.
+func AddComputePayloadHash(stack *middleware.Stack) error {
+	return stack.Finalize.Insert(&ComputePayloadHash{}, SigningMiddlewareID, middleware.Before)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestComputePayloadHash(t *testing.T) {
+	const body = "hello world"
+
+	cases := map[string]struct {
+		PayloadHash string
+		Stream      io.Reader
+		Expect      string
+		ExpectBody  string
+	}{
+		"precomputed": {
+			PayloadHash: "precomputed",
+			Stream:      bytes.NewReader([]byte(body)),
+			Expect:      "precomputed",
+			ExpectBody:  body,
+		},
+		"unsigned payload": {
+			PayloadHash: UnsignedPayload,
+			Stream:      bytes.NewReader([]byte(body)),
+			Expect:      UnsignedPayload,
+			ExpectBody:  body,
+		},
+		"compute and rewind": {
+			Stream:     bytes.NewReader([]byte(body)),
+			Expect:     "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			ExpectBody: body,
+		},
+		"not seekable": {
+			Stream:     ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			Expect:     UnsignedPayload,
+			ExpectBody: body,
+		},
+		"no payload": {
+			Expect: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if len(c.PayloadHash) != 0 {
+				ctx = SetPayloadHash(ctx, c.PayloadHash)
+			}
+
+			req := NewStackRequest().(*Request)
+			if c.Stream != nil {
+				var err error
+				if req, err = req.SetStream(c.Stream); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+			}
+
+			var actual, actualBody string
+			m := ComputePayloadHash{}
+			_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{Request: req},
+				finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					actual = GetPayloadHash(ctx)
+					if stream := in.Request.(*Request).GetStream(); stream != nil {
+						b, err := ioutil.ReadAll(stream)
+						if err != nil {
+							t.Fatalf("expect no error reading stream, got %v", err)
+						}
+						actualBody = string(b)
+					}
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, actual; e != a {
+				t.Errorf("expect %v payload hash, got %v", e, a)
+			}
+			if e, a := c.ExpectBody, actualBody; e != a {
+				t.Errorf("expect %q body, got %q", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// PreSignHook returns a finalize middleware that calls fn with each request
+// attempt immediately before it is signed, so headers fn sets are included in
+// the signature. If fn returns an error, the request is not signed or sent.
+//
+// Use AddPreSignHook to add the hook to a stack before the SignRequest
+// middleware.
+func PreSignHook(fn func(ctx context.Context, req *Request) error) middleware.FinalizeMiddleware {
+	return middleware.FinalizeMiddlewareFunc("PreSignHook", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (
+		out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+	) {
+		req, ok := in.Request.(*Request)
+		if !ok {
+			return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+		}
+
+		if err := fn(ctx, req); err != nil {
+			return out, metadata, fmt.Errorf("pre-sign hook failed, %w", err)
+		}
+
+		return next.HandleFinalize(ctx, in)
+	})
+}
+
+// AddPreSignHook inserts the PreSignHook for fn into the stack's Finalize
+// step, immediately before the SignRequest middleware. Returns an error if
+// the stack does not have a SignRequest middleware.
+func AddPreSignHook(stack *middleware.Stack, fn func(ctx context.Context, req *Request) error) error {
+	return stack.Finalize.Insert(PreSignHook(fn), SigningMiddlewareID, middleware.Before)
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestAddPreSignHook(t *testing.T) {
+	cases := map[string]struct {
+		HookErr         error
+		ExpectCanonical string
+		ExpectErr       string
+	}{
+		"header signed": {
+			ExpectCanonical: "host:example.com\nx-custom-token:token\n",
+		},
+		"hook error": {
+			HookErr:   fmt.Errorf("no token"),
+			ExpectErr: "pre-sign hook failed, no token",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req.URL, _ = url.Parse("https://example.com")
+
+			var canonical string
+			stack := middleware.NewStack("pre-sign hook", func() interface{} { return req })
+			stack.Finalize.Add(&SignRequest{
+				Signer: auth.SignerFunc(func(ctx context.Context, creds auth.Credentials, r *http.Request,
+					payloadHash, service string, regionSet []string, signingTime time.Time,
+				) error {
+					canonical, _ = auth.GetSignedHeaderOptions(ctx).CanonicalHeaders(r)
+					return nil
+				}),
+			}, middleware.After)
+
+			err := AddPreSignHook(stack, func(ctx context.Context, req *Request) error {
+				if c.HookErr != nil {
+					return c.HookErr
+				}
+				req.Header.Set("X-Custom-Token", "token")
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("expect no error adding hook, got %v", err)
+			}
+			if e, a := []string{"PreSignHook", SigningMiddlewareID}, stack.Finalize.List(); !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v finalize middleware, got %v", e, a)
+			}
+
+			var sent bool
+			handler := middleware.DecorateHandler(NewClientHandler(ClientDoFunc(func(r *http.Request) (*http.Response, error) {
+				sent = true
+				return &http.Response{StatusCode: 200, Header: http.Header{}, Body: http.NoBody}, nil
+			})), stack)
+			_, _, err = handler.Handle(context.Background(), struct{}{})
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect error containing %q, got %v", c.ExpectErr, err)
+				}
+				if sent {
+					t.Errorf("expect request not sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectCanonical, canonical; e != a {
+				t.Errorf("expect %q canonical headers, got %q", e, a)
+			}
+		})
+	}
+
+	if err := AddPreSignHook(middleware.NewStack("no signer", NewStackRequest), nil); err == nil {
+		t.Errorf("expect error adding hook without signer, got none")
+	}
+}
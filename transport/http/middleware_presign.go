@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultPresignExpires is the default duration a presigned request is valid
+// for.
+const DefaultPresignExpires = 15 * time.Minute
+
+// PresignedHTTPRequest is the presigned request returned by the
+// PresignRequest middleware in the operation's metadata.
+type PresignedHTTPRequest struct {
+	// The presigned request. The signature is included in the request URL's
+	// query string.
+	Request *http.Request
+
+	// Time the presigned request expires at.
+	Expires time.Time
+}
+
+type presignedRequestKey struct{}
+
+// SetPresignedHTTPRequest sets the presigned request in the metadata.
+func SetPresignedHTTPRequest(metadata *middleware.Metadata, req *PresignedHTTPRequest) {
+	metadata.Set(presignedRequestKey{}, req)
+}
+
+// GetPresignedHTTPRequest returns the presigned request from the metadata,
+// and if the presigned request was set.
+func GetPresignedHTTPRequest(metadata middleware.MetadataReader) (*PresignedHTTPRequest, bool) {
+	v, ok := metadata.Get(presignedRequestKey{}).(*PresignedHTTPRequest)
+	return v, ok
+}
+
+// PresignRequest is a finalize middleware that presigns the request instead
+// of sending it. The presigned request is returned in the metadata as a
+// PresignedHTTPRequest, and the remainder of the stack, including the HTTP
+// client send, is not called.
+//
+// PresignRequest replaces the SignRequest middleware in stacks used to
+// presign operations, (e.g. stack.Finalize.Swap("Signing", presign)).
+type PresignRequest struct {
+	// Signer used to presign the request.
+	Signer auth.PresignSigner
+
+	// Provider of the credentials the request is signed with.
+	Credentials auth.CredentialsProvider
+
+	// Name of the service, and the set of regions the signature is valid
+	// for. If unset, the signing name and region set on the context, see
+	// auth.WithSigningName and auth.WithSigningRegion, are used.
+	Service   string
+	RegionSet []string
+
+	// Duration the presigned request is valid for. If zero,
+	// DefaultPresignExpires is used.
+	Expires time.Duration
+}
+
+// ID returns the middleware identifier.
+func (m *PresignRequest) ID() string {
+	return "Presign"
+}
+
+// HandleFinalize presigns the request, and returns it in the metadata
+// without calling the next handler.
+func (m *PresignRequest) HandleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+	if m.Signer == nil {
+		return out, metadata, fmt.Errorf("presign signer not set")
+	}
+
+	expires := m.Expires
+	if expires == 0 {
+		expires = DefaultPresignExpires
+	} else if expires < 0 {
+		return out, metadata, fmt.Errorf("invalid presign expires %v", expires)
+	}
+
+	var creds auth.Credentials
+	if m.Credentials != nil {
+		creds, err = m.Credentials.Retrieve(ctx)
+		if err != nil {
+			return out, metadata, fmt.Errorf("failed to retrieve credentials, %w", err)
+		}
+	}
+
+	signingTime := middleware.GetClock(ctx).Now()
+	// The request's body is not included, callers of the presigned request
+	// provide their own.
+	httpReq := req.Request.Clone(ctx)
+	service, regionSet := signingNameAndRegions(ctx, m.Service, m.RegionSet)
+	err = m.Signer.PresignHTTP(ctx, creds, httpReq, GetPayloadHash(ctx),
+		service, regionSet, signingTime, expires)
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to presign request, %w", err)
+	}
+
+	SetPresignedHTTPRequest(&metadata, &PresignedHTTPRequest{
+		Request: httpReq,
+		Expires: signingTime.Add(expires),
+	})
+
+	return out, metadata, nil
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+func TestPresignRequest(t *testing.T) {
+	signingTime := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	creds := auth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+
+	presigner := auth.PresignSignerFunc(func(
+		ctx context.Context, credentials auth.Credentials, r *http.Request,
+		payloadHash string, service string, regionSet []string, signingTime time.Time,
+		expires time.Duration,
+	) error {
+		q := r.URL.Query()
+		q.Set("X-Credential", credentials.AccessKeyID+"/"+service+"/"+strings.Join(regionSet, ","))
+		q.Set("X-Date", signingTime.Format("20060102T150405Z"))
+		q.Set("X-Expires", strconv.Itoa(int(expires/time.Second)))
+		q.Set("X-Signature", "abc123")
+		r.URL.RawQuery = q.Encode()
+		return nil
+	})
+
+	cases := map[string]struct {
+		Method         string
+		Expires        time.Duration
+		ContextSigning bool
+		CredsErr       error
+		ExpectExpires  string
+		ExpectErr      string
+	}{
+		"get default expires": {
+			Method:        "GET",
+			ExpectExpires: "900",
+		},
+		"put expires": {
+			Method:        "PUT",
+			Expires:       time.Hour,
+			ExpectExpires: "3600",
+		},
+		"signing name and region from context": {
+			Method:         "GET",
+			ContextSigning: true,
+			ExpectExpires:  "900",
+		},
+		"negative expires": {
+			Method:    "GET",
+			Expires:   -time.Second,
+			ExpectErr: "invalid presign expires",
+		},
+		"credentials error": {
+			Method:    "GET",
+			CredsErr:  fmt.Errorf("no credentials"),
+			ExpectErr: "failed to retrieve credentials, no credentials",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := PresignRequest{
+				Signer: presigner,
+				Credentials: auth.CredentialsProviderFunc(func(context.Context) (auth.Credentials, error) {
+					return creds, c.CredsErr
+				}),
+				Service:   "svc",
+				RegionSet: []string{"us-west-2"},
+				Expires:   c.Expires,
+			}
+
+			ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+				return signingTime
+			}))
+			if c.ContextSigning {
+				m.Service, m.RegionSet = "", nil
+				ctx = auth.WithSigningRegion(auth.WithSigningName(ctx, "svc"), "us-west-2")
+			}
+
+			req := NewStackRequest().(*Request)
+			req.Method = c.Method
+			req.URL, _ = url.Parse("https://bucket.example.com/key?versionId=1")
+
+			var sent bool
+			_, metadata, err := m.HandleFinalize(ctx, middleware.FinalizeInput{Request: req},
+				finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					sent = true
+					return out, metadata, nil
+				}))
+
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect %q error, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if sent {
+				t.Errorf("expect request not sent")
+			}
+
+			presigned, ok := GetPresignedHTTPRequest(metadata)
+			if !ok {
+				t.Fatalf("expect presigned request in metadata")
+			}
+			if e, a := c.Method, presigned.Request.Method; e != a {
+				t.Errorf("expect %v method, got %v", e, a)
+			}
+			if v := presigned.Request.Header.Get("Authorization"); len(v) != 0 {
+				t.Errorf("expect no authorization header, got %v", v)
+			}
+
+			query := presigned.Request.URL.Query()
+			expect := map[string]string{
+				"versionId":    "1",
+				"X-Credential": "AKID/svc/us-west-2",
+				"X-Date":       "20200601T120000Z",
+				"X-Expires":    c.ExpectExpires,
+				"X-Signature":  "abc123",
+			}
+			for k, e := range expect {
+				if a := query.Get(k); e != a {
+					t.Errorf("expect %v query %v, got %v", k, e, a)
+				}
+			}
+
+			expires := c.Expires
+			if expires == 0 {
+				expires = DefaultPresignExpires
+			}
+			if e, a := signingTime.Add(expires), presigned.Expires; !e.Equal(a) {
+				t.Errorf("expect %v expires, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type rawResponseKey struct{}
+
+// SetRawResponseMetadata sets the copy of the operation's HTTP response in
+// the metadata.
+func SetRawResponseMetadata(metadata *middleware.Metadata, resp *http.Response) {
+	metadata.Set(rawResponseKey{}, resp)
+}
+
+// GetRawResponse returns the copy of the operation's HTTP response recorded
+// by the RecordRawResponse middleware, and if it was set in the metadata.
+// The copy has the response's status and headers, but not its body.
+func GetRawResponse(metadata middleware.MetadataReader) (*http.Response, bool) {
+	v, ok := metadata.Get(rawResponseKey{}).(*http.Response)
+	return v, ok
+}
+
+// RecordRawResponse is a deserialize middleware that records a shallow copy
+// of the HTTP response, its status, protocol, and headers without its body,
+// in the operation's metadata, so debugging tools can inspect the response
+// after it has been deserialized, see GetRawResponse. The headers are
+// cloned, so are not modified by later middleware.
+//
+// The middleware should be added after the operation's deserializer, so it
+// records the response before it is deserialized. The response of failed
+// operations is recorded if one was received.
+type RecordRawResponse struct{}
+
+// ID returns the middleware identifier.
+func (m *RecordRawResponse) ID() string {
+	return "RecordRawResponse"
+}
+
+// HandleDeserialize records a copy of the response returned by the remainder
+// of the stack.
+func (m *RecordRawResponse) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok || resp == nil || resp.Response == nil {
+		return out, metadata, err
+	}
+
+	SetRawResponseMetadata(&metadata, &http.Response{
+		Status:        resp.Status,
+		StatusCode:    resp.StatusCode,
+		Proto:         resp.Proto,
+		ProtoMajor:    resp.ProtoMajor,
+		ProtoMinor:    resp.ProtoMinor,
+		Header:        resp.Header.Clone(),
+		Trailer:       resp.Trailer.Clone(),
+		ContentLength: resp.ContentLength,
+		Request:       resp.Request,
+	})
+	return out, metadata, err
+}
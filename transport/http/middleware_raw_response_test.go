@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestRecordRawResponse(t *testing.T) {
+	stack := middleware.NewStack("raw response", NewStackRequest)
+	stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+		func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			out, metadata, err = next.HandleDeserialize(ctx, in)
+			if err != nil {
+				return out, metadata, err
+			}
+
+			resp := out.RawResponse.(*Response)
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				return out, metadata, err
+			}
+			resp.Header.Del("X-Amz-Request-Id")
+			out.Result = string(b)
+			return out, metadata, nil
+		}), middleware.After)
+	stack.Deserialize.Add(&RecordRawResponse{}, middleware.After)
+
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			return &Response{
+				Response: &http.Response{
+					Status:     "201 Created",
+					StatusCode: 201,
+					Header:     http.Header{"X-Amz-Request-Id": []string{"abc123"}},
+					Body:       ioutil.NopCloser(strings.NewReader("hello")),
+				},
+			}, middleware.Metadata{}, nil
+		}), stack)
+
+	result, metadata, err := handler.Handle(context.Background(), struct{}{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "hello", result.(string); e != a {
+		t.Errorf("expect %v result, got %v", e, a)
+	}
+
+	resp, ok := GetRawResponse(metadata)
+	if !ok {
+		t.Fatalf("expect raw response")
+	}
+	if e, a := 201, resp.StatusCode; e != a {
+		t.Errorf("expect %v status code, got %v", e, a)
+	}
+	if e, a := "201 Created", resp.Status; e != a {
+		t.Errorf("expect %v status, got %v", e, a)
+	}
+	if e, a := "abc123", resp.Header.Get("X-Amz-Request-Id"); e != a {
+		t.Errorf("expect %v request id header, got %v", e, a)
+	}
+	if resp.Body != nil {
+		t.Errorf("expect no body")
+	}
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// redactedValue is the value sensitive content is replaced with in the
+// logged representation of messages.
+const redactedValue = "***"
+
+// RedactMiddleware is a finalize middleware that configures the redaction of
+// sensitive header values and body fields from the messages logged by the
+// RequestResponseLogger. Redaction is only applied to the logged
+// representation, the request and response sent and received are never
+// modified.
+//
+// Body field redaction is applied to JSON message bodies. Bodies that are not
+// JSON are redacted entirely. Streaming bodies are not redacted, and are
+// therefore not logged.
+type RedactMiddleware struct {
+	// Names of headers whose values will be redacted.
+	Headers []string
+
+	// BodyFieldMatcher returns if the value of the body field name should be
+	// redacted. If nil body fields will not be redacted.
+	BodyFieldMatcher func(field string) bool
+}
+
+// ID is the middleware identifier.
+func (m *RedactMiddleware) ID() string {
+	return "RedactMiddleware"
+}
+
+// HandleFinalize configures the redaction applied to logged messages for the
+// remainder of the stack.
+func (m *RedactMiddleware) HandleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	headers := make(map[string]struct{}, len(m.Headers))
+	for _, h := range m.Headers {
+		headers[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+
+	ctx = context.WithValue(ctx, redactorKey{}, &redactor{
+		headers:    headers,
+		matchField: m.BodyFieldMatcher,
+	})
+
+	return next.HandleFinalize(ctx, in)
+}
+
+type redactorKey struct{}
+
+func getRedactor(ctx context.Context) *redactor {
+	v, _ := ctx.Value(redactorKey{}).(*redactor)
+	return v
+}
+
+// redactor applies the redaction rules to the logged representation of
+// messages. A nil redactor does not redact any content.
+type redactor struct {
+	headers    map[string]struct{}
+	matchField func(string) bool
+}
+
+// RedactsBody returns if the redactor will modify the content of bodies.
+func (r *redactor) RedactsBody() bool {
+	return r != nil && r.matchField != nil
+}
+
+// Header returns a copy of the header with the values of sensitive headers
+// redacted.
+func (r *redactor) Header(header http.Header) http.Header {
+	if r == nil || len(r.headers) == 0 {
+		return header
+	}
+
+	redacted := make(http.Header, len(header))
+	for k, vs := range header {
+		if _, ok := r.headers[http.CanonicalHeaderKey(k)]; !ok {
+			redacted[k] = vs
+			continue
+		}
+
+		rvs := make([]string, len(vs))
+		for i := range vs {
+			rvs[i] = redactedValue
+		}
+		redacted[k] = rvs
+	}
+
+	return redacted
+}
+
+// Body returns a copy of the JSON body with the values of sensitive fields
+// redacted. If the body is not JSON the entire body will be redacted.
+func (r *redactor) Body(body []byte) []byte {
+	if !r.RedactsBody() || len(body) == 0 {
+		return body
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return []byte(redactedValue)
+	}
+
+	redacted, err := json.Marshal(r.value(v))
+	if err != nil {
+		return []byte(redactedValue)
+	}
+	return redacted
+}
+
+func (r *redactor) value(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range tv {
+			if r.matchField(k) {
+				tv[k] = redactedValue
+			} else {
+				tv[k] = r.value(fv)
+			}
+		}
+	case []interface{}:
+		for i, ev := range tv {
+			tv[i] = r.value(ev)
+		}
+	}
+	return v
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestRedactMiddleware(t *testing.T) {
+	cases := map[string]struct {
+		Body         io.Reader
+		ExpectLogged []string
+		ExpectAbsent []string
+	}{
+		"seekable body": {
+			Body: strings.NewReader(`{"Name":"foo","Password":"secret","Nested":{"Password":"secret"}}`),
+			ExpectLogged: []string{
+				"Authorization: ***",
+				`"Password":"***"`,
+				`"Name":"foo"`,
+				"X-Amz-Security-Token: ***",
+				`{"Token":"***"}`,
+			},
+			ExpectAbsent: []string{"secret", "abc123"},
+		},
+		"streaming body": {
+			Body: ioutil.NopCloser(strings.NewReader(`{"Password":"secret"}`)),
+			ExpectLogged: []string{
+				"Authorization: ***",
+				"request body not logged",
+			},
+			ExpectAbsent: []string{"secret"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			stack := middleware.NewStack("redact", NewStackRequest)
+			stack.Serialize.Add(middleware.SerializeMiddlewareFunc("serialize",
+				func(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (
+					middleware.SerializeOutput, middleware.Metadata, error,
+				) {
+					req := in.Request.(*Request)
+					req.Method = "POST"
+					req.URL, _ = url.Parse("https://example.com/path")
+					req.Header.Set("Authorization", "secret")
+
+					var err error
+					if in.Request, err = req.SetStream(c.Body); err != nil {
+						return middleware.SerializeOutput{}, middleware.Metadata{}, err
+					}
+					return next.HandleSerialize(ctx, in)
+				}),
+				middleware.After,
+			)
+			stack.Finalize.Add(&RedactMiddleware{
+				Headers: []string{"authorization", "X-Amz-Security-Token"},
+				BodyFieldMatcher: func(field string) bool {
+					return field == "Password" || field == "Token"
+				},
+			}, middleware.After)
+			stack.Deserialize.Add(&RequestResponseLogger{
+				LogRequestWithBody:  true,
+				LogResponseWithBody: true,
+			}, middleware.After)
+
+			var sentAuth string
+			handler := middleware.DecorateHandler(middleware.HandlerFunc(
+				func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+					sentAuth = in.(*Request).Header.Get("Authorization")
+					body := `{"Token":"abc123"}`
+					return &Response{
+						Response: &http.Response{
+							Proto:         "HTTP/1.1",
+							Status:        "200 OK",
+							StatusCode:    200,
+							Header:        http.Header{"X-Amz-Security-Token": []string{"abc123"}},
+							ContentLength: int64(len(body)),
+							Body:          ioutil.NopCloser(strings.NewReader(body)),
+						},
+					}, middleware.Metadata{}, nil
+				}), stack)
+
+			var logged bytes.Buffer
+			ctx := middleware.SetLogger(context.Background(), logging.LoggerFunc(
+				func(classification logging.Classification, format string, v ...interface{}) {
+					fmt.Fprintf(&logged, format, v...)
+				}))
+
+			if _, _, err := handler.Handle(ctx, struct{}{}); err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := "secret", sentAuth; e != a {
+				t.Errorf("expect request sent with %v, got %v", e, a)
+			}
+			for _, e := range c.ExpectLogged {
+				if a := logged.String(); !strings.Contains(a, e) {
+					t.Errorf("expect %q logged, got\n%v", e, a)
+				}
+			}
+			for _, e := range c.ExpectAbsent {
+				if a := logged.String(); strings.Contains(a, e) {
+					t.Errorf("expect %q not logged, got\n%v", e, a)
+				}
+			}
+		})
+	}
+}
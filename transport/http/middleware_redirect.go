@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type noFollowRedirectsKey struct{}
+
+// WithNoFollowRedirects returns a context marking that the redirect responses
+// of the operation's requests are not followed by the BuildableClient, and
+// are returned as the response instead, regardless of the client's redirect
+// policy. Not applied to a custom http.Client set with WithHTTPClient.
+func WithNoFollowRedirects(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noFollowRedirectsKey{}, true)
+}
+
+// IsNoFollowRedirects returns if the context marks redirect responses as not
+// followed.
+func IsNoFollowRedirects(ctx context.Context) bool {
+	v, _ := ctx.Value(noFollowRedirectsKey{}).(bool)
+	return v
+}
+
+// AddRedirectAsResult adds an initialize middleware to the stack that
+// surfaces the operation's redirect responses as its result instead of
+// following them, (e.g. operations whose output is a signed redirect URL).
+// Redirects are not followed, see WithNoFollowRedirects, and 3xx status
+// codes are successful, see WithSuccessStatusPredicate, so the operation's
+// deserializer handles the response as its output. Use GetRedirectLocation
+// to read the redirect's location.
+func AddRedirectAsResult(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("RedirectAsResult",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+			out middleware.InitializeOutput, metadata middleware.Metadata, err error,
+		) {
+			ctx = WithNoFollowRedirects(ctx)
+
+			prev, _ := ctx.Value(successStatusPredicateKey{}).(SuccessStatusPredicate)
+			if prev == nil {
+				prev = DefaultSuccessStatus
+			}
+			ctx = WithSuccessStatusPredicate(ctx, func(statusCode int) bool {
+				return isRedirectStatus(statusCode) || prev(statusCode)
+			})
+
+			return next.HandleInitialize(ctx, in)
+		}), middleware.After)
+}
+
+// GetRedirectLocation returns the URL of the redirect response's Location
+// header, resolved relative to the request's URL, and if the response is a
+// redirect with a valid Location.
+func GetRedirectLocation(resp *Response) (*url.URL, bool) {
+	if resp == nil || resp.Response == nil || !isRedirectStatus(resp.StatusCode) {
+		return nil, false
+	}
+	u, err := resp.Location()
+	if err != nil {
+		return nil, false
+	}
+	return u, true
+}
+
+func isRedirectStatus(statusCode int) bool {
+	return statusCode >= 300 && statusCode < 400
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/awslabs/smithy-go"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type redirectOutput struct {
+	StatusCode int
+	Location   string
+}
+
+func TestAddRedirectAsResult(t *testing.T) {
+	var followed int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/signed" {
+			atomic.AddInt32(&followed, 1)
+			w.WriteHeader(200)
+			return
+		}
+		w.Header().Set("Location", "/signed?X-Amz-Signature=abc")
+		w.WriteHeader(302)
+	}))
+	defer server.Close()
+
+	cases := map[string]struct {
+		AsResult       bool
+		ExpectStatus   int
+		ExpectLocation string
+		ExpectFollowed int32
+	}{
+		"redirect as result": {
+			AsResult:       true,
+			ExpectStatus:   302,
+			ExpectLocation: server.URL + "/signed?X-Amz-Signature=abc",
+		},
+		"redirect followed": {
+			ExpectStatus:   200,
+			ExpectFollowed: 1,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&followed, 0)
+
+			req := NewStackRequest().(*Request)
+			req.Method = "GET"
+			req.URL, _ = url.Parse(server.URL + "/object")
+			req, _ = req.SetStream(strings.NewReader(""))
+
+			stack := middleware.NewStack("redirect", func() interface{} { return req })
+			if c.AsResult {
+				if err := AddRedirectAsResult(stack); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+			}
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+				func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					out, metadata, err = next.HandleDeserialize(ctx, in)
+					if err != nil {
+						return out, metadata, err
+					}
+
+					resp := out.RawResponse.(*Response)
+					resp.Body.Close()
+					if !IsSuccessStatus(ctx, resp.StatusCode) {
+						return out, metadata, &smithy.GenericAPIError{Code: resp.Status}
+					}
+
+					output := &redirectOutput{StatusCode: resp.StatusCode}
+					if u, ok := GetRedirectLocation(resp); ok {
+						output.Location = u.String()
+					}
+					out.Result = output
+					return out, metadata, nil
+				}), middleware.After)
+
+			handler := middleware.DecorateHandler(NewClientHandler(NewBuildableClient()), stack)
+			result, _, err := handler.Handle(context.Background(), struct{}{})
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			output := result.(*redirectOutput)
+			if e, a := c.ExpectStatus, output.StatusCode; e != a {
+				t.Errorf("expect %v status code, got %v", e, a)
+			}
+			if e, a := c.ExpectLocation, output.Location; e != a {
+				t.Errorf("expect %v location, got %v", e, a)
+			}
+			if e, a := c.ExpectFollowed, atomic.LoadInt32(&followed); e != a {
+				t.Errorf("expect redirect followed %v times, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestRedirectAsResult_StatusPredicate(t *testing.T) {
+	stack := middleware.NewStack("redirect", NewStackRequest)
+	stack.Initialize.Add(middleware.InitializeMiddlewareFunc("predicate",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+			out middleware.InitializeOutput, metadata middleware.Metadata, err error,
+		) {
+			ctx = WithSuccessStatusPredicate(ctx, func(code int) bool { return code == 200 })
+			return next.HandleInitialize(ctx, in)
+		}), middleware.After)
+	if err := AddRedirectAsResult(stack); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	var ctx context.Context
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(c context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			ctx = c
+			return nil, middleware.Metadata{}, nil
+		}), stack)
+	if _, _, err := handler.Handle(context.Background(), struct{}{}); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if !IsNoFollowRedirects(ctx) {
+		t.Errorf("expect redirects not followed")
+	}
+	for code, expect := range map[int]bool{200: true, 204: false, 302: true, 304: true, 404: false} {
+		if e, a := expect, IsSuccessStatus(ctx, code); e != a {
+			t.Errorf("expect %v success for %v, got %v", e, code, a)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"context"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// DefaultRequestIDHeader is the response header the request ID is read from
+// if RequestIDRetriever's Header is not set.
+const DefaultRequestIDHeader = "X-Amzn-Requestid"
+
+// RequestIDRetriever is a deserialize middleware that reads the request ID
+// from the response's header and stores it in the middleware metadata. The
+// request ID can be retrieved with middleware.GetRequestIDMetadata.
+//
+// The request ID is stored for both successful and error responses. If the
+// response does not include the header, an empty request ID is stored.
+type RequestIDRetriever struct {
+	// Name of the header the request ID is read from. Defaults to
+	// DefaultRequestIDHeader.
+	Header string
+}
+
+// ID returns the middleware identifier.
+func (m *RequestIDRetriever) ID() string {
+	return "RequestIDRetriever"
+}
+
+// HandleDeserialize stores the response's request ID in the metadata.
+func (m *RequestIDRetriever) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok || resp.Response == nil {
+		return out, metadata, err
+	}
+
+	header := m.Header
+	if len(header) == 0 {
+		header = DefaultRequestIDHeader
+	}
+	middleware.SetRequestIDMetadata(&metadata, resp.Header.Get(header))
+
+	return out, metadata, err
+}
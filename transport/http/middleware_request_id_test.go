@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestRequestIDRetriever(t *testing.T) {
+	cases := map[string]struct {
+		Middleware RequestIDRetriever
+		Header     http.Header
+		Err        error
+		Response   bool
+		ExpectID   string
+		ExpectSet  bool
+	}{
+		"present": {
+			Header:    http.Header{"X-Amzn-Requestid": []string{"abc-123"}},
+			Response:  true,
+			ExpectID:  "abc-123",
+			ExpectSet: true,
+		},
+		"present with error": {
+			Header:    http.Header{"X-Amzn-Requestid": []string{"abc-123"}},
+			Err:       fmt.Errorf("some error"),
+			Response:  true,
+			ExpectID:  "abc-123",
+			ExpectSet: true,
+		},
+		"absent": {
+			Header:    http.Header{},
+			Response:  true,
+			ExpectSet: true,
+		},
+		"custom header": {
+			Middleware: RequestIDRetriever{Header: "X-Request-Id"},
+			Header:     http.Header{"X-Request-Id": []string{"def-456"}},
+			Response:   true,
+			ExpectID:   "def-456",
+			ExpectSet:  true,
+		},
+		"no response": {
+			Err: fmt.Errorf("connection error"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, metadata, err := c.Middleware.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+				deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+					out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+				) {
+					if c.Response {
+						out.RawResponse = &Response{
+							Response: &http.Response{StatusCode: 200, Header: c.Header},
+						}
+					}
+					return out, metadata, c.Err
+				}))
+			if e, a := c.Err, err; e != a {
+				t.Errorf("expect %v error, got %v", e, a)
+			}
+
+			id, ok := middleware.GetRequestIDMetadata(metadata)
+			if e, a := c.ExpectSet, ok; e != a {
+				t.Errorf("expect request ID set %v, got %v", e, a)
+			}
+			if e, a := c.ExpectID, id; e != a {
+				t.Errorf("expect %q request ID, got %q", e, a)
+			}
+		})
+	}
+}
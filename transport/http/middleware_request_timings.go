@@ -0,0 +1,166 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+// RequestTimings is the breakdown of the time spent sending a request and
+// receiving its response. Phases that did not occur, (e.g. DNS and connect
+// for a reused connection), are zero.
+type RequestTimings struct {
+	// Time spent resolving the endpoint host's address.
+	DNS time.Duration
+
+	// Time spent dialing the connection.
+	Connect time.Duration
+
+	// Time spent on the TLS handshake of the connection.
+	TLSHandshake time.Duration
+
+	// Time from the start of the request until the first byte of the
+	// response was received.
+	FirstByte time.Duration
+
+	// Time from the start of the request until the transport returned the
+	// response, once its headers were received. Reading and deserializing
+	// the response body is not included. If no response was returned, the
+	// time until the request failed.
+	Total time.Duration
+
+	// If the request was sent on a reused connection.
+	ConnReused bool
+}
+
+type requestTimingsKey struct{}
+
+// SetRequestTimingsMetadata sets the request's timings in the metadata.
+func SetRequestTimingsMetadata(metadata *middleware.Metadata, timings RequestTimings) {
+	metadata.Set(requestTimingsKey{}, timings)
+}
+
+// GetRequestTimingsMetadata returns the request's timings recorded by the
+// RecordRequestTimings middleware, and if they were set in the metadata.
+func GetRequestTimingsMetadata(metadata middleware.MetadataReader) (RequestTimings, bool) {
+	v, ok := metadata.Get(requestTimingsKey{}).(RequestTimings)
+	return v, ok
+}
+
+// RecordRequestTimings is a finalize middleware that traces the request with
+// an httptrace.ClientTrace, recording the DNS, connect, TLS handshake, first
+// byte, and total timings of the request in the operation's metadata, see
+// GetRequestTimingsMetadata.
+//
+// The middleware should be added after the retry middleware, so each attempt
+// is timed. The metadata holds the timings of the last attempt.
+type RecordRequestTimings struct{}
+
+// ID returns the middleware identifier.
+func (m *RecordRequestTimings) ID() string {
+	return "RecordRequestTimings"
+}
+
+// HandleFinalize traces the request sent by the remainder of the stack,
+// recording its timings.
+func (m *RecordRequestTimings) HandleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	clock := middleware.GetClock(ctx)
+	t := &requestTimingsTrace{clock: clock, start: clock.Now()}
+	ctx = httptrace.WithClientTrace(ctx, t.clientTrace())
+	ctx = middleware.WithRawResponseHook(ctx, func(interface{}) { t.record(&t.response) })
+
+	out, metadata, err = next.HandleFinalize(ctx, in)
+
+	SetRequestTimingsMetadata(&metadata, t.timings(clock.Now()))
+	return out, metadata, err
+}
+
+// requestTimingsTrace records the times of the httptrace events of a
+// request. The events may be reported from other goroutines.
+type requestTimingsTrace struct {
+	clock smithytime.Clock
+	start time.Time
+
+	mu                  sync.Mutex
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	firstByte           time.Time
+	response            time.Time
+	connReused          bool
+}
+
+func (t *requestTimingsTrace) record(v *time.Time) {
+	now := t.clock.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*v = now
+}
+
+func (t *requestTimingsTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.record(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Only the first dial is timed, when multiple addresses are
+			// dialed in parallel.
+			if t.connStart.IsZero() {
+				t.connStart = t.clock.Now()
+			}
+		},
+		ConnectDone:       func(string, string, error) { t.record(&t.connDone) },
+		TLSHandshakeStart: func() { t.record(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.record(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connReused = info.Reused
+		},
+		GotFirstResponseByte: func() { t.record(&t.firstByte) },
+	}
+}
+
+// timings returns the durations of the recorded phases, with the request
+// ending when the response was returned, or at end if no response was.
+func (t *requestTimingsTrace) timings(end time.Time) RequestTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.response.IsZero() {
+		end = t.response
+	}
+
+	timings := RequestTimings{
+		FirstByte:  phaseDuration(t.start, t.firstByte),
+		Total:      end.Sub(t.start),
+		ConnReused: t.connReused,
+	}
+	// A connection dialed for the request may not be the one it was sent on,
+	// if an idle connection became available first.
+	if !t.connReused {
+		timings.DNS = phaseDuration(t.dnsStart, t.dnsDone)
+		timings.Connect = phaseDuration(t.connStart, t.connDone)
+		timings.TLSHandshake = phaseDuration(t.tlsStart, t.tlsDone)
+	}
+	return timings
+}
+
+// phaseDuration returns the duration between the phase's start and end, or
+// zero if the phase did not complete.
+func phaseDuration(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
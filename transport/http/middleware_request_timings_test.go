@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func sendWithRequestTimings(t *testing.T, client ClientDo, endpoint string) (*Response, middleware.Metadata) {
+	t.Helper()
+
+	req := NewStackRequest().(*Request)
+	req.Method = "GET"
+	req.URL, _ = url.Parse(endpoint)
+	req, _ = req.SetStream(strings.NewReader(""))
+
+	var m RecordRequestTimings
+	out, metadata, err := m.HandleFinalize(context.Background(), middleware.FinalizeInput{Request: req},
+		finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+			out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+		) {
+			resp, metadata, err := NewClientHandler(client).Handle(ctx, in.Request)
+			out.Result = resp
+			return out, metadata, err
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return out.Result.(*Response), metadata
+}
+
+func TestRecordRequestTimings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	resp, metadata := sendWithRequestTimings(t, server.Client(), server.URL)
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	timings, ok := GetRequestTimingsMetadata(metadata)
+	if !ok {
+		t.Fatalf("expect request timings")
+	}
+	if timings.FirstByte < 10*time.Millisecond {
+		t.Errorf("expect first byte of at least 10ms, got %v", timings.FirstByte)
+	}
+	if timings.Total < timings.FirstByte {
+		t.Errorf("expect total %v to be at least first byte %v", timings.Total, timings.FirstByte)
+	}
+	if timings.Connect == 0 {
+		t.Errorf("expect non-zero connect timing")
+	}
+	if timings.TLSHandshake == 0 {
+		t.Errorf("expect non-zero TLS handshake timing")
+	}
+	if timings.ConnReused {
+		t.Errorf("expect new connection")
+	}
+
+	resp, metadata = sendWithRequestTimings(t, server.Client(), server.URL)
+	resp.Body.Close()
+	timings, _ = GetRequestTimingsMetadata(metadata)
+	if !timings.ConnReused {
+		t.Errorf("expect reused connection")
+	}
+	if e, a := time.Duration(0), timings.Connect; e != a {
+		t.Errorf("expect %v connect timing for reused connection, got %v", e, a)
+	}
+}
+
+func TestRecordRequestTimings_StreamingResponse(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(200)
+		w.(http.Flusher).Flush()
+		<-release
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	resp, metadata := sendWithRequestTimings(t, server.Client(), server.URL)
+	defer resp.Body.Close()
+
+	timings, ok := GetRequestTimingsMetadata(metadata)
+	if !ok {
+		t.Fatalf("expect request timings")
+	}
+	if timings.FirstByte < 10*time.Millisecond {
+		t.Errorf("expect first byte of at least 10ms, got %v", timings.FirstByte)
+	}
+
+	close(release)
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if e, a := "hello", string(b); e != a {
+		t.Errorf("expect %v body, got %v", e, a)
+	}
+}
+
+func TestRecordRequestTimings_ExcludesDeserialize(t *testing.T) {
+	stack := middleware.NewStack("timings", NewStackRequest)
+	stack.Finalize.Add(&RecordRequestTimings{}, middleware.After)
+	stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("deserialize",
+		func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			out, metadata, err = next.HandleDeserialize(ctx, in)
+			time.Sleep(50 * time.Millisecond)
+			return out, metadata, err
+		}), middleware.After)
+
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(
+		func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+			return &Response{Response: &http.Response{StatusCode: 200}}, middleware.Metadata{}, nil
+		}), stack)
+
+	_, metadata, err := handler.Handle(context.Background(), struct{}{})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	timings, ok := GetRequestTimingsMetadata(metadata)
+	if !ok {
+		t.Fatalf("expect request timings")
+	}
+	if timings.Total >= 50*time.Millisecond {
+		t.Errorf("expect total to exclude deserializing, got %v", timings.Total)
+	}
+}
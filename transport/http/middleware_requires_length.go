@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// RequiresLengthError is returned by the RequiresLength middleware when the
+// length of a request body that must be sent with a Content-Length cannot be
+// determined.
+type RequiresLengthError struct{}
+
+func (e *RequiresLengthError) Error() string {
+	return "request body requires a known length, and cannot be sent chunked, " +
+		"but is not seekable and does not report its length"
+}
+
+// RequiresLength is a build middleware for operations whose streaming body
+// member has the @requiresLength trait, and so reject requests sent with
+// chunked transfer encoding. The request's content length is set from the
+// length of its body, (see Request.StreamLength), instead of allowing the
+// request to fall back to being sent chunked.
+//
+// Requests with a content length already set, or without a body, are not
+// modified. Returns a RequiresLengthError if the body's length cannot be
+// determined.
+type RequiresLength struct{}
+
+// ID returns the middleware identifier.
+func (m *RequiresLength) ID() string {
+	return "RequiresLength"
+}
+
+// HandleBuild sets the request's content length from its body, or fails if
+// the length is not known.
+func (m *RequiresLength) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	if req.GetStream() == nil || req.ContentLength > 0 || len(req.Header.Get("Content-Length")) != 0 {
+		return next.HandleBuild(ctx, in)
+	}
+
+	n, ok, err := req.StreamLength()
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to compute request content length, %w", err)
+	}
+	if !ok {
+		return out, metadata, &RequiresLengthError{}
+	}
+	req.ContentLength = n
+
+	return next.HandleBuild(ctx, in)
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestRequiresLength(t *testing.T) {
+	cases := map[string]struct {
+		Body          io.Reader
+		ContentLength int64
+		Expect        int64
+		ExpectErr     bool
+	}{
+		"seekable": {
+			Body:   bytes.NewReader([]byte("abcdefghij")),
+			Expect: 10,
+		},
+		"reports length": {
+			Body:   bytes.NewBuffer([]byte("abc")),
+			Expect: 3,
+		},
+		"already set": {
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte("abcdefghij"))),
+			ContentLength: 10,
+			Expect:        10,
+		},
+		"no body": {},
+		"unknown length": {
+			Body:      ioutil.NopCloser(bytes.NewReader([]byte("abc"))),
+			ExpectErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req.ContentLength = c.ContentLength
+			if c.Body != nil {
+				var err error
+				if req, err = req.SetStream(c.Body); err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+			}
+
+			var called bool
+			m := RequiresLength{}
+			_, _, err := m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					called = true
+					return out, metadata, nil
+				}))
+			if c.ExpectErr {
+				var lengthErr *RequiresLengthError
+				if !errors.As(err, &lengthErr) {
+					t.Fatalf("expect requires length error, got %v", err)
+				}
+				if called {
+					t.Errorf("expect request not sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, req.ContentLength; e != a {
+				t.Errorf("expect %v content length, got %v", e, a)
+			}
+		})
+	}
+}
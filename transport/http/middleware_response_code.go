@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// BindResponseCode is a deserialize middleware that sets the HTTP status code
+// of the response on the deserialized output's member bound with the
+// httpResponseCode trait.
+//
+// The middleware should be added before the operation's deserializer, so the
+// output it deserializes is available once the remainder of the stack
+// returns.
+type BindResponseCode struct {
+	// Sets the status code on the output's member. Required.
+	Set func(output interface{}, code int32)
+}
+
+// ID returns the middleware identifier.
+func (m *BindResponseCode) ID() string {
+	return "BindResponseCode"
+}
+
+// HandleDeserialize sets the response's status code on the deserialized
+// output.
+func (m *BindResponseCode) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil || m.Set == nil || out.Result == nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+
+	m.Set(out.Result, int32(resp.StatusCode))
+	return out, metadata, nil
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+type responseCodeOutput struct {
+	Status int32
+}
+
+func TestBindResponseCode(t *testing.T) {
+	m := BindResponseCode{
+		Set: func(output interface{}, code int32) {
+			output.(*responseCodeOutput).Status = code
+		},
+	}
+
+	out, _, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{},
+		deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			out.RawResponse = &Response{Response: &http.Response{StatusCode: 201}}
+			out.Result = &responseCodeOutput{}
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	if e, a := int32(201), out.Result.(*responseCodeOutput).Status; e != a {
+		t.Errorf("expect %v status, got %v", e, a)
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// ResponseValidationError is returned when the ResponseValidator rejects a
+// response, (e.g. a response missing a required signature header). The
+// response's body is not deserialized.
+type ResponseValidationError struct {
+	Response *Response
+	Err      error
+}
+
+// HTTPResponse returns the HTTP response rejected.
+func (e *ResponseValidationError) HTTPResponse() *Response { return e.Response }
+
+// HTTPStatusCode returns the status code of the HTTP response rejected.
+func (e *ResponseValidationError) HTTPStatusCode() int {
+	if e.Response == nil || e.Response.Response == nil {
+		return 0
+	}
+	return e.Response.StatusCode
+}
+
+// Unwrap returns the validator's error.
+func (e *ResponseValidationError) Unwrap() error { return e.Err }
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("response validation failed, %v", e.Err)
+}
+
+// ResponseValidator is a deserialize middleware that calls Validate with the
+// HTTP response before the response is deserialized. If Validate returns an
+// error, the response is rejected with a *ResponseValidationError and its
+// body is not deserialized. Responses with error status codes are validated
+// the same as successful responses.
+//
+// Use AddResponseValidator to add the middleware to a stack, after the
+// protocol's deserializer, so it handles the response first.
+type ResponseValidator struct {
+	Validate func(ctx context.Context, resp *http.Response) error
+}
+
+// AddResponseValidator adds the ResponseValidator for the function as the
+// last middleware of the stack's Deserialize step, so the response is
+// validated before it is deserialized by the middleware added before it.
+func AddResponseValidator(stack *middleware.Stack, fn func(ctx context.Context, resp *http.Response) error) error {
+	return stack.Deserialize.Add(&ResponseValidator{Validate: fn}, middleware.After)
+}
+
+// ID returns the middleware identifier.
+func (m *ResponseValidator) ID() string {
+	return "ResponseValidator"
+}
+
+// HandleDeserialize validates the response returned by the remainder of the
+// stack.
+func (m *ResponseValidator) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if err != nil || m.Validate == nil {
+		return out, metadata, err
+	}
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", out.RawResponse)
+	}
+
+	if err := m.Validate(ctx, resp.Response); err != nil {
+		return out, metadata, &ResponseValidationError{Response: resp, Err: err}
+	}
+
+	return out, metadata, nil
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestAddResponseValidator(t *testing.T) {
+	errMissingSignature := fmt.Errorf("missing signature header")
+
+	cases := map[string]struct {
+		StatusCode        int
+		Signature         string
+		ExpectErr         bool
+		ExpectDeserialize bool
+	}{
+		"signed response": {
+			StatusCode:        200,
+			Signature:         "abc",
+			ExpectDeserialize: true,
+		},
+		"missing signature": {
+			StatusCode: 200,
+			ExpectErr:  true,
+		},
+		"error response missing signature": {
+			StatusCode: 500,
+			ExpectErr:  true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req.URL, _ = url.Parse("https://example.com")
+
+			stack := middleware.NewStack("response validator", func() interface{} { return req })
+
+			var deserialized bool
+			stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("Deserializer", func(
+				ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+			) (
+				out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+			) {
+				out, metadata, err = next.HandleDeserialize(ctx, in)
+				if err != nil {
+					return out, metadata, err
+				}
+				deserialized = true
+				return out, metadata, nil
+			}), middleware.After)
+
+			err := AddResponseValidator(stack, func(ctx context.Context, resp *http.Response) error {
+				if len(resp.Header.Get("X-Signature")) == 0 {
+					return errMissingSignature
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("expect no error adding validator, got %v", err)
+			}
+
+			handler := middleware.DecorateHandler(NewClientHandler(ClientDoFunc(func(r *http.Request) (*http.Response, error) {
+				header := http.Header{}
+				if len(c.Signature) != 0 {
+					header.Set("X-Signature", c.Signature)
+				}
+				return &http.Response{StatusCode: c.StatusCode, Header: header, Body: http.NoBody}, nil
+			})), stack)
+			_, _, err = handler.Handle(context.Background(), struct{}{})
+
+			if c.ExpectErr {
+				var validationErr *ResponseValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("expect response validation error, got %v", err)
+				}
+				if e, a := c.StatusCode, validationErr.HTTPStatusCode(); e != a {
+					t.Errorf("expect %v status code, got %v", e, a)
+				}
+				if !errors.Is(err, errMissingSignature) {
+					t.Errorf("expect validator error wrapped, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := c.ExpectDeserialize, deserialized; e != a {
+				t.Errorf("expect deserialized %v, got %v", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// UnsignedPayload is the payload hash used for requests whose payload is not
+// included in the signature.
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// The payload hashes of requests whose payload is sent with the aws-chunked
+// content encoding, see AWSChunkedEncoding. The payload's chunks are signed
+// individually, or are unsigned, and the trailer variants are followed by
+// trailing headers, (e.g. a flexible checksum of the payload).
+const (
+	StreamingPayload                = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	StreamingPayloadTrailer         = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	StreamingUnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+)
+
+// ContentSHA256Header is the header the payload hash a request is signed with
+// is sent in.
+const ContentSHA256Header = "X-Amz-Content-Sha256"
+
+// PayloadHashMode is the mode the SignRequest middleware selects the payload
+// hash a request is signed with by.
+type PayloadHashMode int
+
+// The modes of selecting the payload hash a request is signed with.
+const (
+	// PayloadHashModeContext signs the request with the payload hash of the
+	// context, see GetPayloadHash. The ContentSHA256Header is not set. The
+	// default mode.
+	PayloadHashModeContext PayloadHashMode = iota
+
+	// PayloadHashModePrecomputed signs the request with the payload hash set
+	// on the context, (e.g. by ComputePayloadHash), failing if no hash was
+	// set.
+	PayloadHashModePrecomputed
+
+	// PayloadHashModeUnsigned signs the request with UnsignedPayload.
+	PayloadHashModeUnsigned
+
+	// PayloadHashModeStreamingTrailer signs the request with the payload
+	// hash of an aws-chunked payload followed by trailing headers,
+	// StreamingPayloadTrailer if the context's payload hash marks the
+	// payload's chunks as signed, otherwise
+	// StreamingUnsignedPayloadTrailer.
+	PayloadHashModeStreamingTrailer
+)
+
+type payloadHashKey struct{}
+
+// SetPayloadHash sets the hex encoded SHA256 hash of the request payload the
+// SignRequest middleware will sign the request with.
+func SetPayloadHash(ctx context.Context, hash string) context.Context {
+	return context.WithValue(ctx, payloadHashKey{}, hash)
+}
+
+// GetPayloadHash returns the payload hash set on the context, or
+// UnsignedPayload if no hash was set.
+func GetPayloadHash(ctx context.Context) string {
+	v, _ := ctx.Value(payloadHashKey{}).(string)
+	if len(v) == 0 {
+		return UnsignedPayload
+	}
+	return v
+}
+
+type skipSigningKey struct{}
+
+// WithSkipSigning returns a context marking that the operation's request is
+// not signed by the SignRequest middleware, (e.g. internal health check
+// calls).
+func WithSkipSigning(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipSigningKey{}, true)
+}
+
+// IsSkipSigning returns if the context marks the operation's request as not
+// signed.
+func IsSkipSigning(ctx context.Context) bool {
+	v, _ := ctx.Value(skipSigningKey{}).(bool)
+	return v
+}
+
+// SignRequest is a finalize middleware that signs each request attempt with
+// the Signer. Credentials are retrieved from the provider for each attempt,
+// and the signing time is taken from the stack's clock.
+//
+// SignRequest should be added after the retry middleware so that every
+// attempt is signed with a current signing time.
+//
+// If Service or RegionSet are not set, the signing name and region of the
+// context are used, see auth.WithSigningName and auth.WithSigningRegion. The
+// signing name and region used are set on the context for the remainder of
+// the stack, and logged at debug.
+//
+// The payload hash the request is signed with is selected by the
+// PayloadHashMode. With modes other than PayloadHashModeContext, the hash is
+// also set as the request's ContentSHA256Header, so it is included in the
+// signature.
+//
+// If the context is marked with WithSkipSigning, the request is sent
+// unsigned, and credentials are not retrieved.
+type SignRequest struct {
+	// Signer used to sign the request. Defaults to auth.NopSigner.
+	Signer auth.Signer
+
+	// Provider of the credentials the request is signed with.
+	Credentials auth.CredentialsProvider
+
+	// Name of the service, and the set of regions the signature is valid
+	// for.
+	Service   string
+	RegionSet []string
+
+	// Options selecting the headers the signer includes in the canonical
+	// request, e.g auth.SignedHeaders and auth.UnsignableHeaders. If unset
+	// the signer's defaults are used.
+	SignedHeaderOptions []func(*auth.SignedHeaderOptions)
+
+	// Mode selecting the payload hash the request is signed with. Defaults
+	// to PayloadHashModeContext.
+	PayloadHashMode PayloadHashMode
+}
+
+// SigningMiddlewareID is the ID of the SignRequest middleware in the
+// Finalize step. Middleware mutating the request so that the mutation is
+// signed, (e.g. adding a custom auth token header), must be inserted before
+// it, see AddPreSignHook.
+const SigningMiddlewareID = "Signing"
+
+// ID returns the middleware identifier.
+func (m *SignRequest) ID() string {
+	return SigningMiddlewareID
+}
+
+// HandleFinalize signs the request before it is sent.
+func (m *SignRequest) HandleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (
+	out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+) {
+	if IsSkipSigning(ctx) {
+		return next.HandleFinalize(ctx, in)
+	}
+
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	payloadHash, err := m.payloadHash(ctx)
+	if err != nil {
+		return out, metadata, err
+	}
+	if m.PayloadHashMode != PayloadHashModeContext {
+		req.Header.Set(ContentSHA256Header, payloadHash)
+	}
+
+	signer := m.Signer
+	if signer == nil {
+		signer = auth.NopSigner{}
+	}
+
+	var creds auth.Credentials
+	if m.Credentials != nil {
+		creds, err = m.Credentials.Retrieve(ctx)
+		if err != nil {
+			return out, metadata, fmt.Errorf("failed to retrieve credentials, %w", err)
+		}
+	}
+
+	service, regionSet := signingNameAndRegions(ctx, m.Service, m.RegionSet)
+	region := strings.Join(regionSet, ",")
+	ctx = auth.WithSigningRegion(auth.WithSigningName(ctx, service), region)
+	if len(m.SignedHeaderOptions) != 0 {
+		ctx = auth.WithSignedHeaderOptions(ctx, auth.NewSignedHeaderOptions(m.SignedHeaderOptions...))
+	}
+
+	middleware.GetLogger(ctx).Logf(logging.Debug, "signing request, signing name %s, signing region %s",
+		signingLogValue(service), signingLogValue(region))
+
+	err = signer.SignHTTP(ctx, creds, req.Request, payloadHash,
+		service, regionSet, middleware.GetClock(ctx).Now())
+	if err != nil {
+		return out, metadata, fmt.Errorf("failed to sign request, %w", err)
+	}
+
+	return next.HandleFinalize(ctx, in)
+}
+
+// signingNameAndRegions returns the service and region set, falling back to
+// the signing name and region set on the context if unset.
+func signingNameAndRegions(ctx context.Context, service string, regionSet []string) (string, []string) {
+	if len(service) == 0 {
+		service = auth.GetSigningName(ctx)
+	}
+	if len(regionSet) == 0 {
+		if region := auth.GetSigningRegion(ctx); len(region) != 0 {
+			regionSet = []string{region}
+		}
+	}
+	return service, regionSet
+}
+
+// payloadHash returns the payload hash the request is signed with, selected
+// by the middleware's PayloadHashMode.
+func (m *SignRequest) payloadHash(ctx context.Context) (string, error) {
+	switch m.PayloadHashMode {
+	case PayloadHashModeContext:
+		return GetPayloadHash(ctx), nil
+	case PayloadHashModePrecomputed:
+		v, _ := ctx.Value(payloadHashKey{}).(string)
+		if len(v) == 0 {
+			return "", fmt.Errorf("precomputed payload hash not set")
+		}
+		return v, nil
+	case PayloadHashModeUnsigned:
+		return UnsignedPayload, nil
+	case PayloadHashModeStreamingTrailer:
+		if v, _ := ctx.Value(payloadHashKey{}).(string); v == StreamingPayloadTrailer {
+			return v, nil
+		}
+		return StreamingUnsignedPayloadTrailer, nil
+	default:
+		return "", fmt.Errorf("unknown payload hash mode %v", m.PayloadHashMode)
+	}
+}
+
+// signingLogValue returns the signing value to log, or "<unset>" if the value
+// is empty, so an unset value is distinguishable in the log.
+func signingLogValue(v string) string {
+	if len(v) == 0 {
+		return "<unset>"
+	}
+	return v
+}
@@ -0,0 +1,388 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go/auth"
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+	smithytime "github.com/awslabs/smithy-go/time"
+)
+
+type signerCall struct {
+	Credentials auth.Credentials
+	PayloadHash string
+	Service     string
+	RegionSet   []string
+	SigningTime time.Time
+}
+
+func TestSignRequest(t *testing.T) {
+	signingTime := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	creds := auth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+
+	cases := map[string]struct {
+		PayloadHash string
+		SignErr     error
+		CredsErr    error
+		ExpectCall  signerCall
+		ExpectErr   string
+	}{
+		"payload hash": {
+			PayloadHash: "abc123",
+			ExpectCall: signerCall{
+				Credentials: creds,
+				PayloadHash: "abc123",
+				Service:     "svc",
+				RegionSet:   []string{"us-east-1", "us-west-2"},
+				SigningTime: signingTime,
+			},
+		},
+		"unsigned payload": {
+			ExpectCall: signerCall{
+				Credentials: creds,
+				PayloadHash: UnsignedPayload,
+				Service:     "svc",
+				RegionSet:   []string{"us-east-1", "us-west-2"},
+				SigningTime: signingTime,
+			},
+		},
+		"sign error": {
+			SignErr:   fmt.Errorf("signing failed"),
+			ExpectErr: "failed to sign request, signing failed",
+		},
+		"credentials error": {
+			CredsErr:  fmt.Errorf("no credentials"),
+			ExpectErr: "failed to retrieve credentials, no credentials",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var call signerCall
+			m := SignRequest{
+				Signer: auth.SignerFunc(func(
+					ctx context.Context, credentials auth.Credentials, r *http.Request,
+					payloadHash string, service string, regionSet []string, signingTime time.Time,
+				) error {
+					call = signerCall{credentials, payloadHash, service, regionSet, signingTime}
+					r.Header.Set("Authorization", "signed")
+					return c.SignErr
+				}),
+				Credentials: auth.CredentialsProviderFunc(func(context.Context) (auth.Credentials, error) {
+					return creds, c.CredsErr
+				}),
+				Service:   "svc",
+				RegionSet: []string{"us-east-1", "us-west-2"},
+			}
+
+			ctx := middleware.SetClock(context.Background(), smithytime.ClockFunc(func() time.Time {
+				return signingTime
+			}))
+			if len(c.PayloadHash) != 0 {
+				ctx = SetPayloadHash(ctx, c.PayloadHash)
+			}
+
+			var sentAuth string
+			_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{Request: NewStackRequest()},
+				finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					sentAuth = in.Request.(*Request).Header.Get("Authorization")
+					return out, metadata, nil
+				}))
+
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect %q error, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectCall, call; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect signer called with %v, got %v", e, a)
+			}
+			if e, a := "signed", sentAuth; e != a {
+				t.Errorf("expect %v authorization header sent, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestSignRequestNopSigner(t *testing.T) {
+	var m SignRequest
+
+	req := NewStackRequest().(*Request)
+	_, _, err := m.HandleFinalize(context.Background(), middleware.FinalizeInput{Request: req},
+		finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+			out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+		) {
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if v := req.Header.Get("Authorization"); len(v) != 0 {
+		t.Errorf("expect request not signed, got %v", v)
+	}
+}
+
+func TestSignRequestSkipSigning(t *testing.T) {
+	var signed, retrieved bool
+	m := SignRequest{
+		Signer: auth.SignerFunc(func(
+			ctx context.Context, credentials auth.Credentials, r *http.Request,
+			payloadHash string, service string, regionSet []string, signingTime time.Time,
+		) error {
+			signed = true
+			return nil
+		}),
+		Credentials: auth.CredentialsProviderFunc(func(ctx context.Context) (auth.Credentials, error) {
+			retrieved = true
+			return auth.Credentials{}, nil
+		}),
+	}
+
+	var sent bool
+	req := NewStackRequest().(*Request)
+	_, _, err := m.HandleFinalize(WithSkipSigning(context.Background()), middleware.FinalizeInput{Request: req},
+		finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+			out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+		) {
+			sent = true
+			return out, metadata, nil
+		}))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !sent {
+		t.Errorf("expect request sent")
+	}
+	if signed {
+		t.Errorf("expect request not signed")
+	}
+	if retrieved {
+		t.Errorf("expect credentials not retrieved")
+	}
+}
+
+func TestSignRequestPayloadHashMode(t *testing.T) {
+	cases := map[string]struct {
+		Mode         PayloadHashMode
+		PayloadHash  string
+		ExpectHash   string
+		ExpectHeader string
+		ExpectErr    bool
+	}{
+		"context hash": {
+			PayloadHash: "abc123",
+			ExpectHash:  "abc123",
+		},
+		"context unset": {
+			ExpectHash: UnsignedPayload,
+		},
+		"precomputed": {
+			Mode:         PayloadHashModePrecomputed,
+			PayloadHash:  "abc123",
+			ExpectHash:   "abc123",
+			ExpectHeader: "abc123",
+		},
+		"precomputed unset": {
+			Mode:      PayloadHashModePrecomputed,
+			ExpectErr: true,
+		},
+		"unsigned": {
+			Mode:         PayloadHashModeUnsigned,
+			PayloadHash:  "abc123",
+			ExpectHash:   UnsignedPayload,
+			ExpectHeader: "UNSIGNED-PAYLOAD",
+		},
+		"streaming trailer": {
+			Mode:         PayloadHashModeStreamingTrailer,
+			PayloadHash:  StreamingUnsignedPayloadTrailer,
+			ExpectHash:   StreamingUnsignedPayloadTrailer,
+			ExpectHeader: "STREAMING-UNSIGNED-PAYLOAD-TRAILER",
+		},
+		"streaming trailer unset": {
+			Mode:         PayloadHashModeStreamingTrailer,
+			ExpectHash:   StreamingUnsignedPayloadTrailer,
+			ExpectHeader: "STREAMING-UNSIGNED-PAYLOAD-TRAILER",
+		},
+		"streaming trailer signed chunks": {
+			Mode:         PayloadHashModeStreamingTrailer,
+			PayloadHash:  StreamingPayloadTrailer,
+			ExpectHash:   StreamingPayloadTrailer,
+			ExpectHeader: "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var hash, header string
+			m := SignRequest{
+				Signer: auth.SignerFunc(func(
+					ctx context.Context, credentials auth.Credentials, r *http.Request,
+					payloadHash string, service string, regionSet []string, signingTime time.Time,
+				) error {
+					hash = payloadHash
+					header = r.Header.Get("X-Amz-Content-Sha256")
+					return nil
+				}),
+				PayloadHashMode: c.Mode,
+			}
+
+			ctx := context.Background()
+			if len(c.PayloadHash) != 0 {
+				ctx = SetPayloadHash(ctx, c.PayloadHash)
+			}
+
+			_, _, err := m.HandleFinalize(ctx, middleware.FinalizeInput{Request: NewStackRequest()},
+				finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectHash, hash; e != a {
+				t.Errorf("expect %v payload hash signed, got %v", e, a)
+			}
+			if e, a := c.ExpectHeader, header; e != a {
+				t.Errorf("expect %q content sha256 header, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestSignRequestSigningContext(t *testing.T) {
+	cases := map[string]struct {
+		Middleware   SignRequest
+		Context      func(context.Context) context.Context
+		ExpectName   string
+		ExpectRegion string
+		ExpectLog    string
+	}{
+		"from middleware": {
+			Middleware:   SignRequest{Service: "svc", RegionSet: []string{"us-east-1", "us-west-2"}},
+			ExpectName:   "svc",
+			ExpectRegion: "us-east-1,us-west-2",
+			ExpectLog:    "signing request, signing name svc, signing region us-east-1,us-west-2",
+		},
+		"from context": {
+			Context: func(ctx context.Context) context.Context {
+				return auth.WithSigningRegion(auth.WithSigningName(ctx, "endpoint-svc"), "eu-west-1")
+			},
+			ExpectName:   "endpoint-svc",
+			ExpectRegion: "eu-west-1",
+			ExpectLog:    "signing request, signing name endpoint-svc, signing region eu-west-1",
+		},
+		"unset": {
+			ExpectLog: "signing request, signing name <unset>, signing region <unset>",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var logged []string
+			ctx := middleware.SetLogger(context.Background(), logging.LoggerFunc(
+				func(classification logging.Classification, format string, v ...interface{}) {
+					logged = append(logged, fmt.Sprintf(format, v...))
+				}))
+			if c.Context != nil {
+				ctx = c.Context(ctx)
+			}
+
+			var signingName, signingRegion string
+			_, _, err := c.Middleware.HandleFinalize(ctx, middleware.FinalizeInput{Request: NewStackRequest()},
+				finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					signingName, signingRegion = auth.GetSigningName(ctx), auth.GetSigningRegion(ctx)
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+
+			if e, a := c.ExpectName, signingName; e != a {
+				t.Errorf("expect %q signing name, got %q", e, a)
+			}
+			if e, a := c.ExpectRegion, signingRegion; e != a {
+				t.Errorf("expect %q signing region, got %q", e, a)
+			}
+			if e, a := []string{c.ExpectLog}, logged; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v logged, got %v", e, a)
+			}
+		})
+	}
+}
+
+func TestSignRequestSignedHeaderOptions(t *testing.T) {
+	cases := map[string]struct {
+		Options      []func(*auth.SignedHeaderOptions)
+		ExpectSigned string
+	}{
+		"default": {
+			ExpectSigned: "content-type;host;x-amz-date",
+		},
+		"allow": {
+			Options:      []func(*auth.SignedHeaderOptions){auth.SignedHeaders([]string{"X-Amz-Date"})},
+			ExpectSigned: "host;x-amz-date",
+		},
+		"deny": {
+			Options:      []func(*auth.SignedHeaderOptions){auth.UnsignableHeaders([]string{"Content-Type"})},
+			ExpectSigned: "host;user-agent;x-amz-date",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var signed string
+			m := SignRequest{
+				Signer: auth.SignerFunc(func(
+					ctx context.Context, credentials auth.Credentials, r *http.Request,
+					payloadHash string, service string, regionSet []string, signingTime time.Time,
+				) error {
+					_, signed = auth.GetSignedHeaderOptions(ctx).CanonicalHeaders(r)
+					return nil
+				}),
+				SignedHeaderOptions: c.Options,
+			}
+
+			req := NewStackRequest().(*Request)
+			req.URL, _ = url.Parse("https://example.com")
+			req.Header.Set("Content-Type", "text/plain")
+			req.Header.Set("User-Agent", "agent")
+			req.Header.Set("X-Amz-Date", "20200601T120000Z")
+
+			_, _, err := m.HandleFinalize(context.Background(), middleware.FinalizeInput{Request: req},
+				finalizeHandlerFunc(func(ctx context.Context, in middleware.FinalizeInput) (
+					out middleware.FinalizeOutput, metadata middleware.Metadata, err error,
+				) {
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectSigned, signed; e != a {
+				t.Errorf("expect %q signed headers, got %q", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+
+	"github.com/awslabs/smithy-go/logging"
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// TLSVersionNone is the TLS version recorded for requests sent to plaintext
+// http endpoints.
+const TLSVersionNone = "none"
+
+// TLSConnectionInfo is the TLS version and cipher suite negotiated for the
+// connection a request was sent on.
+type TLSConnectionInfo struct {
+	// Name of the negotiated TLS version, (e.g. "TLS 1.3"), or
+	// TLSVersionNone if the connection was not encrypted.
+	Version string
+
+	// Name of the negotiated cipher suite, empty if the connection was not
+	// encrypted.
+	CipherSuite string
+}
+
+type tlsConnectionInfoKey struct{}
+
+// SetTLSConnectionMetadata sets the TLS connection info of the request in the
+// metadata.
+func SetTLSConnectionMetadata(metadata *middleware.Metadata, info TLSConnectionInfo) {
+	metadata.Set(tlsConnectionInfoKey{}, info)
+}
+
+// GetTLSConnectionMetadata returns the TLS connection info of the request
+// recorded by the RecordTLSConnection middleware, and if it was set in the
+// metadata.
+func GetTLSConnectionMetadata(metadata middleware.MetadataReader) (TLSConnectionInfo, bool) {
+	v, ok := metadata.Get(tlsConnectionInfoKey{}).(TLSConnectionInfo)
+	return v, ok
+}
+
+// RecordTLSConnection is a deserialize middleware that records the TLS
+// version and cipher suite negotiated for the connection each request is
+// sent on in the operation's metadata, see GetTLSConnectionMetadata. The
+// negotiated version is logged at debug.
+//
+// If MinVersion is set, responses received over a connection negotiated
+// with an older TLS version fail with an error. Requests to plaintext http
+// endpoints are recorded with TLSVersionNone, and are not failed.
+type RecordTLSConnection struct {
+	// Minimum TLS version the connection must have negotiated, (e.g.
+	// tls.VersionTLS12). If zero, the version is not checked.
+	MinVersion uint16
+}
+
+// ID returns the middleware identifier.
+func (m *RecordTLSConnection) ID() string {
+	return "RecordTLSConnection"
+}
+
+// HandleDeserialize traces the connection the request is sent on, recording
+// its TLS connection state.
+func (m *RecordTLSConnection) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+) {
+	var mu sync.Mutex
+	var state *tls.ConnectionState
+	var gotConn bool
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			gotConn = true
+			if conn, ok := info.Conn.(*tls.Conn); ok {
+				s := conn.ConnectionState()
+				state = &s
+			}
+		},
+	})
+
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+
+	resp, ok := out.RawResponse.(*Response)
+	if !ok || resp == nil || resp.Response == nil {
+		return out, metadata, err
+	}
+
+	mu.Lock()
+	if !gotConn {
+		// The client did not report its connection, (e.g. a custom client),
+		// fallback to the response's connection state.
+		state = resp.TLS
+	}
+	mu.Unlock()
+
+	info := TLSConnectionInfo{Version: TLSVersionNone}
+	if state != nil {
+		info.Version = tlsVersionName(state.Version)
+		info.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	}
+	SetTLSConnectionMetadata(&metadata, info)
+	middleware.GetLogger(ctx).Logf(logging.Debug, "negotiated TLS version %s, cipher suite %s",
+		info.Version, info.CipherSuite)
+
+	if err == nil && state != nil && m.MinVersion != 0 && state.Version < m.MinVersion {
+		return out, metadata, fmt.Errorf("negotiated TLS version %s is below the minimum %s",
+			info.Version, tlsVersionName(m.MinVersion))
+	}
+
+	return out, metadata, err
+}
+
+func tlsVersionName(v uint16) string {
+	switch v {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("0x%04X", v)
+	}
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func sendWithTLSRecorder(t *testing.T, m *RecordTLSConnection, client ClientDo, endpoint string) (
+	middleware.Metadata, error,
+) {
+	t.Helper()
+
+	req := NewStackRequest().(*Request)
+	req.Method = "GET"
+	req.URL, _ = url.Parse(endpoint)
+	req, _ = req.SetStream(strings.NewReader(""))
+
+	_, metadata, err := m.HandleDeserialize(context.Background(), middleware.DeserializeInput{Request: req},
+		deserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+			out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+		) {
+			resp, metadata, err := NewClientHandler(client).Handle(ctx, in.Request)
+			if err != nil {
+				return out, metadata, err
+			}
+			out.RawResponse = resp
+			resp.(*Response).Body.Close()
+			return out, metadata, nil
+		}))
+	return metadata, err
+}
+
+func newTLSServer(maxVersion uint16) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	server.TLS = &tls.Config{MaxVersion: maxVersion}
+	server.StartTLS()
+	return server
+}
+
+func TestRecordTLSConnection(t *testing.T) {
+	server := newTLSServer(tls.VersionTLS12)
+	defer server.Close()
+
+	metadata, err := sendWithTLSRecorder(t, &RecordTLSConnection{}, server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	info, ok := GetTLSConnectionMetadata(metadata)
+	if !ok {
+		t.Fatalf("expect TLS connection info in metadata")
+	}
+	if e, a := "TLS 1.2", info.Version; e != a {
+		t.Errorf("expect %v version, got %v", e, a)
+	}
+	if len(info.CipherSuite) == 0 || strings.HasPrefix(info.CipherSuite, "0x") {
+		t.Errorf("expect named cipher suite, got %q", info.CipherSuite)
+	}
+
+	_, err = sendWithTLSRecorder(t, &RecordTLSConnection{MinVersion: tls.VersionTLS13}, server.Client(), server.URL)
+	if err == nil {
+		t.Fatalf("expect error for version below minimum, got none")
+	}
+	if e, a := "below the minimum TLS 1.3", err.Error(); !strings.Contains(a, e) {
+		t.Errorf("expect error to contain %q, got %q", e, a)
+	}
+}
+
+func TestRecordTLSConnection_Plaintext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	metadata, err := sendWithTLSRecorder(t, &RecordTLSConnection{MinVersion: tls.VersionTLS12},
+		server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	info, ok := GetTLSConnectionMetadata(metadata)
+	if !ok {
+		t.Fatalf("expect TLS connection info in metadata")
+	}
+	if e, a := (TLSConnectionInfo{Version: TLSVersionNone}), info; e != a {
+		t.Errorf("expect %v, got %v", e, a)
+	}
+}
+
+func TestBuildableClientMinTLSVersion(t *testing.T) {
+	server := newTLSServer(tls.VersionTLS12)
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client := NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		tr.TLSClientConfig.RootCAs = pool
+	})
+
+	resp, err := client.Do(mustNewRequest(t, server.URL))
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err = client.WithMinTLSVersion(tls.VersionTLS13).Do(mustNewRequest(t, server.URL)); err == nil {
+		t.Fatalf("expect error connecting below minimum TLS version, got none")
+	}
+}
+
+func mustNewRequest(t *testing.T, endpoint string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	return req
+}
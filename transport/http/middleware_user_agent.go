@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+const userAgentHeader = "User-Agent"
+
+// RequestUserAgent is a build middleware that composes the request's
+// User-Agent header. The components are always written in the same order:
+//
+//	<sdk name>/<sdk version> os/<GOOS> lang/go#<go version> <keys> ft/<feature>...
+//
+// Keys are written in the order they were added, and features, (e.g.
+// retry-mode or paginator use), in sorted order. The composed value is
+// appended to any User-Agent already set on the request, instead of
+// replacing it.
+type RequestUserAgent struct {
+	sdkName    string
+	sdkVersion string
+
+	keys     [][2]string
+	features map[string]struct{}
+}
+
+// NewRequestUserAgent returns a RequestUserAgent for the SDK name and
+// version.
+func NewRequestUserAgent(sdkName, sdkVersion string) *RequestUserAgent {
+	return &RequestUserAgent{
+		sdkName:    sdkName,
+		sdkVersion: sdkVersion,
+		features:   map[string]struct{}{},
+	}
+}
+
+// ID returns the middleware identifier.
+func (u *RequestUserAgent) ID() string {
+	return "UserAgent"
+}
+
+// AddKey adds a component to the User-Agent.
+func (u *RequestUserAgent) AddKey(key string) {
+	u.keys = append(u.keys, [2]string{key})
+}
+
+// AddKeyValue adds a key/value component to the User-Agent.
+func (u *RequestUserAgent) AddKeyValue(key, value string) {
+	u.keys = append(u.keys, [2]string{key, value})
+}
+
+// AddFeature adds a feature flag to the User-Agent. Duplicate features are
+// only written once.
+func (u *RequestUserAgent) AddFeature(feature string) {
+	u.features[feature] = struct{}{}
+}
+
+// Build returns the composed User-Agent value.
+func (u *RequestUserAgent) Build() string {
+	b := NewUserAgentBuilder()
+	if len(u.sdkName) != 0 {
+		if len(u.sdkVersion) != 0 {
+			b.AddKeyValue(u.sdkName, u.sdkVersion)
+		} else {
+			b.AddKey(u.sdkName)
+		}
+	}
+	b.AddKeyValue("os", runtime.GOOS)
+	b.AddKeyValue("lang", "go#"+strings.TrimPrefix(runtime.Version(), "go"))
+
+	for _, kv := range u.keys {
+		if len(kv[1]) != 0 {
+			b.AddKeyValue(kv[0], kv[1])
+		} else {
+			b.AddKey(kv[0])
+		}
+	}
+
+	features := make([]string, 0, len(u.features))
+	for f := range u.features {
+		features = append(features, f)
+	}
+	sort.Strings(features)
+	for _, f := range features {
+		b.AddKeyValue("ft", f)
+	}
+
+	return b.Build()
+}
+
+// HandleBuild appends the composed User-Agent to the request's User-Agent
+// header.
+func (u *RequestUserAgent) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	value := u.Build()
+	if current := req.Header.Get(userAgentHeader); len(current) != 0 {
+		value = current + " " + value
+	}
+	req.Header.Set(userAgentHeader, value)
+
+	return next.HandleBuild(ctx, in)
+}
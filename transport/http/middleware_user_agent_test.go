@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestUserAgentBuilder(t *testing.T) {
+	cases := map[string]struct {
+		Build  func(*UserAgentBuilder)
+		Expect string
+	}{
+		"empty": {
+			Build:  func(*UserAgentBuilder) {},
+			Expect: "",
+		},
+		"keys and values": {
+			Build: func(b *UserAgentBuilder) {
+				b.AddKey("foo")
+				b.AddKeyValue("bar", "1.2.3")
+				b.AddKey("baz")
+			},
+			Expect: "foo bar/1.2.3 baz",
+		},
+		"invalid characters": {
+			Build: func(b *UserAgentBuilder) {
+				b.AddKey("my app")
+				b.AddKeyValue("cfg(x)", "a, b;c=\"d\"")
+				b.AddKeyValue("ü", "v#1")
+			},
+			Expect: `my-app cfg-x-/a--b-c--d- -/v#1`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := NewUserAgentBuilder()
+			c.Build(b)
+			if e, a := c.Expect, b.Build(); e != a {
+				t.Errorf("expect %q, got %q", e, a)
+			}
+		})
+	}
+}
+
+func TestRequestUserAgent(t *testing.T) {
+	lang := "lang/" + escapeUserAgentToken("go#"+strings.TrimPrefix(runtime.Version(), "go"))
+	base := "smithy-go/1.0.0 os/" + runtime.GOOS + " " + lang
+
+	cases := map[string]struct {
+		Current string
+		Modify  func(*RequestUserAgent)
+		Expect  string
+	}{
+		"sdk only": {
+			Modify: func(*RequestUserAgent) {},
+			Expect: base,
+		},
+		"ordered keys and sorted features": {
+			Modify: func(u *RequestUserAgent) {
+				u.AddFeature("waiter")
+				u.AddKeyValue("api", "foo#2.0")
+				u.AddFeature("paginator")
+				u.AddKey("exec-env")
+				u.AddFeature("waiter")
+			},
+			Expect: base + " api/foo#2.0 exec-env ft/paginator ft/waiter",
+		},
+		"append to existing": {
+			Current: "my-app/1.0",
+			Modify:  func(*RequestUserAgent) {},
+			Expect:  "my-app/1.0 " + base,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := NewRequestUserAgent("smithy-go", "1.0.0")
+			c.Modify(u)
+
+			req := NewStackRequest().(*Request)
+			if len(c.Current) != 0 {
+				req.Header.Set("User-Agent", c.Current)
+			}
+
+			var actual string
+			_, _, err := u.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					actual = in.Request.(*Request).Header.Get("User-Agent")
+					return out, metadata, nil
+				}))
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, actual; e != a {
+				t.Errorf("expect %q, got %q", e, a)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+// InvalidHeaderError is returned by the ValidateHeaders middleware when a
+// request header's name or value contains characters not allowed in HTTP
+// headers, (e.g. a CR or LF that would inject additional headers).
+type InvalidHeaderError struct {
+	// Name of the invalid header.
+	Name string
+
+	// If the header's value was invalid, instead of its name.
+	InvalidValue bool
+
+	// The first invalid character found.
+	Char byte
+}
+
+func (e *InvalidHeaderError) Error() string {
+	if e.InvalidValue {
+		return fmt.Sprintf("invalid header %q value, contains invalid character 0x%02x", e.Name, e.Char)
+	}
+	return fmt.Sprintf("invalid header name %q, contains invalid character 0x%02x", e.Name, e.Char)
+}
+
+// ValidateHeaders is a build middleware that validates the names and values
+// of the request's headers before the request is sent, failing with an
+// *InvalidHeaderError if a name is not a valid HTTP token, or a value
+// contains control characters other than horizontal tab.
+//
+// Header names are normalized to their canonical form, (e.g.
+// "x-amz-meta-foo" to "X-Amz-Meta-Foo"), merging the values of names that
+// differ only by case.
+type ValidateHeaders struct{}
+
+// ID returns the middleware identifier.
+func (m *ValidateHeaders) ID() string {
+	return "ValidateHeaders"
+}
+
+// HandleBuild validates and normalizes the request's headers.
+func (m *ValidateHeaders) HandleBuild(
+	ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+) (
+	out middleware.BuildOutput, metadata middleware.Metadata, err error,
+) {
+	req, ok := in.Request.(*Request)
+	if !ok {
+		return out, metadata, fmt.Errorf("unknown transport type %T", in.Request)
+	}
+
+	header, err := normalizeHeader(req.Header)
+	if err != nil {
+		return out, metadata, err
+	}
+	req.Header = header
+
+	return next.HandleBuild(ctx, in)
+}
+
+// normalizeHeader returns the header with its names canonicalized, or an
+// error if a name or value is invalid. Names are visited in sorted order, so
+// the values of names differing only by case are merged deterministically.
+func normalizeHeader(header http.Header) (http.Header, error) {
+	names := make([]string, 0, len(header))
+	for k := range header {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	normalized := make(http.Header, len(header))
+	for _, name := range names {
+		if c, ok := invalidHeaderNameChar(name); !ok {
+			return nil, &InvalidHeaderError{Name: name, Char: c}
+		}
+		for _, v := range header[name] {
+			if c, ok := invalidHeaderValueChar(v); !ok {
+				return nil, &InvalidHeaderError{Name: name, InvalidValue: true, Char: c}
+			}
+		}
+
+		key := http.CanonicalHeaderKey(name)
+		normalized[key] = append(normalized[key], header[name]...)
+	}
+	return normalized, nil
+}
+
+// invalidHeaderNameChar returns the first character of the name that is not
+// an RFC 7230 token character, and if the name is valid. Empty names are
+// invalid.
+func invalidHeaderNameChar(name string) (byte, bool) {
+	if len(name) == 0 {
+		return 0, false
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; !isTokenChar(c) {
+			return c, false
+		}
+	}
+	return 0, true
+}
+
+// invalidHeaderValueChar returns the first control character of the value
+// other than horizontal tab, and if the value is valid.
+func invalidHeaderValueChar(v string) (byte, bool) {
+	for i := 0; i < len(v); i++ {
+		if c := v[i]; (c < 0x20 && c != '\t') || c == 0x7f {
+			return c, false
+		}
+	}
+	return 0, true
+}
+
+func isTokenChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	switch c {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+	return false
+}
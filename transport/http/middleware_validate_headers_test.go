@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/awslabs/smithy-go/middleware"
+)
+
+func TestValidateHeaders(t *testing.T) {
+	cases := map[string]struct {
+		Header       http.Header
+		ExpectHeader http.Header
+		ExpectErr    *InvalidHeaderError
+	}{
+		"valid": {
+			Header: http.Header{
+				"X-Amz-Meta-Foo": []string{"bar\tbaz"},
+				"Content-Type":   []string{"application/json"},
+			},
+			ExpectHeader: http.Header{
+				"X-Amz-Meta-Foo": []string{"bar\tbaz"},
+				"Content-Type":   []string{"application/json"},
+			},
+		},
+		"non-canonical names": {
+			Header: http.Header{
+				"x-amz-meta-foo": []string{"a"},
+				"X-AMZ-META-FOO": []string{"b"},
+				"content-type":   []string{"text/plain"},
+			},
+			ExpectHeader: http.Header{
+				"X-Amz-Meta-Foo": []string{"b", "a"},
+				"Content-Type":   []string{"text/plain"},
+			},
+		},
+		"unicode value": {
+			Header:       http.Header{"X-Amz-Meta-Name": []string{"日本"}},
+			ExpectHeader: http.Header{"X-Amz-Meta-Name": []string{"日本"}},
+		},
+		"CRLF injection value": {
+			Header: http.Header{
+				"X-Amz-Meta-Foo": []string{"bar\r\nAuthorization: evil"},
+			},
+			ExpectErr: &InvalidHeaderError{Name: "X-Amz-Meta-Foo", InvalidValue: true, Char: '\r'},
+		},
+		"LF value": {
+			Header:    http.Header{"X-Amz-Meta-Foo": []string{"bar\nbaz"}},
+			ExpectErr: &InvalidHeaderError{Name: "X-Amz-Meta-Foo", InvalidValue: true, Char: '\n'},
+		},
+		"control character value": {
+			Header:    http.Header{"X-Amz-Meta-Foo": []string{"bar\x00"}},
+			ExpectErr: &InvalidHeaderError{Name: "X-Amz-Meta-Foo", InvalidValue: true, Char: 0},
+		},
+		"delete character value": {
+			Header:    http.Header{"X-Amz-Meta-Foo": []string{"bar\x7f"}},
+			ExpectErr: &InvalidHeaderError{Name: "X-Amz-Meta-Foo", InvalidValue: true, Char: 0x7f},
+		},
+		"CRLF injection name": {
+			Header:    http.Header{"X-Foo\r\nX-Evil": []string{"bar"}},
+			ExpectErr: &InvalidHeaderError{Name: "X-Foo\r\nX-Evil", Char: '\r'},
+		},
+		"space in name": {
+			Header:    http.Header{"X Foo": []string{"bar"}},
+			ExpectErr: &InvalidHeaderError{Name: "X Foo", Char: ' '},
+		},
+		"control character name": {
+			Header:    http.Header{"X-Foo\x01": []string{"bar"}},
+			ExpectErr: &InvalidHeaderError{Name: "X-Foo\x01", Char: 0x01},
+		},
+		"empty name": {
+			Header:    http.Header{"": []string{"bar"}},
+			ExpectErr: &InvalidHeaderError{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := NewStackRequest().(*Request)
+			req.Header = c.Header
+
+			var sent bool
+			var m ValidateHeaders
+			_, _, err := m.HandleBuild(context.Background(), middleware.BuildInput{Request: req},
+				buildHandlerFunc(func(ctx context.Context, in middleware.BuildInput) (
+					out middleware.BuildOutput, metadata middleware.Metadata, err error,
+				) {
+					sent = true
+					return out, metadata, nil
+				}))
+
+			if c.ExpectErr != nil {
+				var headerErr *InvalidHeaderError
+				if !errors.As(err, &headerErr) {
+					t.Fatalf("expect invalid header error, got %v", err)
+				}
+				if e, a := c.ExpectErr, headerErr; !reflect.DeepEqual(e, a) {
+					t.Errorf("expect %#v error, got %#v", e, a)
+				}
+				if sent {
+					t.Errorf("expect request not sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.ExpectHeader, req.Header; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %v header, got %v", e, a)
+			}
+		})
+	}
+}
@@ -23,7 +23,10 @@ type Request struct {
 func NewStackRequest() interface{} {
 	return &Request{
 		Request: &http.Request{
-			Header: http.Header{},
+			Header:     http.Header{},
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
 		},
 	}
 }
@@ -45,6 +48,49 @@ func (r *Request) RewindStream() error {
 	return err
 }
 
+// IsStreamSeekable returns if the request's stream is seekable, and can be
+// rewound with RewindStream.
+func (r *Request) IsStreamSeekable() bool {
+	return r.isStreamSeekable
+}
+
+// StreamLength returns the number of bytes of the serialized stream attached
+// to the request, and if the length could be determined. The length can only
+// be determined for streams that are seekable, or report their length. The
+// length of seekable streams is the number of bytes from the stream's start
+// offset recorded by SetStream to its end, and the stream is restored to its
+// current position after the length is computed.
+func (r *Request) StreamLength() (size int64, ok bool, err error) {
+	if r.stream == nil {
+		return 0, true, nil
+	}
+
+	if l, ok := r.stream.(interface{ Len() int }); ok {
+		return int64(l.Len()), true, nil
+	}
+
+	if !r.isStreamSeekable {
+		return 0, false, nil
+	}
+
+	s := r.stream.(io.Seeker)
+	curOffset, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false, err
+	}
+
+	endOffset, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if _, err = s.Seek(curOffset, io.SeekStart); err != nil {
+		return 0, false, err
+	}
+
+	return endOffset - r.streamStartPos, true, nil
+}
+
 // GetStream returns the request stream io.Reader
 func (r *Request) GetStream() io.Reader {
 	return r.stream
@@ -73,7 +119,8 @@ func (r *Request) SetStream(reader io.Reader) (rc *Request, err error) {
 
 // Build returns a build standard HTTP request value from the Smithy request.
 // The request's stream is wrapped in a safe container that allows it to be
-// reused for subsiquent attempts.
+// reused for subsiquent attempts. A stream known to be empty is sent as
+// http.NoBody, so the request is not sent with chunked transfer encoding.
 func (r *Request) Build(ctx context.Context) *http.Request {
 	req := r.Request.Clone(ctx)
 
@@ -93,6 +140,11 @@ func (r *Request) Build(ctx context.Context) *http.Request {
 	// http round tripper close the stream.
 
 	req.Body = ioutil.NopCloser(r.stream)
+	if r.stream != nil && r.ContentLength == 0 {
+		if n, ok, err := r.StreamLength(); err == nil && ok && n == 0 {
+			req.Body = http.NoBody
+		}
+	}
 
 	return req
 }
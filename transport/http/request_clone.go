@@ -0,0 +1,76 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// CloneRequest returns a deep copy of the HTTP request, including an
+// independent reader of the request's body, so middleware can modify and
+// send the copy without affecting the original, (e.g. hedged or alternately
+// signed requests).
+//
+// The body is copied with the request's GetBody if set. Otherwise the body
+// must be an io.Seeker, and is copied from its current position without
+// changing the position the original body is read from. Returns an error for
+// other bodies, since copying them would consume the original body.
+func CloneRequest(r *http.Request) (*http.Request, error) {
+	rc := r.Clone(r.Context())
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return rc, nil
+	}
+
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get request body copy, %w", err)
+		}
+		rc.Body = body
+		return rc, nil
+	}
+
+	seeker, ok := r.Body.(io.Seeker)
+	if !ok {
+		return nil, fmt.Errorf("unable to copy request body, %T is not seekable", r.Body)
+	}
+
+	body, err := copySeekableBody(r.Body, seeker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy request body, %w", err)
+	}
+	rc.Body = body
+	return rc, nil
+}
+
+// copySeekableBody returns an independent reader of the remaining content of
+// the body, restoring the body to its current position.
+func copySeekableBody(body io.Reader, seeker io.Seeker) (io.ReadCloser, error) {
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	if readerAt, ok := body.(io.ReaderAt); ok {
+		end, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(io.NewSectionReader(readerAt, start, end-start)), nil
+	}
+
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = seeker.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
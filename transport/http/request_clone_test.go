@@ -0,0 +1,122 @@
+package http
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// seekableBody is a seekable request body which does not implement
+// io.ReaderAt.
+type seekableBody struct {
+	io.ReadSeeker
+}
+
+func (seekableBody) Close() error { return nil }
+
+// readerAtBody is a seekable request body which implements io.ReaderAt.
+type readerAtBody struct {
+	*strings.Reader
+}
+
+func (readerAtBody) Close() error { return nil }
+
+func TestCloneRequest(t *testing.T) {
+	cases := map[string]struct {
+		Body      func() io.ReadCloser
+		Offset    int64
+		ExpectErr bool
+		Expect    string
+	}{
+		"no body": {},
+		"seekable body": {
+			Body:   func() io.ReadCloser { return seekableBody{strings.NewReader("hello world")} },
+			Expect: "hello world",
+		},
+		"seekable body offset": {
+			Body:   func() io.ReadCloser { return seekableBody{strings.NewReader("hello world")} },
+			Offset: 6,
+			Expect: "world",
+		},
+		"reader at body offset": {
+			Body:   func() io.ReadCloser { return readerAtBody{strings.NewReader("hello world")} },
+			Offset: 6,
+			Expect: "world",
+		},
+		"non-seekable body": {
+			Body:      func() io.ReadCloser { return ioutil.NopCloser(strings.NewReader("hello world")) },
+			ExpectErr: true,
+			Expect:    "hello world",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var body io.ReadCloser
+			if c.Body != nil {
+				body = c.Body()
+				if c.Offset != 0 {
+					body.(io.Seeker).Seek(c.Offset, io.SeekStart)
+				}
+			}
+			req, _ := http.NewRequest("PUT", "https://example.com", body)
+			req.Header.Set("X-Foo", "bar")
+
+			clone, err := CloneRequest(req)
+			if c.ExpectErr {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("expect no error, got %v", err)
+				}
+
+				clone.Header.Set("X-Foo", "baz")
+				if e, a := "bar", req.Header.Get("X-Foo"); e != a {
+					t.Errorf("expect original %v header, got %v", e, a)
+				}
+
+				if body != nil {
+					b, err := ioutil.ReadAll(clone.Body)
+					if err != nil {
+						t.Fatalf("expect no read error, got %v", err)
+					}
+					if e, a := c.Expect, string(b); e != a {
+						t.Errorf("expect %q cloned body, got %q", e, a)
+					}
+				}
+			}
+
+			// The original body must not have been consumed.
+			if body != nil {
+				b, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("expect no read error, got %v", err)
+				}
+				if e, a := c.Expect, string(b); e != a {
+					t.Errorf("expect %q original body, got %q", e, a)
+				}
+			}
+		})
+	}
+}
+
+func TestCloneRequest_GetBody(t *testing.T) {
+	req, _ := http.NewRequest("PUT", "https://example.com", strings.NewReader("hello"))
+
+	clone, err := CloneRequest(req)
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	b, _ := ioutil.ReadAll(clone.Body)
+	if e, a := "hello", string(b); e != a {
+		t.Errorf("expect %q cloned body, got %q", e, a)
+	}
+	b, _ = ioutil.ReadAll(req.Body)
+	if e, a := "hello", string(b); e != a {
+		t.Errorf("expect %q original body, got %q", e, a)
+	}
+}
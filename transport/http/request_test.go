@@ -0,0 +1,50 @@
+package http
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRequestStreamLength(t *testing.T) {
+	req := NewStackRequest().(*Request)
+
+	stream := strings.NewReader("abcdefghij")
+	stream.Seek(2, io.SeekStart)
+
+	req, err := req.SetStream(&seekOnlyReader{stream})
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	// Partially read the stream before computing its length.
+	if _, err = io.ReadFull(stream, make([]byte, 3)); err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+
+	n, ok, err := req.StreamLength()
+	if err != nil {
+		t.Fatalf("expect no error, got %v", err)
+	}
+	if !ok {
+		t.Fatalf("expect length to be determined")
+	}
+	if e, a := int64(8), n; e != a {
+		t.Errorf("expect %v length, got %v", e, a)
+	}
+	if pos, _ := stream.Seek(0, io.SeekCurrent); pos != 5 {
+		t.Errorf("expect stream position 5 restored, got %v", pos)
+	}
+}
+
+// seekOnlyReader hides the Len method of the reader, so the length is
+// computed by seeking.
+type seekOnlyReader struct {
+	r *strings.Reader
+}
+
+func (r *seekOnlyReader) Read(p []byte) (int, error) { return r.r.Read(p) }
+
+func (r *seekOnlyReader) Seek(offset int64, whence int) (int64, error) {
+	return r.r.Seek(offset, whence)
+}
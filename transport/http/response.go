@@ -21,6 +21,15 @@ type ResponseError struct {
 // HTTPResponse returns the HTTP response received from the service.
 func (e *ResponseError) HTTPResponse() *Response { return e.Response }
 
+// HTTPStatusCode returns the status code of the HTTP response received from
+// the service.
+func (e *ResponseError) HTTPStatusCode() int {
+	if e.Response == nil || e.Response.Response == nil {
+		return 0
+	}
+	return e.Response.StatusCode
+}
+
 // Unwrap returns the nested error if any, or nil.
 func (e *ResponseError) Unwrap() error { return e.Err }
 
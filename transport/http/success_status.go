@@ -0,0 +1,34 @@
+package http
+
+import "context"
+
+// SuccessStatusPredicate returns if the HTTP status code of a response is
+// successful, and the response should be deserialized as the operation's
+// output instead of as an error.
+type SuccessStatusPredicate func(statusCode int) bool
+
+// DefaultSuccessStatus is the SuccessStatusPredicate used when none is set
+// on the context, accepting the 2xx status codes.
+func DefaultSuccessStatus(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+type successStatusPredicateKey struct{}
+
+// WithSuccessStatusPredicate returns a context with the predicate deciding
+// the status codes of the operation's successful responses, (e.g. to accept
+// 304 Not Modified as success for conditional requests). Deserializers
+// should use IsSuccessStatus instead of checking the status code directly.
+func WithSuccessStatusPredicate(ctx context.Context, fn SuccessStatusPredicate) context.Context {
+	return context.WithValue(ctx, successStatusPredicateKey{}, fn)
+}
+
+// IsSuccessStatus returns if the status code is successful, according to the
+// context's SuccessStatusPredicate, or DefaultSuccessStatus if none is set.
+func IsSuccessStatus(ctx context.Context, statusCode int) bool {
+	fn, _ := ctx.Value(successStatusPredicateKey{}).(SuccessStatusPredicate)
+	if fn == nil {
+		fn = DefaultSuccessStatus
+	}
+	return fn(statusCode)
+}
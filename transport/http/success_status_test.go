@@ -0,0 +1,41 @@
+package http
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIsSuccessStatus(t *testing.T) {
+	notModified := func(code int) bool {
+		return DefaultSuccessStatus(code) || code == 304
+	}
+
+	cases := map[string]struct {
+		Predicate  SuccessStatusPredicate
+		StatusCode int
+		Expect     bool
+	}{
+		"default 200":        {StatusCode: 200, Expect: true},
+		"default 299":        {StatusCode: 299, Expect: true},
+		"default 304":        {StatusCode: 304},
+		"default 404":        {StatusCode: 404},
+		"default 199":        {StatusCode: 199},
+		"predicate 200":      {Predicate: notModified, StatusCode: 200, Expect: true},
+		"predicate 304":      {Predicate: notModified, StatusCode: 304, Expect: true},
+		"predicate 301":      {Predicate: notModified, StatusCode: 301},
+		"predicate 500":      {Predicate: notModified, StatusCode: 500},
+		"predicate excludes": {Predicate: func(code int) bool { return code == 200 }, StatusCode: 204},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if c.Predicate != nil {
+				ctx = WithSuccessStatusPredicate(ctx, c.Predicate)
+			}
+			if e, a := c.Expect, IsSuccessStatus(ctx, c.StatusCode); e != a {
+				t.Errorf("expect %v success, got %v", e, a)
+			}
+		})
+	}
+}
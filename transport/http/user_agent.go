@@ -0,0 +1,56 @@
+package http
+
+import (
+	"strings"
+)
+
+// UserAgentBuilder is a builder for a HTTP User-Agent string. Keys and
+// values are joined with a space, with any characters not valid in a
+// User-Agent token replaced with a hyphen.
+type UserAgentBuilder struct {
+	sb strings.Builder
+}
+
+// NewUserAgentBuilder returns a new UserAgentBuilder.
+func NewUserAgentBuilder() *UserAgentBuilder {
+	return &UserAgentBuilder{}
+}
+
+// AddKey adds the named component.
+func (u *UserAgentBuilder) AddKey(key string) {
+	u.appendTo(key)
+}
+
+// AddKeyValue adds the named key to the builder with the given value, as
+// key/value.
+func (u *UserAgentBuilder) AddKeyValue(key, value string) {
+	u.appendTo(escapeUserAgentToken(key) + "/" + escapeUserAgentToken(value))
+}
+
+// Build returns the User-Agent string.
+func (u *UserAgentBuilder) Build() string {
+	return u.sb.String()
+}
+
+func (u *UserAgentBuilder) appendTo(value string) {
+	if u.sb.Len() > 0 {
+		u.sb.WriteRune(' ')
+	}
+	u.sb.WriteString(escapeUserAgentToken(value))
+}
+
+// escapeUserAgentToken replaces the characters not valid in a RFC 7230
+// token, other than the key value separator and the '#' used to qualify
+// values, (e.g. lang/go#1.21), with a hyphen.
+func escapeUserAgentToken(v string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			return r
+		case strings.ContainsRune("!#$%&'*+-./^_`|~", r):
+			return r
+		default:
+			return '-'
+		}
+	}, v)
+}
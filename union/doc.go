@@ -0,0 +1,26 @@
+/*
+Package union provides the helpers generated clients use to represent Smithy
+union shapes, and to decode them from the object a union is serialized as,
+(e.g. by the JSON or CBOR decoders).
+
+Generated unions are defined as an interface embedding Member, with a struct
+type for each of the union's members holding the member's value. The member
+set is decoded with Decode from the union's object, which must have exactly
+one member set, by the constructor for the member's name:
+
+	m, err := union.Decode(v, map[string]union.Constructor{
+		"string": func(v interface{}) (union.Member, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("expect string, got %T", v)
+			}
+			return &FooUnionMemberString{Value: s}, nil
+		},
+	})
+
+Members unknown to the client, (e.g. added to the service after the client
+was generated), are decoded as an UnknownUnionMember carrying the member's
+name and raw value, instead of failing. A Visitor dispatches on the member
+set by its name.
+*/
+package union
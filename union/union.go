@@ -0,0 +1,112 @@
+package union
+
+import "fmt"
+
+// Member is implemented by the member types of a union. UnionMember returns
+// the name of the union member the type represents.
+type Member interface {
+	UnionMember() string
+}
+
+// UnknownUnionMember is a union member returned by a service that is not one
+// of the members known to the client. The member's raw decoded value is
+// preserved unchanged.
+type UnknownUnionMember struct {
+	Tag   string
+	Value interface{}
+}
+
+// UnionMember returns the name of the unknown member.
+func (u *UnknownUnionMember) UnionMember() string {
+	return u.Tag
+}
+
+// Constructor returns the union member for the member's raw decoded value.
+type Constructor func(value interface{}) (Member, error)
+
+// Decode returns the member set in the union's decoded object, built by the
+// constructor for the member's name. The object may be a
+// map[string]interface{}, as decoded from JSON, or a
+// map[interface{}]interface{} with string keys, as decoded from CBOR. Members
+// with a nil value are treated as not set.
+//
+// Returns an UnknownUnionMember if there is no constructor for the member's
+// name. Returns an error if the value is not an object, or if the object
+// does not have exactly one member set.
+func Decode(v interface{}, members map[string]Constructor) (Member, error) {
+	var tag string
+	var value interface{}
+	var set int
+
+	switch obj := v.(type) {
+	case map[string]interface{}:
+		for k, mv := range obj {
+			if mv == nil {
+				continue
+			}
+			tag, value = k, mv
+			set++
+		}
+	case map[interface{}]interface{}:
+		for k, mv := range obj {
+			if mv == nil {
+				continue
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("expect union member name string, got %T", k)
+			}
+			tag, value = key, mv
+			set++
+		}
+	default:
+		return nil, fmt.Errorf("expect union object, got %T", v)
+	}
+
+	if set != 1 {
+		return nil, fmt.Errorf("expect union to have exactly one member set, got %d", set)
+	}
+
+	fn, ok := members[tag]
+	if !ok {
+		return &UnknownUnionMember{Tag: tag, Value: value}, nil
+	}
+	m, err := fn(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode union member %s, %w", tag, err)
+	}
+	return m, nil
+}
+
+// Visitor dispatches on the member set in a union by the member's name.
+type Visitor struct {
+	// Functions called with the member, keyed by member name.
+	Members map[string]func(Member) error
+
+	// Function called with members unknown to the client. If nil, visiting
+	// an unknown member returns an error.
+	Unknown func(*UnknownUnionMember) error
+}
+
+// Visit calls the visitor's function for the member. Returns an error if the
+// member is nil, or the visitor has no function for the member.
+func (v Visitor) Visit(m Member) error {
+	if m == nil {
+		return fmt.Errorf("union member not set")
+	}
+
+	if u, ok := m.(*UnknownUnionMember); ok {
+		if v.Unknown == nil {
+			return fmt.Errorf("unknown union member %s", u.Tag)
+		}
+		return v.Unknown(u)
+	}
+
+	fn, ok := v.Members[m.UnionMember()]
+	if !ok {
+		return fmt.Errorf("no visitor for union member %s", m.UnionMember())
+	}
+	return fn(m)
+}
+
+var _ Member = (*UnknownUnionMember)(nil)
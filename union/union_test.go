@@ -0,0 +1,132 @@
+package union
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type memberString struct {
+	Value string
+}
+
+func (*memberString) UnionMember() string { return "string" }
+
+type memberNumber struct {
+	Value float64
+}
+
+func (*memberNumber) UnionMember() string { return "number" }
+
+var testMembers = map[string]Constructor{
+	"string": func(v interface{}) (Member, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expect string, got %T", v)
+		}
+		return &memberString{Value: s}, nil
+	},
+	"number": func(v interface{}) (Member, error) {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expect number, got %T", v)
+		}
+		return &memberNumber{Value: f}, nil
+	},
+}
+
+func TestDecode(t *testing.T) {
+	cases := map[string]struct {
+		Value     interface{}
+		Expect    Member
+		ExpectErr string
+	}{
+		"json member": {
+			Value:  map[string]interface{}{"string": "foo"},
+			Expect: &memberString{Value: "foo"},
+		},
+		"cbor member": {
+			Value:  map[interface{}]interface{}{"number": float64(12)},
+			Expect: &memberNumber{Value: 12},
+		},
+		"null members ignored": {
+			Value:  map[string]interface{}{"string": nil, "number": float64(1)},
+			Expect: &memberNumber{Value: 1},
+		},
+		"unknown member": {
+			Value:  map[string]interface{}{"added": map[string]interface{}{"a": "b"}},
+			Expect: &UnknownUnionMember{Tag: "added", Value: map[string]interface{}{"a": "b"}},
+		},
+		"no members": {
+			Value:     map[string]interface{}{},
+			ExpectErr: "exactly one member set, got 0",
+		},
+		"multiple members": {
+			Value:     map[string]interface{}{"string": "foo", "number": float64(1)},
+			ExpectErr: "exactly one member set, got 2",
+		},
+		"not an object": {
+			Value:     []interface{}{"string"},
+			ExpectErr: "expect union object",
+		},
+		"non string key": {
+			Value:     map[interface{}]interface{}{uint64(1): "foo"},
+			ExpectErr: "expect union member name string",
+		},
+		"member error": {
+			Value:     map[string]interface{}{"string": float64(1)},
+			ExpectErr: "failed to decode union member string",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := Decode(c.Value, testMembers)
+			if len(c.ExpectErr) != 0 {
+				if err == nil || !strings.Contains(err.Error(), c.ExpectErr) {
+					t.Fatalf("expect error containing %q, got %v", c.ExpectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			}
+			if e, a := c.Expect, m; !reflect.DeepEqual(e, a) {
+				t.Errorf("expect %#v, got %#v", e, a)
+			}
+		})
+	}
+}
+
+func TestVisitor(t *testing.T) {
+	var visited []string
+	v := Visitor{
+		Members: map[string]func(Member) error{
+			"string": func(m Member) error {
+				visited = append(visited, "string:"+m.(*memberString).Value)
+				return nil
+			},
+		},
+		Unknown: func(u *UnknownUnionMember) error {
+			visited = append(visited, "unknown:"+u.Tag)
+			return nil
+		},
+	}
+
+	for _, m := range []Member{&memberString{Value: "foo"}, &UnknownUnionMember{Tag: "added"}} {
+		if err := v.Visit(m); err != nil {
+			t.Fatalf("expect no error, got %v", err)
+		}
+	}
+	if e, a := []string{"string:foo", "unknown:added"}, visited; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v visited, got %v", e, a)
+	}
+
+	if err := v.Visit(&memberNumber{}); err == nil {
+		t.Errorf("expect error for member without visitor, got none")
+	}
+	if err := (Visitor{}).Visit(&UnknownUnionMember{Tag: "added"}); err == nil {
+		t.Errorf("expect error for unknown member without visitor, got none")
+	}
+}
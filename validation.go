@@ -0,0 +1,111 @@
+package smithy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InvalidParamError is an invalid member of an operation's input, identified
+// by the member's path within the input.
+type InvalidParamError struct {
+	// Path of the member within the input, (e.g. Foo.Bar[2].Baz).
+	Path string
+
+	// Reason the member is invalid.
+	Reason string
+}
+
+// NewInvalidParamError returns an InvalidParamError for the member.
+func NewInvalidParamError(member, reason string) *InvalidParamError {
+	return &InvalidParamError{
+		Path:   member,
+		Reason: reason,
+	}
+}
+
+// NewParamRequiredError returns an InvalidParamError for a required member
+// that was not set.
+func NewParamRequiredError(member string) *InvalidParamError {
+	return NewInvalidParamError(member, "missing required field")
+}
+
+func (e *InvalidParamError) Error() string {
+	return fmt.Sprintf("%s, %s", e.Reason, e.Path)
+}
+
+// InvalidParamsError is the error returned when an operation's input fails
+// validation, listing each invalid member of the input.
+//
+// Errors of nested structures, lists, and maps are validated into their own
+// InvalidParamsError, and added to the parent's with AddNested, prefixing
+// their paths with the member the nested value was in.
+//
+//	nested := &smithy.InvalidParamsError{}
+//	nested.Add(smithy.NewParamRequiredError("Baz"))
+//	invalid.AddNested(smithy.ListMemberPath("Bar", 2), nested)
+type InvalidParamsError struct {
+	// Name of the shape validated, (e.g. the operation's input shape).
+	Context string
+
+	Errs []*InvalidParamError
+}
+
+// Add adds the invalid member error.
+func (e *InvalidParamsError) Add(err *InvalidParamError) {
+	e.Errs = append(e.Errs, err)
+}
+
+// AddNested adds the errors of the nested shape, prefixing their paths with
+// the member the nested shape is in.
+func (e *InvalidParamsError) AddNested(member string, nested *InvalidParamsError) {
+	if nested == nil {
+		return
+	}
+	for _, err := range nested.Errs {
+		e.Errs = append(e.Errs, &InvalidParamError{
+			Path:   joinParamPath(member, err.Path),
+			Reason: err.Reason,
+		})
+	}
+}
+
+// Len returns the number of invalid member errors.
+func (e *InvalidParamsError) Len() int {
+	return len(e.Errs)
+}
+
+func (e *InvalidParamsError) Error() string {
+	var w strings.Builder
+	if len(e.Context) != 0 {
+		fmt.Fprintf(&w, "%d validation error(s) found in %s.\n", len(e.Errs), e.Context)
+	} else {
+		fmt.Fprintf(&w, "%d validation error(s) found.\n", len(e.Errs))
+	}
+	for _, err := range e.Errs {
+		fmt.Fprintf(&w, "- %v.\n", err)
+	}
+	return w.String()
+}
+
+// ListMemberPath returns the path of the list member's element at the index,
+// (e.g. Bar[2]).
+func ListMemberPath(member string, index int) string {
+	return member + "[" + strconv.Itoa(index) + "]"
+}
+
+// MapMemberPath returns the path of the map member's value at the key,
+// (e.g. Tags["env"]).
+func MapMemberPath(member, key string) string {
+	return member + "[" + strconv.Quote(key) + "]"
+}
+
+func joinParamPath(member, path string) string {
+	if len(member) == 0 {
+		return path
+	}
+	if len(path) == 0 || path[0] == '[' {
+		return member + path
+	}
+	return member + "." + path
+}
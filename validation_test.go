@@ -0,0 +1,46 @@
+package smithy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInvalidParamsError_NestedPaths(t *testing.T) {
+	// Foo.Bar[2].Baz missing, and Foo.Tags["env"] invalid.
+	baz := &InvalidParamsError{Context: "BarShape"}
+	baz.Add(NewParamRequiredError("Baz"))
+
+	foo := &InvalidParamsError{Context: "FooShape"}
+	foo.AddNested(ListMemberPath("Bar", 2), baz)
+	foo.Add(NewParamRequiredError("Name"))
+	foo.AddNested("Tags", &InvalidParamsError{Errs: []*InvalidParamError{
+		NewInvalidParamError(MapMemberPath("", "env"), "value too long"),
+	}})
+
+	input := &InvalidParamsError{Context: "OperationInput"}
+	input.Add(NewParamRequiredError("Id"))
+	input.AddNested("Foo", foo)
+	input.AddNested("Empty", &InvalidParamsError{})
+	input.AddNested("Nil", nil)
+
+	var paths []string
+	for _, err := range input.Errs {
+		paths = append(paths, err.Path)
+	}
+	expect := []string{"Id", "Foo.Bar[2].Baz", "Foo.Name", `Foo.Tags["env"]`}
+	if e, a := expect, paths; !reflect.DeepEqual(e, a) {
+		t.Errorf("expect %v paths, got %v", e, a)
+	}
+	if e, a := 4, input.Len(); e != a {
+		t.Errorf("expect %v errors, got %v", e, a)
+	}
+
+	expectMsg := "4 validation error(s) found in OperationInput.\n" +
+		"- missing required field, Id.\n" +
+		"- missing required field, Foo.Bar[2].Baz.\n" +
+		"- missing required field, Foo.Name.\n" +
+		"- value too long, Foo.Tags[\"env\"].\n"
+	if e, a := expectMsg, input.Error(); e != a {
+		t.Errorf("expect %q message, got %q", e, a)
+	}
+}
@@ -0,0 +1,5 @@
+/*
+Package waiter provides the core of waiters, which poll an operation until
+its result transitions the waiter to a success or failure state.
+*/
+package waiter
@@ -0,0 +1,222 @@
+package waiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+)
+
+// State is the state of the waiter an acceptor transitions the waiter to.
+type State int
+
+// Enumeration of waiter states.
+const (
+	// RetryState continues polling the operation.
+	RetryState State = iota
+
+	// SuccessState stops polling, with the waiter completing successfully.
+	SuccessState
+
+	// FailureState stops polling, with the waiter failing.
+	FailureState
+)
+
+func (s State) String() string {
+	switch s {
+	case SuccessState:
+		return "success"
+	case FailureState:
+		return "failure"
+	default:
+		return "retry"
+	}
+}
+
+// ErrMaxWaitExceeded is returned by the waiter if the maximum wait duration
+// elapsed before the waiter transitioned to the success or failure state.
+var ErrMaxWaitExceeded = errors.New("waiter: exceeded max wait time")
+
+// ErrFailureState is returned, wrapped, by the waiter if an acceptor
+// transitioned the waiter to the failure state.
+var ErrFailureState = errors.New("waiter: transitioned to failure state")
+
+// Matcher returns if the poll's result matches the acceptor. Returns an error
+// if the result could not be matched.
+type Matcher func(input, output interface{}, err error) (bool, error)
+
+// Acceptor transitions the waiter to its State when its Matcher matches the
+// result of polling the operation.
+type Acceptor struct {
+	State   State
+	Matcher Matcher
+}
+
+// ErrorCodeMatcher returns a Matcher matching polls that failed with an
+// APIError with the error code.
+func ErrorCodeMatcher(code string) Matcher {
+	return func(_, _ interface{}, err error) (bool, error) {
+		var apiErr smithy.APIError
+		return errors.As(err, &apiErr) && apiErr.ErrorCode() == code, nil
+	}
+}
+
+// OutputMatcher returns a Matcher matching polls that succeeded with an
+// output the function reports as matching.
+func OutputMatcher(fn func(output interface{}) bool) Matcher {
+	return func(_, output interface{}, err error) (bool, error) {
+		return err == nil && fn(output), nil
+	}
+}
+
+// PollFunc invokes the operation being waited on with the input.
+type PollFunc func(ctx context.Context, input interface{}) (output interface{}, err error)
+
+// Options are the waiter options.
+type Options struct {
+	// Minimum delay between polls. Must be greater than zero.
+	MinDelay time.Duration
+
+	// Maximum delay between polls. Must be greater than or equal to
+	// MinDelay.
+	MaxDelay time.Duration
+
+	// Acceptors are matched in order against the result of each poll. The
+	// first acceptor matching transitions the waiter to its state.
+	Acceptors []Acceptor
+}
+
+// Waiter polls an operation until an acceptor transitions the waiter to the
+// success or failure state, or the maximum wait duration is exceeded.
+type Waiter struct {
+	poll    PollFunc
+	options Options
+}
+
+// New returns a Waiter polling the function provided, configured by the
+// functional options.
+func New(poll PollFunc, optFns ...func(*Options)) *Waiter {
+	var o Options
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	return &Waiter{
+		poll:    poll,
+		options: o,
+	}
+}
+
+// Wait polls the operation with the input until the waiter transitions to the
+// success state, returning the output of the last poll. Returns an error if
+// the waiter transitions to the failure state, the poll fails with an error
+// not matched by an acceptor, the max wait duration is exceeded, or the
+// context is done.
+//
+// Polls are delayed with exponential backoff and jitter between the MinDelay
+// and MaxDelay options.
+func (w *Waiter) Wait(ctx context.Context, input interface{}, maxWait time.Duration) (interface{}, error) {
+	minDelay, maxDelay := w.options.MinDelay, w.options.MaxDelay
+	if minDelay <= 0 {
+		return nil, fmt.Errorf("waiter: minimum delay must be greater than zero")
+	}
+	if maxDelay < minDelay {
+		return nil, fmt.Errorf("waiter: maximum delay %v must be greater than minimum delay %v", maxDelay, minDelay)
+	}
+	if maxWait <= 0 {
+		return nil, fmt.Errorf("waiter: maximum wait time must be greater than zero")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+	deadline := time.Now().Add(maxWait)
+
+	for attempt := 1; ; attempt++ {
+		output, err := w.poll(ctx, input)
+
+		state, matched, matchErr := w.match(input, output, err)
+		if matchErr != nil {
+			return nil, matchErr
+		}
+
+		switch {
+		case matched && state == SuccessState:
+			return output, nil
+		case matched && state == FailureState && err != nil:
+			return nil, fmt.Errorf("%w, %v", ErrFailureState, err)
+		case matched && state == FailureState:
+			return nil, ErrFailureState
+		case !matched && err != nil:
+			return nil, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= minDelay {
+			return nil, ErrMaxWaitExceeded
+		}
+
+		delay := computeDelay(attempt, minDelay, maxDelay, remaining)
+		if err := sleepWithContext(ctx, delay); err != nil {
+			if ctx.Err() == context.DeadlineExceeded && time.Until(deadline) <= 0 {
+				return nil, ErrMaxWaitExceeded
+			}
+			return nil, err
+		}
+	}
+}
+
+// match returns the state of the first acceptor matching the poll's result.
+func (w *Waiter) match(input, output interface{}, err error) (State, bool, error) {
+	for _, a := range w.options.Acceptors {
+		matched, matchErr := a.Matcher(input, output, err)
+		if matchErr != nil {
+			return RetryState, false, fmt.Errorf("waiter: failed to match acceptor, %w", matchErr)
+		}
+		if matched {
+			return a.State, true, nil
+		}
+	}
+	return RetryState, false, nil
+}
+
+// computeDelay returns the delay before the next poll. The delay grows
+// exponentially from the minimum delay with each attempt, capped at the
+// maximum delay, with jitter applied. The delay is reduced so that a final
+// poll can be made before the remaining time elapses.
+func computeDelay(attempt int, minDelay, maxDelay, remaining time.Duration) time.Duration {
+	attemptCeiling := (math.Log(float64(maxDelay/minDelay)) / math.Log(2)) + 1
+
+	delay := maxDelay
+	if attempt <= int(attemptCeiling) {
+		delay = minDelay * time.Duration(1<<uint(attempt-1))
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+
+	if delay > minDelay {
+		delay = minDelay + time.Duration(rand.Int63n(int64(delay-minDelay)))
+	}
+
+	if remaining-delay <= minDelay {
+		delay = remaining - minDelay
+	}
+
+	return delay
+}
+
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
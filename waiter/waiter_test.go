@@ -0,0 +1,166 @@
+package waiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awslabs/smithy-go"
+)
+
+type mockOutput struct {
+	Status string
+}
+
+func statusMatcher(status string) Matcher {
+	return OutputMatcher(func(output interface{}) bool {
+		return output.(*mockOutput).Status == status
+	})
+}
+
+func mockWaiterOptions(o *Options) {
+	o.MinDelay = time.Millisecond
+	o.MaxDelay = 5 * time.Millisecond
+	o.Acceptors = []Acceptor{
+		{State: SuccessState, Matcher: statusMatcher("available")},
+		{State: FailureState, Matcher: statusMatcher("failed")},
+		{State: FailureState, Matcher: ErrorCodeMatcher("AccessDenied")},
+		{State: RetryState, Matcher: ErrorCodeMatcher("NotFound")},
+	}
+}
+
+func TestWaiter(t *testing.T) {
+	cases := map[string]struct {
+		Results     []error
+		Statuses    []string
+		MaxWait     time.Duration
+		ExpectPolls int
+		ExpectErr   error
+	}{
+		"success after polls": {
+			Statuses:    []string{"pending", "pending", "available"},
+			MaxWait:     time.Minute,
+			ExpectPolls: 3,
+		},
+		"retry error then success": {
+			Results: []error{
+				&smithy.GenericAPIError{Code: "NotFound"},
+				nil,
+			},
+			Statuses:    []string{"", "available"},
+			MaxWait:     time.Minute,
+			ExpectPolls: 2,
+		},
+		"failure output": {
+			Statuses:    []string{"pending", "failed"},
+			MaxWait:     time.Minute,
+			ExpectPolls: 2,
+			ExpectErr:   ErrFailureState,
+		},
+		"failure error": {
+			Results: []error{
+				&smithy.GenericAPIError{Code: "AccessDenied"},
+			},
+			Statuses:    []string{""},
+			MaxWait:     time.Minute,
+			ExpectPolls: 1,
+			ExpectErr:   ErrFailureState,
+		},
+		"unmatched error": {
+			Results: []error{
+				&smithy.GenericAPIError{Code: "Unknown"},
+			},
+			Statuses:    []string{""},
+			MaxWait:     time.Minute,
+			ExpectPolls: 1,
+			ExpectErr:   &smithy.GenericAPIError{Code: "Unknown"},
+		},
+		"max wait exceeded": {
+			MaxWait:   30 * time.Millisecond,
+			ExpectErr: ErrMaxWaitExceeded,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var polls int
+			w := New(func(ctx context.Context, input interface{}) (interface{}, error) {
+				i := polls
+				polls++
+
+				var err error
+				if i < len(c.Results) {
+					err = c.Results[i]
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				status := "pending"
+				if i < len(c.Statuses) {
+					status = c.Statuses[i]
+				}
+				return &mockOutput{Status: status}, nil
+			}, mockWaiterOptions)
+
+			output, err := w.Wait(context.Background(), struct{}{}, c.MaxWait)
+			if c.ExpectErr != nil {
+				if err == nil {
+					t.Fatalf("expect error, got none")
+				}
+				var apiErr smithy.APIError
+				if errors.As(c.ExpectErr, &apiErr) {
+					if e, a := c.ExpectErr.Error(), err.Error(); e != a {
+						t.Errorf("expect %v error, got %v", e, a)
+					}
+				} else if !errors.Is(err, c.ExpectErr) {
+					t.Errorf("expect %v error, got %v", c.ExpectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("expect no error, got %v", err)
+			} else if e, a := "available", output.(*mockOutput).Status; e != a {
+				t.Errorf("expect %v status, got %v", e, a)
+			}
+
+			if c.ExpectPolls != 0 {
+				if e, a := c.ExpectPolls, polls; e != a {
+					t.Errorf("expect %v polls, got %v", e, a)
+				}
+			} else if polls < 2 {
+				t.Errorf("expect multiple polls, got %v", polls)
+			}
+		})
+	}
+}
+
+func TestWaiterContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := New(func(context.Context, interface{}) (interface{}, error) {
+		cancel()
+		return &mockOutput{Status: "pending"}, nil
+	}, func(o *Options) {
+		mockWaiterOptions(o)
+		o.MinDelay = time.Second
+		o.MaxDelay = time.Minute
+	})
+
+	if _, err := w.Wait(ctx, struct{}{}, time.Hour); err != context.Canceled {
+		t.Errorf("expect %v error, got %v", context.Canceled, err)
+	}
+}
+
+func TestComputeDelay(t *testing.T) {
+	minDelay, maxDelay := 2*time.Second, 120*time.Second
+
+	for attempt := 1; attempt < 20; attempt++ {
+		delay := computeDelay(attempt, minDelay, maxDelay, time.Hour)
+		if delay < minDelay || delay > maxDelay {
+			t.Errorf("attempt %v, expect delay within [%v, %v], got %v", attempt, minDelay, maxDelay, delay)
+		}
+	}
+
+	if e, a := 3*time.Second, computeDelay(10, minDelay, maxDelay, 5*time.Second); e != a {
+		t.Errorf("expect delay limited by remaining time to %v, got %v", e, a)
+	}
+}